@@ -6,15 +6,21 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"nodemon/cmd/bots/internal/common"
 	"nodemon/cmd/bots/internal/common/api"
+	"nodemon/cmd/bots/internal/common/chats"
 	"nodemon/cmd/bots/internal/common/initial"
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/rbac"
+	"nodemon/cmd/bots/internal/common/reports"
+	"nodemon/cmd/bots/internal/common/state"
 	"nodemon/cmd/bots/internal/discord/handlers"
 	"nodemon/internal"
+	"nodemon/pkg/entities"
 	generalMessaging "nodemon/pkg/messaging"
 	"nodemon/pkg/messaging/pair"
 	"nodemon/pkg/tools"
@@ -24,7 +30,10 @@ import (
 	"go.uber.org/zap"
 )
 
-const defaultAPIReadTimeout = 30 * time.Second
+const (
+	defaultAPIReadTimeout   = 30 * time.Second
+	defaultAlertMessagesTTL = 7 * 24 * time.Hour
+)
 
 func main() {
 	const contextCanceledExitCode = 130
@@ -39,13 +48,18 @@ func main() {
 }
 
 type discordBotConfig struct {
-	natsMessagingURL string
-	discordBotToken  string
-	discordChatID    string
-	logLevel         string
-	development      bool
-	bindAddress      string
-	scheme           string
+	natsMessagingURL  string
+	discordBotToken   string
+	discordChatID     string
+	chatsFile         string
+	reportsFile       string
+	alertMessagesFile string
+	alertMessagesTTL  time.Duration
+	rbacConfig        string
+	logLevel          string
+	development       bool
+	bindAddress       string
+	scheme            string
 }
 
 func newDiscordBotConfigConfig() *discordBotConfig {
@@ -56,6 +70,17 @@ func newDiscordBotConfigConfig() *discordBotConfig {
 		"", "The secret token used to authenticate the bot")
 	tools.StringVarFlagWithEnv(&c.discordChatID, "discord-chat-id",
 		"", "discord chat ID to send alerts through")
+	tools.StringVarFlagWithEnv(&c.chatsFile, "chats-file", "",
+		"Path to the file with the chats settings. If it's empty, the settings are kept only in memory.")
+	tools.StringVarFlagWithEnv(&c.reportsFile, "reports-file", "",
+		"Path to the file with the scheduled reports. If it's empty, the reports are kept only in memory. "+
+			"The chat gets the daily status report the first time it's served.")
+	tools.StringVarFlagWithEnv(&c.alertMessagesFile, "alert-messages-file", "",
+		"Path to the file with the IDs of the alert messages. If it's empty, the IDs are kept only in memory.")
+	tools.DurationVarFlagWithEnv(&c.alertMessagesTTL, "alert-messages-ttl", defaultAlertMessagesTTL,
+		"How long the IDs of the alert messages are kept to reply with the alert fix")
+	tools.StringVarFlagWithEnv(&c.rbacConfig, "rbac-config", "",
+		"Path to the access control config in YAML or JSON format. If it's empty, everyone may run all commands.")
 	tools.StringVarFlagWithEnv(&c.logLevel, "log-level", "INFO",
 		"Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
 	tools.BoolVarFlagWithEnv(&c.development, "development", false, "Development mode.")
@@ -79,6 +104,10 @@ func (c *discordBotConfig) validate(zap *zap.Logger) error {
 		zap.Error("discord chat ID is required")
 		return common.ErrInvalidParameters
 	}
+	if c.alertMessagesTTL <= 0 {
+		zap.Error("alert messages TTL must be positive")
+		return common.ErrInvalidParameters
+	}
 	return nil
 }
 
@@ -104,6 +133,22 @@ func runDiscordBot() error {
 		return validationErr
 	}
 
+	chatsStorage, err := chats.NewStorage(cfg.chatsFile)
+	if err != nil {
+		logger.Error("Failed to load chats", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+	alertMessages, err := state.NewAlertMessages(cfg.alertMessagesFile, cfg.alertMessagesTTL)
+	if err != nil {
+		logger.Error("Failed to load alert messages", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+	authorizer, err := newAuthorizer(cfg.rbacConfig, logger)
+	if err != nil {
+		logger.Error("Failed to load access control config", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+
 	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer done()
 
@@ -113,6 +158,8 @@ func runDiscordBot() error {
 	discordBotEnv, initErr := initial.InitDiscordBot(
 		cfg.discordBotToken,
 		cfg.discordChatID,
+		chatsStorage,
+		alertMessages,
 		logger,
 		requestChan,
 		responseChan,
@@ -121,7 +168,14 @@ func runDiscordBot() error {
 	if initErr != nil {
 		return errors.Wrap(initErr, "failed to init discord bot")
 	}
-	handlers.InitDscHandlers(discordBotEnv, requestChan, responseChan, logger)
+	taskScheduler := chrono.NewDefaultTaskScheduler()
+	reportsManager, err := initReports(cfg, taskScheduler, requestChan, responseChan, discordBotEnv, logger)
+	if err != nil {
+		taskScheduler.Shutdown()
+		logger.Error("Failed to load scheduled reports", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+	handlers.InitDscHandlers(discordBotEnv, authorizer, reportsManager, requestChan, responseChan, logger)
 
 	runMessagingClients(ctx, cfg, discordBotEnv, logger, requestChan, responseChan)
 
@@ -140,15 +194,7 @@ func runDiscordBot() error {
 		defer botAPI.Shutdown()
 	}
 
-	taskScheduler := chrono.NewDefaultTaskScheduler()
-	err = common.ScheduleNodesStatus(taskScheduler, requestChan, responseChan, discordBotEnv, logger)
-	if err != nil {
-		taskScheduler.Shutdown()
-		logger.Fatal("failed to schedule nodes status", zap.Error(err))
-		return err
-	}
-
-	logger.Info("Nodes status has been scheduled successfully")
+	reportsManager.Start()
 
 	err = discordBotEnv.Start()
 	if err != nil {
@@ -168,6 +214,31 @@ func runDiscordBot() error {
 	return nil
 }
 
+// initReports loads the scheduled reports, the chat gets the daily status report the first time it's served.
+func initReports(
+	cfg *discordBotConfig,
+	taskScheduler chrono.TaskScheduler,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	discordBotEnv *common.DiscordBotEnvironment,
+	logger *zap.Logger,
+) (*reports.Manager, error) {
+	chatID, err := strconv.ParseInt(cfg.discordChatID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid discord chat id '%s'", cfg.discordChatID)
+	}
+	storage, err := reports.NewStorage(cfg.reportsFile, reports.Report{
+		Chat: entities.ChatID(chatID),
+		Kind: reports.StatusKind,
+		Cron: reports.DailyStatusCron,
+	})
+	if err != nil {
+		return nil, err
+	}
+	run := common.ReportRunner(requestChan, responseChan, discordBotEnv, logger)
+	return reports.NewManager(storage, taskScheduler, run, logger), nil
+}
+
 func waitScheduler(taskScheduler chrono.TaskScheduler, logger *zap.Logger) {
 	if !taskScheduler.IsShutdown() {
 		<-taskScheduler.Shutdown()
@@ -200,3 +271,15 @@ func runMessagingClients(
 		}
 	}()
 }
+
+func newAuthorizer(path string, logger *zap.Logger) (*rbac.Authorizer, error) {
+	if path == "" {
+		logger.Warn("Access control isn't configured, everyone may run all commands")
+		return rbac.NewAuthorizer(rbac.AllowAllConfig(), logger), nil
+	}
+	rbacCfg, err := rbac.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return rbac.NewAuthorizer(rbacCfg, logger), nil
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"nodemon/cmd/bots/internal/common"
+	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/email"
+	"nodemon/internal"
+	"nodemon/pkg/tools"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSMTPTimeout    = 30 * time.Second
+	defaultDigestInterval = time.Hour
+)
+
+func main() {
+	const (
+		contextCanceledExitCode   = 130
+		invalidParametersExitCode = 2
+	)
+	if err := runEmailBot(); err != nil {
+		switch {
+		case errors.Is(err, context.Canceled):
+			os.Exit(contextCanceledExitCode)
+		case errors.Is(err, common.ErrInvalidParameters):
+			os.Exit(invalidParametersExitCode)
+		default:
+			log.Fatal(err)
+		}
+	}
+}
+
+type emailBotConfig struct {
+	natsMessagingURL string
+	smtpAddress      string
+	smtpUsername     string
+	smtpPassword     string
+	smtpStartTLS     bool
+	smtpTimeout      time.Duration
+	emailFrom        string
+	emailTo          string
+	digestInterval   time.Duration
+	logLevel         string
+	development      bool
+	scheme           string
+}
+
+func newEmailBotConfig() *emailBotConfig {
+	c := new(emailBotConfig)
+	tools.StringVarFlagWithEnv(&c.natsMessagingURL, "nats-msg-url",
+		"nats://127.0.0.1:4222", "NATS server URL for messaging")
+	tools.StringVarFlagWithEnv(&c.smtpAddress, "smtp-addr", "",
+		"SMTP server address in the host:port form")
+	tools.StringVarFlagWithEnv(&c.smtpUsername, "smtp-username", "",
+		"SMTP username, the authentication is disabled if it's empty")
+	tools.StringVarFlagWithEnv(&c.smtpPassword, "smtp-password", "", "SMTP password")
+	tools.BoolVarFlagWithEnv(&c.smtpStartTLS, "smtp-starttls", true,
+		"Require STARTTLS. If it's disabled, STARTTLS is still used when the server supports it.")
+	tools.DurationVarFlagWithEnv(&c.smtpTimeout, "smtp-timeout", defaultSMTPTimeout,
+		"Timeout of the whole SMTP session")
+	tools.StringVarFlagWithEnv(&c.emailFrom, "email-from", "", "Sender address of the emails")
+	tools.StringVarFlagWithEnv(&c.emailTo, "email-to", "", "Comma separated list of the recipient addresses")
+	tools.DurationVarFlagWithEnv(&c.digestInterval, "digest-interval", defaultDigestInterval,
+		"Interval of sending the digest of the warning and info alerts")
+	tools.StringVarFlagWithEnv(&c.logLevel, "log-level", "INFO",
+		"Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
+	tools.BoolVarFlagWithEnv(&c.development, "development", false, "Development mode.")
+	tools.StringVarFlagWithEnv(&c.scheme, "scheme", "",
+		"Blockchain scheme i.e. mainnet, testnet, stagenet. Used in messaging service")
+	return c
+}
+
+func (c *emailBotConfig) recipients() []string {
+	var to []string
+	for _, addr := range strings.Split(c.emailTo, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			to = append(to, addr)
+		}
+	}
+	return to
+}
+
+func (c *emailBotConfig) validate(zap *zap.Logger) error {
+	if c.smtpAddress == "" {
+		zap.Error("SMTP server address is required")
+		return common.ErrInvalidParameters
+	}
+	if c.emailFrom == "" {
+		zap.Error("sender address is required")
+		return common.ErrInvalidParameters
+	}
+	if len(c.recipients()) == 0 {
+		zap.Error("at least one recipient address is required")
+		return common.ErrInvalidParameters
+	}
+	if c.scheme == "" {
+		zap.Error("the blockchain scheme must be specified")
+		return common.ErrInvalidParameters
+	}
+	if c.smtpTimeout <= 0 {
+		zap.Error("SMTP timeout must be positive")
+		return common.ErrInvalidParameters
+	}
+	if c.digestInterval <= 0 {
+		zap.Error("digest interval must be positive")
+		return common.ErrInvalidParameters
+	}
+	return nil
+}
+
+func runEmailBot() error {
+	cfg := newEmailBotConfig()
+	flag.Parse()
+
+	logger, _, err := tools.SetupZapLogger(cfg.logLevel, cfg.development)
+	if err != nil {
+		log.Printf("Failed to setup zap logger: %v", err)
+		return common.ErrInvalidParameters
+	}
+
+	defer func(zap *zap.Logger) {
+		if syncErr := zap.Sync(); syncErr != nil {
+			log.Println(syncErr)
+		}
+	}(logger)
+
+	logger.Info("Starting email bot", zap.String("version", internal.Version()))
+
+	if validationErr := cfg.validate(logger); validationErr != nil {
+		return validationErr
+	}
+
+	mailer, err := email.NewSMTPMailer(email.SMTPConfig{
+		Address:  cfg.smtpAddress,
+		Username: cfg.smtpUsername,
+		Password: cfg.smtpPassword,
+		StartTLS: cfg.smtpStartTLS,
+		From:     cfg.emailFrom,
+		To:       cfg.recipients(),
+		Timeout:  cfg.smtpTimeout,
+	})
+	if err != nil {
+		logger.Error("Failed to create SMTP mailer", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+
+	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer done()
+
+	emailBotEnv := common.NewEmailBotEnvironment(mailer, cfg.digestInterval, logger, cfg.scheme)
+
+	go func() {
+		clientErr := messaging.StartSubMessagingClient(ctx, cfg.natsMessagingURL, emailBotEnv, logger)
+		if clientErr != nil {
+			logger.Fatal("failed to start sub messaging client", zap.Error(clientErr))
+			return
+		}
+	}()
+
+	emailBotEnv.Start(ctx)
+	return nil
+}
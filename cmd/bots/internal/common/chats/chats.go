@@ -0,0 +1,248 @@
+// Package chats keeps the chats served by a bot together with their settings: alert subscriptions, mute state,
+// access mode and admins.
+package chats
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+
+	"nodemon/cmd/bots/internal/common/state"
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
+
+var ErrChatNotFound = errors.New("chat not found")
+
+// Settings of the chat. By default, the chat is subscribed to all alerts, so only the unsubscribed alerts are kept.
+// It makes the new alert types to be delivered without changing the settings.
+type Settings struct {
+	entities.Chat
+	// ReadOnly chats may view the monitoring state, but may not change it, e.g. add or remove nodes.
+	ReadOnly bool `json:"readOnly,omitempty"`
+	Muted    bool `json:"muted,omitempty"`
+	// Admins are the IDs of the users allowed to change the settings of the chat and to run the commands
+	// which change the monitoring state. Empty list allows it to all members of the chat.
+	Admins       []int64              `json:"admins,omitempty"`
+	Unsubscribed []entities.AlertName `json:"unsubscribed,omitempty"`
+	// Groups are the node tags which the chat receives the alerts about. Empty list means all nodes.
+	Groups []string `json:"groups,omitempty"`
+}
+
+// IsAdmin reports whether the user may change the chat settings and run the privileged commands in the chat.
+func (s Settings) IsAdmin(userID int64) bool {
+	return len(s.Admins) == 0 || slices.Contains(s.Admins, userID)
+}
+
+func (s Settings) IsSubscribed(alertType entities.AlertType) bool {
+	alertName, ok := alertType.AlertName()
+	if !ok {
+		return false
+	}
+	return !slices.Contains(s.Unsubscribed, alertName)
+}
+
+// Subscribe subscribes the chat to the alert, it reports whether the subscription has been changed.
+func (s *Settings) Subscribe(alertName entities.AlertName) bool {
+	i := slices.Index(s.Unsubscribed, alertName)
+	if i == -1 {
+		return false
+	}
+	s.Unsubscribed = slices.Delete(s.Unsubscribed, i, i+1)
+	return true
+}
+
+// Unsubscribe unsubscribes the chat from the alert, it reports whether the subscription has been changed.
+func (s *Settings) Unsubscribe(alertName entities.AlertName) bool {
+	if slices.Contains(s.Unsubscribed, alertName) {
+		return false
+	}
+	s.Unsubscribed = append(s.Unsubscribed, alertName)
+	sort.Slice(s.Unsubscribed, func(i, j int) bool { return s.Unsubscribed[i] < s.Unsubscribed[j] })
+	return true
+}
+
+// SubscribeToGroup adds the node tag to the groups of the chat, it reports whether the groups have been changed.
+func (s *Settings) SubscribeToGroup(tag string) bool {
+	if slices.Contains(s.Groups, tag) {
+		return false
+	}
+	s.Groups = append(s.Groups, tag)
+	slices.Sort(s.Groups)
+	return true
+}
+
+// UnsubscribeFromGroup removes the node tag from the groups of the chat, it reports whether the groups have been
+// changed.
+func (s *Settings) UnsubscribeFromGroup(tag string) bool {
+	i := slices.Index(s.Groups, tag)
+	if i == -1 {
+		return false
+	}
+	s.Groups = slices.Delete(s.Groups, i, i+1)
+	return true
+}
+
+// IsSubscribedToNodes reports whether the chat receives the alert about the nodes. The alerts which aren't
+// related to any node, e.g. internal errors, are received by all chats.
+func (s Settings) IsSubscribedToNodes(nodes []string, tagsOf func(node string) []string) bool {
+	if len(s.Groups) == 0 || len(nodes) == 0 {
+		return true
+	}
+	for _, node := range nodes {
+		for _, tag := range tagsOf(node) {
+			if slices.Contains(s.Groups, tag) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SubscribedAlert returns the alert as the chat receives it or nil if the chat doesn't receive it. The alert group
+// is received if the chat is subscribed to any of its member alerts, the other members are stripped from it.
+func (s Settings) SubscribedAlert(alert entities.Alert, tagsOf func(node string) []string) entities.Alert {
+	if !s.IsSubscribed(alert.Type()) {
+		return nil
+	}
+	group, ok := alert.(*entities.AlertGroup)
+	if !ok {
+		if !s.IsSubscribedToNodes(entities.AlertNodes(alert), tagsOf) {
+			return nil
+		}
+		return alert
+	}
+	group = group.FilterMembers(func(m entities.AlertGroupMember) bool {
+		t, known := m.Name.AlertType()
+		return known && s.IsSubscribed(t) && s.IsSubscribedToNodes(m.Nodes, tagsOf)
+	})
+	if group == nil {
+		return nil // the typed nil pointer must not be returned as the interface
+	}
+	return group
+}
+
+func (s Settings) clone() Settings {
+	s.Admins = slices.Clone(s.Admins)
+	s.Unsubscribed = slices.Clone(s.Unsubscribed)
+	s.Groups = slices.Clone(s.Groups)
+	return s
+}
+
+type dbStruct struct {
+	Chats []Settings `json:"chats"`
+}
+
+// Storage keeps the chats settings in memory and writes them to the file on every change.
+// If the file path is empty, the settings are kept only in memory.
+type Storage struct {
+	mu    *sync.RWMutex
+	path  string
+	chats map[entities.ChatID]Settings
+}
+
+func NewStorage(path string) (*Storage, error) {
+	s := &Storage{
+		mu:    new(sync.RWMutex),
+		chats: make(map[entities.ChatID]Settings),
+	}
+	if path == "" {
+		return s, nil
+	}
+	s.path = filepath.Clean(path)
+	data, err := os.ReadFile(s.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return s, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to read chats file '%s'", s.path)
+	}
+	var db dbStruct
+	if unmarshalErr := json.Unmarshal(data, &db); unmarshalErr != nil {
+		return nil, errors.Wrapf(unmarshalErr, "failed to unmarshal chats file '%s'", s.path)
+	}
+	for _, chat := range db.Chats {
+		if _, ok := s.chats[chat.ChatID]; ok {
+			return nil, errors.Errorf("duplicate chat %d in chats file '%s'", chat.ChatID, s.path)
+		}
+		s.chats[chat.ChatID] = chat
+	}
+	return s, nil
+}
+
+// Chat returns the settings of the chat.
+func (s *Storage) Chat(id entities.ChatID) (Settings, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	chat, ok := s.chats[id]
+	return chat.clone(), ok
+}
+
+// Chats returns the settings of all chats ordered by the chat ID.
+func (s *Storage) Chats() []Settings {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Settings, 0, len(s.chats))
+	for _, chat := range s.chats {
+		out = append(out, chat.clone())
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ChatID < out[j].ChatID })
+	return out
+}
+
+// AddIfNew adds the chat if it's absent, it reports whether the chat has been added.
+func (s *Storage) AddIfNew(chat Settings) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.chats[chat.ChatID]; ok {
+		return false, nil
+	}
+	s.chats[chat.ChatID] = chat.clone()
+	if err := s.sync(); err != nil {
+		delete(s.chats, chat.ChatID)
+		return false, err
+	}
+	return true, nil
+}
+
+// Update changes the settings of the chat with the given function. The settings are stored only if the function
+// reports that they have been changed.
+func (s *Storage) Update(id entities.ChatID, update func(chat *Settings) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	chat, ok := s.chats[id]
+	if !ok {
+		return errors.Wrapf(ErrChatNotFound, "failed to update chat %d", id)
+	}
+	old := chat.clone()
+	if !update(&chat) {
+		return nil
+	}
+	s.chats[id] = chat
+	if err := s.sync(); err != nil {
+		s.chats[id] = old
+		return err
+	}
+	return nil
+}
+
+// sync writes the chats to the file, it must be called under the write lock.
+func (s *Storage) sync() error {
+	if s.path == "" {
+		return nil
+	}
+	db := dbStruct{Chats: make([]Settings, 0, len(s.chats))}
+	for _, chat := range s.chats {
+		db.Chats = append(db.Chats, chat)
+	}
+	sort.Slice(db.Chats, func(i, j int) bool { return db.Chats[i].ChatID < db.Chats[j].ChatID })
+	data, err := json.MarshalIndent(db, "", " ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chats")
+	}
+	return errors.Wrap(state.WriteFile(s.path, data), "failed to sync chats file")
+}
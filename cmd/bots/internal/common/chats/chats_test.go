@@ -0,0 +1,128 @@
+package chats_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nodemon/cmd/bots/internal/common/chats"
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func telegramChat(id entities.ChatID) chats.Settings {
+	return chats.Settings{Chat: entities.Chat{ChatID: id, Platform: entities.TelegramPlatform}}
+}
+
+func TestSettings(t *testing.T) {
+	chat := telegramChat(1)
+	assert.True(t, chat.IsAdmin(42), "empty admins list allows everyone")
+	assert.True(t, chat.IsSubscribed(entities.HeightAlertType))
+
+	assert.True(t, chat.Unsubscribe(entities.HeightAlertName))
+	assert.False(t, chat.Unsubscribe(entities.HeightAlertName))
+	assert.False(t, chat.IsSubscribed(entities.HeightAlertType))
+	assert.True(t, chat.IsSubscribed(entities.UnreachableAlertType))
+
+	assert.True(t, chat.Subscribe(entities.HeightAlertName))
+	assert.False(t, chat.Subscribe(entities.HeightAlertName))
+	assert.True(t, chat.IsSubscribed(entities.HeightAlertType))
+	assert.Empty(t, chat.Unsubscribed)
+
+	chat.Admins = []int64{7}
+	assert.True(t, chat.IsAdmin(7))
+	assert.False(t, chat.IsAdmin(42))
+}
+
+func TestSettings_Groups(t *testing.T) {
+	tags := map[string][]string{"a": {"our-validators"}, "b": {"public-api"}}
+	tagsOf := func(node string) []string { return tags[node] }
+	chat := telegramChat(1)
+	assert.True(t, chat.IsSubscribedToNodes([]string{"b", "c"}, tagsOf), "empty groups mean all nodes")
+
+	assert.True(t, chat.SubscribeToGroup("our-validators"))
+	assert.False(t, chat.SubscribeToGroup("our-validators"))
+	assert.True(t, chat.IsSubscribedToNodes([]string{"a", "b"}, tagsOf))
+	assert.False(t, chat.IsSubscribedToNodes([]string{"b", "c"}, tagsOf))
+	assert.True(t, chat.IsSubscribedToNodes(nil, tagsOf), "the alerts without nodes must be received")
+
+	assert.True(t, chat.UnsubscribeFromGroup("our-validators"))
+	assert.False(t, chat.UnsubscribeFromGroup("our-validators"))
+	assert.Empty(t, chat.Groups)
+}
+
+func TestStorage_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chats.json")
+	storage, err := chats.NewStorage(path)
+	require.NoError(t, err)
+	assert.Empty(t, storage.Chats())
+
+	added, err := storage.AddIfNew(telegramChat(2))
+	require.NoError(t, err)
+	assert.True(t, added)
+	readOnly := telegramChat(1)
+	readOnly.ReadOnly = true
+	added, err = storage.AddIfNew(readOnly)
+	require.NoError(t, err)
+	assert.True(t, added)
+	added, err = storage.AddIfNew(telegramChat(1))
+	require.NoError(t, err)
+	assert.False(t, added, "the existing chat must not be replaced")
+
+	err = storage.Update(2, func(chat *chats.Settings) bool {
+		chat.Muted = true
+		return chat.Unsubscribe(entities.HeightAlertName)
+	})
+	require.NoError(t, err)
+	err = storage.Update(3, func(*chats.Settings) bool { return true })
+	assert.ErrorIs(t, err, chats.ErrChatNotFound)
+
+	reloaded, err := chats.NewStorage(path)
+	require.NoError(t, err)
+	expected := []chats.Settings{readOnly, telegramChat(2)}
+	expected[1].Muted = true
+	expected[1].Unsubscribed = []entities.AlertName{entities.HeightAlertName}
+	assert.Equal(t, expected, reloaded.Chats())
+	assert.Equal(t, storage.Chats(), reloaded.Chats())
+}
+
+func TestStorage_UpdateWithoutChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chats.json")
+	storage, err := chats.NewStorage(path)
+	require.NoError(t, err)
+	_, err = storage.AddIfNew(telegramChat(1))
+	require.NoError(t, err)
+	require.NoError(t, os.Remove(path))
+
+	err = storage.Update(1, func(chat *chats.Settings) bool {
+		chat.Muted = true
+		return false
+	})
+	require.NoError(t, err)
+	_, err = os.Stat(path)
+	assert.ErrorIs(t, err, os.ErrNotExist, "the unchanged settings must not be written")
+}
+
+func TestStorage_InMemory(t *testing.T) {
+	storage, err := chats.NewStorage("")
+	require.NoError(t, err)
+	_, err = storage.AddIfNew(telegramChat(1))
+	require.NoError(t, err)
+	chat, ok := storage.Chat(1)
+	require.True(t, ok)
+	chat.Unsubscribed = append(chat.Unsubscribed, entities.HeightAlertName)
+
+	chat, ok = storage.Chat(1)
+	require.True(t, ok)
+	assert.Empty(t, chat.Unsubscribed, "the returned settings must be a copy")
+}
+
+func TestNewStorage_DuplicateChat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chats.json")
+	data := `{"chats": [{"chatID": 1, "platform": 1}, {"chatID": 1, "platform": 1, "readOnly": true}]}`
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	_, err := chats.NewStorage(path)
+	assert.ErrorContains(t, err, "duplicate chat 1")
+}
@@ -0,0 +1,337 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+	generalMessaging "nodemon/pkg/messaging"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+const maxEmailSubjectLength = 120
+
+// sentAlertEmailTTL is the time after which the alert which is neither repeated nor fixed is forgotten, e.g. if
+// the notification about its fix is lost. The next repeat of such alert starts a new email thread.
+const sentAlertEmailTTL = 7 * 24 * time.Hour
+
+// Mail is the email composed by the email bot.
+type Mail struct {
+	Subject   string
+	Text      string
+	HTML      string
+	MessageID string
+	// InReplyTo is the ID of the email which this one replies to, e.g. the fixed alert email.
+	InReplyTo string
+}
+
+// Mailer delivers the emails to the recipients.
+type Mailer interface {
+	Send(mail Mail) error
+}
+
+type sentAlertEmail struct {
+	messageID string
+	subject   string
+	lastSeen  int64 // unix seconds of the last repeat of the alert
+}
+
+type emailAlert struct {
+	Level string
+	Time  string
+	Body  any // string for the plain text templates and template.HTML for the HTML ones
+}
+
+type emailAlertMail struct {
+	emailAlert
+	Scheme string
+}
+
+type emailDigest struct {
+	Scheme string
+	Since  string
+	Until  string
+	Alerts []emailAlert
+}
+
+type digestItem struct {
+	level string
+	ts    int64
+	text  string
+	html  string
+}
+
+// EmailBotEnvironment sends the alerts by email. The error level alerts are sent immediately and their fixes are sent
+// as replies. The alerts of the lower levels are collected into a digest which is sent once per digest interval.
+type EmailBotEnvironment struct {
+	mailer           Mailer
+	digestInterval   time.Duration
+	scheme           string
+	zap              *zap.Logger
+	subscriptions    subscriptions
+	nc               *nats.Conn
+	alertHandlerFunc func(msg *nats.Msg)
+
+	mu     *sync.Mutex
+	sent   map[crypto.Digest]sentAlertEmail // map[AlertID]email
+	digest []digestItem
+}
+
+func NewEmailBotEnvironment(
+	mailer Mailer,
+	digestInterval time.Duration,
+	zap *zap.Logger,
+	scheme string,
+) *EmailBotEnvironment {
+	return &EmailBotEnvironment{
+		mailer:         mailer,
+		digestInterval: digestInterval,
+		scheme:         scheme,
+		zap:            zap,
+		subscriptions: subscriptions{
+			subs: make(map[entities.AlertType]AlertSubscription),
+			mu:   new(sync.RWMutex),
+		},
+		mu:   new(sync.Mutex),
+		sent: make(map[crypto.Digest]sentAlertEmail),
+	}
+}
+
+func (e *EmailBotEnvironment) TemplatesExtension() ExpectedExtension { return PlainText }
+
+// Start sends the digests until the context is canceled. The collected alerts are sent on exit.
+func (e *EmailBotEnvironment) Start(ctx context.Context) {
+	e.zap.Info("Email bot started")
+	ticker := time.NewTicker(e.digestInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			e.SendDigest()
+			e.forgetStaleAlerts(now)
+		case <-ctx.Done():
+			e.SendDigest()
+			e.zap.Info("Email bot finished")
+			return
+		}
+	}
+}
+
+func (e *EmailBotEnvironment) SendAlertMessage(msg generalMessaging.AlertMessage) {
+	alertType := msg.AlertType()
+	alert, err := entities.NewAlertByType(alertType)
+	if err != nil {
+		e.zap.Error("failed to construct message", zap.Error(err))
+		return
+	}
+	if unmarshalErr := json.Unmarshal(msg.Data(), alert); unmarshalErr != nil {
+		e.zap.Error("failed to unmarshal alert", zap.Error(unmarshalErr))
+		return
+	}
+	text, err := constructMessage(alertType, msg.Data(), PlainText, nil)
+	if err != nil {
+		e.zap.Error("failed to construct message", zap.Error(err))
+		return
+	}
+	html, err := constructMessage(alertType, msg.Data(), HTML, nil)
+	if err != nil {
+		e.zap.Error("failed to construct message", zap.Error(err))
+		return
+	}
+	alertID := msg.ReferenceID()
+
+	e.mu.Lock()
+	original, sentImmediately := e.sent[alertID]
+	if !sentImmediately && alert.Level() != entities.ErrorLevel {
+		e.digest = append(e.digest, digestItem{level: alert.Level(), ts: alert.Time().Unix(), text: text, html: html})
+		e.mu.Unlock()
+		return
+	}
+	switch {
+	case alertType == entities.AlertFixedType:
+		delete(e.sent, alertID)
+	case sentImmediately:
+		original.lastSeen = alert.Time().Unix()
+		e.sent[alertID] = original
+	}
+	e.mu.Unlock()
+
+	mail, err := e.alertMail(alert, text, html)
+	if err != nil {
+		e.zap.Error("failed to construct email", zap.Error(err))
+		return
+	}
+	if sentImmediately { // the repeats and the fix of the alert are sent as replies to the first email
+		mail.Subject = "Re: " + original.subject
+		mail.InReplyTo = original.messageID
+	}
+	if sendErr := e.mailer.Send(mail); sendErr != nil {
+		e.zap.Error("failed to send alert email", zap.Error(sendErr))
+		return
+	}
+	if alertType != entities.AlertFixedType {
+		e.mu.Lock()
+		if _, ok := e.sent[alertID]; !ok { // keep the first email to reply to it
+			e.sent[alertID] = sentAlertEmail{
+				messageID: mail.MessageID,
+				subject:   mail.Subject,
+				lastSeen:  alert.Time().Unix(),
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+func (e *EmailBotEnvironment) alertMail(alert entities.Alert, text, html string) (Mail, error) {
+	data := func(body any) emailAlertMail {
+		return emailAlertMail{
+			emailAlert: emailAlert{Level: alert.Level(), Time: formatAlertTimestamp(alert.Time().Unix()), Body: body},
+			Scheme:     e.scheme,
+		}
+	}
+	textBody, err := executeTemplate("templates/email/alert", data(text), PlainText)
+	if err != nil {
+		return Mail{}, err
+	}
+	// the fragment has been already rendered by html/template, so it's safe
+	htmlBody, err := executeTemplate("templates/email/alert", data(template.HTML(html)), HTML) //nolint:gosec // see above
+	if err != nil {
+		return Mail{}, err
+	}
+	return Mail{
+		Subject:   e.subject(firstLine(alert.Message())),
+		Text:      textBody,
+		HTML:      htmlBody,
+		MessageID: e.messageID(alert.ID().Hex(), alert.Time()),
+	}, nil
+}
+
+// forgetStaleAlerts removes the sent alerts which haven't been repeated for sentAlertEmailTTL.
+func (e *EmailBotEnvironment) forgetStaleAlerts(now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for id, sent := range e.sent {
+		if now.Sub(time.Unix(sent.lastSeen, 0)) > sentAlertEmailTTL {
+			delete(e.sent, id)
+		}
+	}
+}
+
+// SendDigest sends the collected alerts in one email, if there are any.
+func (e *EmailBotEnvironment) SendDigest() {
+	e.mu.Lock()
+	items := e.digest
+	e.digest = nil
+	e.mu.Unlock()
+	if len(items) == 0 {
+		return
+	}
+	mail, err := e.digestMail(items)
+	if err != nil {
+		e.zap.Error("failed to construct digest email", zap.Error(err))
+		return
+	}
+	if sendErr := e.mailer.Send(mail); sendErr != nil {
+		e.zap.Error("failed to send digest email", zap.Int("alerts", len(items)), zap.Error(sendErr))
+	}
+}
+
+func (e *EmailBotEnvironment) digestMail(items []digestItem) (Mail, error) {
+	textDigest := emailDigest{
+		Scheme: e.scheme,
+		Since:  formatAlertTimestamp(items[0].ts),
+		Until:  formatAlertTimestamp(items[len(items)-1].ts),
+	}
+	htmlDigest := textDigest
+	for _, item := range items {
+		textDigest.Alerts = append(textDigest.Alerts, emailAlert{
+			Level: item.level, Time: formatAlertTimestamp(item.ts), Body: item.text,
+		})
+		htmlDigest.Alerts = append(htmlDigest.Alerts, emailAlert{
+			Level: item.level,
+			Time:  formatAlertTimestamp(item.ts),
+			Body:  template.HTML(item.html), //nolint:gosec // rendered by html/template
+		})
+	}
+	textBody, err := executeTemplate("templates/email/digest", textDigest, PlainText)
+	if err != nil {
+		return Mail{}, err
+	}
+	htmlBody, err := executeTemplate("templates/email/digest", htmlDigest, HTML)
+	if err != nil {
+		return Mail{}, err
+	}
+	last := time.Unix(items[len(items)-1].ts, 0)
+	return Mail{
+		Subject:   e.subject(fmt.Sprintf("Digest of %d alert(s)", len(items))),
+		Text:      textBody,
+		HTML:      htmlBody,
+		MessageID: e.messageID("digest", last),
+	}, nil
+}
+
+func (e *EmailBotEnvironment) subject(s string) string {
+	s = fmt.Sprintf("[nodemon %s] %s", e.scheme, s)
+	if r := []rune(s); len(r) > maxEmailSubjectLength {
+		s = string(r[:maxEmailSubjectLength-1]) + "…"
+	}
+	return s
+}
+
+func (e *EmailBotEnvironment) messageID(prefix string, ts time.Time) string {
+	return fmt.Sprintf("<%s.%d.%d@nodemon.%s>", prefix, ts.Unix(), time.Now().UnixNano(), e.scheme)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func (e *EmailBotEnvironment) SendMessage(msg string) {
+	mail := Mail{
+		Subject:   e.subject(firstLine(msg)),
+		Text:      msg,
+		MessageID: e.messageID("message", time.Now()),
+	}
+	if err := e.mailer.Send(mail); err != nil {
+		e.zap.Error("failed to send email", zap.Error(err))
+	}
+}
+
+func (e *EmailBotEnvironment) SetNatsConnection(nc *nats.Conn) {
+	e.nc = nc
+}
+
+func (e *EmailBotEnvironment) SetAlertHandlerFunc(alertHandlerFunc func(msg *nats.Msg)) {
+	e.alertHandlerFunc = alertHandlerFunc
+}
+
+func (e *EmailBotEnvironment) SubscribeToAllAlerts() error {
+	for alertType, alertName := range entities.GetAllAlertTypesAndNames() {
+		if _, ok := e.subscriptions.Read(alertType); ok {
+			return errors.Errorf("failed to subscribe to %s, already subscribed to it", alertName)
+		}
+		topic := generalMessaging.PubSubMsgTopic(e.scheme, alertType)
+		subscription, err := e.nc.Subscribe(topic, e.alertHandlerFunc)
+		if err != nil {
+			return errors.Wrap(err, "failed to subscribe to alert")
+		}
+		e.subscriptions.Add(alertType, alertName, subscription)
+		e.zap.Sugar().Infof("Email bot subscribed to %s", alertName)
+	}
+	return nil
+}
+
+// IsEligibleForAction always returns false, the email bot doesn't accept commands.
+func (e *EmailBotEnvironment) IsEligibleForAction(string) bool {
+	return false
+}
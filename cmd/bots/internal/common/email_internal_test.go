@@ -0,0 +1,103 @@
+package common
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	generalMessaging "nodemon/pkg/messaging"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mailerMock struct {
+	mails []Mail
+}
+
+func (m *mailerMock) Send(mail Mail) error {
+	m.mails = append(m.mails, mail)
+	return nil
+}
+
+func sendAlert(t *testing.T, env *EmailBotEnvironment, alert entities.Alert) {
+	msg, err := generalMessaging.NewAlertMessageFromAlert(alert)
+	require.NoError(t, err)
+	env.SendAlertMessage(msg)
+}
+
+func TestEmailBotEnvironment_ErrorAlert(t *testing.T) {
+	mailer := new(mailerMock)
+	env := NewEmailBotEnvironment(mailer, time.Hour, zap.NewNop(), "mainnet")
+	alert := &entities.UnreachableAlert{Timestamp: 100, Node: "https://node.example.com"}
+
+	sendAlert(t, env, alert)
+	require.Len(t, mailer.mails, 1)
+	first := mailer.mails[0]
+	assert.True(t, strings.HasPrefix(first.Subject, "[nodemon mainnet] "))
+	assert.Contains(t, first.Text, "Node node.example.com is unreachable")
+	assert.Contains(t, first.HTML, "<b>Node node.example.com is unreachable</b>")
+	assert.NotEmpty(t, first.MessageID)
+	assert.Empty(t, first.InReplyTo)
+
+	sendAlert(t, env, alert) // the repeat of the alert
+	sendAlert(t, env, &entities.AlertFixed{Timestamp: 200, Fixed: alert})
+	require.Len(t, mailer.mails, 3)
+	for _, reply := range mailer.mails[1:] {
+		assert.Equal(t, "Re: "+first.Subject, reply.Subject)
+		assert.Equal(t, first.MessageID, reply.InReplyTo)
+	}
+	assert.Empty(t, env.sent)
+
+	env.SendDigest()
+	assert.Len(t, mailer.mails, 3, "nothing should be collected into the digest")
+}
+
+func TestEmailBotEnvironment_ForgetStaleAlerts(t *testing.T) {
+	mailer := new(mailerMock)
+	env := NewEmailBotEnvironment(mailer, time.Hour, zap.NewNop(), "mainnet")
+	start := time.Unix(1700000000, 0)
+	stale := &entities.UnreachableAlert{Timestamp: start.Unix(), Node: "https://stale.example.com"}
+	repeated := &entities.UnreachableAlert{Timestamp: start.Unix(), Node: "https://repeated.example.com"}
+	sendAlert(t, env, stale)
+	sendAlert(t, env, repeated)
+	repeat := *repeated
+	repeat.Timestamp = start.Add(sentAlertEmailTTL / 2).Unix()
+	sendAlert(t, env, &repeat)
+
+	env.forgetStaleAlerts(start.Add(sentAlertEmailTTL / 2))
+	assert.Len(t, env.sent, 2)
+	env.forgetStaleAlerts(start.Add(sentAlertEmailTTL + time.Second))
+	assert.Len(t, env.sent, 1, "the alert which is never fixed must be forgotten")
+	assert.Contains(t, env.sent, repeated.ID(), "the repeated alert must be kept")
+}
+
+func TestEmailBotEnvironment_Digest(t *testing.T) {
+	mailer := new(mailerMock)
+	env := NewEmailBotEnvironment(mailer, time.Hour, zap.NewNop(), "testnet")
+
+	sendAlert(t, env, &entities.SimpleAlert{Timestamp: 100, Description: "first description"})
+	sendAlert(t, env, &entities.SimpleAlert{Timestamp: 200, Description: "second description"})
+	assert.Empty(t, mailer.mails)
+
+	env.SendDigest()
+	require.Len(t, mailer.mails, 1)
+	digest := mailer.mails[0]
+	assert.Equal(t, "[nodemon testnet] Digest of 2 alert(s)", digest.Subject)
+	for _, body := range []string{digest.Text, digest.HTML} {
+		assert.Contains(t, body, "first description")
+		assert.Contains(t, body, "second description")
+	}
+
+	env.SendDigest()
+	assert.Len(t, mailer.mails, 1, "the digest should be empty")
+}
+
+func TestEmailBotEnvironment_Subject(t *testing.T) {
+	env := NewEmailBotEnvironment(new(mailerMock), time.Hour, zap.NewNop(), "mainnet")
+	subject := env.subject(strings.Repeat("a", 2*maxEmailSubjectLength))
+	assert.Len(t, []rune(subject), maxEmailSubjectLength)
+	assert.True(t, strings.HasSuffix(subject, "…"))
+}
@@ -11,14 +11,20 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	textTemplate "text/template"
+	"time"
 
+	"nodemon/cmd/bots/internal/common/chats"
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/reports"
+	"nodemon/cmd/bots/internal/common/state"
+	"nodemon/cmd/bots/internal/discord/components"
 	"nodemon/pkg/entities"
 	generalMessaging "nodemon/pkg/messaging"
 	"nodemon/pkg/messaging/pair"
 	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/uptime"
 
-	"codnect.io/chrono"
 	"github.com/bwmarrin/discordgo"
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
@@ -27,10 +33,6 @@ import (
 	"gopkg.in/telebot.v3"
 )
 
-const (
-	scheduledTimeExpression = "0 0 9 * * *" // 12:00 UTC+3
-)
-
 var (
 	//go:embed templates
 	templateFiles embed.FS
@@ -41,6 +43,8 @@ type ExpectedExtension string
 const (
 	HTML     ExpectedExtension = ".html"
 	Markdown ExpectedExtension = ".md"
+	// PlainText templates are executed without HTML escaping, they exist only for the alerts.
+	PlainText ExpectedExtension = ".txt"
 )
 
 var errUnknownAlertType = errors.New("received unknown alert type")
@@ -83,21 +87,24 @@ func (s *subscriptions) MapR(f func()) {
 }
 
 type DiscordBotEnvironment struct {
-	ChatID                 string
-	Bot                    *discordgo.Session
-	Subscriptions          subscriptions
-	zap                    *zap.Logger
-	requestType            chan<- pair.Request
-	responsePairType       <-chan pair.Response
-	unhandledAlertMessages unhandledAlertMessages
-	scheme                 string
-	nc                     *nats.Conn
-	alertHandlerFunc       func(msg *nats.Msg)
+	ChatID           string
+	Bot              *discordgo.Session
+	Subscriptions    subscriptions
+	chats            *chats.Storage
+	zap              *zap.Logger
+	requestType      chan<- pair.Request
+	responsePairType <-chan pair.Response
+	alertMessages    *state.AlertMessages
+	scheme           string
+	nc               *nats.Conn
+	alertHandlerFunc func(msg *nats.Msg)
 }
 
 func NewDiscordBotEnvironment(
 	bot *discordgo.Session,
 	chatID string,
+	chatsStorage *chats.Storage,
+	alertMessages *state.AlertMessages,
 	zap *zap.Logger,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
@@ -110,11 +117,12 @@ func NewDiscordBotEnvironment(
 			subs: make(map[entities.AlertType]AlertSubscription),
 			mu:   new(sync.RWMutex),
 		},
-		zap:                    zap,
-		requestType:            requestType,
-		responsePairType:       responsePairType,
-		unhandledAlertMessages: newUnhandledAlertMessages(),
-		scheme:                 scheme,
+		chats:            chatsStorage,
+		zap:              zap,
+		requestType:      requestType,
+		responsePairType: responsePairType,
+		alertMessages:    alertMessages,
+		scheme:           scheme,
 	}
 }
 
@@ -163,24 +171,61 @@ func (dscBot *DiscordBotEnvironment) SendAlertMessage(msg generalMessaging.Alert
 		return
 	}
 	alertID := msg.ReferenceID()
+	chat, ok := dscBot.Chat()
+	if !ok || chat.Muted { // the fixes are muted too, as in telegram
+		dscBot.zap.Debug("received an alert, but the chat is muted or unknown", zap.Uint8("alertType", byte(alertType)))
+		return
+	}
 
 	if alertType == entities.AlertFixedType {
-		messageID, ok := dscBot.unhandledAlertMessages.GetMessageIDByAlertID(alertID)
+		messageID, ok := dscBot.alertMessages.MessageID(dscBot.ChatID, alertID)
 		if !ok {
 			dscBot.zap.Error("failed to get message ID by the given alertID: alertID hasn't been found",
 				zap.Stringer("alertID", alertID),
 			)
 			return
 		}
-		msgRef := &discordgo.MessageReference{MessageID: strconv.Itoa(messageID)}
+		alertMessageID := strconv.Itoa(messageID)
+		msgRef := &discordgo.MessageReference{MessageID: alertMessageID, ChannelID: dscBot.ChatID}
 		_, err = dscBot.Bot.ChannelMessageSendReply(dscBot.ChatID, messageToBot, msgRef)
 		if err != nil {
 			dscBot.zap.Error("failed to send a message about fixed alert to discord", zap.Error(err))
 		}
-		dscBot.unhandledAlertMessages.Delete(alertID)
+		// the resolved alert can't be acknowledged anymore
+		noComponents := make([]discordgo.MessageComponent, 0)
+		_, err = dscBot.Bot.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			ID:         alertMessageID,
+			Channel:    dscBot.ChatID,
+			Components: &noComponents,
+		})
+		if err != nil {
+			dscBot.zap.Error("failed to remove buttons from alert message on discord", zap.Error(err))
+		}
+		if deleteErr := dscBot.alertMessages.Delete(dscBot.ChatID, alertID); deleteErr != nil {
+			dscBot.zap.Error("failed to delete alert message", zap.Error(deleteErr))
+		}
+		return
+	}
+	alert, err := decodeAlert(alertType, alertJSON)
+	if err != nil {
+		dscBot.zap.Error("failed to decode alert", zap.Error(err))
+		return
+	}
+	messageToBot, ok, err = chatAlertMessage(chat, alert, messageToBot, nodes, dscBot.TemplatesExtension())
+	if err != nil {
+		dscBot.zap.Error("failed to construct message", zap.Error(err))
 		return
 	}
-	sentMessage, err := dscBot.Bot.ChannelMessageSend(dscBot.ChatID, messageToBot)
+	if !ok {
+		dscBot.zap.Debug("received an alert, but the chat isn't subscribed to it",
+			zap.Uint8("alertType", byte(alertType)),
+		)
+		return
+	}
+	sentMessage, err := dscBot.Bot.ChannelMessageSendComplex(dscBot.ChatID, &discordgo.MessageSend{
+		Content:    messageToBot,
+		Components: []discordgo.MessageComponent{components.AckAlertButton(alertID)},
+	})
 	if err != nil {
 		dscBot.zap.Error("failed to send a message to discord", zap.Error(err))
 		return
@@ -191,7 +236,161 @@ func (dscBot *DiscordBotEnvironment) SendAlertMessage(msg generalMessaging.Alert
 		dscBot.zap.Error("failed to parse messageID from a send message on discord", zap.Error(err))
 		return
 	}
-	dscBot.unhandledAlertMessages.Add(alertID, messageID)
+	if addErr := dscBot.alertMessages.Add(dscBot.ChatID, alertID, messageID); addErr != nil {
+		dscBot.zap.Error("failed to store alert message", zap.Error(addErr))
+	}
+}
+
+// SendReport sends the scheduled report to the alerts channel if it's the chat of the report and isn't muted.
+func (dscBot *DiscordBotEnvironment) SendReport(chatID entities.ChatID, msg string) {
+	chat, ok := dscBot.Chat()
+	if !ok || chat.ChatID != chatID {
+		dscBot.zap.Warn("Report chat isn't served by discord bot", zap.Int64("chat", int64(chatID)))
+		return
+	}
+	if chat.Muted {
+		dscBot.zap.Debug("received a report, but the chat is muted", zap.Int64("chat", int64(chatID)))
+		return
+	}
+	dscBot.SendMessage(msg)
+}
+
+// Chat returns the settings of the chat which the alerts are sent through.
+func (dscBot *DiscordBotEnvironment) Chat() (chats.Settings, bool) {
+	id, err := dscBot.chatID()
+	if err != nil {
+		return chats.Settings{}, false
+	}
+	return dscBot.chats.Chat(id)
+}
+
+func (dscBot *DiscordBotEnvironment) chatID() (entities.ChatID, error) {
+	id, err := strconv.ParseInt(dscBot.ChatID, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid discord chat id '%s'", dscBot.ChatID)
+	}
+	return entities.ChatID(id), nil
+}
+
+// SetMute mutes or unmutes the chat, it reports whether the state has been changed.
+func (dscBot *DiscordBotEnvironment) SetMute(mute bool) (bool, error) {
+	var changed bool
+	err := dscBot.updateChat(func(chat *chats.Settings) bool {
+		changed = chat.Muted != mute
+		chat.Muted = mute
+		return changed
+	})
+	if err != nil {
+		return false, errors.Wrap(err, "failed to set mute of discord chat")
+	}
+	return changed, nil
+}
+
+// IsChatSubscribed reports whether the chat receives the alerts of the given type.
+func (dscBot *DiscordBotEnvironment) IsChatSubscribed(alertType entities.AlertType) bool {
+	chat, ok := dscBot.Chat()
+	return ok && chat.IsSubscribed(alertType)
+}
+
+// SubscribeToAlert subscribes the chat to the alerts of the given type.
+func (dscBot *DiscordBotEnvironment) SubscribeToAlert(alertType entities.AlertType) error {
+	alertName, ok := alertType.AlertName() // check if such an alert exists
+	if !ok {
+		return errors.New("failed to subscribe to alert, unknown alert type")
+	}
+	var subscribedNow bool
+	err := dscBot.updateChat(func(chat *chats.Settings) bool {
+		subscribedNow = chat.Subscribe(alertName)
+		return subscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe to %s", alertName)
+	}
+	if !subscribedNow {
+		return errors.Errorf("failed to subscribe to %s, already subscribed to it", alertName)
+	}
+	dscBot.zap.Info("Discord chat subscribed to alert",
+		zap.String("chat", dscBot.ChatID), zap.Stringer("alert", alertName),
+	)
+	return nil
+}
+
+// UnsubscribeFromAlert unsubscribes the chat from the alerts of the given type.
+func (dscBot *DiscordBotEnvironment) UnsubscribeFromAlert(alertType entities.AlertType) error {
+	alertName, ok := alertType.AlertName() // check if such an alert exists
+	if !ok {
+		return errors.New("failed to unsubscribe from alert, unknown alert type")
+	}
+	var unsubscribedNow bool
+	err := dscBot.updateChat(func(chat *chats.Settings) bool {
+		unsubscribedNow = chat.Unsubscribe(alertName)
+		return unsubscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to unsubscribe from %s", alertName)
+	}
+	if !unsubscribedNow {
+		return errors.Errorf("failed to unsubscribe from %s, was not subscribed to it", alertName)
+	}
+	dscBot.zap.Info("Discord chat unsubscribed from alert",
+		zap.String("chat", dscBot.ChatID), zap.Stringer("alert", alertName),
+	)
+	return nil
+}
+
+// SubscribeToGroup makes the chat receive the alerts about the nodes with the given tag.
+func (dscBot *DiscordBotEnvironment) SubscribeToGroup(tag string) error {
+	var subscribedNow bool
+	err := dscBot.updateChat(func(chat *chats.Settings) bool {
+		subscribedNow = chat.SubscribeToGroup(tag)
+		return subscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe to group %s", tag)
+	}
+	if !subscribedNow {
+		return errors.Errorf("failed to subscribe to group %s, already subscribed to it", tag)
+	}
+	dscBot.zap.Info("Discord chat subscribed to group", zap.String("chat", dscBot.ChatID), zap.String("group", tag))
+	return nil
+}
+
+// UnsubscribeFromGroup stops receiving the alerts about the nodes with the given tag.
+func (dscBot *DiscordBotEnvironment) UnsubscribeFromGroup(tag string) error {
+	var unsubscribedNow bool
+	err := dscBot.updateChat(func(chat *chats.Settings) bool {
+		unsubscribedNow = chat.UnsubscribeFromGroup(tag)
+		return unsubscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to unsubscribe from group %s", tag)
+	}
+	if !unsubscribedNow {
+		return errors.Errorf("failed to unsubscribe from group %s, was not subscribed to it", tag)
+	}
+	dscBot.zap.Info("Discord chat unsubscribed from group", zap.String("chat", dscBot.ChatID), zap.String("group", tag))
+	return nil
+}
+
+// SubscriptionsList returns the message with the alerts which the chat is subscribed and unsubscribed to.
+func (dscBot *DiscordBotEnvironment) SubscriptionsList() (string, error) {
+	chat, ok := dscBot.Chat()
+	if !ok {
+		return "", errors.Wrapf(chats.ErrChatNotFound, "failed to list subscriptions of chat %s", dscBot.ChatID)
+	}
+	return subscriptionsListMessage(chat, dscBot.TemplatesExtension())
+}
+
+func (dscBot *DiscordBotEnvironment) NodesListMessage(nodes []entities.Node) (string, error) {
+	return nodesListMessage(nodes, dscBot.TemplatesExtension())
+}
+
+func (dscBot *DiscordBotEnvironment) updateChat(update func(chat *chats.Settings) bool) error {
+	id, err := dscBot.chatID()
+	if err != nil {
+		return err
+	}
+	return dscBot.chats.Update(id, update)
 }
 
 func (dscBot *DiscordBotEnvironment) SetNatsConnection(nc *nats.Conn) {
@@ -227,70 +426,42 @@ func (dscBot *DiscordBotEnvironment) IsEligibleForAction(chatID string) bool {
 	return chatID == dscBot.ChatID
 }
 
-type unhandledAlertMessages struct {
-	mu            *sync.RWMutex
-	alertMessages map[crypto.Digest]int // map[AlertID]MessageID
-}
-
-func newUnhandledAlertMessages() unhandledAlertMessages {
-	return unhandledAlertMessages{mu: new(sync.RWMutex), alertMessages: make(map[crypto.Digest]int)}
-}
-
-func (m unhandledAlertMessages) Add(alertID crypto.Digest, messageID int) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	m.alertMessages[alertID] = messageID
-}
-
-func (m unhandledAlertMessages) GetMessageIDByAlertID(alertID crypto.Digest) (int, bool) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
-	messageID, ok := m.alertMessages[alertID]
-	return messageID, ok
-}
-
-func (m unhandledAlertMessages) Delete(alertID crypto.Digest) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-	delete(m.alertMessages, alertID)
-}
-
+// TelegramBotEnvironment serves many chats. The bot is subscribed to all alerts and every alert is delivered
+// to the chats which are subscribed to it and not muted.
 type TelegramBotEnvironment struct {
-	ChatID                 int64
-	Bot                    *telebot.Bot
-	Mute                   bool // If it used elsewhere, should be protected by mutex.
-	subscriptions          subscriptions
-	zap                    *zap.Logger
-	requestType            chan<- pair.Request
-	responsePairType       <-chan pair.Response
-	unhandledAlertMessages unhandledAlertMessages
-	scheme                 string
-	nc                     *nats.Conn
-	alertHandlerFunc       func(msg *nats.Msg)
+	Bot              *telebot.Bot
+	chats            *chats.Storage
+	subscriptions    subscriptions
+	zap              *zap.Logger
+	requestType      chan<- pair.Request
+	responsePairType <-chan pair.Response
+	alertMessages    *state.AlertMessages
+	scheme           string
+	nc               *nats.Conn
+	alertHandlerFunc func(msg *nats.Msg)
 }
 
 func NewTelegramBotEnvironment(
 	bot *telebot.Bot,
-	chatID int64,
-	mute bool,
+	chatsStorage *chats.Storage,
+	alertMessages *state.AlertMessages,
 	zap *zap.Logger,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
 	scheme string,
 ) *TelegramBotEnvironment {
 	return &TelegramBotEnvironment{
-		Bot:    bot,
-		ChatID: chatID,
-		Mute:   mute,
+		Bot:   bot,
+		chats: chatsStorage,
 		subscriptions: subscriptions{
 			subs: make(map[entities.AlertType]AlertSubscription),
 			mu:   new(sync.RWMutex),
 		},
-		zap:                    zap,
-		requestType:            requestType,
-		responsePairType:       responsePairType,
-		unhandledAlertMessages: newUnhandledAlertMessages(),
-		scheme:                 scheme,
+		zap:              zap,
+		requestType:      requestType,
+		responsePairType: responsePairType,
+		alertMessages:    alertMessages,
+		scheme:           scheme,
 	}
 }
 
@@ -308,26 +479,12 @@ func (tgEnv *TelegramBotEnvironment) Start(ctx context.Context) error {
 }
 
 func (tgEnv *TelegramBotEnvironment) SendAlertMessage(msg generalMessaging.AlertMessage) {
-	if tgEnv.Mute {
-		tgEnv.zap.Info("received an alert, but asleep now")
-		return
-	}
-
-	chat := &telebot.Chat{ID: tgEnv.ChatID}
-
 	alertType := msg.AlertType()
 	if !alertType.Exist() {
 		tgEnv.zap.Sugar().Errorf("failed to construct message, unknown alert type %c, %v",
 			byte(alertType), errUnknownAlertType,
 		)
-		_, err := tgEnv.Bot.Send(
-			chat,
-			errUnknownAlertType.Error(),
-			&telebot.SendOptions{ParseMode: telebot.ModeHTML},
-		)
-		if err != nil {
-			tgEnv.zap.Error("failed to send a message to telegram", zap.Error(err))
-		}
+		tgEnv.SendMessage(errUnknownAlertType.Error())
 		return
 	}
 
@@ -343,57 +500,161 @@ func (tgEnv *TelegramBotEnvironment) SendAlertMessage(msg generalMessaging.Alert
 		return
 	}
 	alertID := msg.ReferenceID()
-
-	if alertType == entities.AlertFixedType {
-		messageID, ok := tgEnv.unhandledAlertMessages.GetMessageIDByAlertID(alertID)
-		if !ok {
-			tgEnv.zap.Error("failed to get message ID by the given alertID: alertID hasn't been found",
-				zap.Stringer("alertID", alertID),
-			)
-			return
+	alert, err := decodeAlert(alertType, alertJSON)
+	if err != nil {
+		tgEnv.zap.Error("failed to decode alert", zap.Error(err))
+		return
+	}
+	for _, chat := range tgEnv.chats.Chats() {
+		if chat.Muted {
+			tgEnv.zap.Debug("received an alert, but the chat is muted", zap.Int64("chat", int64(chat.ChatID)))
+			continue
 		}
-		opts := &telebot.SendOptions{ReplyTo: &telebot.Message{ID: messageID}, ParseMode: telebot.ModeHTML}
-		_, sendErr := tgEnv.Bot.Send(chat, messageToBot, opts)
-		if sendErr != nil {
-			tgEnv.zap.Error("failed to send a message about fixed alert to telegram", zap.Error(sendErr))
+		if alertType == entities.AlertFixedType {
+			// the fix is sent only to the chats which have received the alert
+			tgEnv.sendAlertFixed(chat.ChatID, alertID, messageToBot)
+			continue
 		}
-		tgEnv.unhandledAlertMessages.Delete(alertID)
-		return
+		chatMessage, ok, msgErr := chatAlertMessage(chat, alert, messageToBot, nodes, tgEnv.TemplatesExtension())
+		if msgErr != nil {
+			tgEnv.zap.Error("failed to construct message", zap.Int64("chat", int64(chat.ChatID)), zap.Error(msgErr))
+			continue
+		}
+		if !ok {
+			continue
+		}
+		tgEnv.sendAlert(chat.ChatID, alertID, chatMessage)
 	}
+}
 
+func (tgEnv *TelegramBotEnvironment) sendAlert(chatID entities.ChatID, alertID crypto.Digest, messageToBot string) {
 	sentMessage, err := tgEnv.Bot.Send(
-		chat,
+		&telebot.Chat{ID: int64(chatID)},
 		messageToBot,
 		&telebot.SendOptions{ParseMode: telebot.ModeHTML},
 	)
 	if err != nil {
-		tgEnv.zap.Error("failed to send a message to telegram", zap.Error(err))
+		tgEnv.zap.Error("failed to send a message to telegram", zap.Int64("chat", int64(chatID)), zap.Error(err))
+		return
+	}
+	chat := strconv.FormatInt(int64(chatID), 10)
+	if addErr := tgEnv.alertMessages.Add(chat, alertID, sentMessage.ID); addErr != nil {
+		tgEnv.zap.Error("failed to store alert message", zap.Int64("chat", int64(chatID)), zap.Error(addErr))
 	}
+}
 
-	tgEnv.unhandledAlertMessages.Add(alertID, sentMessage.ID)
+func (tgEnv *TelegramBotEnvironment) sendAlertFixed(
+	chatID entities.ChatID,
+	alertID crypto.Digest,
+	messageToBot string,
+) {
+	chat := strconv.FormatInt(int64(chatID), 10)
+	messageID, ok := tgEnv.alertMessages.MessageID(chat, alertID)
+	if !ok {
+		tgEnv.zap.Debug("alert message hasn't been found in the chat",
+			zap.Int64("chat", int64(chatID)), zap.Stringer("alertID", alertID),
+		)
+		return
+	}
+	opts := &telebot.SendOptions{ReplyTo: &telebot.Message{ID: messageID}, ParseMode: telebot.ModeHTML}
+	if _, err := tgEnv.Bot.Send(&telebot.Chat{ID: int64(chatID)}, messageToBot, opts); err != nil {
+		tgEnv.zap.Error("failed to send a message about fixed alert to telegram",
+			zap.Int64("chat", int64(chatID)), zap.Error(err),
+		)
+	}
+	if deleteErr := tgEnv.alertMessages.Delete(chat, alertID); deleteErr != nil {
+		tgEnv.zap.Error("failed to delete alert message", zap.Int64("chat", int64(chatID)), zap.Error(deleteErr))
+	}
+}
+
+// AlertIDByMessageID returns the ID of the unresolved alert which has been sent in the message with the given ID.
+func (tgEnv *TelegramBotEnvironment) AlertIDByMessageID(chatID int64, messageID int) (crypto.Digest, bool) {
+	return tgEnv.alertMessages.AlertID(strconv.FormatInt(chatID, 10), messageID)
 }
 
+// SendMessage sends the message to all chats which are not muted.
 func (tgEnv *TelegramBotEnvironment) SendMessage(msg string) {
-	if tgEnv.Mute {
-		tgEnv.zap.Info("received a message, but asleep now")
+	for _, chat := range tgEnv.chats.Chats() {
+		if chat.Muted {
+			tgEnv.zap.Debug("received a message, but the chat is muted", zap.Int64("chat", int64(chat.ChatID)))
+			continue
+		}
+		_, err := tgEnv.Bot.Send(
+			&telebot.Chat{ID: int64(chat.ChatID)},
+			msg,
+			&telebot.SendOptions{ParseMode: telebot.ModeHTML},
+		)
+		if err != nil {
+			tgEnv.zap.Error("failed to send a message to telegram",
+				zap.Int64("chat", int64(chat.ChatID)), zap.Error(err),
+			)
+		}
+	}
+}
+
+// SendReport sends the scheduled report to the chat if the bot serves the chat and the chat isn't muted.
+func (tgEnv *TelegramBotEnvironment) SendReport(chatID entities.ChatID, msg string) {
+	chat, ok := tgEnv.chats.Chat(chatID)
+	if !ok {
+		tgEnv.zap.Warn("Report chat isn't served by telegram bot", zap.Int64("chat", int64(chatID)))
+		return
+	}
+	if chat.Muted {
+		tgEnv.zap.Debug("received a report, but the chat is muted", zap.Int64("chat", int64(chatID)))
 		return
 	}
+	_, err := tgEnv.Bot.Send(&telebot.Chat{ID: int64(chatID)}, msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	if err != nil {
+		tgEnv.zap.Error("failed to send a message to telegram", zap.Int64("chat", int64(chatID)), zap.Error(err))
+	}
+}
 
-	chat := &telebot.Chat{ID: tgEnv.ChatID}
+// Chat returns the settings of the chat, it reports false if the bot doesn't serve the chat.
+func (tgEnv *TelegramBotEnvironment) Chat(chatID int64) (chats.Settings, bool) {
+	return tgEnv.chats.Chat(entities.ChatID(chatID))
+}
 
-	_, err := tgEnv.Bot.Send(
-		chat,
-		msg,
-		&telebot.SendOptions{ParseMode: telebot.ModeHTML},
-	)
+// IsKnownChat reports whether the bot serves the chat. Such chats may run the commands which only view
+// the monitoring state.
+func (tgEnv *TelegramBotEnvironment) IsKnownChat(chatID int64) bool {
+	_, ok := tgEnv.Chat(chatID)
+	return ok
+}
+
+// CanManageChat reports whether the user may change the settings of the chat, e.g. subscriptions or mute.
+func (tgEnv *TelegramBotEnvironment) CanManageChat(chatID, userID int64) bool {
+	chat, ok := tgEnv.Chat(chatID)
+	return ok && chat.IsAdmin(userID)
+}
+
+// CanModify reports whether the user may change the monitoring state from the chat, e.g. add or remove nodes.
+func (tgEnv *TelegramBotEnvironment) CanModify(chatID, userID int64) bool {
+	chat, ok := tgEnv.Chat(chatID)
+	return ok && !chat.ReadOnly && chat.IsAdmin(userID)
+}
 
+// IsEligibleForAction reports whether the chat may change the monitoring state, read-only chats may not.
+func (tgEnv *TelegramBotEnvironment) IsEligibleForAction(chatID string) bool {
+	id, err := strconv.ParseInt(chatID, 10, 64)
 	if err != nil {
-		tgEnv.zap.Error("failed to send a message to telegram", zap.Error(err))
+		return false
 	}
+	chat, ok := tgEnv.Chat(id)
+	return ok && !chat.ReadOnly
 }
 
-func (tgEnv *TelegramBotEnvironment) IsEligibleForAction(chatID string) bool {
-	return chatID == strconv.FormatInt(tgEnv.ChatID, 10)
+// SetMute mutes or unmutes the chat, it reports whether the state has been changed.
+func (tgEnv *TelegramBotEnvironment) SetMute(chatID int64, mute bool) (bool, error) {
+	var changed bool
+	err := tgEnv.chats.Update(entities.ChatID(chatID), func(chat *chats.Settings) bool {
+		changed = chat.Muted != mute
+		chat.Muted = mute
+		return changed
+	})
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to set mute of chat %d", chatID)
+	}
+	return changed, nil
 }
 
 func removeHTTPOrHTTPSScheme(s string) string {
@@ -410,33 +671,32 @@ func nodesToUrls(nodes []entities.Node) []string {
 		} else {
 			host = removeHTTPOrHTTPSScheme(n.URL)
 		}
+		if len(n.Tags) > 0 {
+			host += " [" + strings.Join(n.Tags, ", ") + "]"
+		}
 		urls = append(urls, host)
 	}
 	return urls
 }
 
 func (tgEnv *TelegramBotEnvironment) NodesListMessage(nodes []entities.Node) (string, error) {
-	urls := nodesToUrls(nodes)
-	tmpl, err := template.ParseFS(templateFiles, "templates/nodes_list.html")
-	if err != nil {
-		tgEnv.zap.Error("failed to parse nodes list template", zap.Error(err))
-		return "", err
-	}
+	return nodesListMessage(nodes, tgEnv.TemplatesExtension())
+}
 
+func nodesListMessage(nodes []entities.Node, extension ExpectedExtension) (string, error) {
+	urls := nodesToUrls(nodes)
 	sort.Strings(urls)
-
-	w := &bytes.Buffer{}
-	err = tmpl.Execute(w, urls)
+	msg, err := executeTemplate("templates/nodes_list", urls, extension)
 	if err != nil {
-		tgEnv.zap.Error("failed to construct a message", zap.Error(err))
-		return "", err
+		return "", errors.Wrap(err, "failed to construct nodes list message")
 	}
-	return w.String(), nil
+	return msg, nil
 }
 
+// SubscribeToAllAlerts subscribes the bot to all alerts, the subscriptions of the chats are applied on delivery.
 func (tgEnv *TelegramBotEnvironment) SubscribeToAllAlerts() error {
 	for alertType, alertName := range entities.GetAllAlertTypesAndNames() {
-		if tgEnv.IsAlreadySubscribed(alertType) {
+		if _, ok := tgEnv.subscriptions.Read(alertType); ok {
 			return errors.Errorf("failed to subscribe to %s, already subscribed to it", alertName)
 		}
 		topic := generalMessaging.PubSubMsgTopic(tgEnv.scheme, alertType)
@@ -451,50 +711,81 @@ func (tgEnv *TelegramBotEnvironment) SubscribeToAllAlerts() error {
 	return nil
 }
 
-func (tgEnv *TelegramBotEnvironment) SubscribeToAlert(alertType entities.AlertType) error {
+func (tgEnv *TelegramBotEnvironment) SubscribeToAlert(chatID int64, alertType entities.AlertType) error {
 	alertName, ok := alertType.AlertName() // check if such an alert exists
 	if !ok {
 		return errors.New("failed to subscribe to alert, unknown alert type")
 	}
-
-	if tgEnv.IsAlreadySubscribed(alertType) {
-		return errors.Errorf("failed to subscribe to %s, already subscribed to it", alertName)
-	}
-
-	topic := generalMessaging.PubSubMsgTopic(tgEnv.scheme, alertType)
-	subscription, err := tgEnv.nc.Subscribe(topic, tgEnv.alertHandlerFunc)
+	var subscribedNow bool
+	err := tgEnv.chats.Update(entities.ChatID(chatID), func(chat *chats.Settings) bool {
+		subscribedNow = chat.Subscribe(alertName)
+		return subscribedNow
+	})
 	if err != nil {
-		return errors.Wrap(err, "failed to subscribe to alert")
+		return errors.Wrapf(err, "failed to subscribe to %s", alertName)
 	}
-	tgEnv.subscriptions.Add(alertType, alertName, subscription)
-	tgEnv.zap.Sugar().Infof("Telegram bot subscribed to %s", alertName)
-
+	if !subscribedNow {
+		return errors.Errorf("failed to subscribe to %s, already subscribed to it", alertName)
+	}
+	tgEnv.zap.Info("Telegram chat subscribed to alert",
+		zap.Int64("chat", chatID), zap.Stringer("alert", alertName),
+	)
 	return nil
 }
 
-func (tgEnv *TelegramBotEnvironment) UnsubscribeFromAlert(alertType entities.AlertType) error {
+func (tgEnv *TelegramBotEnvironment) UnsubscribeFromAlert(chatID int64, alertType entities.AlertType) error {
 	alertName, ok := alertType.AlertName() // check if such an alert exists
 	if !ok {
 		return errors.New("failed to unsubscribe from alert, unknown alert type")
 	}
-
-	if !tgEnv.IsAlreadySubscribed(alertType) {
+	var unsubscribedNow bool
+	err := tgEnv.chats.Update(entities.ChatID(chatID), func(chat *chats.Settings) bool {
+		unsubscribedNow = chat.Unsubscribe(alertName)
+		return unsubscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to unsubscribe from %s", alertName)
+	}
+	if !unsubscribedNow {
 		return errors.Errorf("failed to unsubscribe from %s, was not subscribed to it", alertName)
 	}
-	alertSub, ok := tgEnv.subscriptions.Read(alertType)
-	if !ok {
-		return errors.Errorf("subscription didn't exist even though I was subscribed to it")
+	tgEnv.zap.Info("Telegram chat unsubscribed from alert",
+		zap.Int64("chat", chatID), zap.Stringer("alert", alertName),
+	)
+	return nil
+}
+
+// SubscribeToGroup makes the chat receive the alerts about the nodes with the given tag.
+func (tgEnv *TelegramBotEnvironment) SubscribeToGroup(chatID int64, tag string) error {
+	var subscribedNow bool
+	err := tgEnv.chats.Update(entities.ChatID(chatID), func(chat *chats.Settings) bool {
+		subscribedNow = chat.SubscribeToGroup(tag)
+		return subscribedNow
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to subscribe to group %s", tag)
 	}
-	err := alertSub.subscription.Unsubscribe()
+	if !subscribedNow {
+		return errors.Errorf("failed to subscribe to group %s, already subscribed to it", tag)
+	}
+	tgEnv.zap.Info("Telegram chat subscribed to group", zap.Int64("chat", chatID), zap.String("group", tag))
+	return nil
+}
+
+// UnsubscribeFromGroup stops receiving the alerts about the nodes with the given tag.
+func (tgEnv *TelegramBotEnvironment) UnsubscribeFromGroup(chatID int64, tag string) error {
+	var unsubscribedNow bool
+	err := tgEnv.chats.Update(entities.ChatID(chatID), func(chat *chats.Settings) bool {
+		unsubscribedNow = chat.UnsubscribeFromGroup(tag)
+		return unsubscribedNow
+	})
 	if err != nil {
-		return errors.New("failed to unsubscribe from alert")
+		return errors.Wrapf(err, "failed to unsubscribe from group %s", tag)
 	}
-	ok = tgEnv.IsAlreadySubscribed(alertType)
-	if !ok {
-		return errors.New("tried to unsubscribe from alert, but still subscribed to it")
+	if !unsubscribedNow {
+		return errors.Errorf("failed to unsubscribe from group %s, was not subscribed to it", tag)
 	}
-	tgEnv.subscriptions.Delete(alertType)
-	tgEnv.zap.Sugar().Infof("Telegram bot unsubscribed from %s", alertName)
+	tgEnv.zap.Info("Telegram chat unsubscribed from group", zap.Int64("chat", chatID), zap.String("group", tag))
 	return nil
 }
 
@@ -517,46 +808,56 @@ type unsubscribed struct {
 type subscriptionsList struct {
 	SubscribedTo     []subscribed
 	UnsubscribedFrom []unsubscribed
+	Groups           []string // empty list means all nodes
 }
 
-func (tgEnv *TelegramBotEnvironment) SubscriptionsList() (string, error) {
-	tmpl, err := template.ParseFS(templateFiles, "templates/subscriptions.html")
-
-	if err != nil {
-		tgEnv.zap.Error("failed to parse subscriptions template", zap.Error(err))
-		return "", err
+func (tgEnv *TelegramBotEnvironment) SubscriptionsList(chatID int64) (string, error) {
+	chat, ok := tgEnv.Chat(chatID)
+	if !ok {
+		return "", errors.Wrapf(chats.ErrChatNotFound, "failed to list subscriptions of chat %d", chatID)
 	}
-	var subscribedTo []subscribed
-	tgEnv.subscriptions.MapR(func() {
-		for _, alertName := range tgEnv.subscriptions.subs {
-			s := subscribed{AlertName: string(alertName.alertName) + "\n\n"}
-			subscribedTo = append(subscribedTo, s)
-		}
-	})
+	return subscriptionsListMessage(chat, tgEnv.TemplatesExtension())
+}
 
-	var unsubscribedFrom []unsubscribed
-	for alertType, alertName := range entities.GetAllAlertTypesAndNames() {
-		ok := tgEnv.IsAlreadySubscribed(alertType)
-		if !ok { // find those alerts that are not in the subscriptions list
-			u := unsubscribed{AlertName: string(alertName) + "\n\n"}
-			unsubscribedFrom = append(unsubscribedFrom, u)
+func subscriptionsListMessage(chat chats.Settings, extension ExpectedExtension) (string, error) {
+	var (
+		subscribedTo     []subscribed
+		unsubscribedFrom []unsubscribed
+	)
+	for _, alertName := range SortedAlertNames() {
+		alertType, _ := alertName.AlertType()
+		if chat.IsSubscribed(alertType) {
+			subscribedTo = append(subscribedTo, subscribed{AlertName: string(alertName) + "\n\n"})
+		} else {
+			unsubscribedFrom = append(unsubscribedFrom, unsubscribed{AlertName: string(alertName) + "\n\n"})
 		}
 	}
-
-	subsList := subscriptionsList{SubscribedTo: subscribedTo, UnsubscribedFrom: unsubscribedFrom}
-
-	w := &bytes.Buffer{}
-	err = tmpl.Execute(w, subsList)
+	subsList := subscriptionsList{SubscribedTo: subscribedTo, UnsubscribedFrom: unsubscribedFrom, Groups: chat.Groups}
+	msg, err := executeTemplate("templates/subscriptions", subsList, extension)
 	if err != nil {
-		tgEnv.zap.Error("failed to construct a message", zap.Error(err))
-		return "", err
+		return "", errors.Wrap(err, "failed to construct subscriptions list message")
 	}
-	return w.String(), nil
+	return msg, nil
 }
 
-func (tgEnv *TelegramBotEnvironment) IsAlreadySubscribed(alertType entities.AlertType) bool {
-	_, ok := tgEnv.subscriptions.Read(alertType)
-	return ok
+// SortedAlertNames returns the names of all alerts sorted by the alert type.
+func SortedAlertNames() []entities.AlertName {
+	alerts := entities.GetAllAlertTypesAndNames()
+	alertTypes := make([]entities.AlertType, 0, len(alerts))
+	for alertType := range alerts {
+		alertTypes = append(alertTypes, alertType)
+	}
+	sort.Slice(alertTypes, func(i, j int) bool { return alertTypes[i] < alertTypes[j] })
+	names := make([]entities.AlertName, 0, len(alertTypes))
+	for _, alertType := range alertTypes {
+		names = append(names, alerts[alertType])
+	}
+	return names
+}
+
+func (tgEnv *TelegramBotEnvironment) IsAlreadySubscribed(chatID int64, alertType entities.AlertType) bool {
+	chat, ok := tgEnv.Chat(chatID)
+	return ok && chat.IsSubscribed(alertType)
 }
 
 type shortOkNodes struct {
@@ -565,65 +866,124 @@ type shortOkNodes struct {
 	Height      string
 }
 
-type bot interface {
-	messaging.Bot
+// reportsBot is the bot which sends the scheduled reports to its chats.
+type reportsBot interface {
 	TemplatesExtension() ExpectedExtension
+	// SendReport sends the message to the chat if the bot serves the chat and the chat isn't muted.
+	SendReport(chat entities.ChatID, msg string)
 }
 
-func ScheduleNodesStatus(
-	taskScheduler chrono.TaskScheduler,
+// ReportRunner returns the function which builds the scheduled report and sends it to the chat of the report.
+func ReportRunner(
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
-	bot bot,
+	bot reportsBot,
 	zapLogger *zap.Logger,
-) error {
-	_, err := taskScheduler.ScheduleWithCron(func(_ context.Context) {
-		nodes, err := messaging.RequestAllNodes(requestType, responsePairType)
-		if err != nil {
-			zapLogger.Error("failed to get nodes list", zap.Error(err))
-		}
-		urls := messaging.NodesToUrls(nodes)
-
-		nodesStatus, err := messaging.RequestNodesStatements(requestType, responsePairType, urls)
-		if err != nil {
-			zapLogger.Error("failed to get nodes status", zap.Error(err))
+) reports.RunFunc {
+	return func(_ context.Context, r reports.Report) {
+		var (
+			msg string
+			err error
+		)
+		switch r.Kind {
+		case reports.StatusKind:
+			msg, err = nodesStatusReport(requestType, responsePairType, bot.TemplatesExtension())
+		case reports.SummaryKind:
+			msg, err = nodesSummaryReport(requestType, responsePairType, bot.TemplatesExtension())
+		case reports.ChainsKind:
+			msg, err = nodesChainsReport(requestType, responsePairType, bot.TemplatesExtension())
+		default:
+			err = errors.Wrapf(reports.ErrUnknownKind, "'%s'", r.Kind)
 		}
-		handledNodesStatus, statusCondition, err := HandleNodesStatus(nodesStatus, bot.TemplatesExtension(), nodes)
 		if err != nil {
-			zapLogger.Error("failed to handle nodes status", zap.Error(err))
+			zapLogger.Error("Failed to build scheduled report",
+				zap.Int("id", r.ID), zap.String("kind", string(r.Kind)), zap.Error(err),
+			)
+			return
 		}
+		bot.SendReport(r.Chat, msg)
+	}
+}
 
-		if statusCondition.AllNodesAreOk {
-			var msg string
-			okNodes := shortOkNodes{
-				TimeEmoji:   messaging.TimerMsg,
-				NodesNumber: statusCondition.NodesNumber,
-				Height:      statusCondition.Height,
-			}
-			msg, err = executeTemplate("templates/nodes_status_ok_short", okNodes, bot.TemplatesExtension())
-			if err != nil {
-				zapLogger.Error("failed to construct a message", zap.Error(err))
-			}
-			bot.SendMessage(msg)
-			return
+func nodesStatusReport(
+	requestType chan<- pair.Request,
+	responsePairType <-chan pair.Response,
+	ext ExpectedExtension,
+) (string, error) {
+	nodes, err := messaging.RequestAllNodes(requestType, responsePairType)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes list")
+	}
+	urls := messaging.NodesToUrls(nodes)
+
+	nodesStatus, err := messaging.RequestNodesStatements(requestType, responsePairType, urls)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes status")
+	}
+	handledNodesStatus, statusCondition, err := HandleNodesStatus(nodesStatus, ext, nodes)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to handle nodes status")
+	}
+
+	if statusCondition.AllNodesAreOk {
+		okNodes := shortOkNodes{
+			TimeEmoji:   messaging.TimerMsg,
+			NodesNumber: statusCondition.NodesNumber,
+			Height:      statusCondition.Height,
 		}
-		var msg string
-		switch bot.(type) {
-		case *TelegramBotEnvironment:
-			msg = fmt.Sprintf("Status %s\n\n%s", messaging.TimerMsg, handledNodesStatus)
-		case *DiscordBotEnvironment:
-			msg = fmt.Sprintf("```yaml\nStatus %s\n\n%s\n```", messaging.TimerMsg, handledNodesStatus)
-		default:
-			zapLogger.Error("failed to schedule nodes status, unknown bot type")
-			return
+		msg, tmplErr := executeTemplate("templates/nodes_status_ok_short", okNodes, ext)
+		if tmplErr != nil {
+			return "", errors.Wrap(tmplErr, "failed to construct a message")
 		}
-		bot.SendMessage(msg)
-	}, scheduledTimeExpression)
+		return msg, nil
+	}
+	switch ext {
+	case HTML:
+		return fmt.Sprintf("Status %s\n\n%s", messaging.TimerMsg, handledNodesStatus), nil
+	case Markdown:
+		return fmt.Sprintf("```yaml\nStatus %s\n\n%s\n```", messaging.TimerMsg, handledNodesStatus), nil
+	case PlainText:
+		return "", errors.New("plain text nodes status report isn't supported")
+	default:
+		return "", errors.New("unknown message type of nodes status report")
+	}
+}
 
+func nodesSummaryReport(
+	requestType chan<- pair.Request,
+	responsePairType <-chan pair.Response,
+	ext ExpectedExtension,
+) (string, error) {
+	nodes, err := messaging.RequestAllNodes(requestType, responsePairType)
 	if err != nil {
-		return err
+		return "", errors.Wrap(err, "failed to get nodes list")
 	}
-	return nil
+	since := time.Now().Add(-uptime.MaxPeriod)
+	summary, err := messaging.RequestNodesSummary(requestType, responsePairType, since)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes summary")
+	}
+	return HandleNodesSummary(summary, time.Now().Unix(), ext, nodes)
+}
+
+func nodesChainsReport(
+	requestType chan<- pair.Request,
+	responsePairType <-chan pair.Response,
+	ext ExpectedExtension,
+) (string, error) {
+	nodes, err := messaging.RequestAllNodes(requestType, responsePairType)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes list")
+	}
+	urls := messaging.NodesToUrls(nodes)
+	nodesStatements, err := messaging.RequestNodesStatements(requestType, responsePairType, urls)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to get nodes status")
+	}
+	if nodesStatements.ErrMessage != "" {
+		return nodesStatements.ErrMessage, nil
+	}
+	return HandleNodesChains(nodesStatements, ext)
 }
 
 type NodeStatus struct {
@@ -632,6 +992,7 @@ type NodeStatus struct {
 	Status  string
 	Height  string
 	BlockID string
+	Version string
 }
 
 func sortNodesStatuses(statuses []NodeStatus) {
@@ -653,6 +1014,16 @@ func executeTemplate(templateName string, data any, extension ExpectedExtension)
 			return "", err
 		}
 		return buffer.String(), nil
+	case PlainText:
+		tmpl, err := textTemplate.ParseFS(templateFiles, templateName+string(extension))
+		if err != nil {
+			return "", err
+		}
+		buffer := &bytes.Buffer{}
+		if err = tmpl.Execute(buffer, data); err != nil {
+			return "", err
+		}
+		return buffer.String(), nil
 	default:
 		return "", errors.New("unknown message type to execute a template")
 	}
@@ -699,12 +1070,52 @@ type stateHashStatement struct {
 	ForkStateHash            string
 	FirstGroup               stateHashStatementGroup
 	SecondGroup              stateHashStatementGroup
+	Fork                     *stateHashFork // nil if the diverged height is unknown
+}
+
+type stateHashFork struct {
+	Height         uint64
+	DivergedFields string
+	AlertID        string
 }
 
 type fixedStatement struct {
 	PreviousAlert string
 }
 
+type maintenanceEndedStatement struct {
+	Node             string
+	Start            string
+	End              string
+	StatementsCount  int
+	UnreachableCount int
+	LastStatus       entities.NodeStatus
+	LastHeight       uint64
+	LastVersion      string
+}
+
+type versionMismatchStatement struct {
+	BelowMinimum bool
+	Groups       []entities.VersionGroup
+	Since        string
+	Node         string
+	Version      string
+	MinVersion   string
+}
+
+type chainStuckStatement struct {
+	Height   uint64
+	Since    string
+	Duration string
+}
+
+type missingGeneratorStatement struct {
+	Generator       string
+	Interval        string
+	LastBlockHeight uint64
+	LastBlockTime   string
+}
+
 func executeAlertTemplate(
 	alertType entities.AlertType,
 	alertJSON []byte,
@@ -744,6 +1155,16 @@ func executeAlertTemplate(
 		msg, err = executeChallengedBlockTemplate(alertJSON, extension)
 	case entities.L2StuckAlertType:
 		msg, err = executeL2StuckAlertTemplate(alertJSON, extension)
+	case entities.MaintenanceEndedAlertType:
+		msg, err = executeMaintenanceEndedTemplate(alertJSON, nodesAliases, extension)
+	case entities.VersionMismatchAlertType:
+		msg, err = executeVersionMismatchTemplate(alertJSON, nodesAliases, extension)
+	case entities.ChainStuckAlertType:
+		msg, err = executeChainStuckTemplate(alertJSON, extension)
+	case entities.MissingGeneratorAlertType:
+		msg, err = executeMissingGeneratorTemplate(alertJSON, extension)
+	case entities.AlertGroupType:
+		msg, err = executeAlertGroupTemplate(alertJSON, nodesAliases, extension)
 	default:
 		return "", errors.Errorf("unknown alert type (%d)", alertType)
 	}
@@ -869,6 +1290,13 @@ func executeStateHashTemplate(
 			StateHash: stateHashAlert.SecondGroup.StateHash.SumHash.Hex(),
 		},
 	}
+	if fork := stateHashAlert.Fork; fork != nil && fork.ForkHeight != 0 {
+		statement.Fork = &stateHashFork{
+			Height:         fork.ForkHeight,
+			DivergedFields: joinDivergedFields(fork.DivergedFields),
+			AlertID:        fork.AlertID.String(),
+		}
+	}
 
 	if statement.FirstGroup.BlockID != statement.SecondGroup.BlockID {
 		msg, tmplErr := executeTemplate("templates/alerts/state_hash_several_chains_alert", statement, extension)
@@ -992,6 +1420,131 @@ func executeL2StuckAlertTemplate(alertJSON []byte, extension ExpectedExtension)
 	return msg, nil
 }
 
+func executeMaintenanceEndedTemplate(
+	alertJSON []byte,
+	nodesAliases map[string]string,
+	extension ExpectedExtension,
+) (string, error) {
+	var maintenanceEndedAlert entities.MaintenanceEndedAlert
+	err := json.Unmarshal(alertJSON, &maintenanceEndedAlert)
+	if err != nil {
+		return "", err
+	}
+	statement := maintenanceEndedStatement{
+		Node:             replaceNodeWithAlias(maintenanceEndedAlert.Node, nodesAliases),
+		Start:            time.Unix(maintenanceEndedAlert.Start, 0).UTC().Format(time.DateTime),
+		End:              time.Unix(maintenanceEndedAlert.End, 0).UTC().Format(time.DateTime),
+		StatementsCount:  maintenanceEndedAlert.StatementsCount,
+		UnreachableCount: maintenanceEndedAlert.UnreachableCount,
+		LastStatus:       maintenanceEndedAlert.LastStatus,
+		LastHeight:       maintenanceEndedAlert.LastHeight,
+		LastVersion:      maintenanceEndedAlert.LastVersion,
+	}
+	msg, err := executeTemplate("templates/alerts/maintenance_ended_alert", statement, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+func executeVersionMismatchTemplate(
+	alertJSON []byte,
+	nodesAliases map[string]string,
+	extension ExpectedExtension,
+) (string, error) {
+	var versionMismatchAlert entities.VersionMismatchAlert
+	err := json.Unmarshal(alertJSON, &versionMismatchAlert)
+	if err != nil {
+		return "", err
+	}
+	statement := versionMismatchStatement{
+		BelowMinimum: versionMismatchAlert.Kind == entities.VersionBelowMinimum,
+		Groups:       make([]entities.VersionGroup, 0, len(versionMismatchAlert.Groups)),
+		Since:        time.Unix(versionMismatchAlert.Since, 0).UTC().Format(time.DateTime),
+		Node:         replaceNodeWithAlias(versionMismatchAlert.Node, nodesAliases),
+		Version:      versionMismatchAlert.Version,
+		MinVersion:   versionMismatchAlert.MinVersion,
+	}
+	for _, group := range versionMismatchAlert.Groups {
+		nodes := make(entities.Nodes, 0, len(group.Nodes))
+		for _, node := range group.Nodes {
+			nodes = append(nodes, replaceNodeWithAlias(node, nodesAliases))
+		}
+		statement.Groups = append(statement.Groups, entities.VersionGroup{Version: group.Version, Nodes: nodes})
+	}
+	msg, err := executeTemplate("templates/alerts/version_mismatch_alert", statement, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+func executeChainStuckTemplate(alertJSON []byte, extension ExpectedExtension) (string, error) {
+	var chainStuckAlert entities.ChainStuckAlert
+	err := json.Unmarshal(alertJSON, &chainStuckAlert)
+	if err != nil {
+		return "", err
+	}
+	statement := chainStuckStatement{
+		Height:   chainStuckAlert.Height,
+		Since:    time.Unix(chainStuckAlert.Since, 0).UTC().Format(time.DateTime),
+		Duration: (time.Duration(chainStuckAlert.Timestamp-chainStuckAlert.Since) * time.Second).String(),
+	}
+	msg, err := executeTemplate("templates/alerts/chain_stuck_alert", statement, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+func executeMissingGeneratorTemplate(alertJSON []byte, extension ExpectedExtension) (string, error) {
+	var missingGeneratorAlert entities.MissingGeneratorAlert
+	err := json.Unmarshal(alertJSON, &missingGeneratorAlert)
+	if err != nil {
+		return "", err
+	}
+	statement := missingGeneratorStatement{
+		Generator:       missingGeneratorAlert.Generator,
+		Interval:        missingGeneratorAlert.Interval.String(),
+		LastBlockHeight: missingGeneratorAlert.LastBlockHeight,
+		LastBlockTime:   time.Unix(missingGeneratorAlert.LastBlockTime, 0).UTC().Format(time.DateTime),
+	}
+	msg, err := executeTemplate("templates/alerts/missing_generator_alert", statement, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+// executeAlertGroupTemplate replaces the nodes with their aliases, in the messages of the group alerts too.
+func executeAlertGroupTemplate(
+	alertJSON []byte,
+	nodesAliases map[string]string,
+	extension ExpectedExtension,
+) (string, error) {
+	var alertGroup entities.AlertGroup
+	err := json.Unmarshal(alertJSON, &alertGroup)
+	if err != nil {
+		return "", err
+	}
+	var replacements []string // old and new pairs
+	for i, node := range alertGroup.Nodes {
+		alias := replaceNodeWithAlias(node, nodesAliases)
+		replacements = append(replacements, node, alias)
+		alertGroup.Nodes[i] = alias
+	}
+	replacer := strings.NewReplacer(replacements...)
+	alertGroup.RootCause = replacer.Replace(alertGroup.RootCause)
+	for i := range alertGroup.Alerts {
+		alertGroup.Alerts[i].Message = replacer.Replace(alertGroup.Alerts[i].Message)
+	}
+	msg, err := executeTemplate("templates/alerts/alert_group", alertGroup, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
 type StatusCondition struct {
 	AllNodesAreOk bool
 	NodesNumber   int
@@ -1016,8 +1569,9 @@ func HandleNodesStatusError(resp *pair.NodesStatementsResponse,
 		}
 		height := strconv.FormatUint(stat.Height, 10)
 		s := NodeStatus{
-			URL:    stat.URL,
-			Height: height,
+			URL:     stat.URL,
+			Height:  height,
+			Version: stat.Version,
 		}
 		differentHeightsNodes = append(differentHeightsNodes, s)
 	}
@@ -1190,6 +1744,55 @@ func areStateHashesEqual(okNodes []NodeStatus) bool {
 	return hashesEqual
 }
 
+// alertNodes returns the nodes which the alert is related to, nil is returned if the alert can't be decoded.
+func decodeAlert(alertType entities.AlertType, alertJSON []byte) (entities.Alert, error) {
+	alert, err := entities.NewAlertByType(alertType)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode alert")
+	}
+	if unmarshalErr := json.Unmarshal(alertJSON, alert); unmarshalErr != nil {
+		return nil, errors.Wrapf(unmarshalErr, "failed to unmarshal alert of type %d", alertType)
+	}
+	return alert, nil
+}
+
+// chatAlertMessage returns the message of the alert as the chat receives it, it reports false if the chat doesn't
+// receive the alert. The alert group keeps only the member alerts which the chat is subscribed to, so the message
+// is constructed again if some of them are stripped.
+func chatAlertMessage(
+	chat chats.Settings,
+	alert entities.Alert,
+	message string,
+	nodes []entities.Node,
+	extension ExpectedExtension,
+) (string, bool, error) {
+	subscribed := chat.SubscribedAlert(alert, nodesTags(nodes))
+	switch subscribed {
+	case nil:
+		return "", false, nil
+	case alert:
+		return message, true, nil
+	}
+	alertJSON, err := json.Marshal(subscribed)
+	if err != nil {
+		return "", false, errors.Wrap(err, "failed to marshal alert of chat")
+	}
+	chatMessage, err := constructMessage(subscribed.Type(), alertJSON, extension, nodes)
+	if err != nil {
+		return "", false, err
+	}
+	return chatMessage, true, nil
+}
+
+// nodesTags returns the function which finds the tags of the node by its URL.
+func nodesTags(nodes []entities.Node) func(node string) []string {
+	tags := make(map[string][]string, len(nodes))
+	for _, n := range nodes {
+		tags[n.URL] = n.Tags
+	}
+	return func(node string) []string { return tags[node] }
+}
+
 func nodeURLToAlias(allNodes []entities.Node) map[string]string {
 	nodesAliases := make(map[string]string)
 	for _, n := range allNodes {
@@ -1217,6 +1820,7 @@ func sortedNodesByStatusWithHeight(resp *pair.NodesStatementsResponse) ([]NodeSt
 				Status:  string(stat.Status),
 				Height:  height,
 				BlockID: stat.StateHash.BlockID.String(),
+				Version: stat.Version,
 			}
 			okNodes = append(okNodes, s)
 		}
@@ -1257,6 +1861,270 @@ func HandleNodeStatement(resp *pair.NodeStatementResponse, extension ExpectedExt
 	return msg, nil
 }
 
+type activeAlertItem struct {
+	ID       string
+	Name     entities.AlertName
+	Level    string
+	Message  string
+	OpenedAt string
+	Sent     int
+}
+
+type resolvedAlertItem struct {
+	Name     entities.AlertName
+	Level    string
+	Message  string
+	OpenedAt string
+	ClosedAt string
+}
+
+type alertsList struct {
+	Active   []activeAlertItem
+	Resolved []resolvedAlertItem
+}
+
+func formatAlertTimestamp(ts int64) string {
+	return time.Unix(ts, 0).UTC().Format(time.DateTime)
+}
+
+func HandleAlerts(resp *pair.AlertsResponse, extension ExpectedExtension) (string, error) {
+	if resp.ErrMessage != "" {
+		return "", errors.Errorf("failed to get alerts: %s", resp.ErrMessage)
+	}
+	list := alertsList{
+		Active:   make([]activeAlertItem, 0, len(resp.Active)),
+		Resolved: make([]resolvedAlertItem, 0, len(resp.Resolved)),
+	}
+	for _, a := range resp.Active {
+		list.Active = append(list.Active, activeAlertItem{
+			ID:       a.ID.String(),
+			Name:     a.Name,
+			Level:    a.Level,
+			Message:  a.Message,
+			OpenedAt: formatAlertTimestamp(a.OpenedAt),
+			Sent:     a.Sent,
+		})
+	}
+	for _, a := range resp.Resolved {
+		list.Resolved = append(list.Resolved, resolvedAlertItem{
+			Name:     a.Name,
+			Level:    a.Level,
+			Message:  a.Message,
+			OpenedAt: formatAlertTimestamp(a.OpenedAt),
+			ClosedAt: formatAlertTimestamp(a.ClosedAt),
+		})
+	}
+	msg, err := executeTemplate("templates/alerts_list", list, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+// generatorsListLimit is the max number of generators in the list to fit the message size limits.
+const generatorsListLimit = 30
+
+type generatorItem struct {
+	Generator       string
+	Blocks          int
+	Share           string
+	LastBlockHeight uint64
+	LastBlockTime   string
+}
+
+type generatorsList struct {
+	Since      string
+	Until      string
+	Blocks     int
+	Generators []generatorItem
+	Other      int
+}
+
+func HandleGenerators(resp *pair.GeneratorsResponse, extension ExpectedExtension) (string, error) {
+	if resp.ErrMessage != "" {
+		return "", errors.Errorf("failed to collect generators stats: %s", resp.ErrMessage)
+	}
+	stats := resp.Stats
+	list := generatorsList{
+		Since:  formatAlertTimestamp(stats.Since),
+		Until:  formatAlertTimestamp(stats.Until),
+		Blocks: stats.Blocks,
+		Other:  max(len(stats.Generators)-generatorsListLimit, 0),
+	}
+	for _, g := range stats.Generators[:min(len(stats.Generators), generatorsListLimit)] {
+		const percents = 100
+		list.Generators = append(list.Generators, generatorItem{
+			Generator:       g.Generator,
+			Blocks:          g.Blocks,
+			Share:           strconv.FormatFloat(float64(g.Blocks)*percents/float64(stats.Blocks), 'f', 1, 64),
+			LastBlockHeight: g.LastBlockHeight,
+			LastBlockTime:   formatAlertTimestamp(g.LastBlockTime),
+		})
+	}
+	msg, err := executeTemplate("templates/generators_list", list, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+type forkReportBlock struct {
+	Node      string
+	BlockID   string
+	StateHash string
+	Generator string
+}
+
+type forkReportHeight struct {
+	Height         uint64
+	Diverged       bool
+	First          *forkReportBlock
+	Second         *forkReportBlock
+	DivergedFields string
+}
+
+type forkReport struct {
+	AlertID          string
+	Found            bool
+	Time             string
+	LastCommonHeight uint64
+	ForkHeight       uint64
+	DivergedFields   string
+	FirstGroup       string
+	SecondGroup      string
+	Heights          []forkReportHeight
+}
+
+// shortForkID is the length of the block IDs and the state hashes in the fork report to fit the message size limits.
+const shortForkID = 8
+
+func shortenForkID(id string) string {
+	if len(id) <= shortForkID {
+		return id
+	}
+	return id[:shortForkID] + "…"
+}
+
+func joinDivergedFields(fields []string) string {
+	if len(fields) == 0 {
+		return "none"
+	}
+	return strings.Join(fields, ", ")
+}
+
+func newForkReportBlock(b *entities.ForkBlock, nodesAliases map[string]string) *forkReportBlock {
+	if b == nil {
+		return nil
+	}
+	block := &forkReportBlock{
+		Node:      replaceNodeWithAlias(b.Node, nodesAliases),
+		BlockID:   shortenForkID(b.BlockID.String()),
+		StateHash: shortenForkID(b.StateHash.Hex()),
+		Generator: "unknown",
+	}
+	if b.Generator != nil {
+		block.Generator = b.Generator.String()
+	}
+	return block
+}
+
+func HandleForkReport(
+	resp *pair.ForkReportResponse,
+	alertID string,
+	extension ExpectedExtension,
+	nodes []entities.Node,
+) (string, error) {
+	if resp.ErrMessage != "" {
+		return "", errors.Errorf("failed to get fork report: %s", resp.ErrMessage)
+	}
+	nodesAliases := nodeURLToAlias(nodes)
+	report := forkReport{AlertID: alertID}
+	if r := resp.Report; r != nil {
+		aliases := func(nodes []string) string {
+			out := make([]string, 0, len(nodes))
+			for _, node := range nodes {
+				out = append(out, replaceNodeWithAlias(node, nodesAliases))
+			}
+			return strings.Join(out, ", ")
+		}
+		report = forkReport{
+			AlertID:          r.AlertID.String(),
+			Found:            true,
+			Time:             formatAlertTimestamp(r.Timestamp),
+			LastCommonHeight: r.LastCommonHeight,
+			ForkHeight:       r.ForkHeight,
+			DivergedFields:   joinDivergedFields(r.DivergedFields),
+			FirstGroup:       aliases(r.FirstGroup),
+			SecondGroup:      aliases(r.SecondGroup),
+		}
+		for _, h := range r.Heights {
+			report.Heights = append(report.Heights, forkReportHeight{
+				Height:         h.Height,
+				Diverged:       h.Diverged(),
+				First:          newForkReportBlock(h.First, nodesAliases),
+				Second:         newForkReportBlock(h.Second, nodesAliases),
+				DivergedFields: strings.Join(h.DivergedFields, ", "),
+			})
+		}
+	}
+	msg, err := executeTemplate("templates/fork_report", report, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+type nodeSummaryItem struct {
+	Node    string
+	Uptime  string
+	Polls   int
+	OKPolls int
+	Alerts  int
+}
+
+type nodesSummary struct {
+	Since string
+	Until string
+	Nodes []nodeSummaryItem
+}
+
+// HandleNodesSummary builds the message with the uptime and the number of alerts of every node.
+func HandleNodesSummary(
+	resp *pair.NodesSummaryResponse,
+	until int64,
+	extension ExpectedExtension,
+	nodes []entities.Node,
+) (string, error) {
+	nodesAliases := nodeURLToAlias(nodes)
+	summary := nodesSummary{
+		Since: formatAlertTimestamp(resp.Since),
+		Until: formatAlertTimestamp(until),
+	}
+	for _, n := range resp.Nodes {
+		summary.Nodes = append(summary.Nodes, nodeSummaryItem{
+			Node:    replaceNodeWithAlias(n.Node, nodesAliases),
+			Uptime:  strconv.FormatFloat(n.Uptime(), 'f', 2, 64),
+			Polls:   n.Polls,
+			OKPolls: n.OKPolls,
+			Alerts:  n.Alerts,
+		})
+	}
+	msg, err := executeTemplate("templates/nodes_summary", summary, extension)
+	if err != nil {
+		return "", err
+	}
+	return msg, nil
+}
+
+// ReportsListMessage builds the message with the scheduled reports of the chat.
+func ReportsListMessage(list []reports.Report, extension ExpectedExtension) (string, error) {
+	msg, err := executeTemplate("templates/reports_list", list, extension)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to construct reports list message")
+	}
+	return msg, nil
+}
+
 func constructMessage(
 	alertType entities.AlertType,
 	alertJSON []byte,
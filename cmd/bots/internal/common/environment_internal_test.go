@@ -0,0 +1,347 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nodemon/cmd/bots/internal/common/chats"
+	"nodemon/cmd/bots/internal/common/state"
+	"nodemon/pkg/entities"
+	generalMessaging "nodemon/pkg/messaging"
+	"nodemon/pkg/messaging/pair"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"gopkg.in/telebot.v3"
+)
+
+// sentMessage is the message received by the stand-in of the messenger API.
+type sentMessage struct {
+	chat      string
+	text      string
+	replyTo   string
+	messageID int
+}
+
+// messengerStandIn records the sent messages and replies with the sequential message IDs.
+type messengerStandIn struct {
+	mu     sync.Mutex
+	lastID int
+	sent   []sentMessage
+	edited []string // IDs of the edited messages
+}
+
+func (s *messengerStandIn) add(msg sentMessage) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	msg.messageID = s.lastID
+	s.sent = append(s.sent, msg)
+	return s.lastID
+}
+
+func (s *messengerStandIn) messages() []sentMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := s.sent
+	s.sent = nil
+	return out
+}
+
+func newTelegramStandIn(t *testing.T) (*messengerStandIn, *telebot.Bot) {
+	s := new(messengerStandIn)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/sendMessage") {
+			http.NotFound(w, r)
+			return
+		}
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		id := s.add(sentMessage{chat: params["chat_id"], text: params["text"], replyTo: params["reply_to_message_id"]})
+		_, _ = fmt.Fprintf(w, `{"ok":true,"result":{"message_id":%d,"chat":{"id":%s}}}`, id, params["chat_id"])
+	}))
+	t.Cleanup(srv.Close)
+	bot, err := telebot.NewBot(telebot.Settings{URL: srv.URL, Token: "test", Offline: true})
+	require.NoError(t, err)
+	return s, bot
+}
+
+func newDiscordStandIn(t *testing.T) (*messengerStandIn, *discordgo.Session) {
+	s := new(messengerStandIn)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.Split(strings.Trim(r.URL.Path, "/"), "/") // channels/{channel}/messages[/{message}]
+		const channelPathLen, messagePathLen = 3, 4
+		switch {
+		case r.Method == http.MethodPost && len(path) == channelPathLen:
+			var msg struct {
+				Content   string `json:"content"`
+				Reference struct {
+					MessageID string `json:"message_id"`
+				} `json:"message_reference"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			id := s.add(sentMessage{chat: path[1], text: msg.Content, replyTo: msg.Reference.MessageID})
+			_, _ = fmt.Fprintf(w, `{"id":"%d","channel_id":"%s"}`, id, path[1])
+		case r.Method == http.MethodPatch && len(path) == messagePathLen:
+			s.mu.Lock()
+			s.edited = append(s.edited, path[3])
+			s.mu.Unlock()
+			_, _ = fmt.Fprintf(w, `{"id":"%s","channel_id":"%s"}`, path[3], path[1])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	endpoint := discordgo.EndpointChannels
+	discordgo.EndpointChannels = srv.URL + "/channels/"
+	t.Cleanup(func() { discordgo.EndpointChannels = endpoint })
+	session, err := discordgo.New("Bot test")
+	require.NoError(t, err)
+	return s, session
+}
+
+// serveNodes answers the nodes list requests of the bot environment until the test ends.
+func serveNodes(t *testing.T, nodes []entities.Node) (chan<- pair.Request, <-chan pair.Response) {
+	requests := make(chan pair.Request)
+	responses := make(chan pair.Response)
+	done := make(chan struct{})
+	t.Cleanup(func() { close(done) })
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case req := <-requests:
+				var resp pair.Response = &pair.NodesListResponse{}
+				if r, ok := req.(*pair.NodesListRequest); ok && !r.Specific {
+					resp = &pair.NodesListResponse{Nodes: nodes}
+				}
+				responses <- resp
+			}
+		}
+	}()
+	return requests, responses
+}
+
+func alertMessage(t *testing.T, alert entities.Alert) generalMessaging.AlertMessage {
+	msg, err := generalMessaging.NewAlertMessageFromAlert(alert)
+	require.NoError(t, err)
+	return msg
+}
+
+func newChatsStorage(t *testing.T, settings ...chats.Settings) *chats.Storage {
+	storage, err := chats.NewStorage("")
+	require.NoError(t, err)
+	for _, chat := range settings {
+		added, err := storage.AddIfNew(chat)
+		require.NoError(t, err)
+		require.True(t, added)
+	}
+	return storage
+}
+
+func chatSettings(id entities.ChatID, platform entities.Platform) chats.Settings {
+	return chats.Settings{Chat: entities.Chat{ChatID: id, Platform: platform}}
+}
+
+func TestTelegramBotEnvironment_SendAlertMessage(t *testing.T) {
+	const node = "https://node-1.example.com"
+	var (
+		subscribed   = chatSettings(1, entities.TelegramPlatform)
+		muted        = chatSettings(2, entities.TelegramPlatform)
+		unsubscribed = chatSettings(3, entities.TelegramPlatform)
+		readOnly     = chatSettings(4, entities.TelegramPlatform)
+		otherGroup   = chatSettings(5, entities.TelegramPlatform)
+	)
+	muted.Muted = true
+	unsubscribed.Unsubscribed = []entities.AlertName{entities.UnreachableAlertName}
+	readOnly.ReadOnly = true
+	otherGroup.Groups = []string{"public-api"}
+
+	api, bot := newTelegramStandIn(t)
+	alertMessages, err := state.NewAlertMessages("", time.Hour)
+	require.NoError(t, err)
+	requests, responses := serveNodes(t, []entities.Node{{URL: node, Tags: []string{"validators"}}})
+	env := NewTelegramBotEnvironment(bot,
+		newChatsStorage(t, subscribed, muted, unsubscribed, readOnly, otherGroup),
+		alertMessages, zap.NewNop(), requests, responses, "mainnet",
+	)
+
+	alert := &entities.UnreachableAlert{Timestamp: 100, Node: node}
+	env.SendAlertMessage(alertMessage(t, alert))
+	sent := api.messages()
+	require.Len(t, sent, 2, "only the subscribed chats which aren't muted must receive the alert")
+	messageIDs := make(map[string]int)
+	for _, msg := range sent {
+		assert.Contains(t, msg.text, "is unreachable")
+		assert.Empty(t, msg.replyTo)
+		messageIDs[msg.chat] = msg.messageID
+	}
+	assert.Contains(t, messageIDs, "1")
+	assert.Contains(t, messageIDs, "4", "read-only chat must receive the alerts")
+
+	env.SendAlertMessage(alertMessage(t, &entities.ChainStuckAlert{Timestamp: 160, Height: 10, Since: 100}))
+	assert.Len(t, api.messages(), 4, "the alert without nodes must be sent to all subscribed chats but muted")
+
+	env.SendAlertMessage(alertMessage(t, &entities.AlertFixed{Timestamp: 200, Fixed: alert}))
+	fixes := api.messages()
+	require.Len(t, fixes, 2, "the fix must be sent only to the chats which have received the alert")
+	for _, msg := range fixes {
+		assert.Equal(t, strconv.Itoa(messageIDs[msg.chat]), msg.replyTo, "the fix must reply to the alert in its chat")
+	}
+	for _, chat := range []string{"1", "4"} {
+		_, ok := alertMessages.MessageID(chat, alert.ID())
+		assert.False(t, ok, "the message of the fixed alert must be forgotten")
+	}
+}
+
+func TestDiscordBotEnvironment_SendAlertMessage(t *testing.T) {
+	const (
+		node   = "https://node-1.example.com"
+		chatID = 42
+	)
+	api, session := newDiscordStandIn(t)
+	alertMessages, err := state.NewAlertMessages("", time.Hour)
+	require.NoError(t, err)
+	requests, responses := serveNodes(t, []entities.Node{{URL: node}})
+	storage := newChatsStorage(t, chatSettings(chatID, entities.DiscordPlatform))
+	env := NewDiscordBotEnvironment(session, strconv.Itoa(chatID), storage, alertMessages, zap.NewNop(),
+		requests, responses, "mainnet",
+	)
+
+	alert := &entities.UnreachableAlert{Timestamp: 100, Node: node}
+	env.SendAlertMessage(alertMessage(t, alert))
+	sent := api.messages()
+	require.Len(t, sent, 1)
+	assert.Equal(t, strconv.Itoa(chatID), sent[0].chat)
+	assert.Contains(t, sent[0].text, "is unreachable")
+
+	env.SendAlertMessage(alertMessage(t, &entities.AlertFixed{Timestamp: 200, Fixed: alert}))
+	fixes := api.messages()
+	require.Len(t, fixes, 1)
+	assert.Equal(t, strconv.Itoa(sent[0].messageID), fixes[0].replyTo, "the fix must reply to the alert")
+	assert.Equal(t, []string{strconv.Itoa(sent[0].messageID)}, api.edited, "the ack button must be removed")
+
+	_, err = env.SetMute(true)
+	require.NoError(t, err)
+	env.SendAlertMessage(alertMessage(t, alert))
+	assert.Empty(t, api.messages(), "muted chat must not receive the alerts")
+
+	_, err = env.SetMute(false)
+	require.NoError(t, err)
+	env.SendAlertMessage(alertMessage(t, alert))
+	require.Len(t, api.messages(), 1)
+	_, err = env.SetMute(true)
+	require.NoError(t, err)
+	env.SendAlertMessage(alertMessage(t, &entities.AlertFixed{Timestamp: 300, Fixed: alert}))
+	assert.Empty(t, api.messages(), "muted chat must not receive the fixes")
+
+	_, err = env.SetMute(false)
+	require.NoError(t, err)
+	require.NoError(t, env.UnsubscribeFromAlert(entities.UnreachableAlertType))
+	height := &entities.HeightAlert{
+		Timestamp:        100,
+		MaxHeightGroup:   entities.HeightGroup{Height: 10, Nodes: entities.Nodes{"https://node-2.example.com"}},
+		OtherHeightGroup: entities.HeightGroup{Height: 5, Nodes: entities.Nodes{node}},
+	}
+	env.SendAlertMessage(alertMessage(t, &entities.AlertGroup{
+		Timestamp: 100,
+		Nodes:     []string{node},
+		Alerts: []entities.AlertGroupMember{
+			entities.NewAlertGroupMember(alert),
+			entities.NewAlertGroupMember(height),
+		},
+	}))
+	groups := api.messages()
+	require.Len(t, groups, 1, "the group with the subscribed member alert must be received")
+	assert.Contains(t, groups[0].text, entities.HeightAlertName.String()+":")
+	assert.NotContains(t, groups[0].text, entities.UnreachableAlertName.String()+":",
+		"the unsubscribed member alert must be stripped",
+	)
+	env.SendAlertMessage(alertMessage(t, &entities.AlertGroup{
+		Timestamp: 100,
+		Nodes:     []string{node},
+		Alerts:    []entities.AlertGroupMember{entities.NewAlertGroupMember(alert)},
+	}))
+	assert.Empty(t, api.messages(), "the group of the unsubscribed alerts must not be received")
+	env.SendAlertMessage(alertMessage(t, alert))
+	assert.Empty(t, api.messages(), "unsubscribed chat must not receive the alert")
+	env.SendAlertMessage(alertMessage(t, &entities.ChainStuckAlert{Timestamp: 160, Height: 10, Since: 100}))
+	assert.Len(t, api.messages(), 1, "the other alerts must be received")
+}
+
+func TestTelegramBotEnvironment_SendAlertGroup(t *testing.T) {
+	const (
+		nodeA = "https://node-1.example.com"
+		nodeB = "https://node-2.example.com"
+	)
+	var (
+		all            = chatSettings(1, entities.TelegramPlatform)
+		noHeight       = chatSettings(2, entities.TelegramPlatform)
+		noMembers      = chatSettings(3, entities.TelegramPlatform)
+		publicAPI      = chatSettings(4, entities.TelegramPlatform)
+		noGroups       = chatSettings(5, entities.TelegramPlatform)
+		unreachable    = &entities.UnreachableAlert{Timestamp: 100, Node: nodeA}
+		unreachableMsg = entities.UnreachableAlertName.String() + ":"
+		height         = &entities.HeightAlert{
+			Timestamp:        100,
+			MaxHeightGroup:   entities.HeightGroup{Height: 10, Nodes: entities.Nodes{nodeB}},
+			OtherHeightGroup: entities.HeightGroup{Height: 5, Nodes: entities.Nodes{nodeA}},
+		}
+		heightMsg = entities.HeightAlertName.String() + ":"
+	)
+	noHeight.Unsubscribed = []entities.AlertName{entities.HeightAlertName}
+	noMembers.Unsubscribed = []entities.AlertName{entities.HeightAlertName, entities.UnreachableAlertName}
+	publicAPI.Groups = []string{"public-api"}
+	noGroups.Unsubscribed = []entities.AlertName{entities.AlertGroupName}
+
+	api, bot := newTelegramStandIn(t)
+	alertMessages, err := state.NewAlertMessages("", time.Hour)
+	require.NoError(t, err)
+	requests, responses := serveNodes(t, []entities.Node{{URL: nodeA}, {URL: nodeB, Tags: []string{"public-api"}}})
+	env := NewTelegramBotEnvironment(bot,
+		newChatsStorage(t, all, noHeight, noMembers, publicAPI, noGroups),
+		alertMessages, zap.NewNop(), requests, responses, "mainnet",
+	)
+
+	group := &entities.AlertGroup{
+		Timestamp: 100,
+		RootCause: "node is unreachable",
+		Nodes:     []string{nodeA, nodeB},
+		Alerts: []entities.AlertGroupMember{
+			entities.NewAlertGroupMember(unreachable),
+			entities.NewAlertGroupMember(height),
+		},
+	}
+	env.SendAlertMessage(alertMessage(t, group))
+	texts := make(map[string]string)
+	for _, msg := range api.messages() {
+		texts[msg.chat] = msg.text
+	}
+	require.Len(t, texts, 3, "only the chats subscribed to any member alert must receive the group")
+	assert.Contains(t, texts["1"], unreachableMsg)
+	assert.Contains(t, texts["1"], heightMsg)
+	assert.Contains(t, texts["2"], unreachableMsg)
+	assert.NotContains(t, texts["2"], heightMsg, "the unsubscribed member alert must be stripped")
+	assert.Contains(t, texts["4"], heightMsg)
+	assert.NotContains(t, texts["4"], unreachableMsg, "the member alert about other nodes must be stripped")
+
+	env.SendAlertMessage(alertMessage(t, &entities.AlertFixed{Timestamp: 200, Fixed: group}))
+	fixes := api.messages()
+	assert.Len(t, fixes, 3, "the fix must be sent to the chats which have received the stripped group")
+}
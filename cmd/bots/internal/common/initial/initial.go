@@ -1,8 +1,13 @@
 package initial
 
 import (
+	"strconv"
+
 	"nodemon/cmd/bots/internal/common"
+	"nodemon/cmd/bots/internal/common/chats"
+	"nodemon/cmd/bots/internal/common/state"
 	"nodemon/cmd/bots/internal/telegram/config"
+	"nodemon/pkg/entities"
 	"nodemon/pkg/messaging/pair"
 
 	"github.com/bwmarrin/discordgo"
@@ -15,7 +20,8 @@ func InitTgBot(behavior string,
 	webhookLocalAddress string,
 	publicURL string,
 	botToken string,
-	chatID int64,
+	chatsStorage *chats.Storage,
+	alertMessages *state.AlertMessages,
 	logger *zap.Logger,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
@@ -30,15 +36,23 @@ func InitTgBot(behavior string,
 		return nil, errors.Wrap(err, "failed to start telegram bot")
 	}
 
-	logger.Sugar().Debugf("telegram chat id for sending alerts is %d", chatID)
+	for _, chat := range chatsStorage.Chats() {
+		logger.Debug("telegram chat for sending alerts",
+			zap.Int64("chat", int64(chat.ChatID)), zap.Bool("read-only", chat.ReadOnly),
+		)
+	}
 
-	tgBotEnv := common.NewTelegramBotEnvironment(bot, chatID, false, logger, requestType, responsePairType, scheme)
+	tgBotEnv := common.NewTelegramBotEnvironment(bot, chatsStorage, alertMessages, logger,
+		requestType, responsePairType, scheme,
+	)
 	return tgBotEnv, nil
 }
 
 func InitDiscordBot(
 	botToken string,
 	chatID string,
+	chatsStorage *chats.Storage,
+	alertMessages *state.AlertMessages,
 	logger *zap.Logger,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
@@ -50,7 +64,19 @@ func InitDiscordBot(
 	}
 	logger.Sugar().Debugf("discord chat id for sending alerts is %s", chatID)
 
-	bot.Identify.Intents = discordgo.IntentsGuildMessages | discordgo.IntentsMessageContent
-	dscBotEnv := common.NewDiscordBotEnvironment(bot, chatID, logger, requestType, responsePairType, scheme)
+	id, err := strconv.ParseInt(chatID, 10, 64)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid discord chat id '%s'", chatID)
+	}
+	chat := chats.Settings{Chat: entities.Chat{ChatID: entities.ChatID(id), Platform: entities.DiscordPlatform}}
+	if _, addErr := chatsStorage.AddIfNew(chat); addErr != nil {
+		return nil, errors.Wrap(addErr, "failed to add discord chat")
+	}
+
+	// the commands are received as interactions, so the bot doesn't need to read the messages
+	bot.Identify.Intents = discordgo.IntentsGuilds
+	dscBotEnv := common.NewDiscordBotEnvironment(bot, chatID, chatsStorage, alertMessages, logger,
+		requestType, responsePairType, scheme,
+	)
 	return dscBotEnv, nil
 }
@@ -3,21 +3,36 @@ package messaging
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"nodemon/pkg/entities"
 	"nodemon/pkg/messaging/pair"
 
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
 const (
 	insufficientPermissionMsg = "Sorry, you have no right to add a new node"
 	incorrectURLMsg           = "Sorry, the url seems to be incorrect"
+	muteAlertWrongFormatMsg   = "Format: /%s <alert_id> [duration] or /%s <alert_name> <node> [duration]"
+	MaintenanceWrongFormatMsg = "Format: /maintenance <node> <duration>, e.g. /maintenance mynode 2h. " +
+		"Use 'off' as the duration to finish the maintenance"
+	TagWrongFormatMsg   = "Format: /tag <node> <tag> [tag...], e.g. /tag mynode our-validators public-api"
+	UntagWrongFormatMsg = "Format: /untag <node> <tag> [tag...], e.g. /untag mynode public-api"
+	GroupWrongFormatMsg = "Format: /%s <tag>, e.g. /%s our-validators. See the tags of the nodes in /pool"
+	ForkWrongFormatMsg  = "Format: /fork <alert_id>. See the IDs of the state hash alerts in /alerts"
 )
 
 var (
 	ErrInsufficientPermissions = errors.New("insufficient permissions")
 	ErrIncorrectURL            = errors.New("incorrect url")
+	ErrMuteAlertWrongFormat    = errors.New("wrong format of alert mute command")
+	ErrMaintenanceWrongFormat  = errors.New("wrong format of maintenance command")
+	ErrTagsWrongFormat         = errors.New("wrong format of tags command")
+	ErrForkWrongFormat         = errors.New("wrong format of fork command")
 )
 
 func AddNewNodeHandler(
@@ -154,3 +169,301 @@ func RequestNodeStatement(
 	}
 	return nodeStatementResp, nil
 }
+
+func RequestAlerts(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	historyLimit int,
+) (*pair.AlertsResponse, error) {
+	requestChan <- &pair.AlertsRequest{HistoryLimit: historyLimit}
+	response := <-responseChan
+	alertsResp, ok := response.(*pair.AlertsResponse)
+	if !ok {
+		return nil, errors.New("failed to convert response interface to the alerts type")
+	}
+	return alertsResp, nil
+}
+
+func RequestGenerators(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+) (*pair.GeneratorsResponse, error) {
+	requestChan <- &pair.GeneratorsRequest{}
+	response := <-responseChan
+	generatorsResp, ok := response.(*pair.GeneratorsResponse)
+	if !ok {
+		return nil, errors.New("failed to convert response interface to the generators type")
+	}
+	return generatorsResp, nil
+}
+
+// ParseForkAlertID parses the argument of the fork command, which has the format '<alert_id>'.
+func ParseForkAlertID(args []string) (crypto.Digest, error) {
+	if len(args) != 1 {
+		return crypto.Digest{}, ErrForkWrongFormat
+	}
+	id, err := crypto.NewDigestFromBase58(args[0])
+	if err != nil {
+		return crypto.Digest{}, errors.Wrap(ErrForkWrongFormat, "invalid alert ID")
+	}
+	return id, nil
+}
+
+func RequestForkReport(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	alertID crypto.Digest,
+) (*pair.ForkReportResponse, error) {
+	requestChan <- &pair.ForkReportRequest{AlertID: alertID}
+	response := <-responseChan
+	forkResp, ok := response.(*pair.ForkReportResponse)
+	if !ok {
+		return nil, errors.New("failed to convert response interface to the fork report type")
+	}
+	return forkResp, nil
+}
+
+func RequestNodesSummary(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	since time.Time,
+) (*pair.NodesSummaryResponse, error) {
+	requestChan <- &pair.NodesSummaryRequest{Since: since.Unix()}
+	response := <-responseChan
+	summaryResp, ok := response.(*pair.NodesSummaryResponse)
+	if !ok {
+		return nil, errors.New("failed to convert response interface to the nodes summary type")
+	}
+	return summaryResp, nil
+}
+
+// ParseAlertMute parses the arguments of the ack and silence commands, which have the next formats:
+// '<alert_id> [duration]' and '<alert_name> <node> [duration]'.
+func ParseAlertMute(kind entities.AlertMuteKind, args []string, now time.Time) (entities.AlertMute, error) {
+	const (
+		byIDMaxArgs   = 2
+		byNodeMinArgs = 2
+		byNodeMaxArgs = 3
+	)
+	if len(args) == 0 {
+		return entities.AlertMute{}, ErrMuteAlertWrongFormat
+	}
+	parseExpiresAt := func(rest []string) (int64, error) {
+		if len(rest) == 0 {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(rest[0])
+		if err != nil || d <= 0 {
+			return 0, ErrMuteAlertWrongFormat
+		}
+		return entities.ExpiresAfter(now, d), nil
+	}
+	alertName := entities.AlertName(args[0])
+	if _, ok := alertName.AlertType(); ok {
+		if len(args) < byNodeMinArgs || len(args) > byNodeMaxArgs {
+			return entities.AlertMute{}, ErrMuteAlertWrongFormat
+		}
+		node, err := entities.CheckAndUpdateURL(args[1])
+		if err != nil {
+			return entities.AlertMute{}, ErrIncorrectURL
+		}
+		expiresAt, err := parseExpiresAt(args[2:])
+		if err != nil {
+			return entities.AlertMute{}, err
+		}
+		return entities.NewAlertMuteByNode(kind, alertName, node, expiresAt), nil
+	}
+	if len(args) > byIDMaxArgs {
+		return entities.AlertMute{}, ErrMuteAlertWrongFormat
+	}
+	alertID, err := crypto.NewDigestFromBase58(args[0])
+	if err != nil {
+		return entities.AlertMute{}, ErrMuteAlertWrongFormat
+	}
+	expiresAt, err := parseExpiresAt(args[1:])
+	if err != nil {
+		return entities.AlertMute{}, err
+	}
+	return entities.NewAlertMuteByID(kind, alertID, expiresAt), nil
+}
+
+func MuteAlertWrongFormatMessage(kind entities.AlertMuteKind) string {
+	return fmt.Sprintf(muteAlertWrongFormatMsg, kind, kind)
+}
+
+func MuteAlertHandler(
+	chatID string,
+	bot Bot,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	mute entities.AlertMute,
+) (string, error) {
+	if !bot.IsEligibleForAction(chatID) {
+		return insufficientPermissionMsg, ErrInsufficientPermissions
+	}
+	requestChan <- &pair.MuteAlertRequest{Mute: mute}
+	response := <-responseChan
+	muteResp, ok := response.(*pair.MuteAlertResponse)
+	if !ok {
+		return "", errors.New("failed to convert response interface to the alert mute type")
+	}
+	if muteResp.ErrMessage != "" {
+		return fmt.Sprintf("Failed to %s the alert: %s", mute.Kind, muteResp.ErrMessage), nil
+	}
+	return MuteAlertMessage(muteResp.Mute), nil
+}
+
+func MuteAlertMessage(mute entities.AlertMute) string {
+	var target string
+	if mute.AlertID != nil {
+		target = fmt.Sprintf("Alert %s", mute.AlertID.String())
+	} else {
+		target = fmt.Sprintf("Alerts %s of node %s", mute.AlertName, mute.Node)
+	}
+	var verb string
+	switch mute.Kind {
+	case entities.AckAlertMuteKind:
+		verb = "acknowledged"
+	case entities.SilenceAlertMuteKind:
+		verb = "silenced"
+	}
+	if mute.ExpiresAt == 0 {
+		return fmt.Sprintf("%s has been %s", target, verb)
+	}
+	expiresAt := time.Unix(mute.ExpiresAt, 0).UTC().Format(time.DateTime)
+	return fmt.Sprintf("%s has been %s until %s UTC", target, verb, expiresAt)
+}
+
+// ParseNodeMaintenance parses the arguments of the maintenance command, which has the format '<node> <duration>'.
+// The node can be either a URL or an alias. Nil window is returned if the duration is 'off'.
+func ParseNodeMaintenance(args []string, now time.Time) (string, *entities.MaintenanceWindow, error) {
+	const maintenanceArgs = 2
+	if len(args) != maintenanceArgs {
+		return "", nil, ErrMaintenanceWrongFormat
+	}
+	node, rawDuration := args[0], args[1]
+	if rawDuration == "off" {
+		return node, nil, nil
+	}
+	d, err := time.ParseDuration(rawDuration)
+	if err != nil || d < time.Second {
+		return "", nil, ErrMaintenanceWrongFormat
+	}
+	start := now.Unix()
+	return node, &entities.MaintenanceWindow{Start: start, End: now.Add(d).Unix()}, nil
+}
+
+func NodeMaintenanceHandler(
+	chatID string,
+	bot Bot,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	node string,
+	window *entities.MaintenanceWindow,
+) (string, error) {
+	if !bot.IsEligibleForAction(chatID) {
+		return insufficientPermissionMsg, ErrInsufficientPermissions
+	}
+	nodes, err := RequestAllNodes(requestChan, responseChan)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request nodes list")
+	}
+	for _, n := range nodes {
+		if n.Alias != "" && n.Alias == node {
+			node = n.URL
+			break
+		}
+	}
+	url, err := entities.CheckAndUpdateURL(node)
+	if err != nil {
+		return incorrectURLMsg, ErrIncorrectURL
+	}
+	requestChan <- &pair.NodeMaintenanceRequest{URL: url, Window: window}
+	response := <-responseChan
+	maintenanceResp, ok := response.(*pair.NodeMaintenanceResponse)
+	if !ok {
+		return "", errors.New("failed to convert response interface to the node maintenance type")
+	}
+	if maintenanceResp.ErrMessage != "" {
+		return fmt.Sprintf("Failed to set maintenance of node %s: %s", url, maintenanceResp.ErrMessage), nil
+	}
+	return NodeMaintenanceMessage(url, window), nil
+}
+
+func NodeMaintenanceMessage(url string, window *entities.MaintenanceWindow) string {
+	if window == nil {
+		return fmt.Sprintf("Maintenance of node %s has been finished", url)
+	}
+	end := time.Unix(window.End, 0).UTC().Format(time.DateTime)
+	return fmt.Sprintf("Node %s is under maintenance until %s UTC, its alerts are suppressed", url, end)
+}
+
+// ParseNodeTags parses the arguments of the tag commands, which have the format '<node> <tag> [tag...]'.
+// The node can be either a URL or an alias.
+func ParseNodeTags(args []string) (string, []string, error) {
+	const minArgs = 2 // node and at least one tag
+	if len(args) < minArgs {
+		return "", nil, ErrTagsWrongFormat
+	}
+	tags, err := entities.NormalizeTags(args[1:])
+	if err != nil {
+		return "", nil, errors.Wrap(ErrTagsWrongFormat, err.Error())
+	}
+	return args[0], tags, nil
+}
+
+// NodeTagsHandler adds the tags to the node or removes them from it.
+func NodeTagsHandler(
+	chatID string,
+	bot Bot,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	node string,
+	tags []string,
+	remove bool,
+) (string, error) {
+	if !bot.IsEligibleForAction(chatID) {
+		return insufficientPermissionMsg, ErrInsufficientPermissions
+	}
+	nodes, err := RequestAllNodes(requestChan, responseChan)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request nodes list")
+	}
+	for _, n := range nodes {
+		if n.Alias != "" && n.Alias == node {
+			node = n.URL
+			break
+		}
+	}
+	url, err := entities.CheckAndUpdateURL(node)
+	if err != nil {
+		return incorrectURLMsg, ErrIncorrectURL
+	}
+	i := slices.IndexFunc(nodes, func(n entities.Node) bool { return n.URL == url })
+	if i == -1 {
+		return fmt.Sprintf("Node %s is not monitored", url), nil
+	}
+	newTags := slices.Clone(nodes[i].Tags)
+	if remove {
+		newTags = slices.DeleteFunc(newTags, func(tag string) bool { return slices.Contains(tags, tag) })
+	} else {
+		newTags = append(newTags, tags...)
+	}
+	requestChan <- &pair.NodeTagsRequest{URL: url, Tags: newTags}
+	response := <-responseChan
+	tagsResp, ok := response.(*pair.NodeTagsResponse)
+	if !ok {
+		return "", errors.New("failed to convert response interface to the node tags type")
+	}
+	if tagsResp.ErrMessage != "" {
+		return fmt.Sprintf("Failed to set tags of node %s: %s", url, tagsResp.ErrMessage), nil
+	}
+	return NodeTagsMessage(url, tagsResp.Tags), nil
+}
+
+func NodeTagsMessage(url string, tags []string) string {
+	if len(tags) == 0 {
+		return fmt.Sprintf("Node %s has no tags", url)
+	}
+	return fmt.Sprintf("Tags of node %s: %s", url, strings.Join(tags, ", "))
+}
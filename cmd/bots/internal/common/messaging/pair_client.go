@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -100,6 +101,22 @@ func handlePairRequest(
 		return handleNodesStatementsRequest(ctx, r.URLs, message, nc, logger, responsePair, botRequestsTopic)
 	case *pair.NodeStatementRequest:
 		return handleNodesStatementRequest(ctx, r.URL, r.Height, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.AlertsRequest:
+		return handleAlertsRequest(ctx, r.HistoryLimit, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.MuteAlertRequest:
+		return handleMuteAlertRequest(ctx, r.Mute, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.NodeMaintenanceRequest:
+		node := entities.Node{URL: r.URL, Maintenance: r.Window}
+		return handleNodeMaintenanceRequest(ctx, node, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.GeneratorsRequest:
+		return handleGeneratorsRequest(ctx, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.NodesSummaryRequest:
+		return handleNodesSummaryRequest(ctx, r.Since, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.NodeTagsRequest:
+		node := entities.Node{URL: r.URL, Tags: r.Tags}
+		return handleNodeTagsRequest(ctx, node, logger, message, nc, responsePair, botRequestsTopic)
+	case *pair.ForkReportRequest:
+		return handleForkReportRequest(ctx, r.AlertID.String(), logger, message, nc, responsePair, botRequestsTopic)
 	default:
 		return errors.New("unknown request type to pair socket")
 	}
@@ -148,6 +165,231 @@ func handleNodesStatementRequest(
 	}
 }
 
+func handleAlertsRequest(
+	ctx context.Context,
+	historyLimit int,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	message.WriteString(strconv.Itoa(historyLimit))
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	alertsResp := pair.AlertsResponse{}
+	err = json.Unmarshal(response.Data, &alertsResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &alertsResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send alerts response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("alerts-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
+func handleMuteAlertRequest(
+	ctx context.Context,
+	mute entities.AlertMute,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	req, err := json.Marshal(mute)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message to pair socket")
+	}
+	message.Write(req)
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	muteResp := pair.MuteAlertResponse{}
+	err = json.Unmarshal(response.Data, &muteResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &muteResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send alert mute response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("alert-mute-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
+func handleNodeMaintenanceRequest(
+	ctx context.Context,
+	node entities.Node,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	req, err := json.Marshal(node)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message to pair socket")
+	}
+	message.Write(req)
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	maintenanceResp := pair.NodeMaintenanceResponse{}
+	err = json.Unmarshal(response.Data, &maintenanceResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &maintenanceResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send node maintenance response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("node-maintenance-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
+func handleNodeTagsRequest(
+	ctx context.Context,
+	node entities.Node,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	req, err := json.Marshal(node)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal message to pair socket")
+	}
+	message.Write(req)
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	tagsResp := pair.NodeTagsResponse{}
+	err = json.Unmarshal(response.Data, &tagsResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &tagsResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send node tags response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("node-tags-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
+func handleForkReportRequest(
+	ctx context.Context,
+	alertID string,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	message.WriteString(alertID)
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	forkResp := pair.ForkReportResponse{}
+	err = json.Unmarshal(response.Data, &forkResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &forkResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send fork report response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("fork-report-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
+func handleGeneratorsRequest(
+	ctx context.Context,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	generatorsResp := pair.GeneratorsResponse{}
+	err = json.Unmarshal(response.Data, &generatorsResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &generatorsResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send generators response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("generators-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
+
 func handleNodesStatementsRequest(
 	ctx context.Context,
 	urls []string,
@@ -251,3 +493,38 @@ func handleNodesListRequest(
 		return ctx.Err()
 	}
 }
+
+func handleNodesSummaryRequest(
+	ctx context.Context,
+	since int64,
+	logger *zap.Logger,
+	message *bytes.Buffer,
+	nc *nats.Conn,
+	responsePair chan<- pair.Response,
+	botRequestsTopic string,
+) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultResponseTimeout)
+	defer cancel()
+
+	message.WriteString(strconv.FormatInt(since, 10))
+	response, err := nc.Request(botRequestsTopic, message.Bytes(), defaultResponseTimeout)
+	if err != nil {
+		return errors.Wrap(err, "failed to receive message from nodemon")
+	}
+	summaryResp := pair.NodesSummaryResponse{}
+	err = json.Unmarshal(response.Data, &summaryResp)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal message from pair socket")
+	}
+	select {
+	case responsePair <- &summaryResp:
+		return nil
+	case <-ctx.Done():
+		logger.Error("failed to send nodes summary response, timeout exceeded",
+			zap.Duration("timeout", defaultResponseTimeout),
+			zap.ByteString("nodes-summary-response", response.Data),
+			zap.Error(ctx.Err()),
+		)
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,201 @@
+// Package rbac implements the role-based access control of the bot commands. Every user has a role which is looked up
+// by the user ID or, in Discord, by the IDs of the user's server roles. The privileged commands are audit-logged.
+package rbac
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"gopkg.in/yaml.v3"
+)
+
+// Role defines the commands available to the user, every next role includes the commands of the previous one.
+type Role byte
+
+const (
+	// NoneRole denies all commands.
+	NoneRole Role = iota
+	// ViewerRole allows the commands which only view the monitoring state, e.g. /status.
+	ViewerRole
+	// OperatorRole allows the commands which handle the alerts, e.g. /ack or /mute.
+	OperatorRole
+	// AdminRole allows the commands which change the monitoring configuration, e.g. /add or /remove.
+	AdminRole
+)
+
+func ParseRole(s string) (Role, error) {
+	switch strings.ToLower(s) {
+	case "none":
+		return NoneRole, nil
+	case "viewer":
+		return ViewerRole, nil
+	case "operator":
+		return OperatorRole, nil
+	case "admin":
+		return AdminRole, nil
+	default:
+		return NoneRole, errors.Errorf("unknown role '%s'", s)
+	}
+}
+
+func (r Role) String() string {
+	switch r {
+	case NoneRole:
+		return "none"
+	case ViewerRole:
+		return "viewer"
+	case OperatorRole:
+		return "operator"
+	case AdminRole:
+		return "admin"
+	default:
+		return "unknown"
+	}
+}
+
+// Allows reports whether the role includes the required one.
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+func (r Role) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Role) UnmarshalText(text []byte) error {
+	role, err := ParseRole(string(text))
+	if err != nil {
+		return err
+	}
+	*r = role
+	return nil
+}
+
+// Config maps the users to their roles.
+type Config struct {
+	// DefaultRole is the role of the users which aren't listed in the config.
+	DefaultRole Role `yaml:"default_role"`
+	Telegram    struct {
+		Users map[int64]Role `yaml:"users"`
+	} `yaml:"telegram"`
+	Discord struct {
+		Users map[string]Role `yaml:"users"`
+		// Roles maps the IDs of Discord server roles to the bot roles.
+		Roles map[string]Role `yaml:"roles"`
+	} `yaml:"discord"`
+}
+
+// AllowAllConfig gives the admin role to everyone, it's used if the access control isn't configured.
+func AllowAllConfig() Config {
+	return Config{DefaultRole: AdminRole}
+}
+
+// LoadConfig reads the access control configuration file in YAML or JSON format. If the default role isn't set,
+// it's the viewer role.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return Config{}, errors.Wrapf(err, "failed to read access control config file '%s'", path)
+	}
+	cfg := Config{DefaultRole: ViewerRole}
+	dec := yaml.NewDecoder(bytes.NewReader(data)) // JSON is a subset of YAML
+	dec.KnownFields(true)
+	if decErr := dec.Decode(&cfg); decErr != nil && !errors.Is(decErr, io.EOF) { // EOF means empty file
+		return Config{}, errors.Wrapf(decErr, "failed to parse access control config file '%s'", path)
+	}
+	return cfg, nil
+}
+
+// Platform is the messenger which the command has been received from.
+type Platform string
+
+const (
+	Telegram Platform = "telegram"
+	Discord  Platform = "discord"
+)
+
+// Action is the command run by the user.
+type Action struct {
+	Platform Platform
+	ChatID   string
+	UserID   string
+	UserRole Role
+	Command  string
+	Args     []string
+}
+
+// Authorizer checks the access to the commands and writes the audit log.
+type Authorizer struct {
+	cfg   Config
+	audit *zap.Logger
+}
+
+func NewAuthorizer(cfg Config, logger *zap.Logger) *Authorizer {
+	return &Authorizer{cfg: cfg, audit: logger.Named("audit")}
+}
+
+// TelegramRole returns the role of the Telegram user.
+func (a *Authorizer) TelegramRole(userID int64) Role {
+	if role, ok := a.cfg.Telegram.Users[userID]; ok {
+		return role
+	}
+	return a.cfg.DefaultRole
+}
+
+// DiscordRole returns the role of the Discord user, it's the highest of the roles given to the user
+// and to the user's server roles.
+func (a *Authorizer) DiscordRole(userID string, roleIDs []string) Role {
+	role, found := a.cfg.Discord.Users[userID]
+	for _, id := range roleIDs {
+		if r, ok := a.cfg.Discord.Roles[id]; ok && (!found || r > role) {
+			role, found = r, true
+		}
+	}
+	if !found {
+		return a.cfg.DefaultRole
+	}
+	return role
+}
+
+// Authorize reports whether the user's role allows the command. The privileged commands are audit-logged
+// whether they are allowed or not.
+func (a *Authorizer) Authorize(act Action) bool {
+	required := RequiredRole(act.Command)
+	allowed := act.UserRole.Allows(required)
+	if required > ViewerRole || !allowed {
+		fields := []zap.Field{
+			zap.String("platform", string(act.Platform)),
+			zap.String("chat", act.ChatID),
+			zap.String("user", act.UserID),
+			zap.Stringer("role", act.UserRole),
+			zap.String("command", act.Command),
+			zap.Strings("args", act.Args),
+			zap.Stringer("required", required),
+			zap.Bool("allowed", allowed),
+		}
+		if allowed {
+			a.audit.Info("Command authorized", fields...)
+		} else {
+			a.audit.Warn("Command denied", fields...)
+		}
+	}
+	return allowed
+}
+
+// RequiredRole returns the role required to run the command. Unknown commands require the viewer role.
+func RequiredRole(command string) Role {
+	switch command {
+	case "/add", "/add_specific", "/remove", "/add_alias", "/tag", "/untag":
+		return AdminRole
+	case "/mute", "/start", "/subscribe", "/unsubscribe", "/ack", "/silence", "/maintenance",
+		"/add_report", "/remove_report", "/subscribe_group", "/unsubscribe_group":
+		return OperatorRole
+	default:
+		return ViewerRole
+	}
+}
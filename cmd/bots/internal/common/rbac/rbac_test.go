@@ -0,0 +1,103 @@
+package rbac_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"nodemon/cmd/bots/internal/common/rbac"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+const testConfig = `
+default_role: none
+telegram:
+  users:
+    100: admin
+    200: viewer
+discord:
+  users:
+    "300": operator
+  roles:
+    "10": viewer
+    "20": admin
+`
+
+func loadTestConfig(t *testing.T, data string) rbac.Config {
+	path := filepath.Join(t.TempDir(), "rbac.yml")
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	cfg, err := rbac.LoadConfig(path)
+	require.NoError(t, err)
+	return cfg
+}
+
+func TestAuthorizer_Roles(t *testing.T) {
+	a := rbac.NewAuthorizer(loadTestConfig(t, testConfig), zap.NewNop())
+
+	assert.Equal(t, rbac.AdminRole, a.TelegramRole(100))
+	assert.Equal(t, rbac.ViewerRole, a.TelegramRole(200))
+	assert.Equal(t, rbac.NoneRole, a.TelegramRole(300))
+
+	assert.Equal(t, rbac.OperatorRole, a.DiscordRole("300", nil))
+	assert.Equal(t, rbac.OperatorRole, a.DiscordRole("300", []string{"10"}), "the highest role is used")
+	assert.Equal(t, rbac.AdminRole, a.DiscordRole("300", []string{"10", "20"}))
+	assert.Equal(t, rbac.ViewerRole, a.DiscordRole("400", []string{"10", "30"}))
+	assert.Equal(t, rbac.NoneRole, a.DiscordRole("400", nil))
+}
+
+func TestLoadConfig_Defaults(t *testing.T) {
+	cfg := loadTestConfig(t, "telegram:\n  users:\n    1: operator\n")
+	assert.Equal(t, rbac.ViewerRole, cfg.DefaultRole)
+
+	path := filepath.Join(t.TempDir(), "rbac.yml")
+	require.NoError(t, os.WriteFile(path, []byte("default_role: superuser\n"), 0600))
+	_, err := rbac.LoadConfig(path)
+	assert.ErrorContains(t, err, "unknown role 'superuser'")
+}
+
+func TestRequiredRole(t *testing.T) {
+	assert.Equal(t, rbac.AdminRole, rbac.RequiredRole("/remove"))
+	assert.Equal(t, rbac.OperatorRole, rbac.RequiredRole("/mute"))
+	assert.Equal(t, rbac.OperatorRole, rbac.RequiredRole("/add_report"))
+	assert.Equal(t, rbac.AdminRole, rbac.RequiredRole("/tag"))
+	assert.Equal(t, rbac.OperatorRole, rbac.RequiredRole("/subscribe_group"))
+	assert.Equal(t, rbac.ViewerRole, rbac.RequiredRole("/reports"))
+	assert.Equal(t, rbac.ViewerRole, rbac.RequiredRole("/status"))
+	assert.Equal(t, rbac.ViewerRole, rbac.RequiredRole("/unknown"))
+}
+
+func TestAuthorizer_Audit(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	a := rbac.NewAuthorizer(rbac.Config{DefaultRole: rbac.OperatorRole}, zap.New(core))
+	action := func(command string) rbac.Action {
+		return rbac.Action{
+			Platform: rbac.Telegram,
+			ChatID:   "1",
+			UserID:   "2",
+			UserRole: a.TelegramRole(2),
+			Command:  command,
+			Args:     []string{"arg"},
+		}
+	}
+
+	assert.True(t, a.Authorize(action("/status")))
+	assert.Zero(t, logs.Len(), "the view commands aren't audit-logged")
+
+	assert.True(t, a.Authorize(action("/ack")))
+	assert.False(t, a.Authorize(action("/remove")))
+	entries := logs.TakeAll()
+	require.Len(t, entries, 2)
+	assert.Equal(t, "audit", entries[0].LoggerName)
+	assert.Equal(t, zapcore.InfoLevel, entries[0].Level)
+	assert.Equal(t, "/ack", entries[0].ContextMap()["command"])
+	assert.Equal(t, true, entries[0].ContextMap()["allowed"])
+	assert.Equal(t, zapcore.WarnLevel, entries[1].Level)
+	assert.Equal(t, "/remove", entries[1].ContextMap()["command"])
+	assert.Equal(t, "operator", entries[1].ContextMap()["role"])
+	assert.Equal(t, false, entries[1].ContextMap()["allowed"])
+}
@@ -0,0 +1,272 @@
+// Package reports keeps the scheduled reports of the bot chats and runs them by their cron expressions.
+package reports
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"nodemon/cmd/bots/internal/common/state"
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrReportNotFound  = errors.New("report not found")
+	ErrUnknownKind     = errors.New("unknown report kind")
+	ErrAddWrongFormat  = errors.New("wrong format of add report command")
+	ErrInvalidReportID = errors.New("invalid report id")
+)
+
+const (
+	AddWrongFormatMsg = "Format: /add_report <status|summary|chains> <cron>, e.g. /add_report summary 0 0 9 * * MON. " +
+		"The cron expression has six fields: seconds, minutes, hours, day of month, month and day of week"
+	RemoveWrongFormatMsg = "Format: /remove_report <id>, see the IDs in /reports"
+)
+
+// DailyStatusCron is the schedule of the status report which every chat has by default, 12:00 UTC+3.
+const DailyStatusCron = "0 0 9 * * *"
+
+type Kind string
+
+const (
+	// StatusKind is the short status of all nodes, the same as the /status command shows.
+	StatusKind Kind = "status"
+	// SummaryKind is the uptime and the number of alerts of every node for the last week.
+	SummaryKind Kind = "summary"
+	// ChainsKind is the chains of the nodes, the same as the /viewchains command shows.
+	ChainsKind Kind = "chains"
+)
+
+// Kinds returns all report kinds.
+func Kinds() []Kind {
+	return []Kind{StatusKind, SummaryKind, ChainsKind}
+}
+
+func ParseKind(s string) (Kind, error) {
+	kind := Kind(strings.ToLower(strings.TrimSpace(s)))
+	switch kind {
+	case StatusKind, SummaryKind, ChainsKind:
+		return kind, nil
+	default:
+		return "", errors.Wrapf(ErrUnknownKind, "'%s'", s)
+	}
+}
+
+// ParseAddArgs parses the arguments of the add report command, which has the format '<kind> <cron>'.
+// The cron expression may be split into several arguments.
+func ParseAddArgs(args []string) (Kind, string, error) {
+	const minArgs = 2 // kind and at least one cron field
+	if len(args) < minArgs {
+		return "", "", ErrAddWrongFormat
+	}
+	kind, err := ParseKind(args[0])
+	if err != nil {
+		return "", "", errors.Wrap(ErrAddWrongFormat, err.Error())
+	}
+	return kind, strings.Join(args[1:], " "), nil
+}
+
+func ParseID(s string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || id <= 0 {
+		return 0, errors.Wrapf(ErrInvalidReportID, "'%s'", s)
+	}
+	return id, nil
+}
+
+// Report is sent to the chat on the schedule given by the cron expression with seconds,
+// e.g. "0 0 9 * * MON" means every Monday at 09:00 of the bot time zone.
+type Report struct {
+	ID   int             `json:"id"`
+	Chat entities.ChatID `json:"chat"`
+	Kind Kind            `json:"kind"`
+	Cron string          `json:"cron"`
+}
+
+type dbStruct struct {
+	NextID  int      `json:"nextId"`
+	Reports []Report `json:"reports"`
+	// DefaultedChats are the chats which have got the default reports, nil in the files of the older versions.
+	DefaultedChats []entities.ChatID `json:"defaultedChats"`
+}
+
+// Storage keeps the reports in memory and writes them to the file on every change.
+// If the file path is empty, the reports are kept only in memory.
+type Storage struct {
+	mu        *sync.RWMutex
+	path      string
+	nextID    int
+	reports   map[int]Report
+	defaulted map[entities.ChatID]struct{} // chats which have got the default reports
+}
+
+// NewStorage loads the reports from the file. The default reports of a chat are added only once, the first time
+// the chat is seen, so the chats added later get them too, and the reports removed by the users don't come back
+// after the restart.
+func NewStorage(path string, defaults ...Report) (*Storage, error) {
+	s := &Storage{
+		mu:        new(sync.RWMutex),
+		nextID:    1,
+		reports:   make(map[int]Report),
+		defaulted: make(map[entities.ChatID]struct{}),
+	}
+	if path != "" {
+		s.path = filepath.Clean(path)
+		data, err := os.ReadFile(s.path)
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			// defaults are added below
+		case err != nil:
+			return nil, errors.Wrapf(err, "failed to read reports file '%s'", s.path)
+		default:
+			if loadErr := s.load(data); loadErr != nil {
+				return nil, loadErr
+			}
+		}
+	}
+	if err := s.addDefaults(defaults); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// addDefaults adds the default reports of the chats which haven't got them yet.
+func (s *Storage) addDefaults(defaults []Report) error {
+	gets := make(map[entities.ChatID]bool) // whether the chat gets the defaults now
+	for _, r := range defaults {
+		add, ok := gets[r.Chat]
+		if !ok {
+			_, done := s.defaulted[r.Chat]
+			add, gets[r.Chat] = !done, !done
+			s.defaulted[r.Chat] = struct{}{} // it's synced with the report
+		}
+		if !add {
+			continue
+		}
+		if _, err := s.Add(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Storage) load(data []byte) error {
+	var db dbStruct
+	if err := json.Unmarshal(data, &db); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal reports file '%s'", s.path)
+	}
+	for _, r := range db.Reports {
+		if _, ok := s.reports[r.ID]; ok {
+			return errors.Errorf("duplicate report %d in reports file '%s'", r.ID, s.path)
+		}
+		if _, err := ParseKind(string(r.Kind)); err != nil {
+			return errors.Wrapf(err, "invalid report %d in reports file '%s'", r.ID, s.path)
+		}
+		s.reports[r.ID] = r
+		s.nextID = max(s.nextID, r.ID+1)
+	}
+	s.nextID = max(s.nextID, db.NextID)
+	if db.DefaultedChats == nil {
+		// the older versions added the defaults once per file, so the chats with reports have got them
+		for _, r := range db.Reports {
+			s.defaulted[r.Chat] = struct{}{}
+		}
+	}
+	for _, chat := range db.DefaultedChats {
+		s.defaulted[chat] = struct{}{}
+	}
+	return nil
+}
+
+// Add stores the report with the new ID and returns it.
+func (s *Storage) Add(r Report) (Report, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r.ID = s.nextID
+	s.reports[r.ID] = r
+	s.nextID++
+	if err := s.sync(); err != nil {
+		delete(s.reports, r.ID)
+		s.nextID--
+		return Report{}, err
+	}
+	return r, nil
+}
+
+// Remove deletes the report of the chat.
+func (s *Storage) Remove(chat entities.ChatID, id int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.reports[id]
+	if !ok || r.Chat != chat {
+		return errors.Wrapf(ErrReportNotFound, "failed to remove report %d", id)
+	}
+	delete(s.reports, id)
+	if err := s.sync(); err != nil {
+		s.reports[id] = r
+		return err
+	}
+	return nil
+}
+
+// List returns the reports of the chat ordered by ID.
+func (s *Storage) List(chat entities.ChatID) []Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Report, 0)
+	for _, r := range s.reports {
+		if r.Chat == chat {
+			out = append(out, r)
+		}
+	}
+	sortByID(out)
+	return out
+}
+
+// All returns the reports of all chats ordered by ID.
+func (s *Storage) All() []Report {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	out := make([]Report, 0, len(s.reports))
+	for _, r := range s.reports {
+		out = append(out, r)
+	}
+	sortByID(out)
+	return out
+}
+
+// sync writes the reports to the file, it must be called under the write lock.
+func (s *Storage) sync() error {
+	if s.path == "" {
+		return nil
+	}
+	db := dbStruct{
+		NextID:         s.nextID,
+		Reports:        make([]Report, 0, len(s.reports)),
+		DefaultedChats: make([]entities.ChatID, 0, len(s.defaulted)),
+	}
+	for _, r := range s.reports {
+		db.Reports = append(db.Reports, r)
+	}
+	sortByID(db.Reports)
+	for chat := range s.defaulted {
+		db.DefaultedChats = append(db.DefaultedChats, chat)
+	}
+	slices.Sort(db.DefaultedChats)
+	data, err := json.MarshalIndent(db, "", " ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal reports")
+	}
+	return errors.Wrap(state.WriteFile(s.path, data), "failed to sync reports file")
+}
+
+func sortByID(reports []Report) {
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+}
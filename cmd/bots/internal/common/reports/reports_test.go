@@ -0,0 +1,158 @@
+package reports_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nodemon/cmd/bots/internal/common/reports"
+	"nodemon/pkg/entities"
+
+	"codnect.io/chrono"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestParseKind(t *testing.T) {
+	for _, kind := range reports.Kinds() {
+		parsed, err := reports.ParseKind(" " + strings.ToUpper(string(kind)))
+		require.NoError(t, err)
+		assert.Equal(t, kind, parsed)
+	}
+	_, err := reports.ParseKind("weekly")
+	assert.ErrorIs(t, err, reports.ErrUnknownKind)
+}
+
+func TestStorage_Persistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports.json")
+	daily := reports.Report{Chat: 1, Kind: reports.StatusKind, Cron: reports.DailyStatusCron}
+	storage, err := reports.NewStorage(path, daily)
+	require.NoError(t, err)
+	expectedDaily := daily
+	expectedDaily.ID = 1
+	assert.Equal(t, []reports.Report{expectedDaily}, storage.All(), "defaults must be added to the new file")
+
+	weekly, err := storage.Add(reports.Report{Chat: 2, Kind: reports.SummaryKind, Cron: "0 0 9 * * MON"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, weekly.ID)
+	assert.Equal(t, []reports.Report{weekly}, storage.List(2))
+
+	assert.ErrorIs(t, storage.Remove(2, expectedDaily.ID), reports.ErrReportNotFound,
+		"the report of another chat must not be removed",
+	)
+	require.NoError(t, storage.Remove(1, expectedDaily.ID))
+
+	reloaded, err := reports.NewStorage(path, daily)
+	require.NoError(t, err)
+	assert.Equal(t, []reports.Report{weekly}, reloaded.All(), "defaults must not be added to the existing file")
+	chains, err := reloaded.Add(reports.Report{Chat: 1, Kind: reports.ChainsKind, Cron: "0 0 * * * *"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, chains.ID, "the IDs of the removed reports must not be reused")
+}
+
+type fakeTask struct{ cancelled bool }
+
+func (t *fakeTask) Cancel()           { t.cancelled = true }
+func (t *fakeTask) IsCancelled() bool { return t.cancelled }
+
+// fakeScheduler accepts only the cron expressions with six fields and keeps the scheduled tasks.
+type fakeScheduler struct {
+	chrono.TaskScheduler
+	tasks map[string]chrono.Task
+	runs  map[string]*fakeTask
+}
+
+func (s *fakeScheduler) ScheduleWithCron(
+	task chrono.Task,
+	expr string,
+	_ ...chrono.Option,
+) (chrono.ScheduledTask, error) {
+	const cronFields = 6
+	if len(strings.Fields(expr)) != cronFields {
+		return nil, errors.New("cron expression must consist of 6 fields")
+	}
+	st := new(fakeTask)
+	s.tasks[expr] = task
+	s.runs[expr] = st
+	return st, nil
+}
+
+func TestStorage_DefaultsPerChat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports.json")
+	daily := func(chat entities.ChatID) reports.Report {
+		return reports.Report{Chat: chat, Kind: reports.StatusKind, Cron: reports.DailyStatusCron}
+	}
+	storage, err := reports.NewStorage(path, daily(1))
+	require.NoError(t, err)
+	require.Len(t, storage.List(1), 1)
+	require.NoError(t, storage.Remove(1, storage.List(1)[0].ID))
+
+	reloaded, err := reports.NewStorage(path, daily(1), daily(2))
+	require.NoError(t, err)
+	assert.Empty(t, reloaded.List(1), "the removed default report must not come back")
+	require.Len(t, reloaded.List(2), 1, "the chat added later must get the default report")
+	assert.Equal(t, reports.DailyStatusCron, reloaded.List(2)[0].Cron)
+
+	reloaded, err = reports.NewStorage(path, daily(1), daily(2))
+	require.NoError(t, err)
+	assert.Len(t, reloaded.All(), 1, "the defaults must be added once per chat")
+}
+
+func TestStorage_DefaultsOfOlderFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reports.json")
+	older := `{"nextId": 2, "reports": [{"id": 1, "chat": 1, "kind": "summary", "cron": "0 0 9 * * MON"}]}`
+	require.NoError(t, os.WriteFile(path, []byte(older), 0o600))
+	daily := func(chat entities.ChatID) reports.Report {
+		return reports.Report{Chat: chat, Kind: reports.StatusKind, Cron: reports.DailyStatusCron}
+	}
+	storage, err := reports.NewStorage(path, daily(1), daily(2))
+	require.NoError(t, err)
+	assert.Len(t, storage.List(1), 1, "the chat with reports has got the defaults before")
+	assert.Len(t, storage.List(2), 1)
+}
+
+func TestManager(t *testing.T) {
+	storage, err := reports.NewStorage("", reports.Report{Chat: 1, Kind: reports.StatusKind, Cron: "0 0 9 * * *"})
+	require.NoError(t, err)
+	scheduler := &fakeScheduler{tasks: make(map[string]chrono.Task), runs: make(map[string]*fakeTask)}
+	var sent []reports.Report
+	run := func(_ context.Context, r reports.Report) { sent = append(sent, r) }
+	manager := reports.NewManager(storage, scheduler, run, zap.NewNop())
+	manager.Start()
+	require.Contains(t, scheduler.tasks, "0 0 9 * * *")
+
+	_, err = manager.Add(1, reports.SummaryKind, "0 0 9 MON")
+	require.ErrorIs(t, err, reports.ErrInvalidCron)
+	assert.Len(t, manager.List(1), 1, "the report with invalid cron must not be stored")
+
+	weekly, err := manager.Add(1, reports.SummaryKind, "0 0 9 * * MON")
+	require.NoError(t, err)
+	scheduler.tasks["0 0 9 * * MON"](context.Background())
+	assert.Equal(t, []reports.Report{weekly}, sent)
+
+	require.NoError(t, manager.Remove(1, weekly.ID))
+	assert.True(t, scheduler.runs["0 0 9 * * MON"].IsCancelled())
+	assert.ErrorIs(t, manager.Remove(1, weekly.ID), reports.ErrReportNotFound)
+}
+
+func TestParseAddArgs(t *testing.T) {
+	kind, cron, err := reports.ParseAddArgs([]string{"summary", "0", "0", "9", "*", "*", "MON"})
+	require.NoError(t, err)
+	assert.Equal(t, reports.SummaryKind, kind)
+	assert.Equal(t, "0 0 9 * * MON", cron)
+
+	_, _, err = reports.ParseAddArgs([]string{"summary"})
+	require.ErrorIs(t, err, reports.ErrAddWrongFormat)
+	_, _, err = reports.ParseAddArgs([]string{"weekly", "0 0 9 * * MON"})
+	require.ErrorIs(t, err, reports.ErrAddWrongFormat)
+
+	id, err := reports.ParseID("3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, id)
+	_, err = reports.ParseID("-1")
+	require.ErrorIs(t, err, reports.ErrInvalidReportID)
+}
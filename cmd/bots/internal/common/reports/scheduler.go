@@ -0,0 +1,98 @@
+package reports
+
+import (
+	"context"
+	"sync"
+
+	"nodemon/pkg/entities"
+
+	"codnect.io/chrono"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var ErrInvalidCron = errors.New("invalid cron expression")
+
+// RunFunc builds the report and sends it to the chat of the report.
+type RunFunc func(ctx context.Context, r Report)
+
+// Manager schedules the stored reports and keeps the schedules in sync with the storage.
+type Manager struct {
+	mu        *sync.Mutex
+	storage   *Storage
+	scheduler chrono.TaskScheduler
+	run       RunFunc
+	tasks     map[int]chrono.ScheduledTask
+	zap       *zap.Logger
+}
+
+func NewManager(storage *Storage, scheduler chrono.TaskScheduler, run RunFunc, logger *zap.Logger) *Manager {
+	return &Manager{
+		mu:        new(sync.Mutex),
+		storage:   storage,
+		scheduler: scheduler,
+		run:       run,
+		tasks:     make(map[int]chrono.ScheduledTask),
+		zap:       logger,
+	}
+}
+
+// Start schedules all stored reports. The reports with invalid cron expressions are skipped and logged,
+// so the broken file entry doesn't prevent the bot from starting.
+func (m *Manager) Start() {
+	for _, r := range m.storage.All() {
+		if err := m.schedule(r); err != nil {
+			m.zap.Error("Failed to schedule report", zap.Int("id", r.ID), zap.String("cron", r.Cron), zap.Error(err))
+			continue
+		}
+		m.zap.Info("Report has been scheduled",
+			zap.Int("id", r.ID), zap.Int64("chat", int64(r.Chat)),
+			zap.String("kind", string(r.Kind)), zap.String("cron", r.Cron),
+		)
+	}
+}
+
+// List returns the reports of the chat ordered by ID.
+func (m *Manager) List(chat entities.ChatID) []Report {
+	return m.storage.List(chat)
+}
+
+// Add schedules the new report of the chat and stores it.
+func (m *Manager) Add(chat entities.ChatID, kind Kind, cron string) (Report, error) {
+	r, err := m.storage.Add(Report{Chat: chat, Kind: kind, Cron: cron})
+	if err != nil {
+		return Report{}, err
+	}
+	if scheduleErr := m.schedule(r); scheduleErr != nil {
+		if removeErr := m.storage.Remove(chat, r.ID); removeErr != nil {
+			m.zap.Error("Failed to remove unscheduled report", zap.Int("id", r.ID), zap.Error(removeErr))
+		}
+		return Report{}, scheduleErr
+	}
+	return r, nil
+}
+
+// Remove cancels the report of the chat and deletes it from the storage.
+func (m *Manager) Remove(chat entities.ChatID, id int) error {
+	if err := m.storage.Remove(chat, id); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if task, ok := m.tasks[id]; ok {
+		task.Cancel()
+		delete(m.tasks, id)
+	}
+	return nil
+}
+
+func (m *Manager) schedule(r Report) error {
+	task, err := m.scheduler.ScheduleWithCron(func(ctx context.Context) { m.run(ctx, r) }, r.Cron)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidCron, "'%s': %v", r.Cron, err)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tasks[r.ID] = task
+	return nil
+}
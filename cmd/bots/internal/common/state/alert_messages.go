@@ -0,0 +1,162 @@
+// Package state keeps the runtime state of the bots which must survive the restarts.
+package state
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+type alertMessageKey struct {
+	chat    string
+	alertID crypto.Digest
+}
+
+type alertMessage struct {
+	Chat      string        `json:"chat"`
+	AlertID   crypto.Digest `json:"alertID"`
+	MessageID int           `json:"messageID"`
+	SentAt    time.Time     `json:"sentAt"`
+}
+
+type alertMessagesDB struct {
+	Messages []alertMessage `json:"messages"`
+}
+
+// AlertMessages keeps the IDs of the messages with the unresolved alerts, so the alert fix can be sent as a reply
+// to the alert message. The messages older than TTL are forgotten, e.g. if the fix has been lost.
+// If the file path is empty, the messages are kept only in memory.
+type AlertMessages struct {
+	mu       *sync.Mutex
+	path     string
+	ttl      time.Duration
+	now      func() time.Time
+	messages map[alertMessageKey]alertMessage
+}
+
+func NewAlertMessages(path string, ttl time.Duration) (*AlertMessages, error) {
+	return newAlertMessages(path, ttl, time.Now)
+}
+
+func newAlertMessages(path string, ttl time.Duration, now func() time.Time) (*AlertMessages, error) {
+	if ttl <= 0 {
+		return nil, errors.Errorf("invalid alert messages TTL %s", ttl)
+	}
+	m := &AlertMessages{
+		mu:       new(sync.Mutex),
+		ttl:      ttl,
+		now:      now,
+		messages: make(map[alertMessageKey]alertMessage),
+	}
+	if path == "" {
+		return m, nil
+	}
+	m.path = filepath.Clean(path)
+	data, err := os.ReadFile(m.path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		return m, nil
+	case err != nil:
+		return nil, errors.Wrapf(err, "failed to read alert messages file '%s'", m.path)
+	}
+	var db alertMessagesDB
+	if unmarshalErr := json.Unmarshal(data, &db); unmarshalErr != nil {
+		return nil, errors.Wrapf(unmarshalErr, "failed to unmarshal alert messages file '%s'", m.path)
+	}
+	for _, msg := range db.Messages {
+		m.messages[alertMessageKey{chat: msg.Chat, alertID: msg.AlertID}] = msg
+	}
+	m.vacuum()
+	return m, nil
+}
+
+// Add remembers the message with the alert sent to the chat.
+func (m *AlertMessages) Add(chat string, alertID crypto.Digest, messageID int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.vacuum()
+	m.messages[alertMessageKey{chat: chat, alertID: alertID}] = alertMessage{
+		Chat:      chat,
+		AlertID:   alertID,
+		MessageID: messageID,
+		SentAt:    m.now().UTC(),
+	}
+	return m.sync()
+}
+
+// MessageID returns the ID of the message with the alert sent to the chat.
+func (m *AlertMessages) MessageID(chat string, alertID crypto.Digest) (int, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	msg, ok := m.messages[alertMessageKey{chat: chat, alertID: alertID}]
+	if !ok || m.expired(msg) {
+		return 0, false
+	}
+	return msg.MessageID, true
+}
+
+// AlertID returns the ID of the alert which has been sent to the chat in the message with the given ID.
+func (m *AlertMessages) AlertID(chat string, messageID int) (crypto.Digest, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, msg := range m.messages {
+		if key.chat == chat && msg.MessageID == messageID && !m.expired(msg) {
+			return key.alertID, true
+		}
+	}
+	return crypto.Digest{}, false
+}
+
+// Delete forgets the message with the alert sent to the chat, e.g. after the alert fix.
+func (m *AlertMessages) Delete(chat string, alertID crypto.Digest) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := alertMessageKey{chat: chat, alertID: alertID}
+	if _, ok := m.messages[key]; !ok {
+		return nil
+	}
+	delete(m.messages, key)
+	m.vacuum()
+	return m.sync()
+}
+
+func (m *AlertMessages) expired(msg alertMessage) bool {
+	return m.now().Sub(msg.SentAt) > m.ttl
+}
+
+// vacuum deletes the expired messages, it must be called under the lock.
+func (m *AlertMessages) vacuum() {
+	for key, msg := range m.messages {
+		if m.expired(msg) {
+			delete(m.messages, key)
+		}
+	}
+}
+
+// sync writes the messages to the file, it must be called under the lock.
+func (m *AlertMessages) sync() error {
+	if m.path == "" {
+		return nil
+	}
+	db := alertMessagesDB{Messages: make([]alertMessage, 0, len(m.messages))}
+	for _, msg := range m.messages {
+		db.Messages = append(db.Messages, msg)
+	}
+	sort.Slice(db.Messages, func(i, j int) bool {
+		if !db.Messages[i].SentAt.Equal(db.Messages[j].SentAt) {
+			return db.Messages[i].SentAt.Before(db.Messages[j].SentAt)
+		}
+		return db.Messages[i].Chat < db.Messages[j].Chat
+	})
+	data, err := json.MarshalIndent(db, "", " ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alert messages")
+	}
+	return errors.Wrap(WriteFile(m.path, data), "failed to sync alert messages file")
+}
@@ -0,0 +1,89 @@
+package state
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time { return c.now }
+
+func TestAlertMessages_Persistence(t *testing.T) {
+	const ttl = time.Hour
+	path := filepath.Join(t.TempDir(), "alert_messages.json")
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	alert1 := crypto.MustFastHash([]byte("alert1"))
+	alert2 := crypto.MustFastHash([]byte("alert2"))
+
+	m, err := newAlertMessages(path, ttl, c.Now)
+	require.NoError(t, err)
+	require.NoError(t, m.Add("chat1", alert1, 10))
+	require.NoError(t, m.Add("chat2", alert1, 20))
+	require.NoError(t, m.Add("chat1", alert2, 11))
+	require.NoError(t, m.Delete("chat1", alert2))
+
+	// the bot is restarted
+	m, err = newAlertMessages(path, ttl, c.Now)
+	require.NoError(t, err)
+	messageID, ok := m.MessageID("chat1", alert1)
+	assert.True(t, ok)
+	assert.Equal(t, 10, messageID)
+	messageID, ok = m.MessageID("chat2", alert1)
+	assert.True(t, ok)
+	assert.Equal(t, 20, messageID)
+	_, ok = m.MessageID("chat1", alert2)
+	assert.False(t, ok)
+
+	alertID, ok := m.AlertID("chat2", 20)
+	assert.True(t, ok)
+	assert.Equal(t, alert1, alertID)
+	_, ok = m.AlertID("chat2", 10)
+	assert.False(t, ok)
+}
+
+func TestAlertMessages_TTL(t *testing.T) {
+	const ttl = time.Hour
+	path := filepath.Join(t.TempDir(), "alert_messages.json")
+	c := &clock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	alert1 := crypto.MustFastHash([]byte("alert1"))
+	alert2 := crypto.MustFastHash([]byte("alert2"))
+
+	m, err := newAlertMessages(path, ttl, c.Now)
+	require.NoError(t, err)
+	require.NoError(t, m.Add("chat", alert1, 1))
+	c.now = c.now.Add(ttl / 2)
+	require.NoError(t, m.Add("chat", alert2, 2))
+
+	c.now = c.now.Add(ttl/2 + time.Second)
+	_, ok := m.MessageID("chat", alert1)
+	assert.False(t, ok, "the message must be expired")
+	_, ok = m.AlertID("chat", 1)
+	assert.False(t, ok, "the message must be expired")
+	_, ok = m.MessageID("chat", alert2)
+	assert.True(t, ok)
+
+	m, err = newAlertMessages(path, ttl, c.Now)
+	require.NoError(t, err)
+	assert.Len(t, m.messages, 1, "the expired message must be dropped on load")
+}
+
+func TestAlertMessages_InMemory(t *testing.T) {
+	m, err := NewAlertMessages("", time.Hour)
+	require.NoError(t, err)
+	alert := crypto.MustFastHash([]byte("alert"))
+	require.NoError(t, m.Add("chat", alert, 1))
+	messageID, ok := m.MessageID("chat", alert)
+	assert.True(t, ok)
+	assert.Equal(t, 1, messageID)
+
+	_, err = NewAlertMessages("", 0)
+	assert.Error(t, err)
+}
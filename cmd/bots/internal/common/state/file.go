@@ -0,0 +1,20 @@
+package state
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// WriteFile replaces the file with the data. The data is written to the temporary file first, so the file
+// is never left half-written.
+func WriteFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write file '%s'", tmp)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return errors.Wrapf(err, "failed to replace file '%s'", path)
+	}
+	return nil
+}
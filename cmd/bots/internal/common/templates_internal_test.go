@@ -7,9 +7,13 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/reports"
 	"nodemon/pkg/entities"
+	"nodemon/pkg/messaging/pair"
+	"nodemon/pkg/storing/uptime"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -31,6 +35,10 @@ func expectedFormats() []ExpectedExtension {
 	return []ExpectedExtension{HTML, Markdown}
 }
 
+func alertFormats() []ExpectedExtension {
+	return []ExpectedExtension{HTML, Markdown, PlainText}
+}
+
 func TestMain(m *testing.M) {
 	flag.Parse()
 	os.Exit(m.Run())
@@ -61,7 +69,7 @@ func TestSimpleAlertTemplate(t *testing.T) {
 		Timestamp:   100500,
 		Description: "Simple alert !!!",
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/simple_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -79,7 +87,7 @@ func TestBaseTargetTemplate(t *testing.T) {
 		},
 		Threshold: 101,
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/base_target_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -93,7 +101,7 @@ func TestUnreachableTemplate(t *testing.T) {
 		Timestamp: 100,
 		Node:      "node",
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/unreachable_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -115,7 +123,7 @@ func TestAlertFixed(t *testing.T) {
 	statement := fixedStatement{
 		PreviousAlert: data.Fixed.Name().String(),
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/alert_fixed"
 		actual, err := executeTemplate(template, statement, f)
 		require.NoError(t, err)
@@ -147,7 +155,7 @@ func TestHeightTemplate(t *testing.T) {
 			Height: heightAlert.OtherHeightGroup.Height,
 		},
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/height_alert"
 		actual, err := executeTemplate(template, statement, f)
 		require.NoError(t, err)
@@ -222,12 +230,20 @@ func TestStateHashTemplate(t *testing.T) {
 			StateHash: stateHashAlert.SecondGroup.StateHash.SumHash.Hex(),
 		},
 	}
-	for _, f := range expectedFormats() {
-		const template = "templates/alerts/state_hash_alert"
-		actual, err := executeTemplate(template, statement, f)
-		require.NoError(t, err)
-		expected := goldenValue(t, template, f, actual)
-		assert.Equal(t, expected, actual)
+	withFork := statement
+	withFork.Fork = &stateHashFork{
+		Height:         2,
+		DivergedFields: "waves_balance, lease_balance",
+		AlertID:        "2gTbXStMt8rGNbqFnDTYvdMgiA9Bce4jWHN26fc5aBDs",
+	}
+	for suffix, data := range map[string]stateHashStatement{"": statement, "_fork": withFork} {
+		for _, f := range alertFormats() {
+			const template = "templates/alerts/state_hash_alert"
+			actual, err := executeTemplate(template, data, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
 	}
 }
 
@@ -266,7 +282,7 @@ func TestDifferentChainsTemplate(t *testing.T) {
 			StateHash: stateHashAlert.SecondGroup.StateHash.SumHash.Hex(),
 		},
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/state_hash_several_chains_alert"
 		actual, err := executeTemplate(template, statement, f)
 		require.NoError(t, err)
@@ -279,7 +295,7 @@ func TestIncompleteTemplate(t *testing.T) {
 	data := &entities.IncompleteAlert{
 		NodeStatement: entities.NodeStatement{Node: "a", Version: "1", Height: 1},
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/incomplete_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -292,7 +308,7 @@ func TestInternalErrorTemplate(t *testing.T) {
 	data := &entities.InternalErrorAlert{
 		Error: "error",
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/internal_error_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -305,7 +321,7 @@ func TestInvalidHeightTemplate(t *testing.T) {
 	data := &entities.InvalidHeightAlert{
 		NodeStatement: entities.NodeStatement{Node: "a", Version: "1", Height: 1},
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/invalid_height_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -314,25 +330,136 @@ func TestInvalidHeightTemplate(t *testing.T) {
 	}
 }
 
-func TestNodesListTemplateHTML(t *testing.T) {
+func TestMaintenanceEndedTemplate(t *testing.T) {
+	data := maintenanceEndedStatement{
+		Node:             "node",
+		Start:            "2024-01-02 10:00:00",
+		End:              "2024-01-02 11:00:00",
+		StatementsCount:  60,
+		UnreachableCount: 12,
+		LastStatus:       entities.OK,
+		LastHeight:       100500,
+		LastVersion:      "v1.5.0",
+	}
+	for _, f := range alertFormats() {
+		const template = "templates/alerts/maintenance_ended_alert"
+		actual, err := executeTemplate(template, data, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestVersionMismatchTemplate(t *testing.T) {
+	tests := map[string]versionMismatchStatement{
+		"drift": {
+			Since: "2024-01-02 10:00:00",
+			Groups: []entities.VersionGroup{
+				{Version: "Waves v1.5.2", Nodes: entities.Nodes{"a"}},
+				{Version: "Waves v1.5.3", Nodes: entities.Nodes{"b", "c"}},
+			},
+		},
+		"below_minimum": {
+			BelowMinimum: true,
+			Node:         "a",
+			Version:      "Waves v1.5.2",
+			MinVersion:   "1.5.3",
+		},
+	}
+	for name, data := range tests {
+		for _, f := range alertFormats() {
+			const template = "templates/alerts/version_mismatch_alert"
+			actual, err := executeTemplate(template, data, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+"_"+name, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestChainStuckTemplate(t *testing.T) {
+	data := chainStuckStatement{Height: 100500, Since: "2024-01-02 10:00:00", Duration: "15m0s"}
+	for _, f := range alertFormats() {
+		const template = "templates/alerts/chain_stuck_alert"
+		actual, err := executeTemplate(template, data, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestMissingGeneratorTemplate(t *testing.T) {
+	tests := map[string]missingGeneratorStatement{
+		"": {
+			Generator:       "3P9CaF1EYqsoQGWNPskv4aTTMqnsv7eP7nz",
+			Interval:        "1h0m0s",
+			LastBlockHeight: 4200098,
+			LastBlockTime:   "2024-01-01 11:57:00",
+		},
+		"_no_blocks": {Generator: "3P9CaF1EYqsoQGWNPskv4aTTMqnsv7eP7nz", Interval: "1h0m0s"},
+	}
+	for suffix, data := range tests {
+		for _, f := range alertFormats() {
+			const template = "templates/alerts/missing_generator_alert"
+			actual, err := executeTemplate(template, data, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestAlertGroupTemplate(t *testing.T) {
+	tests := map[string]entities.AlertGroup{
+		"": {
+			Timestamp: 1704067200,
+			RootCause: "node a.example.com is unreachable, the other alerts are likely caused by it",
+			Nodes:     []string{"a.example.com", "b-alias"},
+			Alerts: []entities.AlertGroupMember{
+				{Name: entities.UnreachableAlertName, Level: entities.ErrorLevel, Message: "Node a.example.com is unreachable"},
+				{Name: entities.HeightAlertName, Level: entities.WarnLevel, Message: "Nodes have different heights"},
+			},
+		},
+		"_storm": {
+			Timestamp: 1704067200,
+			Storm:     true,
+			RootCause: "alerts storm, 2 alerts have exceeded the limit of 30 alerts per minute",
+			Alerts: []entities.AlertGroupMember{
+				{Name: entities.InternalErrorName, Level: entities.ErrorLevel, Message: "timeout"},
+				{Name: entities.InternalErrorName, Level: entities.ErrorLevel, Message: "connection refused"},
+			},
+		},
+	}
+	for suffix, data := range tests {
+		for _, f := range alertFormats() {
+			const template = "templates/alerts/alert_group"
+			actual, err := executeTemplate(template, data, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestNodesListTemplate(t *testing.T) {
 	data := []entities.Node{
 		{URL: "blah", Enabled: false, Alias: "al"},
 		{URL: "lala", Enabled: true, Alias: "la"},
 		{URL: "b", Enabled: true, Alias: ""},
 		{URL: "m", Enabled: true, Alias: ""},
+		{URL: "t", Enabled: true, Alias: "", Tags: []string{"our-validators", "public-api"}},
 	}
-	const (
-		template = "templates/nodes_list"
-		ext      = HTML
-	)
+	const template = "templates/nodes_list"
 	urls := nodesToUrls(data)
-	actual, err := executeTemplate(template, urls, ext)
-	require.NoError(t, err)
-	expected := goldenValue(t, template, ext, actual)
-	assert.Equal(t, expected, actual)
+	for _, f := range expectedFormats() {
+		actual, err := executeTemplate(template, urls, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
+	}
 }
 
-func TestNodeStatementTemplateHTML(t *testing.T) {
+func TestNodeStatementTemplate(t *testing.T) {
 	data := nodeStatement{
 		Node:      "blah-blah",
 		Height:    999,
@@ -340,29 +467,28 @@ func TestNodeStatementTemplateHTML(t *testing.T) {
 		StateHash: "sample-state-hash",
 		Version:   "v1.0.1",
 	}
-	const (
-		template = "templates/node_statement"
-		ext      = HTML
-	)
-	actual, err := executeTemplate(template, data, ext)
-	require.NoError(t, err)
-	expected := goldenValue(t, template, ext, actual)
-	assert.Equal(t, expected, actual)
+	const template = "templates/node_statement"
+	for _, f := range expectedFormats() {
+		actual, err := executeTemplate(template, data, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
+	}
 }
 
-func TestSubscriptionsTemplateHTML(t *testing.T) {
+func TestSubscriptionsTemplate(t *testing.T) {
 	data := subscriptionsList{
 		SubscribedTo:     []subscribed{{AlertName: "SubscribedToFirst"}, {AlertName: "SubscribedToSecond"}},
 		UnsubscribedFrom: []unsubscribed{{AlertName: "UnsubscribedFromFirst"}, {AlertName: "UnsubscribedFromSecond"}},
+		Groups:           []string{"our-validators", "public-api"},
+	}
+	const template = "templates/subscriptions"
+	for _, f := range expectedFormats() {
+		actual, err := executeTemplate(template, data, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
 	}
-	const (
-		template = "templates/subscriptions"
-		ext      = HTML
-	)
-	actual, err := executeTemplate(template, data, ext)
-	require.NoError(t, err)
-	expected := goldenValue(t, template, ext, actual)
-	assert.Equal(t, expected, actual)
 }
 
 func TestNodesStatusDifferentHashesTemplate(t *testing.T) {
@@ -373,6 +499,7 @@ func TestNodesStatusDifferentHashesTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "1234",
 			BlockID: "some-block-id",
+			Version: "Waves v1.5.3",
 		},
 		{
 			URL:     "another-url",
@@ -380,6 +507,7 @@ func TestNodesStatusDifferentHashesTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "4321",
 			BlockID: "another-block-id",
+			Version: "Waves v1.5.2",
 		},
 		{
 			URL:     "one-more-url",
@@ -445,6 +573,7 @@ func TestNodesStatusDifferentHeightsTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "1234",
 			BlockID: "some-block-id",
+			Version: "Waves v1.5.3",
 		},
 		{
 			URL:     "another-url",
@@ -452,6 +581,7 @@ func TestNodesStatusDifferentHeightsTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "4321",
 			BlockID: "another-block-id",
+			Version: "Waves v1.5.2",
 		},
 		{
 			URL:     "one-more-url",
@@ -478,6 +608,7 @@ func TestNodesStatusOkTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "1234",
 			BlockID: "some-block-id",
+			Version: "Waves v1.5.3",
 		},
 		{
 			URL:     "another-url",
@@ -485,6 +616,7 @@ func TestNodesStatusOkTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "4321",
 			BlockID: "another-block-id",
+			Version: "Waves v1.5.2",
 		},
 		{
 			URL:     "one-more-url",
@@ -526,6 +658,7 @@ func TestNodesStatusUnavailableTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "1234",
 			BlockID: "some-block-id",
+			Version: "Waves v1.5.3",
 		},
 		{
 			URL:     "another-url",
@@ -533,6 +666,7 @@ func TestNodesStatusUnavailableTemplate(t *testing.T) {
 			Status:  "some-status",
 			Height:  "4321",
 			BlockID: "another-block-id",
+			Version: "Waves v1.5.2",
 		},
 		{
 			URL:     "one-more-url",
@@ -557,7 +691,7 @@ func TestL2StuckTemplate(t *testing.T) {
 		L2Node:    "node_name",
 		L2Height:  100,
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/l2/l2_stuck_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -572,7 +706,7 @@ func TestChallengedBlockTemplate(t *testing.T) {
 		BlockID:   proto.NewBlockIDFromDigest(crypto.Digest{1, 2, 3, 4, 5}),
 		Nodes:     []string{"node1", "node2", "node3"},
 	}
-	for _, f := range expectedFormats() {
+	for _, f := range alertFormats() {
 		const template = "templates/alerts/challenged_block_alert"
 		actual, err := executeTemplate(template, data, f)
 		require.NoError(t, err)
@@ -580,3 +714,165 @@ func TestChallengedBlockTemplate(t *testing.T) {
 		assert.Equal(t, expected, actual)
 	}
 }
+
+func TestAlertsListTemplate(t *testing.T) {
+	data := alertsList{
+		Active: []activeAlertItem{
+			{
+				ID:       "5Fe1yzeoBEPsi7Lwbs1Ym1oBaDp4AmTTPCpbCPhbD5c4",
+				Name:     entities.UnreachableAlertName,
+				Level:    entities.ErrorLevel,
+				Message:  "Node \"node1\" is unreachable",
+				OpenedAt: "2024-01-01 10:00:00",
+				Sent:     3,
+			},
+		},
+		Resolved: []resolvedAlertItem{
+			{
+				Name:     entities.HeightAlertName,
+				Level:    entities.ErrorLevel,
+				Message:  "Some node(s) are 10 blocks behind",
+				OpenedAt: "2024-01-01 09:00:00",
+				ClosedAt: "2024-01-01 09:05:00",
+			},
+		},
+	}
+	for _, f := range expectedFormats() {
+		const template = "templates/alerts_list"
+		actual, err := executeTemplate(template, data, f)
+		require.NoError(t, err)
+		expected := goldenValue(t, template, f, actual)
+		assert.Equal(t, expected, actual)
+	}
+}
+
+func TestGeneratorsListTemplate(t *testing.T) {
+	tests := map[string]generatorsList{
+		"": {
+			Since:  "2024-01-01 10:00:00",
+			Until:  "2024-01-01 12:00:00",
+			Blocks: 120,
+			Generators: []generatorItem{
+				{
+					Generator:       "3PA1KvFfq9VuJjg45p2ytGgaNjrgnLSgf4r",
+					Blocks:          90,
+					Share:           "75.0",
+					LastBlockHeight: 4200100,
+					LastBlockTime:   "2024-01-01 11:59:00",
+				},
+				{
+					Generator:       "3P9CaF1EYqsoQGWNPskv4aTTMqnsv7eP7nz",
+					Blocks:          30,
+					Share:           "25.0",
+					LastBlockHeight: 4200098,
+					LastBlockTime:   "2024-01-01 11:57:00",
+				},
+			},
+			Other: 5,
+		},
+		"_empty": {},
+	}
+	for suffix, data := range tests {
+		for _, f := range expectedFormats() {
+			const template = "templates/generators_list"
+			actual, err := executeTemplate(template, data, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestForkReportTemplate(t *testing.T) {
+	stateHashInfo := generateStateHashes(1, 3)
+	generator := proto.MustAddressFromString("3PA1KvFfq9VuJjg45p2ytGgaNjrgnLSgf4r")
+	block := func(node string, i int) *entities.ForkBlock {
+		return &entities.ForkBlock{
+			Node:      node,
+			BlockID:   stateHashInfo[i].id,
+			StateHash: stateHashInfo[i].sh.SumHash,
+			Generator: &generator,
+		}
+	}
+	alertID := crypto.MustDigestFromBase58("2gTbXStMt8rGNbqFnDTYvdMgiA9Bce4jWHN26fc5aBDs")
+	nodes := []entities.Node{{URL: "https://node-1.example.com", Alias: "first"}}
+	tests := map[string]*pair.ForkReportResponse{
+		"": {Report: &entities.ForkReport{
+			AlertID:          alertID,
+			Timestamp:        time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC).Unix(),
+			LastCommonHeight: 99,
+			ForkHeight:       100,
+			DivergedFields:   []string{"waves_balance"},
+			FirstGroup:       []string{"https://node-1.example.com"},
+			SecondGroup:      []string{"https://node-2.example.com"},
+			Heights: []entities.ForkHeight{
+				{
+					Height: 99,
+					First:  block("https://node-1.example.com", 0),
+					Second: block("https://node-2.example.com", 0),
+				},
+				{
+					Height:         100,
+					First:          block("https://node-1.example.com", 1),
+					Second:         block("https://node-2.example.com", 2),
+					DivergedFields: []string{"waves_balance"},
+				},
+				{Height: 101, First: block("https://node-1.example.com", 2)},
+			},
+		}},
+		"_not_found": {},
+	}
+	for suffix, resp := range tests {
+		for _, f := range expectedFormats() {
+			const template = "templates/fork_report"
+			actual, err := HandleForkReport(resp, alertID.String(), f, nodes)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestNodesSummaryTemplate(t *testing.T) {
+	since := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC).Unix()
+	until := since + int64((7*24*time.Hour)/time.Second)
+	nodes := []entities.Node{{URL: "https://node-1.example.com", Alias: "first"}}
+	tests := map[string]*pair.NodesSummaryResponse{
+		"": {
+			Since: since,
+			Nodes: []uptime.NodeSummary{
+				{Node: "https://node-1.example.com", Polls: 10080, OKPolls: 10075, Alerts: 2},
+				{Node: "https://node-2.example.com", Polls: 10080, OKPolls: 10080},
+			},
+		},
+		"_empty": {Since: since},
+	}
+	for suffix, resp := range tests {
+		for _, f := range expectedFormats() {
+			const template = "templates/nodes_summary"
+			actual, err := HandleNodesSummary(resp, until, f, nodes)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestReportsListTemplate(t *testing.T) {
+	tests := map[string][]reports.Report{
+		"": {
+			{ID: 1, Chat: 1, Kind: reports.StatusKind, Cron: reports.DailyStatusCron},
+			{ID: 3, Chat: 1, Kind: reports.SummaryKind, Cron: "0 0 9 * * MON"},
+		},
+		"_empty": nil,
+	}
+	for suffix, list := range tests {
+		for _, f := range expectedFormats() {
+			const template = "templates/reports_list"
+			actual, err := ReportsListMessage(list, f)
+			require.NoError(t, err)
+			expected := goldenValue(t, template+suffix, f, actual)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+// Package components defines the custom IDs of the Discord message components, e.g. buttons and select menus.
+package components
+
+import (
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+const (
+	SubscribeTo     = "subscribe_to"
+	UnsubscribeFrom = "unsubscribe_from"
+	RemoveNode      = "remove_node"
+
+	ackAlertPrefix = "ack_alert:"
+)
+
+// AckAlertButton is the button attached to the alert message, it acknowledges the alert.
+func AckAlertButton(alertID crypto.Digest) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.Button{
+			Label:    "Acknowledge",
+			Style:    discordgo.SecondaryButton,
+			CustomID: ackAlertPrefix + alertID.String(),
+		},
+	}}
+}
+
+// ParseAckAlertID returns the ID of the alert acknowledged by the button with the given custom ID.
+func ParseAckAlertID(customID string) (crypto.Digest, bool) {
+	rawID, ok := strings.CutPrefix(customID, ackAlertPrefix)
+	if !ok {
+		return crypto.Digest{}, false
+	}
+	alertID, err := crypto.NewDigestFromBase58(rawID)
+	if err != nil {
+		return crypto.Digest{}, false
+	}
+	return alertID, true
+}
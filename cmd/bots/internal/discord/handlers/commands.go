@@ -0,0 +1,252 @@
+package handlers
+
+import (
+	"nodemon/cmd/bots/internal/common"
+	"nodemon/cmd/bots/internal/common/reports"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	nodeOption     = "node"
+	aliasOption    = "alias"
+	heightOption   = "height"
+	limitOption    = "limit"
+	alertOption    = "alert"
+	durationOption = "duration"
+	kindOption     = "kind"
+	cronOption     = "cron"
+	idOption       = "id"
+	tagsOption     = "tags"
+	groupOption    = "group"
+)
+
+// Commands returns the slash commands of the bot, they are registered in Discord when the bot connects.
+func Commands() []*discordgo.ApplicationCommand {
+	nodeOpt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        nodeOption,
+		Description: "Node URL or alias",
+		Required:    true,
+	}
+	alertNameOpt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        alertOption,
+		Description: "Alert name",
+		Required:    true,
+		Choices:     alertNameChoices(),
+	}
+	durationOpt := func(description string, required bool) *discordgo.ApplicationCommandOption {
+		return &discordgo.ApplicationCommandOption{
+			Type:        discordgo.ApplicationCommandOptionString,
+			Name:        durationOption,
+			Description: description,
+			Required:    required,
+		}
+	}
+	tagsOpt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        tagsOption,
+		Description: "Space separated tags, e.g. our-validators public-api",
+		Required:    true,
+	}
+	groupOpt := &discordgo.ApplicationCommandOption{
+		Type:        discordgo.ApplicationCommandOptionString,
+		Name:        groupOption,
+		Description: "Node tag, e.g. our-validators",
+		Required:    true,
+	}
+	return []*discordgo.ApplicationCommand{
+		{Name: "ping", Description: "Check whether the bot is available and what its current state is"},
+		{Name: "help", Description: "Show the available commands"},
+		{Name: "chat", Description: "Show the ID and the state of this chat"},
+		{Name: "start", Description: "Start sending alerts to the chat"},
+		{Name: "mute", Description: "Stop sending alerts to the chat"},
+		{Name: "pool", Description: "Show the list of nodes and edit it"},
+		{Name: "subscriptions", Description: "Show the alert subscriptions of the chat and edit them"},
+		{Name: "status", Description: "Show the status of all nodes"},
+		{Name: "viewchains", Description: "Show the chains of the nodes"},
+		{
+			Name:        "statement",
+			Description: "Show the node statement at a specific height",
+			Options: []*discordgo.ApplicationCommandOption{
+				nodeOpt,
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        heightOption,
+					Description: "Blockchain height",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "alerts",
+			Description: "Show the active alerts and the last resolved ones",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        limitOption,
+					Description: "The number of resolved alerts, 5 by default",
+					MinValue:    func(v float64) *float64 { return &v }(1),
+				},
+			},
+		},
+		{Name: "generators", Description: "Show the block generators statistics"},
+		{
+			Name:        "fork",
+			Description: "Show the block-by-block report of the state hash alert fork",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        alertOption,
+					Description: "State hash alert ID, see /alerts",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "ack",
+			Description: "Stop repeating the alert until it is resolved",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        alertOption,
+					Description: "Alert ID",
+					Required:    true,
+				},
+				durationOpt("How long the alert is acknowledged for, e.g. 2h", false),
+			},
+		},
+		{
+			Name:        "silence",
+			Description: "Stop sending the matching alerts",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        alertOption,
+					Description: "Alert ID or alert name",
+					Required:    true,
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        nodeOption,
+					Description: "Node URL, required if the alert name is given",
+				},
+				durationOpt("How long the alerts are silenced for, e.g. 2h", false),
+			},
+		},
+		{
+			Name:        "maintenance",
+			Description: "Suppress the alerts about the node for the given duration",
+			Options: []*discordgo.ApplicationCommandOption{
+				nodeOpt,
+				durationOpt("Maintenance duration, e.g. 2h, or 'off' to finish the maintenance", true),
+			},
+		},
+		{Name: "add", Description: "Add a node to the list", Options: []*discordgo.ApplicationCommandOption{nodeOpt}},
+		{
+			Name:        "add_specific",
+			Description: "Add a specific node to the list",
+			Options:     []*discordgo.ApplicationCommandOption{nodeOpt},
+		},
+		{
+			Name:        "remove",
+			Description: "Remove a node from the list",
+			Options:     []*discordgo.ApplicationCommandOption{nodeOpt},
+		},
+		{
+			Name:        "add_alias",
+			Description: "Set the alias of the node",
+			Options: []*discordgo.ApplicationCommandOption{
+				nodeOpt,
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        aliasOption,
+					Description: "Node alias",
+					Required:    true,
+				},
+			},
+		},
+		{Name: "aliases", Description: "Show the nodes aliases"},
+		{
+			Name:        "tag",
+			Description: "Add the tags to the node",
+			Options:     []*discordgo.ApplicationCommandOption{nodeOpt, tagsOpt},
+		},
+		{
+			Name:        "untag",
+			Description: "Remove the tags from the node",
+			Options:     []*discordgo.ApplicationCommandOption{nodeOpt, tagsOpt},
+		},
+		{
+			Name:        "subscribe",
+			Description: "Subscribe the chat to the alert",
+			Options:     []*discordgo.ApplicationCommandOption{alertNameOpt},
+		},
+		{
+			Name:        "unsubscribe",
+			Description: "Unsubscribe the chat from the alert",
+			Options:     []*discordgo.ApplicationCommandOption{alertNameOpt},
+		},
+		{
+			Name:        "subscribe_group",
+			Description: "Receive only the alerts about the nodes with the tag, may be used for several tags",
+			Options:     []*discordgo.ApplicationCommandOption{groupOpt},
+		},
+		{
+			Name:        "unsubscribe_group",
+			Description: "Stop receiving the alerts about the nodes with the tag",
+			Options:     []*discordgo.ApplicationCommandOption{groupOpt},
+		},
+		{Name: "reports", Description: "Show the scheduled reports of the alerts channel"},
+		{
+			Name:        "add_report",
+			Description: "Schedule a report to the alerts channel",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        kindOption,
+					Description: "Report kind",
+					Required:    true,
+					Choices:     reportKindChoices(),
+				},
+				{
+					Type:        discordgo.ApplicationCommandOptionString,
+					Name:        cronOption,
+					Description: "Cron expression with seconds, e.g. 0 0 9 * * MON",
+					Required:    true,
+				},
+			},
+		},
+		{
+			Name:        "remove_report",
+			Description: "Remove a scheduled report",
+			Options: []*discordgo.ApplicationCommandOption{
+				{
+					Type:        discordgo.ApplicationCommandOptionInteger,
+					Name:        idOption,
+					Description: "Report ID, see /reports",
+					Required:    true,
+				},
+			},
+		},
+	}
+}
+
+func reportKindChoices() []*discordgo.ApplicationCommandOptionChoice {
+	kinds := reports.Kinds()
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(kinds))
+	for _, kind := range kinds {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: string(kind), Value: string(kind)})
+	}
+	return choices
+}
+
+func alertNameChoices() []*discordgo.ApplicationCommandOptionChoice {
+	names := common.SortedAlertNames()
+	choices := make([]*discordgo.ApplicationCommandOptionChoice, 0, len(names))
+	for _, name := range names {
+		choices = append(choices, &discordgo.ApplicationCommandOptionChoice{Name: name.String(), Value: name.String()})
+	}
+	return choices
+}
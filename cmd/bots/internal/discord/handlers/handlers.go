@@ -2,11 +2,19 @@ package handlers
 
 import (
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	"nodemon/cmd/bots/internal/common"
+	"nodemon/cmd/bots/internal/common/chats"
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/rbac"
+	"nodemon/cmd/bots/internal/common/reports"
+	"nodemon/cmd/bots/internal/discord/components"
 	"nodemon/cmd/bots/internal/discord/messages"
+	"nodemon/pkg/entities"
 	"nodemon/pkg/messaging/pair"
 
 	"github.com/bwmarrin/discordgo"
@@ -14,183 +22,719 @@ import (
 	"go.uber.org/zap"
 )
 
+const (
+	noRightsMsg          = "Sorry, you have no right to use this command"
+	alertsChannelOnlyMsg = "Sorry, this command is available only in the channel which the alerts are sent through"
+	// maxSelectMenuOptions is the limit of Discord.
+	maxSelectMenuOptions = 25
+)
+
+// response is the reply to the interaction.
+type response struct {
+	content    string
+	components []discordgo.MessageComponent
+}
+
+func textResponse(content string) response {
+	return response{content: content}
+}
+
+// interaction is the slash command or the message component interaction, which is run as the command.
+type interaction struct {
+	channelID string
+	command   string
+	options   map[string]string
+	args      []string // the option values in the order given by the user, they are written to the audit log
+}
+
+func (in interaction) option(name string) string {
+	return in.options[name]
+}
+
+type commandHandler func(in interaction) (response, error)
+
+type command struct {
+	handle commandHandler
+	// alertsChannelOnly means that the command changes the monitoring or the alerts channel state,
+	// so it may be run only in the alerts channel
+	alertsChannelOnly bool
+}
+
+type handlers struct {
+	env        *common.DiscordBotEnvironment
+	authorizer *rbac.Authorizer
+	reports    *reports.Manager
+	requestCh  chan<- pair.Request
+	responseCh <-chan pair.Response
+	logger     *zap.Logger
+	commands   map[string]command
+}
+
 func InitDscHandlers(
 	environment *common.DiscordBotEnvironment,
+	authorizer *rbac.Authorizer,
+	reportsManager *reports.Manager,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
 	logger *zap.Logger,
 ) {
-	isEligibleForAction := func(m *discordgo.MessageCreate) bool {
-		if environment.IsEligibleForAction(m.ChannelID) {
-			return true
+	h := &handlers{
+		env:        environment,
+		authorizer: authorizer,
+		reports:    reportsManager,
+		requestCh:  requestType,
+		responseCh: responsePairType,
+		logger:     logger,
+	}
+	h.commands = h.handledCommands()
+	environment.Bot.AddHandler(h.registerCommands)
+	environment.Bot.AddHandler(h.onInteraction)
+}
+
+// handledCommands maps the commands to their handlers, the component interactions are mapped to the commands too.
+func (h *handlers) handledCommands() map[string]command {
+	return map[string]command{
+		"/ping":              {handle: h.pingCmd},
+		"/help":              {handle: h.helpCmd},
+		"/chat":              {handle: h.chatCmd},
+		"/start":             {handle: h.muteCmd(false), alertsChannelOnly: true},
+		"/mute":              {handle: h.muteCmd(true), alertsChannelOnly: true},
+		"/pool":              {handle: h.poolCmd},
+		"/subscriptions":     {handle: h.subscriptionsCmd},
+		"/status":            {handle: h.statusCmd},
+		"/viewchains":        {handle: h.viewChainsCmd},
+		"/statement":         {handle: h.statementCmd},
+		"/alerts":            {handle: h.alertsCmd},
+		"/generators":        {handle: h.generatorsCmd},
+		"/fork":              {handle: h.forkCmd},
+		"/ack":               {handle: h.muteAlertCmd(entities.AckAlertMuteKind), alertsChannelOnly: true},
+		"/silence":           {handle: h.muteAlertCmd(entities.SilenceAlertMuteKind), alertsChannelOnly: true},
+		"/maintenance":       {handle: h.maintenanceCmd, alertsChannelOnly: true},
+		"/add":               {handle: h.addCmd(false), alertsChannelOnly: true},
+		"/add_specific":      {handle: h.addCmd(true), alertsChannelOnly: true},
+		"/remove":            {handle: h.removeCmd, alertsChannelOnly: true},
+		"/add_alias":         {handle: h.addAliasCmd, alertsChannelOnly: true},
+		"/aliases":           {handle: h.aliasesCmd},
+		"/tag":               {handle: h.tagCmd(false), alertsChannelOnly: true},
+		"/untag":             {handle: h.tagCmd(true), alertsChannelOnly: true},
+		"/subscribe":         {handle: h.subscribeCmd, alertsChannelOnly: true},
+		"/unsubscribe":       {handle: h.unsubscribeCmd, alertsChannelOnly: true},
+		"/subscribe_group":   {handle: h.groupSubscriptionCmd(false), alertsChannelOnly: true},
+		"/unsubscribe_group": {handle: h.groupSubscriptionCmd(true), alertsChannelOnly: true},
+		"/reports":           {handle: h.reportsCmd},
+		"/add_report":        {handle: h.addReportCmd, alertsChannelOnly: true},
+		"/remove_report":     {handle: h.removeReportCmd, alertsChannelOnly: true},
+	}
+}
+
+// registerCommands registers the slash commands in the server of the alerts channel when the bot connects.
+// If the channel isn't a server channel, the commands are registered globally.
+func (h *handlers) registerCommands(s *discordgo.Session, r *discordgo.Ready) {
+	var guildID string
+	channel, err := s.Channel(h.env.ChatID)
+	if err != nil {
+		h.logger.Warn("Failed to get discord alerts channel, registering commands globally", zap.Error(err))
+	} else {
+		guildID = channel.GuildID
+	}
+	registered, err := s.ApplicationCommandBulkOverwrite(r.User.ID, guildID, Commands())
+	if err != nil {
+		h.logger.Error("Failed to register discord commands", zap.Error(err), zap.String("guildID", guildID))
+		return
+	}
+	h.logger.Info("Discord commands have been registered",
+		zap.Int("count", len(registered)), zap.String("guildID", guildID),
+	)
+}
+
+func (h *handlers) onInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	in, ok := parseInteraction(i)
+	if !ok {
+		return
+	}
+	cmd, ok := h.commands[in.command]
+	if !ok {
+		h.logger.Warn("Unknown discord command", zap.String("command", in.command))
+		return
+	}
+	if cmd.alertsChannelOnly && !h.env.IsEligibleForAction(in.channelID) {
+		h.respondNow(s, i, alertsChannelOnlyMsg)
+		return
+	}
+	if !h.authorize(i, in) {
+		h.respondNow(s, i, noRightsMsg)
+		return
+	}
+	// the requests to the monitoring service may take longer than the time given to respond to the interaction
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+	})
+	if err != nil {
+		h.logger.Error("Failed to respond to discord interaction", zap.Error(err), zap.String("command", in.command))
+		return
+	}
+	resp, err := cmd.handle(in)
+	if err != nil {
+		h.logger.Error("Failed to handle discord command", zap.Error(err), zap.String("command", in.command))
+		resp = textResponse(fmt.Sprintf("Failed to run %s, %v", in.command, err))
+	}
+	edit := &discordgo.WebhookEdit{Content: &resp.content}
+	if len(resp.components) > 0 {
+		edit.Components = &resp.components
+	}
+	if _, editErr := s.InteractionResponseEdit(i.Interaction, edit); editErr != nil {
+		h.logger.Error("Failed to send a message to discord", zap.Error(editErr), zap.String("command", in.command))
+	}
+}
+
+// respondNow responds to the interaction with the message visible only to the user.
+func (h *handlers) respondNow(s *discordgo.Session, i *discordgo.InteractionCreate, msg string) {
+	err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{Content: msg, Flags: discordgo.MessageFlagsEphemeral},
+	})
+	if err != nil {
+		h.logger.Error("Failed to respond to discord interaction", zap.Error(err))
+	}
+}
+
+// parseInteraction converts the slash command or the message component interaction to the command.
+func parseInteraction(i *discordgo.InteractionCreate) (interaction, bool) {
+	in := interaction{channelID: i.ChannelID, options: make(map[string]string)}
+	setOption := func(name, value string) {
+		in.options[name] = value
+		in.args = append(in.args, value)
+	}
+	switch i.Type { //nolint:exhaustive // the bot doesn't use the other interactions
+	case discordgo.InteractionApplicationCommand:
+		data := i.ApplicationCommandData()
+		in.command = "/" + data.Name
+		for _, opt := range data.Options {
+			if opt.Type == discordgo.ApplicationCommandOptionInteger {
+				setOption(opt.Name, strconv.FormatInt(opt.IntValue(), 10))
+			} else { // the other options of the commands are strings
+				setOption(opt.Name, opt.StringValue())
+			}
 		}
-		_, err := environment.Bot.ChannelMessageSend(m.ChannelID, "Sorry, you have no right to use this command")
-		if err != nil {
-			logger.Error("Failed to send a message to discord", zap.Error(err), zap.String("channelID", m.ChannelID))
+		return in, true
+	case discordgo.InteractionMessageComponent:
+		data := i.MessageComponentData()
+		if alertID, ok := components.ParseAckAlertID(data.CustomID); ok {
+			in.command = "/ack"
+			setOption(alertOption, alertID.String())
+			return in, true
+		}
+		if len(data.Values) != 1 {
+			return interaction{}, false
+		}
+		switch data.CustomID {
+		case components.RemoveNode:
+			in.command = "/remove"
+			setOption(nodeOption, data.Values[0])
+		case components.SubscribeTo:
+			in.command = "/subscribe"
+			setOption(alertOption, data.Values[0])
+		case components.UnsubscribeFrom:
+			in.command = "/unsubscribe"
+			setOption(alertOption, data.Values[0])
+		default:
+			return interaction{}, false
 		}
+		return in, true
+	default:
+		return interaction{}, false
+	}
+}
+
+// authorize checks the access of the interaction author to the command.
+func (h *handlers) authorize(i *discordgo.InteractionCreate, in interaction) bool {
+	var (
+		user    *discordgo.User
+		roleIDs []string
+	)
+	if i.Member != nil { // the member is set only for the interactions in the server channels
+		user, roleIDs = i.Member.User, i.Member.Roles
+	} else {
+		user = i.User
+	}
+	if user == nil {
 		return false
 	}
-	environment.Bot.AddHandler(func(s *discordgo.Session, m *discordgo.MessageCreate) {
-		switch {
-		case m.Author.ID == s.State.User.ID: // ignore self messages
-			return
-		case m.Content == "/ping":
-			handlePingCmd(s, environment, logger)
-		case m.Content == "/help":
-			handleHelpCmd(s, environment, logger)
-		case m.Content == "/status":
-			handleStatusCmd(s, requestType, responsePairType, logger, environment)
-		case strings.Contains(m.Content, "/add"):
-			if isEligibleForAction(m) {
-				handleAddCmd(s, m, environment, logger, requestType)
-			}
-		case strings.Contains(m.Content, "/remove"):
-			if isEligibleForAction(m) {
-				handleRemoveCmd(s, m, environment, logger, requestType)
-			}
-		}
+	return h.authorizer.Authorize(rbac.Action{
+		Platform: rbac.Discord,
+		ChatID:   in.channelID,
+		UserID:   user.ID,
+		UserRole: h.authorizer.DiscordRole(user.ID, roleIDs),
+		Command:  in.command,
+		Args:     in.args,
 	})
 }
 
-func handleRemoveCmd(
-	s *discordgo.Session,
-	m *discordgo.MessageCreate,
-	environment *common.DiscordBotEnvironment,
-	logger *zap.Logger,
-	requestType chan<- pair.Request,
-) {
-	url := strings.Replace(m.Content, "/remove ", "", 1)
-	if url == "" {
-		_, err := s.ChannelMessageSend(environment.ChatID, "Please provide a URL to remove")
+func yamlBlock(msg string) string {
+	return fmt.Sprintf("```yaml\n%s\n```", msg)
+}
+
+func (h *handlers) pingCmd(interaction) (response, error) {
+	chat, ok := h.env.Chat()
+	switch {
+	case !ok:
+		return textResponse(messages.PongText), nil
+	case chat.Muted:
+		return textResponse(messages.PongText + " I am currently sleeping" + messaging.SleepingMsg), nil
+	default:
+		return textResponse(messages.PongText + " I am monitoring" + messaging.MonitoringMsg), nil
+	}
+}
+
+func (h *handlers) helpCmd(interaction) (response, error) {
+	return textResponse(messages.HelpInfoText), nil
+}
+
+func (h *handlers) chatCmd(in interaction) (response, error) {
+	chat, ok := h.env.Chat()
+	if !ok || in.channelID != h.env.ChatID {
+		return textResponse(fmt.Sprintf("This chat id is %s, I am not sending alerts through it", in.channelID)), nil
+	}
+	if chat.Muted {
+		return textResponse(fmt.Sprintf("This chat id is %s, I am not sending alerts through it, the chat is muted",
+			in.channelID)), nil
+	}
+	return textResponse(fmt.Sprintf("This chat id is %s, I am sending alerts through it", in.channelID)), nil
+}
+
+func (h *handlers) muteCmd(mute bool) commandHandler {
+	return func(interaction) (response, error) {
+		changed, err := h.env.SetMute(mute)
 		if err != nil {
-			logger.Error("failed to send a message to discord", zap.Error(err))
+			return response{}, err
 		}
-		return
-	}
-	err := RequestRemoveNode(s, environment, m.ChannelID, requestType, url)
-	if err != nil {
-		_, sendErr := s.ChannelMessageSend(environment.ChatID, "Failed to remove a node, "+err.Error())
-		if sendErr != nil {
-			logger.Error("failed to send a message to discord", zap.Error(sendErr))
+		switch {
+		case mute && changed:
+			return textResponse("I had been monitoring, but going to sleep now.." + messaging.SleepingMsg), nil
+		case mute:
+			return textResponse("I had already been sleeping, continue sleeping.." + messaging.SleepingMsg), nil
+		case changed:
+			return textResponse("I had been asleep, but started monitoring now... " + messaging.MonitoringMsg), nil
+		default:
+			return textResponse("I had already been monitoring" + messaging.MonitoringMsg), nil
 		}
 	}
 }
 
-func handleAddCmd(
-	s *discordgo.Session,
-	m *discordgo.MessageCreate,
-	environment *common.DiscordBotEnvironment,
-	logger *zap.Logger,
-	requestType chan<- pair.Request,
-) {
-	url := strings.Replace(m.Content, "/add ", "", 1)
-	if url == "" {
-		_, err := s.ChannelMessageSend(environment.ChatID, "Please provide a URL to add")
-		if err != nil {
-			logger.Error("failed to send a message to discord", zap.Error(err))
+func (h *handlers) poolCmd(interaction) (response, error) {
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes list")
+	}
+	msg, err := h.env.NodesListMessage(nodes)
+	if err != nil {
+		return response{}, err
+	}
+	options := make([]discordgo.SelectMenuOption, 0, len(nodes))
+	for _, n := range nodes {
+		if len(options) == maxSelectMenuOptions {
+			break
 		}
-		return
+		label := n.URL
+		if n.Alias != "" {
+			label = n.Alias
+		}
+		options = append(options, discordgo.SelectMenuOption{Label: label, Value: n.URL, Description: n.URL})
+	}
+	resp := textResponse(msg + "\nUse /add to add a new node")
+	if len(options) > 0 {
+		resp.components = []discordgo.MessageComponent{selectMenu(components.RemoveNode, "Remove node", options)}
 	}
-	err := RequestAddNode(s, environment, m.ChannelID, requestType, url, false)
+	return resp, nil
+}
+
+func (h *handlers) subscriptionsCmd(interaction) (response, error) {
+	msg, err := h.env.SubscriptionsList()
 	if err != nil {
-		_, sendErr := s.ChannelMessageSend(environment.ChatID, "Failed to add a node, "+err.Error())
-		if sendErr != nil {
-			logger.Error("failed to send a message to discord", zap.Error(sendErr))
+		return response{}, err
+	}
+	var subscribed, unsubscribed []discordgo.SelectMenuOption
+	for _, name := range common.SortedAlertNames() {
+		alertType, _ := name.AlertType()
+		opt := discordgo.SelectMenuOption{Label: name.String(), Value: name.String()}
+		if h.env.IsChatSubscribed(alertType) {
+			subscribed = append(subscribed, opt)
+		} else {
+			unsubscribed = append(unsubscribed, opt)
 		}
 	}
+	resp := textResponse(msg)
+	if len(unsubscribed) > 0 {
+		resp.components = append(resp.components, selectMenu(components.SubscribeTo, "Subscribe to", unsubscribed))
+	}
+	if len(subscribed) > 0 {
+		resp.components = append(resp.components,
+			selectMenu(components.UnsubscribeFrom, "Unsubscribe from", subscribed),
+		)
+	}
+	return resp, nil
 }
 
-func handleStatusCmd(
-	s *discordgo.Session,
-	requestType chan<- pair.Request,
-	responsePairType <-chan pair.Response,
-	logger *zap.Logger,
-	env *common.DiscordBotEnvironment,
-) {
-	nodes, err := messaging.RequestAllNodes(requestType, responsePairType)
+func selectMenu(customID, placeholder string, options []discordgo.SelectMenuOption) discordgo.ActionsRow {
+	return discordgo.ActionsRow{Components: []discordgo.MessageComponent{
+		discordgo.SelectMenu{CustomID: customID, Placeholder: placeholder, MaxValues: 1, Options: options},
+	}}
+}
+
+func (h *handlers) statusCmd(interaction) (response, error) {
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
 	if err != nil {
-		logger.Error("failed to get nodes list", zap.Error(err))
+		return response{}, errors.Wrap(err, "failed to request nodes list")
 	}
 	urls := messaging.NodesToUrls(nodes)
-
-	nodesStatus, err := messaging.RequestNodesStatements(requestType, responsePairType, urls)
+	nodesStatus, err := messaging.RequestNodesStatements(h.requestCh, h.responseCh, urls)
 	if err != nil {
-		logger.Error("failed to request nodes status", zap.Error(err))
+		return response{}, errors.Wrap(err, "failed to request nodes status")
 	}
-	msg, statusCondition, err := common.HandleNodesStatus(nodesStatus, env.TemplatesExtension(), nodes)
+	msg, statusCondition, err := common.HandleNodesStatus(nodesStatus, h.env.TemplatesExtension(), nodes)
 	if err != nil {
-		logger.Error("failed to handle nodes status", zap.Error(err))
+		return response{}, errors.Wrap(err, "failed to handle nodes status")
 	}
 	if statusCondition.AllNodesAreOk {
 		msg = fmt.Sprintf("%d %s", statusCondition.NodesNumber, msg)
 	}
+	return textResponse(yamlBlock(msg)), nil
+}
+
+func (h *handlers) viewChainsCmd(interaction) (response, error) {
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes list")
+	}
+	urls := messaging.NodesToUrls(nodes)
+	nodesStatements, err := messaging.RequestNodesStatements(h.requestCh, h.responseCh, urls)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes status")
+	}
+	if nodesStatements.ErrMessage != "" {
+		return textResponse(nodesStatements.ErrMessage), nil
+	}
+	msg, err := common.HandleNodesChains(nodesStatements, h.env.TemplatesExtension())
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to handle nodes chains")
+	}
+	return textResponse(msg), nil
+}
 
-	msg = fmt.Sprintf("```yaml\n%s\n```", msg)
-	_, err = s.ChannelMessageSend(env.ChatID, msg)
+func (h *handlers) statementCmd(in interaction) (response, error) {
+	url, err := entities.CheckAndUpdateURL(in.option(nodeOption))
 	if err != nil {
-		logger.Error("failed to send a message to discord", zap.Error(err))
+		return textResponse(messages.InvalidURL), nil
 	}
+	height, err := strconv.Atoi(in.option(heightOption))
+	if err != nil {
+		return textResponse(fmt.Sprintf("Failed to parse height: %v", err)), nil
+	}
+	statement, err := messaging.RequestNodeStatement(h.requestCh, h.responseCh, url, height)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request node statement")
+	}
+	msg, err := common.HandleNodeStatement(statement, h.env.TemplatesExtension())
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to handle node statement")
+	}
+	return textResponse(msg), nil
 }
 
-func handleHelpCmd(s *discordgo.Session, environment *common.DiscordBotEnvironment, logger *zap.Logger) {
-	_, err := s.ChannelMessageSend(environment.ChatID, messages.HelpInfoText)
+func (h *handlers) alertsCmd(in interaction) (response, error) {
+	const defaultHistoryLimit = 5
+	historyLimit := defaultHistoryLimit
+	if rawLimit := in.option(limitOption); rawLimit != "" {
+		limit, err := strconv.Atoi(rawLimit)
+		if err != nil || limit <= 0 {
+			return textResponse(messages.AlertsWrongFormat), nil
+		}
+		historyLimit = limit
+	}
+	alerts, err := messaging.RequestAlerts(h.requestCh, h.responseCh, historyLimit)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request alerts")
+	}
+	msg, err := common.HandleAlerts(alerts, h.env.TemplatesExtension())
 	if err != nil {
-		logger.Error("failed to send a message to discord", zap.Error(err))
+		return response{}, errors.Wrap(err, "failed to handle alerts")
 	}
+	return textResponse(yamlBlock(msg)), nil
 }
 
-func handlePingCmd(s *discordgo.Session, environment *common.DiscordBotEnvironment, logger *zap.Logger) {
-	_, err := s.ChannelMessageSend(environment.ChatID, "Pong!")
+func (h *handlers) generatorsCmd(interaction) (response, error) {
+	generators, err := messaging.RequestGenerators(h.requestCh, h.responseCh)
 	if err != nil {
-		logger.Error("failed to send a message to discord", zap.Error(err))
+		return response{}, errors.Wrap(err, "failed to request generators")
 	}
+	msg, err := common.HandleGenerators(generators, h.env.TemplatesExtension())
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to handle generators")
+	}
+	return textResponse(yamlBlock(msg)), nil
 }
 
-func RequestAddNode(
-	s *discordgo.Session,
-	bot messaging.Bot,
-	chatID string,
-	requestType chan<- pair.Request,
-	url string,
-	specific bool,
-) error {
-	response, err := messaging.AddNewNodeHandler(chatID, bot, requestType, url, specific)
+// forkCmd shows the fork report of the state hash alert.
+func (h *handlers) forkCmd(in interaction) (response, error) {
+	alertID, err := messaging.ParseForkAlertID([]string{in.option(alertOption)})
+	if err != nil {
+		return textResponse(messaging.ForkWrongFormatMsg), nil
+	}
+	report, err := messaging.RequestForkReport(h.requestCh, h.responseCh, alertID)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request fork report")
+	}
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes")
+	}
+	msg, err := common.HandleForkReport(report, alertID.String(), h.env.TemplatesExtension(), nodes)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to handle fork report")
+	}
+	return textResponse(yamlBlock(msg)), nil
+}
+
+// muteAlertCmd acks or silences an alert. The alert is either given by the command options
+// or by the button attached to the alert message.
+func (h *handlers) muteAlertCmd(kind entities.AlertMuteKind) commandHandler {
+	return func(in interaction) (response, error) {
+		args := []string{in.option(alertOption)}
+		if node := in.option(nodeOption); node != "" {
+			args = append(args, node)
+		}
+		if duration := in.option(durationOption); duration != "" {
+			args = append(args, duration)
+		}
+		mute, err := messaging.ParseAlertMute(kind, args, time.Now())
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) {
+				return textResponse(messages.InvalidURL), nil
+			}
+			return textResponse(messaging.MuteAlertWrongFormatMessage(kind)), nil
+		}
+		msg, err := messaging.MuteAlertHandler(in.channelID, h.env, h.requestCh, h.responseCh, mute)
+		if err != nil {
+			if errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return textResponse(msg), nil
+			}
+			return response{}, errors.Wrapf(err, "failed to %s an alert", kind)
+		}
+		return textResponse(msg), nil
+	}
+}
+
+// maintenanceCmd puts the node under maintenance for the given duration or finishes its maintenance.
+func (h *handlers) maintenanceCmd(in interaction) (response, error) {
+	args := []string{in.option(nodeOption), in.option(durationOption)}
+	node, window, err := messaging.ParseNodeMaintenance(args, time.Now())
+	if err != nil {
+		return textResponse(messaging.MaintenanceWrongFormatMsg), nil
+	}
+	msg, err := messaging.NodeMaintenanceHandler(in.channelID, h.env, h.requestCh, h.responseCh, node, window)
 	if err != nil {
 		if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
-			_, err = s.ChannelMessageSend(chatID, response)
-			if err != nil {
-				return errors.Wrap(err, "failed to send a message to discord")
+			return textResponse(msg), nil
+		}
+		return response{}, errors.Wrap(err, "failed to set node maintenance")
+	}
+	return textResponse(msg), nil
+}
+
+// tagCmd adds the tags to the node or removes them from it.
+func (h *handlers) tagCmd(remove bool) func(in interaction) (response, error) {
+	wrongFormatMsg := messaging.TagWrongFormatMsg
+	if remove {
+		wrongFormatMsg = messaging.UntagWrongFormatMsg
+	}
+	return func(in interaction) (response, error) {
+		args := append([]string{in.option(nodeOption)}, strings.Fields(in.option(tagsOption))...)
+		node, tags, err := messaging.ParseNodeTags(args)
+		if err != nil {
+			return textResponse(fmt.Sprintf("%v\n\n%s", err, wrongFormatMsg)), nil
+		}
+		msg, err := messaging.NodeTagsHandler(in.channelID, h.env, h.requestCh, h.responseCh, node, tags, remove)
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return textResponse(msg), nil
 			}
+			return response{}, errors.Wrap(err, "failed to set node tags")
 		}
-		return errors.Wrap(err, "failed to add a new node")
+		return textResponse(msg), nil
 	}
-	_, err = s.ChannelMessageSend(chatID, response)
+}
+
+// reportsCmd shows the scheduled reports, the reports are sent only to the alerts channel.
+func (h *handlers) reportsCmd(interaction) (response, error) {
+	chat, ok := h.env.Chat()
+	if !ok {
+		return response{}, errors.Wrapf(chats.ErrChatNotFound, "failed to list reports of chat %s", h.env.ChatID)
+	}
+	msg, err := common.ReportsListMessage(h.reports.List(chat.ChatID), h.env.TemplatesExtension())
 	if err != nil {
-		return errors.Wrap(err, "failed to send a message to discord")
+		return response{}, err
 	}
-	return nil
+	return textResponse(msg), nil
 }
 
-func RequestRemoveNode(
-	s *discordgo.Session,
-	bot messaging.Bot,
-	chatID string,
-	requestType chan<- pair.Request,
-	url string,
-) error {
-	response, err := messaging.RemoveNodeHandler(chatID, bot, requestType, url)
+func (h *handlers) addReportCmd(in interaction) (response, error) {
+	chat, ok := h.env.Chat()
+	if !ok {
+		return response{}, errors.Wrapf(chats.ErrChatNotFound, "failed to add report to chat %s", h.env.ChatID)
+	}
+	kind, cron, err := reports.ParseAddArgs([]string{in.option(kindOption), in.option(cronOption)})
+	if err != nil {
+		return textResponse(reports.AddWrongFormatMsg), nil
+	}
+	r, err := h.reports.Add(chat.ChatID, kind, cron)
+	if err != nil {
+		if errors.Is(err, reports.ErrInvalidCron) {
+			return textResponse(fmt.Sprintf("Failed to add report: %v\n\n%s", err, reports.AddWrongFormatMsg)), nil
+		}
+		return response{}, errors.Wrap(err, "failed to add report")
+	}
+	return textResponse(fmt.Sprintf("Report %d (%s) has been scheduled on `%s`", r.ID, r.Kind, r.Cron)), nil
+}
+
+func (h *handlers) removeReportCmd(in interaction) (response, error) {
+	chat, ok := h.env.Chat()
+	if !ok {
+		return response{}, errors.Wrapf(chats.ErrChatNotFound, "failed to remove report of chat %s", h.env.ChatID)
+	}
+	id, err := reports.ParseID(in.option(idOption))
+	if err != nil {
+		return textResponse(reports.RemoveWrongFormatMsg), nil
+	}
+	if removeErr := h.reports.Remove(chat.ChatID, id); removeErr != nil {
+		if errors.Is(removeErr, reports.ErrReportNotFound) {
+			return textResponse(fmt.Sprintf("Report %d hasn't been found in this channel", id)), nil
+		}
+		return response{}, errors.Wrap(removeErr, "failed to remove report")
+	}
+	return textResponse(fmt.Sprintf("Report %d has been removed", id)), nil
+}
+
+func (h *handlers) addCmd(specific bool) commandHandler {
+	return func(in interaction) (response, error) {
+		msg, err := messaging.AddNewNodeHandler(in.channelID, h.env, h.requestCh, in.option(nodeOption), specific)
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return textResponse(msg), nil
+			}
+			return response{}, errors.Wrap(err, "failed to add a new node")
+		}
+		return textResponse(msg), nil
+	}
+}
+
+func (h *handlers) removeCmd(in interaction) (response, error) {
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes list")
+	}
+	url := common.GetNodeURLByAlias(in.option(nodeOption), nodes)
+	msg, err := messaging.RemoveNodeHandler(in.channelID, h.env, h.requestCh, url)
+	if err != nil {
+		if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
+			return textResponse(msg), nil
+		}
+		return response{}, errors.Wrap(err, "failed to remove a node")
+	}
+	return textResponse(msg), nil
+}
+
+func (h *handlers) addAliasCmd(in interaction) (response, error) {
+	msg, err := messaging.UpdateAliasHandler(in.channelID, h.env, h.requestCh,
+		in.option(nodeOption), in.option(aliasOption),
+	)
 	if err != nil {
 		if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
-			_, err = s.ChannelMessageSend(chatID, response)
-			if err != nil {
-				return errors.Wrap(err, "failed to send a message to discord")
+			return textResponse(msg), nil
+		}
+		return response{}, errors.Wrap(err, "failed to update a node")
+	}
+	return textResponse(msg), nil
+}
+
+func (h *handlers) aliasesCmd(interaction) (response, error) {
+	nodes, err := messaging.RequestAllNodes(h.requestCh, h.responseCh)
+	if err != nil {
+		return response{}, errors.Wrap(err, "failed to request nodes list")
+	}
+	var msg string
+	for _, n := range nodes {
+		if n.Alias != "" {
+			msg += fmt.Sprintf("Node: %s\nAlias: %s\n\n", n.URL, n.Alias)
+		}
+	}
+	if msg == "" {
+		return textResponse("No aliases have been found"), nil
+	}
+	return textResponse(yamlBlock(msg)), nil
+}
+
+func (h *handlers) subscribeCmd(in interaction) (response, error) {
+	alertName := entities.AlertName(in.option(alertOption))
+	alertType, ok := alertName.AlertType()
+	if !ok {
+		return textResponse("Sorry, this alert does not exist"), nil
+	}
+	if h.env.IsChatSubscribed(alertType) {
+		return textResponse("I am already subscribed to it"), nil
+	}
+	if err := h.env.SubscribeToAlert(alertType); err != nil {
+		return response{}, err
+	}
+	return h.subscriptionsResponse(fmt.Sprintf("I succesfully subscribed to %s", alertName))
+}
+
+func (h *handlers) unsubscribeCmd(in interaction) (response, error) {
+	alertName := entities.AlertName(in.option(alertOption))
+	alertType, ok := alertName.AlertType()
+	if !ok {
+		return textResponse("Sorry, this alert does not exist"), nil
+	}
+	if !h.env.IsChatSubscribed(alertType) {
+		return textResponse("I was not subscribed to it"), nil
+	}
+	if err := h.env.UnsubscribeFromAlert(alertType); err != nil {
+		return response{}, err
+	}
+	return h.subscriptionsResponse(fmt.Sprintf("I succesfully unsubscribed from %s", alertName))
+}
+
+// groupSubscriptionCmd makes the chat receive or stop receiving the alerts about the nodes with the given tag.
+func (h *handlers) groupSubscriptionCmd(unsubscribe bool) func(in interaction) (response, error) {
+	return func(in interaction) (response, error) {
+		tag, err := entities.NormalizeTag(in.option(groupOption))
+		if err != nil {
+			return textResponse(err.Error()), nil
+		}
+		chat, _ := h.env.Chat()
+		subscribed := slices.Contains(chat.Groups, tag)
+		switch {
+		case !unsubscribe && subscribed:
+			return textResponse("I am already subscribed to this group"), nil
+		case unsubscribe && !subscribed:
+			return textResponse("I was not subscribed to this group"), nil
+		case unsubscribe:
+			if unsubscribeErr := h.env.UnsubscribeFromGroup(tag); unsubscribeErr != nil {
+				return response{}, unsubscribeErr
 			}
+			return h.subscriptionsResponse(fmt.Sprintf("I succesfully unsubscribed from group %s", tag))
+		default:
+			if subscribeErr := h.env.SubscribeToGroup(tag); subscribeErr != nil {
+				return response{}, subscribeErr
+			}
+			return h.subscriptionsResponse(fmt.Sprintf("I succesfully subscribed to group %s", tag))
 		}
-		return errors.Wrap(err, "failed to remove a node")
 	}
-	_, err = s.ChannelMessageSend(chatID, response)
+}
+
+func (h *handlers) subscriptionsResponse(header string) (response, error) {
+	msg, err := h.env.SubscriptionsList()
 	if err != nil {
-		return errors.Wrap(err, "failed to send a message to discord")
+		return response{}, errors.Wrap(err, "failed to receive list of subscriptions")
 	}
-	return nil
+	return textResponse(header + "\n" + msg), nil
 }
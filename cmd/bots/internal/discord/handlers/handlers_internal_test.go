@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"sort"
+	"testing"
+
+	"nodemon/cmd/bots/internal/common/rbac"
+	"nodemon/cmd/bots/internal/discord/components"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+func TestCommandsAreHandled(t *testing.T) {
+	h := new(handlers)
+	handled := h.handledCommands()
+	registered := make([]string, 0, len(handled))
+	for _, cmd := range Commands() {
+		registered = append(registered, "/"+cmd.Name)
+	}
+	names := make([]string, 0, len(handled))
+	for name := range handled {
+		names = append(names, name)
+	}
+	sort.Strings(registered)
+	sort.Strings(names)
+	assert.Equal(t, names, registered, "every slash command must have a handler")
+
+	for name, cmd := range handled {
+		if rbac.RequiredRole(name) > rbac.ViewerRole {
+			assert.True(t, cmd.alertsChannelOnly, "the privileged command %s must be run in the alerts channel", name)
+		}
+	}
+}
+
+func TestParseInteraction_SlashCommand(t *testing.T) {
+	i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type:      discordgo.InteractionApplicationCommand,
+		ChannelID: "42",
+		Data: discordgo.ApplicationCommandInteractionData{
+			Name: "statement",
+			Options: []*discordgo.ApplicationCommandInteractionDataOption{
+				{Name: nodeOption, Type: discordgo.ApplicationCommandOptionString, Value: "node.example.com"},
+				{Name: heightOption, Type: discordgo.ApplicationCommandOptionInteger, Value: float64(4000000)},
+			},
+		},
+	}}
+	in, ok := parseInteraction(i)
+	require.True(t, ok)
+	assert.Equal(t, "42", in.channelID)
+	assert.Equal(t, "/statement", in.command)
+	assert.Equal(t, "node.example.com", in.option(nodeOption))
+	assert.Equal(t, "4000000", in.option(heightOption))
+	assert.Equal(t, []string{"node.example.com", "4000000"}, in.args)
+	assert.Empty(t, in.option(durationOption))
+}
+
+func TestParseInteraction_Components(t *testing.T) {
+	alertID := crypto.MustFastHash([]byte("alert"))
+	ackButton, ok := components.AckAlertButton(alertID).Components[0].(discordgo.Button)
+	require.True(t, ok)
+	tests := []struct {
+		data    discordgo.MessageComponentInteractionData
+		command string
+		option  string
+		value   string
+	}{
+		{
+			data:    discordgo.MessageComponentInteractionData{CustomID: ackButton.CustomID},
+			command: "/ack",
+			option:  alertOption,
+			value:   alertID.String(),
+		},
+		{
+			data:    discordgo.MessageComponentInteractionData{CustomID: components.RemoveNode, Values: []string{"n"}},
+			command: "/remove",
+			option:  nodeOption,
+			value:   "n",
+		},
+		{
+			data: discordgo.MessageComponentInteractionData{
+				CustomID: components.SubscribeTo, Values: []string{"HeightAlert"},
+			},
+			command: "/subscribe",
+			option:  alertOption,
+			value:   "HeightAlert",
+		},
+		{
+			data: discordgo.MessageComponentInteractionData{
+				CustomID: components.UnsubscribeFrom, Values: []string{"HeightAlert"},
+			},
+			command: "/unsubscribe",
+			option:  alertOption,
+			value:   "HeightAlert",
+		},
+	}
+	for _, test := range tests {
+		i := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+			Type: discordgo.InteractionMessageComponent,
+			Data: test.data,
+		}}
+		in, parsed := parseInteraction(i)
+		require.True(t, parsed, test.command)
+		assert.Equal(t, test.command, in.command)
+		assert.Equal(t, test.value, in.option(test.option))
+	}
+
+	unknown := &discordgo.InteractionCreate{Interaction: &discordgo.Interaction{
+		Type: discordgo.InteractionMessageComponent,
+		Data: discordgo.MessageComponentInteractionData{CustomID: "unknown", Values: []string{"v"}},
+	}}
+	_, ok = parseInteraction(unknown)
+	assert.False(t, ok)
+}
@@ -2,9 +2,43 @@ package messages
 
 const (
 	HelpInfoText = "" +
-		"ℹ️ This is a bot for monitoring Waves nodes. The next commands are available:\n\n\n" +
-		"`/ping` - the command to check whether the bot is available,\n\n" +
-		"`/status` - to see status of all monitored nodes,\n\n" +
-		"`/add <node_name>` - to add a node to the list,\n\n" +
-		"`/remove <node_name>` - to remove a node from the list."
+		"ℹ️ This is a bot for monitoring Waves nodes. The next commands are available:\n\n" +
+		"`/ping` - to check whether the bot is available and what its current state is\n" +
+		"`/chat` - to see the ID and the state of this chat\n" +
+		"`/start` - to make the bot **start sending alerts**\n" +
+		"`/mute` - to make the bot **stop sending alerts**\n" +
+		"`/pool` - to see the list of nodes and edit it\n" +
+		"`/subscriptions` - to see the list of subscriptions and edit it\n" +
+		"`/status` - to see the status of all nodes\n" +
+		"`/viewchains` - to see the chains of the nodes\n" +
+		"`/statement <node> <height>` - to see a node statement at a specific height\n" +
+		"`/alerts [limit]` - to see the active alerts and the last resolved ones\n" +
+		"`/generators` - to see the block generators statistics\n" +
+		"`/fork <alert_id>` - to see the block-by-block report of the state hash alert fork\n" +
+		"`/ack <alert_id> [duration]` - to stop repeating the alert until it is resolved, " +
+		"the alert message has the button for it too\n" +
+		"`/silence <alert_id> [duration]` or `/silence <alert_name> <node> [duration]` - " +
+		"to stop sending the matching alerts\n" +
+		"`/maintenance <node> <duration|off>` - to suppress the alerts about the node for the given duration\n" +
+		"`/reports` - to see the scheduled reports of the alerts channel\n" +
+		"`/add_report <status|summary|chains> <cron>` - to schedule a report, e.g. `/add_report summary 0 0 9 * * MON`\n" +
+		"`/remove_report <id>` - to remove a scheduled report\n" +
+		"`/add <node>` - to add a node to the list\n" +
+		"`/add_specific <node>` - to add a specific node to the list\n" +
+		"`/remove <node>` - to remove a node from the list\n" +
+		"`/add_alias <node> <alias>` - to set the alias of the node\n" +
+		"`/aliases` - to see the matching list with aliases\n" +
+		"`/tag <node> <tags>` - to add the tags to the node, e.g. `/tag mynode our-validators`\n" +
+		"`/untag <node> <tags>` - to remove the tags from the node\n" +
+		"`/subscribe <alert>` - to subscribe to a specific alert\n" +
+		"`/unsubscribe <alert>` - to unsubscribe from a specific alert\n" +
+		"`/subscribe_group <tag>` - to receive only the alerts about the nodes with the tag\n" +
+		"`/unsubscribe_group <tag>` - to stop receiving the alerts about the nodes with the tag"
+
+	PongText = "Pong!🏓"
+)
+
+const (
+	InvalidURL        = "Invalid URL"
+	AlertsWrongFormat = "Alerts should be in format: /alerts [positive history limit]"
 )
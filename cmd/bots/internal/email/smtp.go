@@ -0,0 +1,190 @@
+package email
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"nodemon/cmd/bots/internal/common"
+
+	"github.com/pkg/errors"
+)
+
+// SMTPConfig describes the SMTP server and the envelope of the emails.
+type SMTPConfig struct {
+	Address  string // host:port
+	Username string // empty value disables authentication
+	Password string
+	// StartTLS requires the server to support STARTTLS. If it's false, STARTTLS is used only if it's supported.
+	StartTLS bool
+	From     string
+	To       []string
+	Timeout  time.Duration
+}
+
+// SMTPMailer sends the emails over SMTP. Every email is sent in a new connection.
+type SMTPMailer struct {
+	cfg       SMTPConfig
+	host      string
+	from      string   // envelope sender address without the display name
+	to        []string // envelope recipient addresses without the display names
+	tlsConfig *tls.Config
+	now       func() time.Time
+}
+
+func NewSMTPMailer(cfg SMTPConfig) (*SMTPMailer, error) {
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid SMTP server address '%s'", cfg.Address)
+	}
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid sender address '%s'", cfg.From)
+	}
+	if len(cfg.To) == 0 {
+		return nil, errors.New("no recipients")
+	}
+	to := make([]string, 0, len(cfg.To))
+	for _, rcpt := range cfg.To {
+		addr, parseErr := mail.ParseAddress(rcpt)
+		if parseErr != nil {
+			return nil, errors.Wrapf(parseErr, "invalid recipient address '%s'", rcpt)
+		}
+		to = append(to, addr.Address)
+	}
+	return &SMTPMailer{
+		cfg:       cfg,
+		host:      host,
+		from:      from.Address,
+		to:        to,
+		tlsConfig: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12},
+		now:       time.Now,
+	}, nil
+}
+
+func (m *SMTPMailer) Send(email common.Mail) error {
+	msg, err := m.compose(email)
+	if err != nil {
+		return errors.Wrap(err, "failed to compose email")
+	}
+	conn, err := net.DialTimeout("tcp", m.cfg.Address, m.cfg.Timeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to SMTP server '%s'", m.cfg.Address)
+	}
+	if deadlineErr := conn.SetDeadline(m.now().Add(m.cfg.Timeout)); deadlineErr != nil {
+		_ = conn.Close()
+		return errors.Wrap(deadlineErr, "failed to set SMTP connection deadline")
+	}
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		_ = conn.Close()
+		return errors.Wrap(err, "failed to start SMTP session")
+	}
+	defer func() { _ = c.Close() }()
+	if err = m.send(c, msg); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+func (m *SMTPMailer) send(c *smtp.Client, msg []byte) error {
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(m.tlsConfig); err != nil {
+			return errors.Wrap(err, "STARTTLS failed")
+		}
+	} else if m.cfg.StartTLS {
+		return errors.New("SMTP server doesn't support STARTTLS")
+	}
+	if m.cfg.Username != "" {
+		// PlainAuth refuses to send the credentials over an unencrypted connection to a remote host
+		auth := smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, m.host)
+		if err := c.Auth(auth); err != nil {
+			return errors.Wrap(err, "SMTP authentication failed")
+		}
+	}
+	if err := c.Mail(m.from); err != nil {
+		return errors.Wrap(err, "MAIL command failed")
+	}
+	for _, to := range m.to {
+		if err := c.Rcpt(to); err != nil {
+			return errors.Wrapf(err, "RCPT command failed for '%s'", to)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return errors.Wrap(err, "DATA command failed")
+	}
+	if _, err = w.Write(msg); err != nil {
+		_ = w.Close()
+		return errors.Wrap(err, "failed to write email")
+	}
+	return errors.Wrap(w.Close(), "failed to finish email")
+}
+
+// compose builds the MIME message. The email has both plain text and HTML parts if the HTML is set.
+func (m *SMTPMailer) compose(email common.Mail) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	header := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(buf, "%s: %s\r\n", key, value)
+		}
+	}
+	header("From", m.cfg.From)
+	header("To", strings.Join(m.cfg.To, ", "))
+	header("Subject", mime.QEncoding.Encode("utf-8", email.Subject))
+	header("Date", m.now().Format(time.RFC1123Z))
+	header("Message-ID", email.MessageID)
+	header("In-Reply-To", email.InReplyTo)
+	header("References", email.InReplyTo)
+	header("MIME-Version", "1.0")
+	if email.HTML == "" {
+		header("Content-Type", "text/plain; charset=utf-8")
+		header("Content-Transfer-Encoding", "quoted-printable")
+		buf.WriteString("\r\n")
+		if err := writeQuotedPrintable(buf, email.Text); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	mw := multipart.NewWriter(buf)
+	header("Content-Type", fmt.Sprintf("multipart/alternative; boundary=%q", mw.Boundary()))
+	buf.WriteString("\r\n")
+	for _, part := range []struct{ contentType, body string }{
+		{"text/plain; charset=utf-8", email.Text},
+		{"text/html; charset=utf-8", email.HTML},
+	} {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err = writeQuotedPrintable(w, part.body); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeQuotedPrintable encodes the text, the line breaks are written as CRLF.
+func writeQuotedPrintable(w io.Writer, s string) error {
+	qp := quotedprintable.NewWriter(w)
+	if _, err := qp.Write([]byte(s)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
@@ -0,0 +1,183 @@
+package email_test
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"nodemon/cmd/bots/internal/common"
+	"nodemon/cmd/bots/internal/email"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type receivedMail struct {
+	auth string
+	from string
+	to   []string
+	data string
+}
+
+// startSMTPStandIn starts the minimal SMTP server which accepts a single session.
+func startSMTPStandIn(t *testing.T, extensions ...string) (string, <-chan receivedMail) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = l.Close() })
+	out := make(chan receivedMail, 1)
+	go func() {
+		conn, acceptErr := l.Accept()
+		if acceptErr != nil {
+			return
+		}
+		defer conn.Close()
+		tc := textproto.NewConn(conn)
+		var rcv receivedMail
+		reply := func(format string, args ...any) { _ = tc.PrintfLine(format, args...) }
+		reply("220 localhost ESMTP stand-in")
+		for {
+			line, readErr := tc.ReadLine()
+			if readErr != nil {
+				return
+			}
+			cmd, arg, _ := strings.Cut(line, " ")
+			switch strings.ToUpper(cmd) {
+			case "EHLO":
+				for _, ext := range extensions {
+					reply("250-%s", ext)
+				}
+				reply("250 localhost")
+			case "AUTH":
+				rcv.auth = arg
+				reply("235 Authentication successful")
+			case "MAIL":
+				rcv.from = arg
+				reply("250 OK")
+			case "RCPT":
+				rcv.to = append(rcv.to, arg)
+				reply("250 OK")
+			case "DATA":
+				reply("354 Go ahead")
+				data, dataErr := io.ReadAll(tc.DotReader())
+				if dataErr != nil {
+					return
+				}
+				rcv.data = string(data)
+				reply("250 OK")
+			case "QUIT":
+				reply("221 Bye")
+				out <- rcv
+				return
+			default:
+				reply("502 Not implemented")
+			}
+		}
+	}()
+	return l.Addr().String(), out
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	addr, received := startSMTPStandIn(t, "AUTH PLAIN")
+	mailer, err := email.NewSMTPMailer(email.SMTPConfig{
+		Address:  addr,
+		Username: "user",
+		Password: "secret",
+		From:     "nodemon@example.com",
+		To:       []string{"ops@example.com", "dev@example.com"},
+		Timeout:  5 * time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mailer.Send(common.Mail{
+		Subject:   "[nodemon mainnet] Node is unreachable ✅",
+		Text:      "Node is unreachable\nsecond line",
+		HTML:      "<b>Node is unreachable</b>",
+		MessageID: "<2@nodemon>",
+		InReplyTo: "<1@nodemon>",
+	}))
+
+	var rcv receivedMail
+	select {
+	case rcv = <-received:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout waiting for email")
+	}
+	assert.Equal(t, "PLAIN "+base64.StdEncoding.EncodeToString([]byte("\x00user\x00secret")), rcv.auth)
+	assert.Equal(t, "FROM:<nodemon@example.com>", rcv.from)
+	assert.Equal(t, []string{"TO:<ops@example.com>", "TO:<dev@example.com>"}, rcv.to)
+
+	msg, err := mail.ReadMessage(strings.NewReader(rcv.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "[nodemon mainnet] Node is unreachable ✅", subject)
+	assert.Equal(t, "<2@nodemon>", msg.Header.Get("Message-ID"))
+	assert.Equal(t, "<1@nodemon>", msg.Header.Get("In-Reply-To"))
+
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+	mr := multipart.NewReader(msg.Body, params["boundary"])
+	var parts []string
+	for {
+		part, partErr := mr.NextPart() // decodes quoted-printable
+		if partErr == io.EOF {
+			break
+		}
+		require.NoError(t, partErr)
+		body, readErr := io.ReadAll(bufio.NewReader(part))
+		require.NoError(t, readErr)
+		parts = append(parts, part.Header.Get("Content-Type")+": "+string(body))
+	}
+	assert.Equal(t, []string{
+		"text/plain; charset=utf-8: Node is unreachable\nsecond line",
+		"text/html; charset=utf-8: <b>Node is unreachable</b>",
+	}, parts)
+}
+
+func TestSMTPMailer_StartTLSRequired(t *testing.T) {
+	addr, _ := startSMTPStandIn(t)
+	mailer, err := email.NewSMTPMailer(email.SMTPConfig{
+		Address:  addr,
+		StartTLS: true,
+		From:     "nodemon@example.com",
+		To:       []string{"ops@example.com"},
+		Timeout:  5 * time.Second,
+	})
+	require.NoError(t, err)
+	err = mailer.Send(common.Mail{Subject: "test", Text: "test"})
+	assert.ErrorContains(t, err, "doesn't support STARTTLS")
+}
+
+func TestSMTPMailer_DisplayNames(t *testing.T) {
+	addr, received := startSMTPStandIn(t)
+	mailer, err := email.NewSMTPMailer(email.SMTPConfig{
+		Address: addr,
+		From:    "Nodemon <nodemon@example.com>",
+		To:      []string{"Ops Team <ops@example.com>", "dev@example.com"},
+		Timeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	require.NoError(t, mailer.Send(common.Mail{Subject: "test", Text: "test"}))
+
+	var rcv receivedMail
+	select {
+	case rcv = <-received:
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "timeout waiting for email")
+	}
+	// the envelope has the bare addresses, the headers keep the display names
+	assert.Equal(t, "FROM:<nodemon@example.com>", rcv.from)
+	assert.Equal(t, []string{"TO:<ops@example.com>", "TO:<dev@example.com>"}, rcv.to)
+	msg, err := mail.ReadMessage(strings.NewReader(rcv.data))
+	require.NoError(t, err)
+	assert.Equal(t, "Nodemon <nodemon@example.com>", msg.Header.Get("From"))
+	assert.Equal(t, "Ops Team <ops@example.com>, dev@example.com", msg.Header.Get("To"))
+}
@@ -2,11 +2,15 @@ package handlers
 
 import (
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"nodemon/cmd/bots/internal/common"
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/rbac"
+	"nodemon/cmd/bots/internal/common/reports"
 	"nodemon/cmd/bots/internal/telegram/buttons"
 	"nodemon/cmd/bots/internal/telegram/messages"
 	"nodemon/pkg/entities"
@@ -19,74 +23,197 @@ import (
 
 func InitTgHandlers(
 	env *common.TelegramBotEnvironment,
+	authorizer *rbac.Authorizer,
+	reportsManager *reports.Manager,
 	zapLogger *zap.Logger,
 	requestCh chan<- pair.Request,
 	responseCh <-chan pair.Response,
 ) {
+	isKnownChatMiddleware := func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			if !env.IsKnownChat(c.Chat().ID) {
+				return c.Send(messages.UnknownChat)
+			}
+			return next(c)
+		}
+	}
+	canManageChatMiddleware := func(next telebot.HandlerFunc) telebot.HandlerFunc {
+		return func(c telebot.Context) error {
+			if !env.CanManageChat(c.Chat().ID, senderID(c)) {
+				return c.Send("Sorry, you have no right to use this command")
+			}
+			return next(c)
+		}
+	}
 	isEligibleForActionMiddleware := func(next telebot.HandlerFunc) telebot.HandlerFunc {
 		return func(c telebot.Context) error {
-			if !env.IsEligibleForAction(strconv.FormatInt(c.Chat().ID, 10)) {
+			if !env.CanModify(c.Chat().ID, senderID(c)) {
 				return c.Send("Sorry, you have no right to use this command")
 			}
 			return next(c)
 		}
 	}
+	// handle registers the command handler, the access to the command is checked after the chat middlewares
+	handle := func(command string, h telebot.HandlerFunc, m ...telebot.MiddlewareFunc) {
+		authorizeMiddleware := func(next telebot.HandlerFunc) telebot.HandlerFunc {
+			return func(c telebot.Context) error {
+				if !authorize(c, authorizer, command) {
+					return c.Send("Sorry, you have no right to use this command")
+				}
+				return next(c)
+			}
+		}
+		env.Bot.Handle(command, h, append(m, authorizeMiddleware)...)
+	}
 
-	env.Bot.Handle("/chat", func(c telebot.Context) error {
-		return c.Send(fmt.Sprintf("I am sending alerts through %d chat id", env.ChatID))
-	})
+	handle("/chat", chatCmd(env))
 
-	env.Bot.Handle("/ping", pingCmd(env))
+	handle("/ping", pingCmd(env))
 
-	env.Bot.Handle("/start", startCmd(env), isEligibleForActionMiddleware)
+	handle("/start", startCmd(env), canManageChatMiddleware)
 
-	env.Bot.Handle("/mute", muteCmd(env), isEligibleForActionMiddleware)
+	handle("/mute", muteCmd(env), canManageChatMiddleware)
 
-	env.Bot.Handle("/help", func(c telebot.Context) error {
+	handle("/help", func(c telebot.Context) error {
 		return c.Send(messages.HelpInfoText, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
 	})
 
-	env.Bot.Handle("\f"+buttons.AddNewNode, func(c telebot.Context) error {
+	handle("\f"+buttons.AddNewNode, func(c telebot.Context) error {
 		return c.Send(messages.AddNewNodeMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	})
-	env.Bot.Handle("\f"+buttons.RemoveNode, func(c telebot.Context) error {
+	handle("\f"+buttons.RemoveNode, func(c telebot.Context) error {
 		return c.Send(messages.RemoveNode, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	})
-	env.Bot.Handle("\f"+buttons.SubscribeTo, func(c telebot.Context) error {
+	handle("\f"+buttons.SubscribeTo, func(c telebot.Context) error {
 		return c.Send(messages.SubscribeTo, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	})
-	env.Bot.Handle("\f"+buttons.UnsubscribeFrom, func(c telebot.Context) error {
+	handle("\f"+buttons.UnsubscribeFrom, func(c telebot.Context) error {
 		return c.Send(messages.UnsubscribeFrom, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	})
 
-	env.Bot.Handle("/pool", func(c telebot.Context) error {
+	handle("/pool", func(c telebot.Context) error {
 		return editPool(c, env, requestCh, responseCh)
 	}, isEligibleForActionMiddleware)
-	env.Bot.Handle("/subscriptions", func(c telebot.Context) error {
+	handle("/subscriptions", func(c telebot.Context) error {
 		return editSubscriptions(c, env)
-	}, isEligibleForActionMiddleware)
+	}, canManageChatMiddleware)
+
+	handle("/add", addCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+
+	handle("/add_specific", addSpecificCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+
+	handle("/remove", removeCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+
+	handle("/add_alias", addAliasCmd(env, requestCh), isEligibleForActionMiddleware)
+
+	handle("/aliases", aliasesCmd(requestCh, responseCh, zapLogger), isKnownChatMiddleware)
+
+	handle("/subscribe", subscribeCmd(env), canManageChatMiddleware)
+
+	handle("/unsubscribe", unsubscribeCmd(env), canManageChatMiddleware)
+
+	handle("/statement", statementCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
+
+	env.Bot.Handle(telebot.OnText, onTextMsgHandler(env, authorizer, requestCh, responseCh))
+
+	handle("/status", statusCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
+
+	handle("/viewchains", viewChains(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
+
+	handle("/alerts", alertsCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
+
+	handle("/generators", generatorsCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
 
-	env.Bot.Handle("/add", addCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+	handle("/fork", forkCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger),
+		isKnownChatMiddleware,
+	)
+
+	handle("/ack", muteAlertCmd(env, requestCh, responseCh, entities.AckAlertMuteKind),
+		isEligibleForActionMiddleware,
+	)
 
-	env.Bot.Handle("/add_specific", addSpecificCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+	handle("/silence", muteAlertCmd(env, requestCh, responseCh, entities.SilenceAlertMuteKind),
+		isEligibleForActionMiddleware,
+	)
 
-	env.Bot.Handle("/remove", removeCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
+	handle("/maintenance", maintenanceCmd(env, requestCh, responseCh), isEligibleForActionMiddleware)
 
-	env.Bot.Handle("/add_alias", addAliasCmd(env, requestCh), isEligibleForActionMiddleware)
+	handle("/tag", tagCmd(env, requestCh, responseCh, false), isEligibleForActionMiddleware)
 
-	env.Bot.Handle("/aliases", aliasesCmd(requestCh, responseCh, zapLogger))
+	handle("/untag", tagCmd(env, requestCh, responseCh, true), isEligibleForActionMiddleware)
 
-	env.Bot.Handle("/subscribe", subscribeCmd(env), isEligibleForActionMiddleware)
+	handle("/subscribe_group", groupSubscriptionCmd(env, false), canManageChatMiddleware)
 
-	env.Bot.Handle("/unsubscribe", unsubscribeCmd(env), isEligibleForActionMiddleware)
+	handle("/unsubscribe_group", groupSubscriptionCmd(env, true), canManageChatMiddleware)
 
-	env.Bot.Handle("/statement", statementCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger))
+	handle("/reports", reportsCmd(env, reportsManager), isKnownChatMiddleware)
 
-	env.Bot.Handle(telebot.OnText, onTextMsgHandler(env, requestCh, responseCh), isEligibleForActionMiddleware)
+	handle("/add_report", addReportCmd(reportsManager), canManageChatMiddleware)
 
-	env.Bot.Handle("/status", statusCmd(requestCh, responseCh, env.TemplatesExtension(), zapLogger))
+	handle("/remove_report", removeReportCmd(reportsManager), canManageChatMiddleware)
+}
+
+func reportsCmd(env *common.TelegramBotEnvironment, manager *reports.Manager) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		msg, err := common.ReportsListMessage(manager.List(entities.ChatID(c.Chat().ID)), env.TemplatesExtension())
+		if err != nil {
+			return err
+		}
+		return c.Send(msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+func addReportCmd(manager *reports.Manager) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		kind, cron, err := reports.ParseAddArgs(c.Args())
+		if err != nil {
+			return c.Send(reports.AddWrongFormatMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		r, err := manager.Add(entities.ChatID(c.Chat().ID), kind, cron)
+		if err != nil {
+			if errors.Is(err, reports.ErrInvalidCron) {
+				return c.Send(fmt.Sprintf("Failed to add report: %v\n\n%s", err, reports.AddWrongFormatMsg),
+					&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+				)
+			}
+			return errors.Wrap(err, "failed to add report")
+		}
+		return c.Send(fmt.Sprintf("Report %d (%s) has been scheduled on '%s'", r.ID, r.Kind, r.Cron),
+			&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+		)
+	}
+}
 
-	env.Bot.Handle("/viewchains", viewChains(requestCh, responseCh, env.TemplatesExtension(), zapLogger))
+func removeReportCmd(manager *reports.Manager) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) != 1 {
+			return c.Send(reports.RemoveWrongFormatMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		id, err := reports.ParseID(args[0])
+		if err != nil {
+			return c.Send(reports.RemoveWrongFormatMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		if removeErr := manager.Remove(entities.ChatID(c.Chat().ID), id); removeErr != nil {
+			if errors.Is(removeErr, reports.ErrReportNotFound) {
+				return c.Send(fmt.Sprintf("Report %d hasn't been found in this chat", id),
+					&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+				)
+			}
+			return errors.Wrap(removeErr, "failed to remove report")
+		}
+		return c.Send(fmt.Sprintf("Report %d has been removed", id), &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+	}
 }
 
 func removeCmd(
@@ -142,30 +269,48 @@ func aliasesCmd(
 
 func onTextMsgHandler(
 	environment *common.TelegramBotEnvironment,
+	authorizer *rbac.Authorizer,
 	requestType chan<- pair.Request,
 	responsePairType <-chan pair.Response,
 ) func(c telebot.Context) error {
 	return func(c telebot.Context) error {
+		if !environment.CanManageChat(c.Chat().ID, senderID(c)) {
+			return nil // ignore the chat conversation, the text commands are available only to the chat admins
+		}
 		command := strings.ToLower(c.Text())
+		var handler func() error
 		switch {
 		case strings.HasPrefix(command, "add specific"):
 			u := strings.TrimPrefix(command, "add specific ")
-			return AddNewNodeHandler(c, environment, requestType, responsePairType, u, true)
+			handler = func() error {
+				return AddNewNodeHandler(c, environment, requestType, responsePairType, u, true)
+			}
+			command = "/add_specific"
 		case strings.HasPrefix(command, "add"):
 			u := strings.TrimPrefix(command, "add ")
-			return AddNewNodeHandler(c, environment, requestType, responsePairType, u, false)
+			handler = func() error {
+				return AddNewNodeHandler(c, environment, requestType, responsePairType, u, false)
+			}
+			command = "/add"
 		case strings.HasPrefix(command, "remove"):
 			u := strings.TrimPrefix(command, "remove ")
-			return RemoveNodeHandler(c, environment, requestType, responsePairType, u)
+			handler = func() error { return RemoveNodeHandler(c, environment, requestType, responsePairType, u) }
+			command = "/remove"
 		case strings.HasPrefix(command, "subscribe to"):
 			alertName := strings.TrimSpace(strings.TrimPrefix(command, "subscribe to "))
-			return SubscribeHandler(c, environment, entities.AlertName(alertName))
+			handler = func() error { return SubscribeHandler(c, environment, entities.AlertName(alertName)) }
+			command = "/subscribe"
 		case strings.HasPrefix(command, "unsubscribe from"):
 			alertName := strings.TrimSpace(strings.TrimPrefix(command, "unsubscribe from "))
-			return UnsubscribeHandler(c, environment, entities.AlertName(alertName))
+			handler = func() error { return UnsubscribeHandler(c, environment, entities.AlertName(alertName)) }
+			command = "/unsubscribe"
 		default:
 			return nil // do nothing
 		}
+		if !authorize(c, authorizer, command) {
+			return c.Send("Sorry, you have no right to use this command")
+		}
+		return handler()
 	}
 }
 
@@ -237,6 +382,226 @@ func statusCmd(
 	}
 }
 
+func alertsCmd(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	ext common.ExpectedExtension,
+	zapLogger *zap.Logger,
+) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		const defaultHistoryLimit = 5
+		args := c.Args()
+		if len(args) > 1 {
+			return c.Send(messages.AlertsWrongFormat, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		historyLimit := defaultHistoryLimit
+		if len(args) == 1 {
+			limit, err := strconv.Atoi(args[0])
+			if err != nil || limit <= 0 {
+				return c.Send(messages.AlertsWrongFormat, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+			}
+			historyLimit = limit
+		}
+		alerts, err := messaging.RequestAlerts(requestChan, responseChan, historyLimit)
+		if err != nil {
+			zapLogger.Error("failed to request alerts", zap.Error(err))
+			return err
+		}
+		msg, err := common.HandleAlerts(alerts, ext)
+		if err != nil {
+			zapLogger.Error("failed to handle alerts", zap.Error(err))
+			return err
+		}
+		return c.Send(msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+func generatorsCmd(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	ext common.ExpectedExtension,
+	zapLogger *zap.Logger,
+) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		generators, err := messaging.RequestGenerators(requestChan, responseChan)
+		if err != nil {
+			zapLogger.Error("failed to request generators", zap.Error(err))
+			return err
+		}
+		msg, err := common.HandleGenerators(generators, ext)
+		if err != nil {
+			zapLogger.Error("failed to handle generators", zap.Error(err))
+			return err
+		}
+		return c.Send(msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+// forkCmd shows the fork report of the state hash alert.
+func forkCmd(
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	ext common.ExpectedExtension,
+	zapLogger *zap.Logger,
+) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		alertID, err := messaging.ParseForkAlertID(c.Args())
+		if err != nil {
+			return c.Send(messaging.ForkWrongFormatMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		report, err := messaging.RequestForkReport(requestChan, responseChan, alertID)
+		if err != nil {
+			zapLogger.Error("failed to request fork report", zap.Error(err))
+			return err
+		}
+		nodes, err := messaging.RequestAllNodes(requestChan, responseChan)
+		if err != nil {
+			zapLogger.Error("failed to request nodes", zap.Error(err))
+			return err
+		}
+		msg, err := common.HandleForkReport(report, alertID.String(), ext, nodes)
+		if err != nil {
+			zapLogger.Error("failed to handle fork report", zap.Error(err))
+			return err
+		}
+		return c.Send(msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+// muteAlertCmd acks or silences an alert. If the command is a reply to an alert message
+// and the alert isn't specified, the alert from the message is used.
+func muteAlertCmd(
+	env *common.TelegramBotEnvironment,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	kind entities.AlertMuteKind,
+) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		args := c.Args()
+		if replyTo := c.Message().ReplyTo; replyTo != nil && (len(args) == 0 || isDuration(args[0])) {
+			if alertID, ok := env.AlertIDByMessageID(c.Chat().ID, replyTo.ID); ok {
+				args = append([]string{alertID.String()}, args...)
+			}
+		}
+		mute, err := messaging.ParseAlertMute(kind, args, time.Now())
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) {
+				return c.Send(messages.InvalidURL, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+			}
+			return c.Send(messaging.MuteAlertWrongFormatMessage(kind),
+				&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+			)
+		}
+		chatID := strconv.FormatInt(c.Chat().ID, 10)
+		response, err := messaging.MuteAlertHandler(chatID, env, requestChan, responseChan, mute)
+		if err != nil {
+			if errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+			}
+			return errors.Wrapf(err, "failed to %s an alert", kind)
+		}
+		return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+	}
+}
+
+// maintenanceCmd puts the node under maintenance for the given duration or finishes its maintenance.
+func maintenanceCmd(
+	env *common.TelegramBotEnvironment,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		node, window, err := messaging.ParseNodeMaintenance(c.Args(), time.Now())
+		if err != nil {
+			return c.Send(messaging.MaintenanceWrongFormatMsg, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		chatID := strconv.FormatInt(c.Chat().ID, 10)
+		response, err := messaging.NodeMaintenanceHandler(chatID, env, requestChan, responseChan, node, window)
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+			}
+			return errors.Wrap(err, "failed to set node maintenance")
+		}
+		return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+	}
+}
+
+// tagCmd adds the tags to the node or removes them from it.
+func tagCmd(
+	env *common.TelegramBotEnvironment,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	remove bool,
+) func(c telebot.Context) error {
+	wrongFormatMsg := messaging.TagWrongFormatMsg
+	if remove {
+		wrongFormatMsg = messaging.UntagWrongFormatMsg
+	}
+	return func(c telebot.Context) error {
+		node, tags, err := messaging.ParseNodeTags(c.Args())
+		if err != nil {
+			return c.Send(fmt.Sprintf("%v\n\n%s", err, wrongFormatMsg),
+				&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+			)
+		}
+		chatID := strconv.FormatInt(c.Chat().ID, 10)
+		response, err := messaging.NodeTagsHandler(chatID, env, requestChan, responseChan, node, tags, remove)
+		if err != nil {
+			if errors.Is(err, messaging.ErrIncorrectURL) || errors.Is(err, messaging.ErrInsufficientPermissions) {
+				return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+			}
+			return errors.Wrap(err, "failed to set node tags")
+		}
+		return c.Send(response, &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+	}
+}
+
+// groupSubscriptionCmd makes the chat receive or stop receiving the alerts about the nodes with the given tag.
+func groupSubscriptionCmd(env *common.TelegramBotEnvironment, unsubscribe bool) func(c telebot.Context) error {
+	command := "subscribe_group"
+	if unsubscribe {
+		command = "unsubscribe_group"
+	}
+	return func(c telebot.Context) error {
+		args := c.Args()
+		if len(args) != 1 {
+			return c.Send(fmt.Sprintf(messaging.GroupWrongFormatMsg, command, command),
+				&telebot.SendOptions{ParseMode: telebot.ModeDefault},
+			)
+		}
+		tag, err := entities.NormalizeTag(args[0])
+		if err != nil {
+			return c.Send(err.Error(), &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		}
+		chatID := c.Chat().ID
+		chat, _ := env.Chat(chatID)
+		switch subscribed := slices.Contains(chat.Groups, tag); {
+		case !unsubscribe && subscribed:
+			return c.Send("I am already subscribed to this group", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		case unsubscribe && !subscribed:
+			return c.Send("I was not subscribed to this group", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
+		case unsubscribe:
+			err = env.UnsubscribeFromGroup(chatID, tag)
+		default:
+			err = env.SubscribeToGroup(chatID, tag)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to change subscription to group %s", tag)
+		}
+		msg, err := env.SubscriptionsList(chatID)
+		if err != nil {
+			return errors.Wrap(err, "failed to receive list of subscriptions")
+		}
+		return c.Send(msg, &telebot.SendOptions{ParseMode: telebot.ModeHTML})
+	}
+}
+
+func isDuration(s string) bool {
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
+
 func statementCmd(
 	requestChan chan<- pair.Request,
 	responseChan <-chan pair.Response,
@@ -348,20 +713,65 @@ func addCmd(
 	}
 }
 
+// authorize checks the access of the sender to the command.
+func authorize(c telebot.Context, authorizer *rbac.Authorizer, command string) bool {
+	userID := senderID(c)
+	return authorizer.Authorize(rbac.Action{
+		Platform: rbac.Telegram,
+		ChatID:   strconv.FormatInt(c.Chat().ID, 10),
+		UserID:   strconv.FormatInt(userID, 10),
+		UserRole: authorizer.TelegramRole(userID),
+		Command:  command,
+		Args:     c.Args(),
+	})
+}
+
+// senderID returns the ID of the user who has sent the update, it's zero for the anonymous channel posts.
+func senderID(c telebot.Context) int64 {
+	if sender := c.Sender(); sender != nil {
+		return sender.ID
+	}
+	return 0
+}
+
+func chatCmd(environment *common.TelegramBotEnvironment) func(c telebot.Context) error {
+	return func(c telebot.Context) error {
+		chat, ok := environment.Chat(c.Chat().ID)
+		if !ok {
+			return c.Send(fmt.Sprintf("This chat id is %d, I am not sending alerts through it", c.Chat().ID))
+		}
+		mode := "full access"
+		if chat.ReadOnly {
+			mode = "read-only"
+		}
+		state := "I am sending alerts through it"
+		if chat.Muted {
+			state = "I am not sending alerts through it, the chat is muted"
+		}
+		return c.Send(fmt.Sprintf("This chat id is %d, %s, the chat is in %s mode", chat.ChatID, state, mode))
+	}
+}
+
 func muteCmd(environment *common.TelegramBotEnvironment) func(c telebot.Context) error {
 	return func(c telebot.Context) error {
-		if environment.Mute {
+		changed, err := environment.SetMute(c.Chat().ID, true)
+		if err != nil {
+			return errors.Wrap(err, "failed to mute the chat")
+		}
+		if !changed {
 			return c.Send("I had already been sleeping, continue sleeping.." + messaging.SleepingMsg)
 		}
-		environment.Mute = true
 		return c.Send("I had been monitoring, but going to sleep now.." + messaging.SleepingMsg)
 	}
 }
 
 func startCmd(environment *common.TelegramBotEnvironment) func(c telebot.Context) error {
 	return func(c telebot.Context) error {
-		if environment.Mute {
-			environment.Mute = false
+		changed, err := environment.SetMute(c.Chat().ID, false)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmute the chat")
+		}
+		if changed {
 			return c.Send("I had been asleep, but started monitoring now... " + messaging.MonitoringMsg)
 		}
 		return c.Send("I had already been monitoring" + messaging.MonitoringMsg)
@@ -370,10 +780,15 @@ func startCmd(environment *common.TelegramBotEnvironment) func(c telebot.Context
 
 func pingCmd(environment *common.TelegramBotEnvironment) func(c telebot.Context) error {
 	return func(c telebot.Context) error {
-		if environment.Mute {
+		chat, ok := environment.Chat(c.Chat().ID)
+		switch {
+		case !ok:
+			return c.Send(messages.PongText + " " + messages.UnknownChat)
+		case chat.Muted:
 			return c.Send(messages.PongText + " I am currently sleeping" + messaging.SleepingMsg)
+		default:
+			return c.Send(messages.PongText + " I am monitoring" + messaging.MonitoringMsg)
 		}
-		return c.Send(messages.PongText + " I am monitoring" + messaging.MonitoringMsg)
 	}
 }
 
@@ -422,7 +837,7 @@ func editSubscriptions(
 	c telebot.Context,
 	environment *common.TelegramBotEnvironment,
 ) error {
-	msg, err := environment.SubscriptionsList()
+	msg, err := environment.SubscriptionsList(c.Chat().ID)
 	if err != nil {
 		return errors.Wrap(err, "failed to request subscriptions")
 	}
@@ -105,7 +105,8 @@ func UpdateAliasHandler(
 }
 
 func SubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, alertName entities.AlertName) error {
-	if !env.IsEligibleForAction(strconv.FormatInt(c.Chat().ID, 10)) {
+	chatID := c.Chat().ID
+	if !env.CanManageChat(chatID, senderID(c)) {
 		return c.Send("Sorry, you have no right to subscribe to alerts")
 	}
 
@@ -113,10 +114,10 @@ func SubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, ale
 	if !ok {
 		return c.Send("Sorry, this alert does not exist", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	}
-	if env.IsAlreadySubscribed(alertType) {
+	if env.IsAlreadySubscribed(chatID, alertType) {
 		return c.Send("I am already subscribed to it", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	}
-	err := env.SubscribeToAlert(alertType)
+	err := env.SubscribeToAlert(chatID, alertType)
 	if err != nil {
 		return errors.Wrapf(err, "failed to subscribe to alert %s", alertName)
 	}
@@ -126,7 +127,7 @@ func SubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, ale
 	if err != nil {
 		return errors.Wrap(err, "failed to send a message")
 	}
-	msg, err := env.SubscriptionsList()
+	msg, err := env.SubscriptionsList(chatID)
 	if err != nil {
 		return errors.Wrap(err, "failed to receive list of subscriptions")
 	}
@@ -134,8 +135,8 @@ func SubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, ale
 }
 
 func UnsubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, alertName entities.AlertName) error {
-	chatID := strconv.FormatInt(c.Chat().ID, 10)
-	if !env.IsEligibleForAction(chatID) {
+	chatID := c.Chat().ID
+	if !env.CanManageChat(chatID, senderID(c)) {
 		return c.Send("Sorry, you have no right to unsubscribe from alerts")
 	}
 
@@ -143,10 +144,10 @@ func UnsubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, a
 	if !ok {
 		return c.Send("Sorry, this alert does not exist", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	}
-	if !env.IsAlreadySubscribed(alertType) {
+	if !env.IsAlreadySubscribed(chatID, alertType) {
 		return c.Send("I was not subscribed to it", &telebot.SendOptions{ParseMode: telebot.ModeDefault})
 	}
-	err := env.UnsubscribeFromAlert(alertType)
+	err := env.UnsubscribeFromAlert(chatID, alertType)
 	if err != nil {
 		return errors.Wrapf(err, "failed to unsubscribe from alert %s", alertName)
 	}
@@ -156,7 +157,7 @@ func UnsubscribeHandler(c telebot.Context, env *common.TelegramBotEnvironment, a
 	if err != nil {
 		return errors.Wrap(err, "failed to send a message")
 	}
-	msg, err := env.SubscriptionsList()
+	msg, err := env.SubscriptionsList(chatID)
 	if err != nil {
 		return errors.Wrap(err, "failed to receive list of subscriptions")
 	}
@@ -4,12 +4,31 @@ const (
 	HelpInfoText = "" +
 		"ℹ️ This is a bot for monitoring Waves nodes. The next commands are available:\n\n" +
 		"/ping -  the command to check whether the bot is available and what his current state is\n" +
+		"/chat - to see the ID and the mode of this chat\n" +
 		"/start - the command to make the bot <b>start getting alerts</b>\n" +
 		"/mute -  the command to make the bot <b>stop listening to alerts</b>\n" +
 		"/pool -  to see the list of nodes and edit it\n" +
 		"/subscriptions - to see the list of subscriptions and edit it\n" +
 		"/status - to see the status of all nodes\n" +
 		"/statement <b>node</b> <b>height</b> - to see a node statement at a specific height.\n" +
+		"/alerts <b>[limit]</b> - to see the active alerts and the last resolved ones\n" +
+		"/generators - to see the block generators statistics\n" +
+		"/fork <b>alert id</b> - to see the block-by-block report of the state hash alert fork\n" +
+		"/ack <b>alert id</b> <b>[duration]</b> - to stop repeating the alert until it is resolved, " +
+		"can be sent as a reply to the alert\n" +
+		"/silence <b>alert id</b> <b>[duration]</b> or /silence <b>alert name</b> <b>node</b> <b>[duration]</b> - " +
+		"to stop sending the matching alerts\n" +
+		"/maintenance <b>node</b> <b>duration</b> - to suppress the alerts about the node for the given duration, " +
+		"use <b>off</b> to finish the maintenance\n" +
+		"/tag <b>node</b> <b>tags</b> - to add the tags to the node, e.g. /tag mynode our-validators\n" +
+		"/untag <b>node</b> <b>tags</b> - to remove the tags from the node\n" +
+		"/subscribe_group <b>tag</b> - to receive only the alerts about the nodes with the tag, " +
+		"may be used several times\n" +
+		"/unsubscribe_group <b>tag</b> - to stop receiving the alerts about the nodes with the tag\n" +
+		"/reports - to see the scheduled reports of this chat\n" +
+		"/add_report <b>status|summary|chains</b> <b>cron</b> - to schedule a report, " +
+		"e.g. /add_report summary 0 0 9 * * MON\n" +
+		"/remove_report <b>id</b> - to remove a scheduled report\n" +
 		"/add <b>node</b> - to add a node to the list\n" +
 		"/add_specific <b>node</b> - to add a specific node to the list\n" +
 		"/remove <b>node</b> - to remove a node from the list\n" +
@@ -33,3 +52,5 @@ Example: Subscribe to <alert>
 Example: Unsubscribe from <alert>
 `
 )
+
+const UnknownChat = "I am not serving this chat, ask the bot administrator to add it"
@@ -7,4 +7,5 @@ const (
 	SubscribeWrongNumberOfNodes = "Subscribe to or unsubscribe from only one node at a time"
 	StatementWrongFormat        = "Statement should be in format: /statement <node> <height>"
 	InvalidURL                  = "Invalid URL"
+	AlertsWrongFormat           = "Alerts should be in format: /alerts [positive history limit]"
 )
@@ -7,16 +7,23 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
 	"nodemon/cmd/bots/internal/common"
 	"nodemon/cmd/bots/internal/common/api"
+	"nodemon/cmd/bots/internal/common/chats"
 	"nodemon/cmd/bots/internal/common/initial"
 	"nodemon/cmd/bots/internal/common/messaging"
+	"nodemon/cmd/bots/internal/common/rbac"
+	"nodemon/cmd/bots/internal/common/reports"
+	"nodemon/cmd/bots/internal/common/state"
 	"nodemon/cmd/bots/internal/telegram/config"
 	"nodemon/cmd/bots/internal/telegram/handlers"
 	"nodemon/internal"
+	"nodemon/pkg/entities"
 	generalMessaging "nodemon/pkg/messaging"
 	"nodemon/pkg/messaging/pair"
 	"nodemon/pkg/tools"
@@ -25,7 +32,10 @@ import (
 	"go.uber.org/zap"
 )
 
-const defaultAPIReadTimeout = 30 * time.Second
+const (
+	defaultAPIReadTimeout   = 30 * time.Second
+	defaultAlertMessagesTTL = 7 * 24 * time.Hour
+)
 
 func main() {
 	const contextCanceledExitCode = 130
@@ -47,6 +57,12 @@ type telegramBotConfig struct {
 	publicURL           string // only for webhook method
 	tgBotToken          string
 	tgChatID            int64
+	tgReadOnlyChatIDs   string
+	chatsFile           string
+	reportsFile         string
+	alertMessagesFile   string
+	alertMessagesTTL    time.Duration
+	rbacConfig          string
 	logLevel            string
 	development         bool
 	bindAddress         string
@@ -66,7 +82,20 @@ func newTelegramBotConfig() *telegramBotConfig {
 	tools.StringVarFlagWithEnv(&c.publicURL, "public-url", "",
 		"The public url for webhook only")
 	tools.Int64VarFlagWithEnv(&c.tgChatID, "telegram-chat-id",
-		0, "telegram chat ID to send alerts through")
+		0, "telegram chat ID to send alerts through, the chat is added with full access if it's new")
+	tools.StringVarFlagWithEnv(&c.tgReadOnlyChatIDs, "telegram-readonly-chat-ids", "",
+		"Comma separated list of telegram chat IDs which are added in read-only mode if they are new")
+	tools.StringVarFlagWithEnv(&c.chatsFile, "chats-file", "",
+		"Path to the file with the chats settings. If it's empty, the settings are kept only in memory.")
+	tools.StringVarFlagWithEnv(&c.reportsFile, "reports-file", "",
+		"Path to the file with the scheduled reports. If it's empty, the reports are kept only in memory. "+
+			"Every chat gets the daily status report the first time it's served.")
+	tools.StringVarFlagWithEnv(&c.alertMessagesFile, "alert-messages-file", "",
+		"Path to the file with the IDs of the alert messages. If it's empty, the IDs are kept only in memory.")
+	tools.DurationVarFlagWithEnv(&c.alertMessagesTTL, "alert-messages-ttl", defaultAlertMessagesTTL,
+		"How long the IDs of the alert messages are kept to reply with the alert fix")
+	tools.StringVarFlagWithEnv(&c.rbacConfig, "rbac-config", "",
+		"Path to the access control config in YAML or JSON format. If it's empty, everyone may run all commands.")
 	tools.StringVarFlagWithEnv(&c.logLevel, "log-level", "INFO",
 		"Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
 	tools.BoolVarFlagWithEnv(&c.development, "development", false, "Development mode.")
@@ -90,13 +119,101 @@ func (c *telegramBotConfig) validate(logger *zap.Logger) error {
 		logger.Error("the blockchain scheme must be specified")
 		return common.ErrInvalidParameters
 	}
-	if c.tgChatID == 0 {
-		logger.Error("telegram chat ID is required")
+	if c.tgChatID == 0 && c.tgReadOnlyChatIDs == "" && c.chatsFile == "" {
+		logger.Error("telegram chat ID or chats file is required")
+		return common.ErrInvalidParameters
+	}
+	if c.alertMessagesTTL <= 0 {
+		logger.Error("alert messages TTL must be positive")
+		return common.ErrInvalidParameters
+	}
+	if _, err := c.readOnlyChatIDs(); err != nil {
+		logger.Error("invalid read-only telegram chat IDs", zap.Error(err))
 		return common.ErrInvalidParameters
 	}
 	return nil
 }
 
+func (c *telegramBotConfig) readOnlyChatIDs() ([]int64, error) {
+	var ids []int64
+	for _, s := range strings.Split(c.tgReadOnlyChatIDs, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// initChats loads the chats settings and adds the chats from the configuration which are absent in the storage.
+func (c *telegramBotConfig) initChats(logger *zap.Logger) (*chats.Storage, error) {
+	storage, err := chats.NewStorage(c.chatsFile)
+	if err != nil {
+		return nil, err
+	}
+	newChats := make([]chats.Settings, 0, 1)
+	if c.tgChatID != 0 {
+		newChats = append(newChats, chats.Settings{
+			Chat: entities.Chat{ChatID: entities.ChatID(c.tgChatID), Platform: entities.TelegramPlatform},
+		})
+	}
+	readOnlyIDs, err := c.readOnlyChatIDs()
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range readOnlyIDs {
+		newChats = append(newChats, chats.Settings{
+			Chat:     entities.Chat{ChatID: entities.ChatID(id), Platform: entities.TelegramPlatform},
+			ReadOnly: true,
+		})
+	}
+	for _, chat := range newChats {
+		added, addErr := storage.AddIfNew(chat)
+		if addErr != nil {
+			return nil, addErr
+		}
+		if added {
+			logger.Info("Telegram chat has been added",
+				zap.Int64("chat", int64(chat.ChatID)), zap.Bool("read-only", chat.ReadOnly),
+			)
+		}
+	}
+	if len(storage.Chats()) == 0 {
+		return nil, errors.New("no telegram chats to send alerts through")
+	}
+	return storage, nil
+}
+
+// initReports loads the scheduled reports, every chat gets the daily status report the first time it's served.
+func initReports(
+	path string,
+	chatsStorage *chats.Storage,
+	taskScheduler chrono.TaskScheduler,
+	requestChan chan<- pair.Request,
+	responseChan <-chan pair.Response,
+	tgBotEnv *common.TelegramBotEnvironment,
+	logger *zap.Logger,
+) (*reports.Manager, error) {
+	var defaults []reports.Report
+	for _, chat := range chatsStorage.Chats() {
+		defaults = append(defaults, reports.Report{
+			Chat: chat.ChatID,
+			Kind: reports.StatusKind,
+			Cron: reports.DailyStatusCron,
+		})
+	}
+	storage, err := reports.NewStorage(path, defaults...)
+	if err != nil {
+		return nil, err
+	}
+	run := common.ReportRunner(requestChan, responseChan, tgBotEnv, logger)
+	return reports.NewManager(storage, taskScheduler, run, logger), nil
+}
+
 func runTelegramBot() error {
 	cfg := newTelegramBotConfig()
 	flag.Parse()
@@ -119,6 +236,22 @@ func runTelegramBot() error {
 		return validationErr
 	}
 
+	chatsStorage, err := cfg.initChats(logger)
+	if err != nil {
+		logger.Error("Failed to initialize telegram chats", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+	alertMessages, err := state.NewAlertMessages(cfg.alertMessagesFile, cfg.alertMessagesTTL)
+	if err != nil {
+		logger.Error("Failed to load alert messages", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+	authorizer, err := newAuthorizer(cfg.rbacConfig, logger)
+	if err != nil {
+		logger.Error("Failed to load access control config", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+
 	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer done()
 
@@ -126,12 +259,22 @@ func runTelegramBot() error {
 	responseChan := make(chan pair.Response)
 
 	tgBotEnv, initErr := initial.InitTgBot(cfg.behavior, cfg.webhookLocalAddress, cfg.publicURL,
-		cfg.tgBotToken, cfg.tgChatID, logger, requestChan, responseChan, cfg.scheme)
+		cfg.tgBotToken, chatsStorage, alertMessages, logger, requestChan, responseChan, cfg.scheme)
 	if initErr != nil {
 		logger.Fatal("failed to initialize telegram bot", zap.Error(initErr))
 	}
 
-	handlers.InitTgHandlers(tgBotEnv, logger, requestChan, responseChan)
+	taskScheduler := chrono.NewDefaultTaskScheduler()
+	reportsManager, err := initReports(cfg.reportsFile, chatsStorage, taskScheduler, requestChan, responseChan,
+		tgBotEnv, logger,
+	)
+	if err != nil {
+		taskScheduler.Shutdown()
+		logger.Error("Failed to load scheduled reports", zap.Error(err))
+		return common.ErrInvalidParameters
+	}
+
+	handlers.InitTgHandlers(tgBotEnv, authorizer, reportsManager, logger, requestChan, responseChan)
 
 	runMessagingClients(ctx, cfg, tgBotEnv, logger, requestChan, responseChan)
 
@@ -150,13 +293,7 @@ func runTelegramBot() error {
 		defer botAPI.Shutdown()
 	}
 
-	taskScheduler := chrono.NewDefaultTaskScheduler()
-	err = common.ScheduleNodesStatus(taskScheduler, requestChan, responseChan, tgBotEnv, logger)
-	if err != nil {
-		taskScheduler.Shutdown()
-		logger.Fatal("failed to schedule nodes status alert", zap.Error(err))
-	}
-	logger.Info("Nodes status alert has been scheduled successfully")
+	reportsManager.Start()
 
 	err = tgBotEnv.Start(ctx)
 	if err != nil {
@@ -196,3 +333,15 @@ func runMessagingClients(
 		}
 	}()
 }
+
+func newAuthorizer(path string, logger *zap.Logger) (*rbac.Authorizer, error) {
+	if path == "" {
+		logger.Warn("Access control isn't configured, everyone may run all commands")
+		return rbac.NewAuthorizer(rbac.AllowAllConfig(), logger), nil
+	}
+	rbacCfg, err := rbac.LoadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return rbac.NewAuthorizer(rbacCfg, logger), nil
+}
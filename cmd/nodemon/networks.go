@@ -0,0 +1,112 @@
+package main
+
+import (
+	"bytes"
+	stderrs "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"nodemon/pkg/analysis"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// networkConfig is the configuration of one monitored blockchain network. Each network has its own nodes storage,
+// events storage, analyzer and messaging topics.
+type networkConfig struct {
+	Scheme            string   `yaml:"scheme"`
+	Nodes             []string `yaml:"nodes"`               // initial list of the nodes
+	Storage           string   `yaml:"storage"`             // path to the nodes storage, ignored if Vault is used
+	VaultSecretPath   string   `yaml:"vault_secret_path"`   // Vault secret of the nodes storage
+	EventsStoragePath string   `yaml:"events_storage_path"` // empty path means in-memory events storage
+	AnalyzerConfig    string   `yaml:"analyzer_config"`     // empty path means the path from the flag
+}
+
+type networksFile struct {
+	Networks []networkConfig `yaml:"networks"`
+}
+
+// loadNetworksConfig reads the networks configuration file in YAML or JSON format.
+func loadNetworksConfig(path string) ([]networkConfig, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read networks config file '%s'", path)
+	}
+	var file networksFile
+	dec := yaml.NewDecoder(bytes.NewReader(data)) // JSON is a subset of YAML
+	dec.KnownFields(true)
+	if decErr := dec.Decode(&file); decErr != nil && !errors.Is(decErr, io.EOF) {
+		return nil, errors.Wrapf(decErr, "failed to parse networks config file '%s'", path)
+	}
+	return file.Networks, nil
+}
+
+// validateNetworks checks that the networks don't share the schemes and the storages.
+func validateNetworks(networks []networkConfig, vault bool) error {
+	if len(networks) == 0 {
+		return errors.New("no networks are configured")
+	}
+	var (
+		errs     []error
+		schemes  = make(map[string]struct{}, len(networks))
+		storages = make(map[string]string, len(networks)) // storage -> scheme
+	)
+	checkStorage := func(kind, storage, scheme string) {
+		if other, ok := storages[storage]; ok {
+			errs = append(errs, errors.Errorf("networks '%s' and '%s' share %s '%s'", other, scheme, kind, storage))
+		}
+		storages[storage] = scheme
+	}
+	for i, network := range networks {
+		if network.Scheme == "" {
+			errs = append(errs, errors.Errorf("%d-th network has empty scheme", i+1))
+			continue
+		}
+		if _, ok := schemes[network.Scheme]; ok {
+			errs = append(errs, errors.Errorf("duplicate network scheme '%s'", network.Scheme))
+			continue
+		}
+		schemes[network.Scheme] = struct{}{}
+		switch {
+		case vault && network.VaultSecretPath == "":
+			errs = append(errs, errors.Errorf("network '%s' has empty vault secret path", network.Scheme))
+		case vault:
+			checkStorage("vault secret", network.VaultSecretPath, network.Scheme)
+		case network.Storage == "" || len(strings.Fields(network.Storage)) > 1:
+			errs = append(errs, errors.Errorf("network '%s' has invalid storage path '%s'",
+				network.Scheme, network.Storage,
+			))
+		default:
+			checkStorage("storage", network.Storage, network.Scheme)
+		}
+		if network.EventsStoragePath != "" {
+			checkStorage("events storage", network.EventsStoragePath, network.Scheme)
+		}
+	}
+	return stderrs.Join(errs...)
+}
+
+// analyzer returns the analyzer config of the network: the network config file with the overriding flags.
+func (n *networkConfig) analyzer(flags *nodemonAnalyzerConfig) *nodemonAnalyzerConfig {
+	c := *flags
+	if n.AnalyzerConfig != "" {
+		c.path = n.AnalyzerConfig
+	}
+	return &c
+}
+
+// loadAnalyzerConfigs loads and validates the analyzer configs of the networks.
+func loadAnalyzerConfigs(networks []networkConfig, flags *nodemonAnalyzerConfig) ([]*analysis.Config, error) {
+	out := make([]*analysis.Config, 0, len(networks))
+	for i := range networks {
+		cfg, err := networks[i].analyzer(flags).load()
+		if err != nil {
+			return nil, errors.Wrapf(err, "network '%s'", networks[i].Scheme)
+		}
+		out = append(out, cfg)
+	}
+	return out, nil
+}
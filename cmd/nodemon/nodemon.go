@@ -5,6 +5,7 @@ import (
 	stderrs "errors"
 	"flag"
 	"log"
+	"math"
 	"net/url"
 	"os"
 	"os/signal"
@@ -18,17 +19,22 @@ import (
 
 	"nodemon/internal"
 	"nodemon/pkg/analysis"
-	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/analysis/correlation"
+	"nodemon/pkg/analysis/forks"
 	"nodemon/pkg/analysis/l2"
+	"nodemon/pkg/analysis/storage"
 	"nodemon/pkg/api"
 	"nodemon/pkg/clients"
 	"nodemon/pkg/entities"
 	"nodemon/pkg/messaging/pair"
 	"nodemon/pkg/messaging/pubsub"
 	"nodemon/pkg/scraping"
+	"nodemon/pkg/storing/alertlog"
 	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/maintenance"
 	"nodemon/pkg/storing/nodes"
 	"nodemon/pkg/storing/specific"
+	"nodemon/pkg/storing/uptime"
 	"nodemon/pkg/tools"
 
 	"github.com/pkg/errors"
@@ -39,9 +45,18 @@ const (
 	defaultPollingInterval   = 60 * time.Second
 	defaultRetentionDuration = 12 * time.Hour
 	defaultAPIReadTimeout    = 30 * time.Second
+	defaultBackoffAfter      = 10 * time.Minute
+	defaultMaxBackoff        = 10 * time.Minute
+	defaultFollowUpDuration  = 5 * time.Minute
 
 	natsMaxPayloadSize            int32 = 1024 * 1024 // 1 MB
 	natsConnectionsTimeoutDefault       = 5 * time.Second
+
+	scraperBackendREST = "rest"
+	scraperBackendGRPC = "grpc"
+	// grpcMaxHeaderAgeIntervals is the number of polling intervals after which the streamed header without updates
+	// is checked against the height by REST API
+	grpcMaxHeaderAgeIntervals = 3
 )
 
 var (
@@ -181,7 +196,8 @@ func (n *nodemonVaultConfig) present() bool {
 	return n.address != ""
 }
 
-func (n *nodemonVaultConfig) validate(logger *zap.Logger) error {
+// validate checks the Vault config. The secret path isn't required if it's set by the networks config file.
+func (n *nodemonVaultConfig) validate(logger *zap.Logger, requireSecretPath bool) error {
 	if n.address == "" { // skip further validation
 		return nil
 	}
@@ -197,34 +213,152 @@ func (n *nodemonVaultConfig) validate(logger *zap.Logger) error {
 		logger.Error("Empty vault mount path")
 		return errInvalidParameters
 	}
-	if len(n.secretPath) == 0 {
+	if requireSecretPath && len(n.secretPath) == 0 {
 		logger.Error("Empty vault secret path")
 		return errInvalidParameters
 	}
 	return nil
 }
 
-type nodemonConfig struct {
-	storage             string
-	nodes               string
-	L2nodeName          string
-	L2nodeURL           string
-	bindAddress         string
-	interval            time.Duration
-	timeout             time.Duration
-	natsMessagingURL    string
-	natsPairTelegram    bool
-	natsPairDiscord     bool
-	natsTimeout         time.Duration
-	retention           time.Duration
-	apiReadTimeout      time.Duration
+// nodemonAnalyzerConfig holds the path to the analyzer config file and the flags which override its values.
+// Zero value of an overriding flag means that the value from the file or the default one is used.
+type nodemonAnalyzerConfig struct {
+	path                string
+	alertBackoff        int
+	alertVacuumQuota    int
+	unreachableStreak   int
+	unreachableDepth    int
+	incompleteStreak    int
+	incompleteDepth     int
+	maxHeightDiff       uint64
+	maxForkDepth        uint64
+	heightBucketSize    uint64
 	baseTargetThreshold uint64
-	logLevel            string
-	development         bool
-	vault               *nodemonVaultConfig
-	l2                  *nodemonL2Config
-	scheme              string
-	natsOptionalConfig  *natsOptionalConfig
+	versionDriftGrace   time.Duration
+	minNodeVersion      string
+	chainStuckDuration  time.Duration
+	generators          string
+	generatorInterval   time.Duration
+	disabledCriteria    string
+}
+
+func newNodemonAnalyzerConfig() *nodemonAnalyzerConfig {
+	c := new(nodemonAnalyzerConfig)
+	tools.StringVarFlagWithEnv(&c.path, "analyzer-config", "",
+		"Path to the analyzer config file in YAML or JSON format. The file is reloaded on SIGHUP.")
+	tools.IntVarFlagWithEnv(&c.alertBackoff, "alert-backoff", 0,
+		"Alert backoff multiplier. Overrides the analyzer config value.")
+	tools.IntVarFlagWithEnv(&c.alertVacuumQuota, "alert-vacuum-quota", 0,
+		"Number of vacuum stages the alert survives without repeats. Overrides the analyzer config value.")
+	tools.IntVarFlagWithEnv(&c.unreachableStreak, "unreachable-streak", 0,
+		"Number of unreachable statements in a row to send the unreachable alert. Overrides the analyzer config value.")
+	tools.IntVarFlagWithEnv(&c.unreachableDepth, "unreachable-depth", 0,
+		"Number of statements checked by the unreachable criterion. Overrides the analyzer config value.")
+	tools.IntVarFlagWithEnv(&c.incompleteStreak, "incomplete-streak", 0,
+		"Number of incomplete statements in a row to send the incomplete alert. Overrides the analyzer config value.")
+	tools.IntVarFlagWithEnv(&c.incompleteDepth, "incomplete-depth", 0,
+		"Number of statements checked by the incomplete criterion. Overrides the analyzer config value.")
+	tools.Uint64VarFlagWithEnv(&c.maxHeightDiff, "max-height-diff", 0,
+		"Max height difference between nodes before the height alert. Overrides the analyzer config value.")
+	tools.Uint64VarFlagWithEnv(&c.maxForkDepth, "max-fork-depth", 0,
+		"Max fork depth checked by the state hash criterion. Overrides the analyzer config value.")
+	tools.Uint64VarFlagWithEnv(&c.heightBucketSize, "height-bucket-size", 0,
+		"Height bucket size of the state hash criterion. Overrides the analyzer config value.")
+	tools.Uint64VarFlagWithEnv(&c.baseTargetThreshold, "base-target-threshold", 0,
+		"Base target threshold. Must be specified either here or in the analyzer config.")
+	tools.DurationVarFlagWithEnv(&c.versionDriftGrace, "version-drift-grace-period", 0,
+		"Time during which nodes may run different versions without an alert. Overrides the analyzer config value.")
+	tools.StringVarFlagWithEnv(&c.minNodeVersion, "min-node-version", "",
+		"Minimal allowed node version, e.g. 1.5.3. Overrides the analyzer config value.")
+	tools.DurationVarFlagWithEnv(&c.chainStuckDuration, "chain-stuck-duration", 0,
+		"Time during which the max height of the nodes may stay the same. Overrides the analyzer config value.")
+	tools.StringVarFlagWithEnv(&c.generators, "generators", "",
+		"Space separated list of the generator addresses which are expected to produce blocks. "+
+			"Overrides the analyzer config value.")
+	tools.DurationVarFlagWithEnv(&c.generatorInterval, "missing-generator-interval", 0,
+		"Time during which each of the generators is expected to produce a block. Overrides the analyzer config value.")
+	tools.StringVarFlagWithEnv(&c.disabledCriteria, "disabled-criteria", "",
+		"Space separated list of the analyzer criteria names to disable. Overrides the analyzer config value.")
+	return c
+}
+
+// load reads the analyzer config file, applies the overriding flags and validates the result.
+func (c *nodemonAnalyzerConfig) load() (*analysis.Config, error) {
+	cfg, err := analysis.LoadConfig(c.path)
+	if err != nil {
+		return nil, err
+	}
+	overrideIfSet(&cfg.AlertBackoff, c.alertBackoff)
+	overrideIfSet(&cfg.AlertVacuumQuota, c.alertVacuumQuota)
+	overrideIfSet(&cfg.Unreachable.Streak, c.unreachableStreak)
+	overrideIfSet(&cfg.Unreachable.Depth, c.unreachableDepth)
+	overrideIfSet(&cfg.Incomplete.Streak, c.incompleteStreak)
+	overrideIfSet(&cfg.Incomplete.Depth, c.incompleteDepth)
+	overrideIfSet(&cfg.Height.MaxHeightDiff, c.maxHeightDiff)
+	overrideIfSet(&cfg.BaseTarget.Threshold, c.baseTargetThreshold)
+	overrideIfSet(&cfg.Version.DriftGracePeriod, c.versionDriftGrace)
+	overrideIfSet(&cfg.Version.MinVersion, c.minNodeVersion)
+	overrideIfSet(&cfg.ChainStuck.Duration, c.chainStuckDuration)
+	overrideIfSet(&cfg.MissingGenerator.Interval, c.generatorInterval)
+	if generators := strings.Fields(c.generators); len(generators) > 0 {
+		cfg.MissingGenerator.Addresses = generators
+	}
+	if disabled := strings.Fields(c.disabledCriteria); len(disabled) > 0 {
+		cfg.DisabledCriteria = disabled
+	}
+	if c.maxForkDepth > math.MaxUint32 || c.heightBucketSize > math.MaxUint32 {
+		return nil, errors.New("max fork depth and height bucket size must fit into uint32")
+	}
+	overrideIfSet(&cfg.StateHash.MaxForkDepth, uint32(c.maxForkDepth))
+	overrideIfSet(&cfg.StateHash.HeightBucketSize, uint32(c.heightBucketSize))
+	if validateErr := cfg.Validate(); validateErr != nil {
+		return nil, errors.Wrap(validateErr, "invalid analyzer config")
+	}
+	return cfg, nil
+}
+
+func overrideIfSet[T comparable](p *T, v T) {
+	var zero T
+	if v != zero {
+		*p = v
+	}
+}
+
+type nodemonConfig struct {
+	storage            string
+	nodes              string
+	networks           string
+	L2nodeName         string
+	L2nodeURL          string
+	bindAddress        string
+	interval           time.Duration
+	timeout            time.Duration
+	scraperBackend     string
+	grpcBlocksPort     uint64
+	grpcUpdatesPort    uint64
+	pollJitter         time.Duration
+	pollConcurrency    int
+	backoffAfter       time.Duration
+	maxBackoff         time.Duration
+	followUpDuration   time.Duration
+	natsMessagingURL   string
+	natsPairTelegram   bool
+	natsPairDiscord    bool
+	natsTimeout        time.Duration
+	retention          time.Duration
+	eventsStoragePath  string
+	alertsHistorySize  uint64
+	forkReportHeights  uint64
+	alertGroupWait     time.Duration
+	alertsRateLimit    int
+	apiReadTimeout     time.Duration
+	logLevel           string
+	development        bool
+	analyzer           *nodemonAnalyzerConfig
+	vault              *nodemonVaultConfig
+	l2                 *nodemonL2Config
+	scheme             string
+	natsOptionalConfig *natsOptionalConfig
 }
 
 func newNodemonConfig() *nodemonConfig {
@@ -233,20 +367,54 @@ func newNodemonConfig() *nodemonConfig {
 		".nodes.json", "Path to storage. Default value is \".nodes.json\"")
 	tools.StringVarFlagWithEnv(&c.nodes, "nodes", "",
 		"Initial list of Waves Blockchain nodes to monitor. Provide space separated list of REST API URLs here.")
+	tools.StringVarFlagWithEnv(&c.networks, "networks", "",
+		"Path to the networks config file in YAML or JSON format. If set, the networks from the file are monitored "+
+			"and -scheme, -nodes, -storage, -vault-secret-path and -events-storage-path flags are ignored.")
 	tools.StringVarFlagWithEnv(&c.bindAddress, "bind", ":8080",
 		"Local network address to bind the HTTP API of the service on. Default value is \":8080\".")
 	tools.DurationVarFlagWithEnv(&c.interval, "interval",
 		defaultPollingInterval, "Polling interval, seconds. Default value is 60")
 	tools.DurationVarFlagWithEnv(&c.timeout, "timeout",
 		defaultNetworkTimeout, "Network timeout, seconds. Default value is 15")
-	tools.Uint64VarFlagWithEnv(&c.baseTargetThreshold, "base-target-threshold",
-		0, "Base target threshold. Must be specified")
+	tools.StringVarFlagWithEnv(&c.scraperBackend, "scraper-backend", scraperBackendREST,
+		"Nodes scraping backend: 'rest' polls REST API, 'grpc' streams block headers by gRPC API and uses REST API "+
+			"for the state hash and as the fallback. Default value is \"rest\".")
+	tools.Uint64VarFlagWithEnv(&c.grpcBlocksPort, "grpc-blocks-port", scraping.DefaultGRPCBlocksPort,
+		"Port of the nodes gRPC blocks API used by the 'grpc' scraper backend. Default value is 6870.")
+	tools.Uint64VarFlagWithEnv(&c.grpcUpdatesPort, "grpc-updates-port", scraping.DefaultGRPCUpdatesPort,
+		"Port of the nodes gRPC blockchain updates API used by the 'grpc' scraper backend. Default value is 6881.")
+	tools.DurationVarFlagWithEnv(&c.pollJitter, "poll-jitter", 0,
+		"Max random delay of the node poll start within the polling round. Zero value disables jitter.")
+	tools.IntVarFlagWithEnv(&c.pollConcurrency, "poll-concurrency", 0,
+		"Max number of the nodes polled at the same time. Zero value means no limit.")
+	tools.DurationVarFlagWithEnv(&c.backoffAfter, "unreachable-backoff-after", defaultBackoffAfter,
+		"The node unreachable for this time is probed with the growing intervals, the node is considered "+
+			"unreachable between the probes. Zero value disables back-off. Default value is 10m.")
+	tools.DurationVarFlagWithEnv(&c.maxBackoff, "unreachable-backoff-max", defaultMaxBackoff,
+		"Max interval between the probes of the unreachable node. Default value is 10m.")
+	tools.DurationVarFlagWithEnv(&c.followUpDuration, "alert-follow-up-duration", defaultFollowUpDuration,
+		"The node with its own polling interval is polled each round during this time after an alert about it. "+
+			"The nodes polled each round aren't affected. Zero value disables follow-up. Default value is 5m.")
 	tools.StringVarFlagWithEnv(&c.natsMessagingURL, "nats-msg-url",
 		"nats://127.0.0.1:4222", "Nats URL for messaging")
 	tools.DurationVarFlagWithEnv(&c.natsTimeout, "nats-connection-timeout",
 		natsConnectionsTimeoutDefault, "NATS connection to server timeout")
 	tools.DurationVarFlagWithEnv(&c.retention, "retention", defaultRetentionDuration,
 		"Events retention duration. Default value is 12h")
+	tools.StringVarFlagWithEnv(&c.eventsStoragePath, "events-storage-path", "",
+		"Path to the file of the persistent events storage. Alert acks and silences are kept in the same file. "+
+			"If empty, events are kept in memory only and the alert mutes are lost on restart.")
+	tools.Uint64VarFlagWithEnv(&c.alertsHistorySize, "alerts-history-size", alertlog.DefaultHistorySize,
+		"Max number of resolved alerts kept in memory for the alerts history API. Default value is 1000.")
+	tools.Uint64VarFlagWithEnv(&c.forkReportHeights, "fork-report-heights", forks.DefaultMaxHeights,
+		"Max number of heights after the last common one compared block by block in the fork report of the state "+
+			"hash alert. Default value is 10.")
+	tools.DurationVarFlagWithEnv(&c.alertGroupWait, "alert-group-wait", correlation.DefaultGroupWait,
+		"How long the alerts of the same polling round are collected to group the related ones. "+
+			"Zero value disables grouping. Default value is 2s.")
+	tools.IntVarFlagWithEnv(&c.alertsRateLimit, "alerts-rate-limit", correlation.DefaultRateLimit,
+		"Max number of alerts sent per minute, the excess alerts are sent as one storm alert. "+
+			"Zero value disables the limit. Default value is 30.")
 	tools.DurationVarFlagWithEnv(&c.apiReadTimeout, "api-read-timeout", defaultAPIReadTimeout,
 		"HTTP API read timeout. Default value is 30s.")
 	tools.BoolVarFlagWithEnv(&c.development, "development", false, "Development mode.")
@@ -256,6 +424,7 @@ func newNodemonConfig() *nodemonConfig {
 		"Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
 	tools.StringVarFlagWithEnv(&c.scheme, "scheme",
 		"", "Blockchain scheme i.e. mainnet, testnet, stagenet")
+	c.analyzer = newNodemonAnalyzerConfig()
 	c.vault = newNodemonVaultConfig()
 	c.l2 = newNodemonL2Config()
 	c.natsOptionalConfig = newNatsOptionalConfig()
@@ -263,7 +432,8 @@ func newNodemonConfig() *nodemonConfig {
 }
 
 func (c *nodemonConfig) validate(logger *zap.Logger) error {
-	if !c.vault.present() {
+	singleNetwork := c.networks == ""
+	if singleNetwork && !c.vault.present() {
 		if len(c.storage) == 0 || len(strings.Fields(c.storage)) > 1 {
 			logger.Error("Invalid storage path", zap.String("path", c.storage))
 			return errInvalidParameters
@@ -273,7 +443,21 @@ func (c *nodemonConfig) validate(logger *zap.Logger) error {
 		logger.Error("Invalid polling interval", zap.Stringer("interval", c.interval))
 		return errInvalidParameters
 	}
-	if c.scheme == "" {
+	switch c.scraperBackend {
+	case scraperBackendREST:
+	case scraperBackendGRPC:
+		if c.grpcBlocksPort == 0 || c.grpcBlocksPort > math.MaxUint16 ||
+			c.grpcUpdatesPort == 0 || c.grpcUpdatesPort > math.MaxUint16 {
+			logger.Error("Invalid gRPC ports",
+				zap.Uint64("blocks", c.grpcBlocksPort), zap.Uint64("updates", c.grpcUpdatesPort),
+			)
+			return errInvalidParameters
+		}
+	default:
+		logger.Error("Invalid scraper backend", zap.String("backend", c.scraperBackend))
+		return errInvalidParameters
+	}
+	if singleNetwork && c.scheme == "" {
 		logger.Error("Empty blockchain scheme", zap.String("scheme", c.scheme))
 		return errInvalidParameters
 	}
@@ -281,15 +465,101 @@ func (c *nodemonConfig) validate(logger *zap.Logger) error {
 		logger.Error("Invalid network timeout", zap.Stringer("timeout", c.timeout))
 		return errInvalidParameters
 	}
+	if err := c.validateScheduling(logger); err != nil {
+		return err
+	}
 	if c.retention <= 0 {
 		logger.Error("Invalid retention duration", zap.Stringer("retention", c.retention))
 		return errInvalidParameters
 	}
-	if c.baseTargetThreshold == 0 {
-		logger.Error("Invalid base target threshold", zap.Uint64("threshold", c.baseTargetThreshold))
+	if singleNetwork && len(strings.Fields(c.eventsStoragePath)) > 1 {
+		logger.Error("Invalid events storage path", zap.String("path", c.eventsStoragePath))
+		return errInvalidParameters
+	}
+	if c.alertsHistorySize == 0 || c.alertsHistorySize > math.MaxInt32 {
+		logger.Error("Invalid alerts history size", zap.Uint64("size", c.alertsHistorySize))
+		return errInvalidParameters
+	}
+	if c.forkReportHeights == 0 || c.forkReportHeights > math.MaxInt32 {
+		logger.Error("Invalid fork report heights", zap.Uint64("heights", c.forkReportHeights))
 		return errInvalidParameters
 	}
-	return stderrs.Join(c.vault.validate(logger), c.l2.validate(logger))
+	if c.alertGroupWait < 0 {
+		logger.Error("Invalid alert group wait", zap.Stringer("wait", c.alertGroupWait))
+		return errInvalidParameters
+	}
+	if c.alertsRateLimit < 0 {
+		logger.Error("Invalid alerts rate limit", zap.Int("limit", c.alertsRateLimit))
+		return errInvalidParameters
+	}
+	return stderrs.Join(c.vault.validate(logger, singleNetwork), c.l2.validate(logger))
+}
+
+func (c *nodemonConfig) validateScheduling(logger *zap.Logger) error {
+	if c.pollJitter < 0 || (c.pollJitter > 0 && c.pollJitter+c.timeout >= c.interval) {
+		logger.Error("Invalid poll jitter, jitter with network timeout must be less than polling interval",
+			zap.Stringer("jitter", c.pollJitter), zap.Stringer("timeout", c.timeout),
+		)
+		return errInvalidParameters
+	}
+	if c.pollConcurrency < 0 {
+		logger.Error("Invalid poll concurrency", zap.Int("concurrency", c.pollConcurrency))
+		return errInvalidParameters
+	}
+	if c.backoffAfter < 0 || c.maxBackoff < 0 {
+		logger.Error("Invalid unreachable nodes back-off",
+			zap.Stringer("after", c.backoffAfter), zap.Stringer("max", c.maxBackoff),
+		)
+		return errInvalidParameters
+	}
+	if c.followUpDuration < 0 {
+		logger.Error("Invalid alert follow-up duration", zap.Stringer("duration", c.followUpDuration))
+		return errInvalidParameters
+	}
+	return nil
+}
+
+// networkConfigs returns the monitored networks: the networks from the config file if it's set,
+// otherwise the single network set by the flags.
+func (c *nodemonConfig) networkConfigs() ([]networkConfig, error) {
+	if c.networks == "" {
+		return []networkConfig{{
+			Scheme:            c.scheme,
+			Nodes:             strings.Fields(c.nodes),
+			Storage:           c.storage,
+			VaultSecretPath:   c.vault.secretPath,
+			EventsStoragePath: c.eventsStoragePath,
+		}}, nil
+	}
+	networks, err := loadNetworksConfig(c.networks)
+	if err != nil {
+		return nil, err
+	}
+	if validateErr := validateNetworks(networks, c.vault.present()); validateErr != nil {
+		return nil, errors.Wrap(validateErr, "invalid networks config")
+	}
+	return networks, nil
+}
+
+// scraperOptions returns the options of the configured scraper backend and polling schedule.
+func (c *nodemonConfig) scraperOptions(maxPollingInterval func() time.Duration) []scraping.ScraperOption {
+	opts := []scraping.ScraperOption{scraping.WithScheduling(scraping.SchedulingOptions{
+		Jitter:           c.pollJitter,
+		Concurrency:      c.pollConcurrency,
+		BackoffAfter:     c.backoffAfter,
+		MaxBackoff:       c.maxBackoff,
+		FollowUpDuration: c.followUpDuration,
+		MaxInterval:      maxPollingInterval,
+	})}
+	if c.scraperBackend == scraperBackendGRPC {
+		opts = append(opts, scraping.WithGRPC(scraping.GRPCOptions{
+			BlocksPort:     uint16(c.grpcBlocksPort),
+			UpdatesPort:    uint16(c.grpcUpdatesPort),
+			ReconnectDelay: c.interval,
+			MaxHeaderAge:   grpcMaxHeaderAgeIntervals * c.interval,
+		}))
+	}
+	return opts
 }
 
 func (c *nodemonConfig) runDiscordPairServer() bool { return c.natsPairDiscord }
@@ -299,14 +569,16 @@ func (c *nodemonConfig) runTelegramPairServer() bool { return c.natsPairTelegram
 func (c *nodemonConfig) runAnalyzers(
 	ctx context.Context,
 	cfg *nodemonConfig,
-	es *events.Storage,
+	scheme string,
+	analyzer *analysis.Analyzer,
+	withL2 bool,
 	logger *zap.Logger,
 	notifications <-chan entities.NodesGatheringNotification,
 ) <-chan entities.Alert {
-	alerts := runAnalyzer(cfg, es, logger, notifications)
+	alerts := analyzer.Start(notifications)
 	// L2 analyzer will only be run if the arguments are set
-	if cfg.l2.present() {
-		alertL2 := l2.RunL2Analyzers(ctx, logger, cfg.l2.Nodes())
+	if withL2 && cfg.l2.present() {
+		alertL2 := l2.RunL2Analyzers(ctx, logger, scheme, cfg.l2.Nodes())
 		// merge alerts from different analyzers, wait till both are done
 		mergedAlerts := tools.FanIn(alerts, alertL2)
 		alerts = mergedAlerts
@@ -334,56 +606,129 @@ func run() error {
 	if validateErr := cfg.validate(logger); validateErr != nil {
 		return validateErr
 	}
+	networkCfgs, err := cfg.networkConfigs()
+	if err != nil {
+		logger.Error("Failed to load networks config", zap.Error(err))
+		return errInvalidParameters
+	}
+	analyzerCfgs, err := loadAnalyzerConfigs(networkCfgs, cfg.analyzer)
+	if err != nil {
+		logger.Error("Failed to load analyzer config", zap.Error(err))
+		return errInvalidParameters
+	}
 
 	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer done()
 
-	ns, es, err := initializeStorages(ctx, cfg, logger)
+	networks := make([]*network, 0, len(networkCfgs))
+	defer func() {
+		for _, n := range networks {
+			closeStorages(n.ns, n.es, n.zap)
+		}
+	}()
+	for i := range networkCfgs {
+		n, nErr := newNetwork(ctx, cfg, &networkCfgs[i], analyzerCfgs[i], logger)
+		if nErr != nil {
+			return nErr
+		}
+		networks = append(networks, n)
+		reloadAnalyzerOnSIGHUP(ctx, networkCfgs[i].analyzer(cfg.analyzer), n.analyzer, n.zap)
+	}
+
+	shutdownFn, serviceErr := startServices(ctx, cfg, networks, atom, logger)
+	if serviceErr != nil {
+		return serviceErr
+	}
+	defer shutdownFn()
+
+	<-ctx.Done()
+	logger.Info("Shutting down")
+	return nil
+}
+
+// network holds the storages and the services of one monitored blockchain network.
+type network struct {
+	scheme              string
+	ns                  nodes.Storage
+	es                  *events.Storage
+	scraper             *scraping.Scraper
+	privateNodesHandler *specific.PrivateNodesHandler
+	analyzer            *analysis.Analyzer
+	pollingInterval     time.Duration        // interval of the polling rounds
+	maxPollingInterval  func() time.Duration // limit of the node polling interval with the current analyzer config
+	zap                 *zap.Logger
+}
+
+func newNetwork(
+	ctx context.Context,
+	cfg *nodemonConfig,
+	nc *networkConfig,
+	analyzerCfg *analysis.Config,
+	logger *zap.Logger,
+) (*network, error) {
+	logger = logger.With(zap.String("scheme", nc.Scheme))
+	ns, es, err := initializeStorages(ctx, cfg, nc, logger)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	n := &network{scheme: nc.Scheme, es: es, pollingInterval: cfg.interval, zap: logger}
+	maxPollingInterval := func() time.Duration { // the analyzer is created below, the limit is used after start
+		return entities.MaxPollingInterval(cfg.interval, n.analyzer.AlertVacuumQuota())
 	}
-	defer closeStorages(ns, es, logger)
+	n.ns, n.maxPollingInterval = nodes.WithMaxPollingInterval(ns, maxPollingInterval), maxPollingInterval
 
-	scraper, err := scraping.NewScraper(ns, es, cfg.interval, cfg.timeout, logger)
+	n.scraper, err = scraping.NewScraper(nc.Scheme, ns, es, cfg.interval, cfg.timeout, logger,
+		cfg.scraperOptions(maxPollingInterval)...,
+	)
 	if err != nil {
 		logger.Error("failed to initialize scraper", zap.Error(err))
-		return err
+		closeStorages(ns, es, logger)
+		return nil, err
 	}
 
-	privateNodesHandler, err := specific.NewPrivateNodesHandlerWithUnreachableInitialState(es, ns, logger)
+	n.privateNodesHandler, err = specific.NewPrivateNodesHandlerWithUnreachableInitialState(es, ns, logger)
 	if err != nil {
 		logger.Error("failed to create private nodes handler with unreachable initial state", zap.Error(err))
-		return err
+		closeStorages(ns, es, logger)
+		return nil, err
 	}
 
-	shutdownFn, serviceErr := startServices(ctx, cfg, ns, es, scraper, privateNodesHandler, atom, logger)
-	if serviceErr != nil {
-		return serviceErr
+	n.analyzer = analysis.NewAnalyzer(nc.Scheme, es, ns, analyzerCfg.Options(), logger)
+	if restoreErr := n.analyzer.RestoreMutes(); restoreErr != nil {
+		logger.Error("failed to restore alert mutes", zap.Error(restoreErr))
+		closeStorages(ns, es, logger)
+		return nil, restoreErr
 	}
-	defer shutdownFn()
-
-	<-ctx.Done()
-	logger.Info("Shutting down")
-	return nil
+	return n, nil
 }
 
-func initializeStorages(ctx context.Context, cfg *nodemonConfig,
+func initializeStorages(ctx context.Context, cfg *nodemonConfig, nc *networkConfig,
 	logger *zap.Logger) (nodes.Storage, *events.Storage, error) {
-	ns, err := createNodesStorage(ctx, cfg, logger)
+	ns, err := createNodesStorage(ctx, cfg, nc, logger)
 	if err != nil {
 		logger.Error("failed to initialize nodes storage", zap.Error(err))
 		return nil, nil, err
 	}
 
-	es, err := events.NewStorage(cfg.retention, logger)
+	es, err := createEventsStorage(cfg, nc, logger)
 	if err != nil {
 		logger.Error("failed to initialize events storage", zap.Error(err))
+		if closeErr := ns.Close(); closeErr != nil {
+			logger.Error("failed to close nodes storage", zap.Error(closeErr))
+		}
 		return nil, nil, err
 	}
 
 	return ns, es, nil
 }
 
+func createEventsStorage(cfg *nodemonConfig, nc *networkConfig, logger *zap.Logger) (*events.Storage, error) {
+	if nc.EventsStoragePath == "" {
+		return events.NewStorage(cfg.retention, logger)
+	}
+	return events.NewPersistentStorage(nc.EventsStoragePath, cfg.retention, logger)
+}
+
 func closeStorages(ns nodes.Storage, es *events.Storage, logger *zap.Logger) {
 	if err := ns.Close(); err != nil {
 		logger.Error("failed to close nodes storage", zap.Error(err))
@@ -395,21 +740,101 @@ func closeStorages(ns nodes.Storage, es *events.Storage, logger *zap.Logger) {
 
 type shutdownFunc func()
 
+// networkPipeline is the started processing of the network nodes statements and alerts.
+type networkPipeline struct {
+	*network
+	notifications     <-chan entities.NodesGatheringNotification
+	maintenanceAlerts <-chan entities.Alert
+	ut                *uptime.Tracker
+	pew               specific.PrivateNodesEventsWriter
+	alertsLog         *alertlog.Log
+	forks             *forks.Reporter
+	correlator        *correlation.Correlator
+	mutes             storage.AlertMutes
+}
+
+func (n *network) startPipeline(ctx context.Context, cfg *nodemonConfig) (*networkPipeline, error) {
+	ut, err := uptime.NewTracker(n.es, n.zap)
+	if err != nil {
+		return nil, err
+	}
+	notifications := n.scraper.Start(ctx)
+	notifications = n.privateNodesHandler.Run(notifications) // wraps scraper's notifications
+	notifications, maintenanceAlerts := maintenance.NewHandler(n.ns, n.es, n.zap).Run(notifications)
+	notifications = ut.RunNotifications(notifications) // counts polls for the periodic reports
+
+	alertsLog := alertlog.NewLog(int(cfg.alertsHistorySize), n.analyzer, n.zap)
+	correlator := correlation.NewCorrelator(
+		correlation.Options{GroupWait: cfg.alertGroupWait, RateLimit: cfg.alertsRateLimit},
+		n.zap,
+	)
+	return &networkPipeline{
+		network:           n,
+		notifications:     notifications,
+		maintenanceAlerts: maintenanceAlerts,
+		ut:                ut,
+		pew:               n.privateNodesHandler.PrivateNodesEventsWriter(),
+		alertsLog:         alertsLog,
+		forks:             forks.NewReporter(n.es, cfg.forkReportHeights, forks.DefaultReportsLimit, n.zap),
+		correlator:        correlator,
+		mutes:             correlator.Mutes(n.analyzer.AlertMutes()), // alert groups are muted by their member alerts
+	}, nil
+}
+
+func (p *networkPipeline) apiNetwork() api.Network {
+	return api.Network{
+		Scheme:             p.scheme,
+		NodesStorage:       p.ns,
+		EventsStorage:      p.es,
+		AlertsLog:          p.alertsLog,
+		Mutes:              p.mutes,
+		Forks:              p.forks,
+		PrivateNodesEvents: p.pew,
+		PollingInterval:    p.pollingInterval,
+		MaxPollingInterval: p.maxPollingInterval,
+	}
+}
+
+// runAlerts runs the analyzers of the network and publishes their alerts.
+func (p *networkPipeline) runAlerts(ctx context.Context, cfg *nodemonConfig, withL2 bool) {
+	alerts := cfg.runAnalyzers(ctx, cfg, p.scheme, p.analyzer, withL2, p.zap, p.notifications)
+	alerts = p.forks.Run(alerts)     // attaches fork reports to the state hash alerts
+	alerts = p.alertsLog.Run(alerts) // records alerts before publishing them
+	alerts = p.ut.RunAlerts(alerts)
+	alerts = p.scraper.RunAlerts(alerts) // speeds up polling of the alerted nodes
+	alerts = p.correlator.Run(alerts)    // groups related alerts and suppresses alerts storms
+	// maintenance summaries are one-off messages, so they aren't recorded as active alerts
+	alerts = tools.FanIn(alerts, p.maintenanceAlerts)
+
+	runMessagingServices(ctx, cfg, p, alerts)
+}
+
 func startServices( //nolint:nonamedreturns // needs in defer
 	ctx context.Context,
 	cfg *nodemonConfig,
-	ns nodes.Storage,
-	es *events.Storage,
-	scraper *scraping.Scraper,
-	privateNodesHandler *specific.PrivateNodesHandler,
+	networks []*network,
 	atom *zap.AtomicLevel,
 	logger *zap.Logger,
 ) (_ shutdownFunc, runErr error) {
-	notifications := scraper.Start(ctx)
-	notifications = privateNodesHandler.Run(notifications) // wraps scraper's notifications
-
-	pew := privateNodesHandler.PrivateNodesEventsWriter()
-	a, err := api.NewAPI(cfg.bindAddress, ns, es, cfg.apiReadTimeout, logger, pew, atom, cfg.development)
+	pipelines := make([]*networkPipeline, 0, len(networks))
+	apiNetworks := make([]api.Network, 0, len(networks))
+	for _, n := range networks {
+		p, err := n.startPipeline(ctx, cfg)
+		if err != nil {
+			logger.Error("failed to start network pipeline", zap.String("network", n.scheme), zap.Error(err))
+			return nil, err
+		}
+		pipelines = append(pipelines, p)
+		apiNetworks = append(apiNetworks, p.apiNetwork())
+	}
+	a, err := api.NewAPI(
+		cfg.bindAddress,
+		apiNetworks,
+		cfg.apiReadTimeout,
+		logger,
+		atom,
+		cfg.development,
+	)
 	if err != nil {
 		logger.Error("failed to initialize API", zap.Error(err))
 		return nil, err
@@ -445,10 +870,9 @@ func startServices( //nolint:nonamedreturns // needs in defer
 		shutdownFn = chainShutdownFuncs(shutdownFn, natsShutdown) // add NATS server shutdown to the chain
 	}
 
-	alerts := cfg.runAnalyzers(ctx, cfg, es, logger, notifications)
-
-	runMessagingServices(ctx, cfg, alerts, logger, ns, es, pew)
-
+	for i, p := range pipelines {
+		p.runAlerts(ctx, cfg, i == 0) // L2 nodes are monitored within the first network
+	}
 	return shutdownFn, err
 }
 
@@ -460,7 +884,12 @@ func chainShutdownFuncs(fns ...shutdownFunc) shutdownFunc {
 	}
 }
 
-func createNodesStorage(ctx context.Context, cfg *nodemonConfig, logger *zap.Logger) (nodes.Storage, error) {
+func createNodesStorage(
+	ctx context.Context,
+	cfg *nodemonConfig,
+	nc *networkConfig,
+	logger *zap.Logger,
+) (nodes.Storage, error) {
 	var (
 		ns  nodes.Storage
 		err error
@@ -475,12 +904,12 @@ func createNodesStorage(ctx context.Context, cfg *nodemonConfig, logger *zap.Log
 			ctx,
 			cl,
 			cfg.vault.mountPath,
-			cfg.vault.secretPath,
-			strings.Fields(cfg.nodes),
+			nc.VaultSecretPath,
+			nc.Nodes,
 			logger,
 		)
 	} else {
-		ns, err = nodes.NewJSONFileStorage(cfg.storage, strings.Fields(cfg.nodes), logger)
+		ns, err = nodes.NewJSONFileStorage(nc.Storage, nc.Nodes, logger)
 	}
 	if err != nil {
 		logger.Error("failed to initialize nodes storage", zap.Error(err))
@@ -489,31 +918,44 @@ func createNodesStorage(ctx context.Context, cfg *nodemonConfig, logger *zap.Log
 	return ns, nil
 }
 
-func runAnalyzer(
-	cfg *nodemonConfig,
-	es *events.Storage,
-	zap *zap.Logger,
-	notifications <-chan entities.NodesGatheringNotification,
-) <-chan entities.Alert {
-	opts := &analysis.AnalyzerOptions{
-		BaseTargetCriterionOpts: &criteria.BaseTargetCriterionOptions{Threshold: cfg.baseTargetThreshold},
-	}
-	analyzer := analysis.NewAnalyzer(es, opts, zap)
-	alerts := analyzer.Start(notifications)
-	return alerts
+// reloadAnalyzerOnSIGHUP reloads the analyzer config on each SIGHUP. Invalid config is rejected
+// and the analyzer keeps working with the previous one.
+func reloadAnalyzerOnSIGHUP(
+	ctx context.Context,
+	c *nodemonAnalyzerConfig,
+	analyzer *analysis.Analyzer,
+	logger *zap.Logger,
+) {
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+	go func() {
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				analyzerCfg, err := c.load()
+				if err != nil {
+					logger.Error("Failed to reload analyzer config, the previous one is kept", zap.Error(err))
+					continue
+				}
+				analyzer.SetOptions(analyzerCfg.Options())
+				logger.Info("Analyzer config has been reloaded", zap.String("path", c.path))
+			}
+		}
+	}()
 }
 
 func runMessagingServices(
 	ctx context.Context,
 	cfg *nodemonConfig,
+	p *networkPipeline,
 	alerts <-chan entities.Alert,
-	logger *zap.Logger,
-	ns nodes.Storage,
-	es *events.Storage,
-	pew specific.PrivateNodesEventsWriter,
 ) {
+	logger := p.zap
 	go func() {
-		pubSubErr := pubsub.StartPubMessagingServer(ctx, cfg.natsMessagingURL, alerts, logger, cfg.scheme)
+		pubSubErr := pubsub.StartPubMessagingServer(ctx, cfg.natsMessagingURL, alerts, logger, p.scheme)
 		if pubSubErr != nil {
 			logger.Fatal("failed to start pub messaging server", zap.Error(pubSubErr))
 		}
@@ -521,8 +963,10 @@ func runMessagingServices(
 
 	if cfg.runTelegramPairServer() {
 		go func() {
-			telegramTopic := messaging.TelegramBotRequestsTopic(cfg.scheme)
-			pairErr := pair.StartPairMessagingServer(ctx, cfg.natsMessagingURL, ns, es, pew, logger, telegramTopic)
+			telegramTopic := messaging.TelegramBotRequestsTopic(p.scheme)
+			pairErr := pair.StartPairMessagingServer(ctx, cfg.natsMessagingURL, p.ns, p.es, p.pew, p.alertsLog,
+				p.mutes, p.ut, p.forks, logger, telegramTopic,
+			)
 			if pairErr != nil {
 				logger.Fatal("failed to start pair messaging server", zap.Error(pairErr))
 			}
@@ -531,8 +975,10 @@ func runMessagingServices(
 
 	if cfg.runDiscordPairServer() {
 		go func() {
-			discordTopic := messaging.DiscordBotRequestsTopic(cfg.scheme)
-			pairErr := pair.StartPairMessagingServer(ctx, cfg.natsMessagingURL, ns, es, pew, logger, discordTopic)
+			discordTopic := messaging.DiscordBotRequestsTopic(p.scheme)
+			pairErr := pair.StartPairMessagingServer(ctx, cfg.natsMessagingURL, p.ns, p.es, p.pew, p.alertsLog,
+				p.mutes, p.ut, p.forks, logger, discordTopic,
+			)
 			if pairErr != nil {
 				logger.Fatal("failed to start pair messaging server", zap.Error(pairErr))
 			}
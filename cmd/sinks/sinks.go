@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	stderrs "errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"nodemon/internal"
+	"nodemon/pkg/messaging"
+	"nodemon/pkg/sinks"
+	"nodemon/pkg/tools"
+
+	"github.com/nats-io/nats.go"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+var (
+	errInvalidParameters = stderrs.New("invalid parameters")
+)
+
+func main() {
+	const (
+		contextCanceledExitCode   = 130
+		invalidParametersExitCode = 2
+	)
+	if err := run(); err != nil {
+		switch {
+		case stderrs.Is(err, context.Canceled):
+			os.Exit(contextCanceledExitCode)
+		case stderrs.Is(err, errInvalidParameters):
+			os.Exit(invalidParametersExitCode)
+		default:
+			log.Fatal(err)
+		}
+	}
+}
+
+type sinksConfig struct {
+	natsMessagingURL string
+	configPath       string
+	logLevel         string
+	development      bool
+	scheme           string
+}
+
+func newSinksConfig() *sinksConfig {
+	c := new(sinksConfig)
+	tools.StringVarFlagWithEnv(&c.natsMessagingURL, "nats-msg-url",
+		"nats://127.0.0.1:4222", "NATS server URL for messaging")
+	tools.StringVarFlagWithEnv(&c.configPath, "config", "",
+		"Path to the sinks configuration file in YAML or JSON format.")
+	tools.StringVarFlagWithEnv(&c.logLevel, "log-level", "INFO",
+		"Logging level. Supported levels: DEBUG, INFO, WARN, ERROR, FATAL. Default logging level INFO.")
+	tools.BoolVarFlagWithEnv(&c.development, "development", false, "Development mode.")
+	tools.StringVarFlagWithEnv(&c.scheme, "scheme", "",
+		"Blockchain scheme i.e. mainnet, testnet, stagenet. Used in messaging service")
+	return c
+}
+
+func (c *sinksConfig) validate(zap *zap.Logger) error {
+	if c.configPath == "" {
+		zap.Error("the sinks configuration file must be specified")
+		return errInvalidParameters
+	}
+	if c.scheme == "" {
+		zap.Error("the blockchain scheme must be specified")
+		return errInvalidParameters
+	}
+	return nil
+}
+
+func run() error {
+	cfg := newSinksConfig()
+	flag.Parse()
+
+	logger, _, err := tools.SetupZapLogger(cfg.logLevel, cfg.development)
+	if err != nil {
+		log.Printf("Failed to setup zap logger: %v", err)
+		return errInvalidParameters
+	}
+	defer func(zap *zap.Logger) {
+		if syncErr := zap.Sync(); syncErr != nil {
+			log.Println(syncErr)
+		}
+	}(logger)
+
+	logger.Info("Starting alert sinks", zap.String("version", internal.Version()))
+
+	if validationErr := cfg.validate(logger); validationErr != nil {
+		return validationErr
+	}
+	sinksCfg, err := sinks.LoadConfig(cfg.configPath)
+	if err != nil {
+		logger.Error("Failed to load sinks config", zap.Error(err))
+		return errInvalidParameters
+	}
+	dispatcher, err := sinks.NewDispatcher(sinksCfg, cfg.scheme, logger)
+	if err != nil {
+		logger.Error("Failed to create sinks", zap.Error(err))
+		return errInvalidParameters
+	}
+
+	ctx, done := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer done()
+
+	nc, err := nats.Connect(cfg.natsMessagingURL, nats.Timeout(nats.DefaultTimeout))
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to nats server")
+	}
+	defer nc.Close()
+
+	handler := func(msg *nats.Msg) {
+		alertMsg, msgErr := messaging.NewAlertMessageFromBytes(msg.Data)
+		if msgErr != nil {
+			logger.Error("Failed to parse alert message", zap.String("topic", msg.Subject), zap.Error(msgErr))
+			return
+		}
+		n, decodeErr := sinks.NewNotification(alertMsg)
+		if decodeErr != nil {
+			logger.Error("Failed to decode alert", zap.String("topic", msg.Subject), zap.Error(decodeErr))
+			return
+		}
+		dispatcher.Dispatch(n)
+	}
+	for _, alertType := range dispatcher.AlertTypes() {
+		topic := messaging.PubSubMsgTopic(cfg.scheme, alertType)
+		if _, subErr := nc.Subscribe(topic, handler); subErr != nil {
+			return errors.Wrapf(subErr, "failed to subscribe to topic '%s'", topic)
+		}
+		logger.Debug("Subscribed to alerts", zap.String("topic", topic))
+	}
+
+	dispatcher.Run(ctx)
+	logger.Info("Alert sinks finished")
+	return nil
+}
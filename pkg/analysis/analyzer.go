@@ -2,7 +2,8 @@ package analysis
 
 import (
 	"context"
-	"iter"
+	"maps"
+	"slices"
 	"sync"
 	"time"
 
@@ -10,8 +11,10 @@ import (
 	"nodemon/pkg/analysis/storage"
 	"nodemon/pkg/entities"
 	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/nodes"
 
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"go.uber.org/zap"
 )
 
@@ -25,20 +28,58 @@ type AnalyzerOptions struct {
 	StateHashCriteriaOpts   *criteria.StateHashCriterionOptions
 	BaseTargetCriterionOpts *criteria.BaseTargetCriterionOptions
 	ChallengeCriterionOpts  *criteria.ChallengedBlockCriterionOptions
+	VersionCriterionOpts    *criteria.VersionCriterionOptions
+	ChainStuckCriterionOpts *criteria.ChainStuckCriterionOptions
+	MissingGeneratorOpts    *criteria.MissingGeneratorCriterionOptions
+	DisabledCriteria        []string // names of the criteria which are skipped
+	// GroupedCriteria are the names of the criteria which compare the nodes only within their groups.
+	// Each node tag defines a group, the nodes without tags form a group of their own.
+	GroupedCriteria []string
 }
 
 type Analyzer struct {
-	es   *events.Storage
-	as   *storage.AlertsStorage
-	opts *AnalyzerOptions
-	zap  *zap.Logger
+	es       *events.Storage
+	ns       nodes.Storage // optional, used to group the nodes by tags
+	as       *storage.AlertsStorage
+	criteria *criteria.Registry
+	mu       *sync.RWMutex // guards opts
+	opts     *AnalyzerOptions
+	zap      *zap.Logger
 }
 
 const (
 	heightAlertConfirmationsDefault = 2
 )
 
-func NewAnalyzer(es *events.Storage, opts *AnalyzerOptions, logger *zap.Logger) *Analyzer {
+// NewAnalyzer creates the analyzer of the network with the given scheme. The nodes storage is optional: without it
+// the grouped criteria compare all the nodes together.
+func NewAnalyzer(
+	scheme string,
+	es *events.Storage,
+	ns nodes.Storage,
+	opts *AnalyzerOptions,
+	logger *zap.Logger,
+) *Analyzer {
+	opts = withDefaults(opts)
+	asOpts := append(alertsStorageOptions(opts), storage.MetricsScheme(scheme))
+	if es != nil {
+		asOpts = append(asOpts, storage.PersistentMutes(es))
+	}
+	as := storage.NewAlertsStorage(logger, asOpts...)
+	a := &Analyzer{
+		es:       es,
+		ns:       ns,
+		as:       as,
+		criteria: criteria.NewRegistry(),
+		mu:       new(sync.RWMutex),
+		opts:     opts,
+		zap:      logger,
+	}
+	a.registerBuiltinCriteria()
+	return a
+}
+
+func withDefaults(opts *AnalyzerOptions) *AnalyzerOptions {
 	if opts == nil {
 		opts = &AnalyzerOptions{}
 	}
@@ -56,19 +97,59 @@ func NewAnalyzer(es *events.Storage, opts *AnalyzerOptions, logger *zap.Logger)
 			},
 		}
 	}
+	return opts
+}
 
-	as := storage.NewAlertsStorage(logger,
+func alertsStorageOptions(opts *AnalyzerOptions) []storage.AlertsStorageOption {
+	return []storage.AlertsStorageOption{
 		storage.AlertBackoff(opts.AlertBackoff),
 		storage.AlertVacuumQuota(opts.AlertVacuumQuota),
 		storage.AlertConfirmations(opts.AlertConfirmations...),
-	)
-	return &Analyzer{es: es, as: as, opts: opts, zap: logger}
+	}
+}
+
+// SetOptions replaces the analyzer options. The new options are used starting from the next polling result,
+// the already tracked alerts are kept.
+func (a *Analyzer) SetOptions(opts *AnalyzerOptions) {
+	opts = withDefaults(opts)
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.as.Reconfigure(alertsStorageOptions(opts)...)
+	a.opts = opts
+	a.warnUnknownCriteria(opts.DisabledCriteria)
 }
 
+func (a *Analyzer) options() *AnalyzerOptions {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.opts
+}
+
+// AlertState returns the repeats and backoff state of the alert tracked by the analyzer.
+func (a *Analyzer) AlertState(alertID crypto.Digest) (storage.AlertState, bool) {
+	return a.as.AlertState(alertID)
+}
+
+// AlertVacuumQuota returns the number of the rounds without repeats after which the alert is resolved.
+func (a *Analyzer) AlertVacuumQuota() int { return a.options().AlertVacuumQuota }
+
+// AlertMutes returns the manager of the alert mutes which are honoured by the analyzer.
+func (a *Analyzer) AlertMutes() storage.AlertMutes { return a.as }
+
+// RestoreMutes loads the alert mutes kept in the events storage before the restart.
+func (a *Analyzer) RestoreMutes() error { return a.as.RestoreMutes() }
+
 func (a *Analyzer) analyze(alerts chan<- entities.Alert, pollingResult entities.NodesGatheringNotification) error {
+	polledNodes := make(map[string]struct{}, pollingResult.NodesCount())
+	for _, node := range pollingResult.Nodes() {
+		polledNodes[node] = struct{}{}
+	}
 	statements := make(entities.NodeStatements, 0, pollingResult.NodesCount())
 	err := a.es.ViewStatementsByTimestamp(pollingResult.Timestamp(), func(statement *entities.NodeStatement) bool {
-		statements = append(statements, *statement)
+		// statements of the nodes which are excluded from the notification (e.g. under maintenance) are skipped
+		if _, ok := polledNodes[statement.Node]; ok {
+			statements = append(statements, *statement)
+		}
 		return true
 	})
 	if err != nil {
@@ -88,16 +169,37 @@ func (a *Analyzer) analyze(alerts chan<- entities.Alert, pollingResult entities.
 	}()
 
 	// run criterion routines
-	routines := a.criteriaRoutines(statements, pollingResult.Timestamp())
-	wg.Add(len(routines))
-	for _, f := range routines {
-		go func(f func(in chan<- entities.Alert) error) {
+	var (
+		ts          = pollingResult.Timestamp()
+		statusSplit = statements.SplitByNodeStatus()
+		opts        = a.options()
+		enabled     = a.criteria.Enabled(opts.DisabledCriteria)
+		nodesTags   = a.nodesTags()
+	)
+	for _, nodeStatements := range statusSplit {
+		nodeStatements.SortByNodeAsc()
+	}
+	wg.Add(len(enabled))
+	for _, criterion := range enabled {
+		go func(criterion criteria.Criterion) {
 			defer wg.Done()
-			if routineErr := f(criteriaOut); routineErr != nil {
-				a.zap.Error("Error occurred on criterion routine", zap.Error(routineErr))
-				criteriaOut <- entities.NewInternalErrorAlert(pollingResult.Timestamp(), routineErr)
+			var consumed entities.NodeStatements
+			for _, status := range criterion.Statuses() {
+				consumed = append(consumed, statusSplit[status]...)
+			}
+			groups := []entities.NodeStatements{consumed}
+			if slices.Contains(opts.GroupedCriteria, criterion.Name()) {
+				groups = splitByGroups(consumed, nodesTags)
+			}
+			for _, group := range groups {
+				if routineErr := criterion.Analyze(ctx, criteriaOut, ts, group); routineErr != nil {
+					a.zap.Error("Error occurred on criterion routine",
+						zap.String("criterion", criterion.Name()), zap.Error(routineErr),
+					)
+					criteriaOut <- entities.NewInternalErrorAlert(ts, routineErr)
+				}
 			}
-		}(f)
+		}(criterion)
 	}
 	// run analyzer proxy
 	go func(ctx context.Context, alertsIn chan<- entities.Alert, criteriaOut <-chan entities.Alert) {
@@ -129,70 +231,156 @@ func (a *Analyzer) analyze(alerts chan<- entities.Alert, pollingResult entities.
 	return nil
 }
 
-func joinSlicesSeq2[Slice ~[]E, E any](slices ...Slice) iter.Seq2[int, E] {
-	return func(yield func(int, E) bool) {
-		var i int
-		for _, slice := range slices {
-			for _, v := range slice {
-				if !yield(i, v) {
-					return
-				}
-				i++
+// nodesTags returns the tags of the regular and specific nodes. Nil map is returned if there are no tags.
+func (a *Analyzer) nodesTags() map[string][]string {
+	if a.ns == nil {
+		return nil
+	}
+	var tags map[string][]string
+	for _, specific := range []bool{false, true} {
+		nodesList, err := a.ns.Nodes(specific)
+		if err != nil {
+			a.zap.Error("Failed to get nodes tags, the nodes aren't grouped", zap.Error(err))
+			return nil
+		}
+		for _, node := range nodesList {
+			if len(node.Tags) == 0 {
+				continue
+			}
+			if tags == nil {
+				tags = make(map[string][]string)
 			}
+			tags[node.URL] = node.Tags
 		}
 	}
+	return tags
 }
 
-func (a *Analyzer) criteriaRoutines(
-	statements entities.NodeStatements,
-	timestamp int64,
-) []func(in chan<- entities.Alert) error {
-	statusSplit := statements.SplitByNodeStatus()
-	for _, nodeStatements := range statusSplit {
-		nodeStatements.SortByNodeAsc()
+// splitByGroups splits the statements by the node groups. A node with several tags is included in each of its
+// groups, the nodes without tags form a separate group. The groups with the same nodes are analyzed once.
+func splitByGroups(statements entities.NodeStatements, nodesTags map[string][]string) []entities.NodeStatements {
+	if len(nodesTags) == 0 {
+		return []entities.NodeStatements{statements}
 	}
-	return []func(in chan<- entities.Alert) error{
-		func(in chan<- entities.Alert) error {
-			criterion := criteria.NewIncompleteCriterion(a.es, a.opts.IncompleteCriteriaOpts, a.zap)
-			return criterion.Analyze(in, statusSplit[entities.Incomplete])
-		},
-		func(in chan<- entities.Alert) error {
-			for _, statement := range statusSplit[entities.InvalidHeight] {
-				in <- &entities.InvalidHeightAlert{NodeStatement: statement}
-			}
-			return nil
-		},
-		func(in chan<- entities.Alert) error {
-			criterion := criteria.NewChallengedBlockCriterion(a.opts.ChallengeCriterionOpts, a.zap)
-			statementsToAnalyze := joinSlicesSeq2(statusSplit[entities.Incomplete], statusSplit[entities.OK])
-			criterion.Analyze(in, timestamp, statementsToAnalyze)
-			return nil
-		},
-		func(in chan<- entities.Alert) error {
-			criterion := criteria.NewUnreachableCriterion(a.es, a.opts.UnreachableCriteriaOpts, a.zap)
-			return criterion.Analyze(in, timestamp, statusSplit[entities.Unreachable])
-		},
-		func(in chan<- entities.Alert) error {
-			criterion := criteria.NewHeightCriterion(a.opts.HeightCriteriaOpts, a.zap)
-			criterion.Analyze(in, timestamp, statusSplit[entities.OK])
-			return nil
-		},
-		func(in chan<- entities.Alert) error {
-			criterion := criteria.NewStateHashCriterion(a.es, a.opts.StateHashCriteriaOpts, a.zap)
-			return criterion.Analyze(in, timestamp, statusSplit[entities.OK])
-		},
-		func(in chan<- entities.Alert) error {
-			criterion, err := criteria.NewBaseTargetCriterion(a.opts.BaseTargetCriterionOpts)
-			if err != nil {
-				return err
-			}
-			criterion.Analyze(in, timestamp, statusSplit[entities.OK])
-			return nil
-		},
+	const untagged = ""
+	byTag := make(map[string]entities.NodeStatements)
+	for _, statement := range statements {
+		tags := nodesTags[statement.Node]
+		if len(tags) == 0 {
+			byTag[untagged] = append(byTag[untagged], statement)
+			continue
+		}
+		for _, tag := range tags {
+			byTag[tag] = append(byTag[tag], statement)
+		}
+	}
+	tags := slices.Sorted(maps.Keys(byTag))
+	groups := make([]entities.NodeStatements, 0, len(tags))
+	for _, tag := range tags {
+		group := byTag[tag]
+		if !slices.ContainsFunc(groups, func(other entities.NodeStatements) bool {
+			return slices.Equal(other.Nodes(), group.Nodes())
+		}) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+func (a *Analyzer) registerBuiltinCriteria() {
+	versionDrift := criteria.NewVersionDrift()
+	builtin := []criteria.Criterion{
+		criteria.NewCriterion(criteria.IncompleteCriterionName, []entities.NodeStatus{entities.Incomplete},
+			func(_ context.Context, in chan<- entities.Alert, _ int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewIncompleteCriterion(a.es, a.options().IncompleteCriteriaOpts, a.zap)
+				return criterion.Analyze(in, statements)
+			},
+		),
+		criteria.NewCriterion(criteria.InvalidHeightCriterionName, []entities.NodeStatus{entities.InvalidHeight},
+			func(_ context.Context, in chan<- entities.Alert, _ int64, statements entities.NodeStatements) error {
+				for _, statement := range statements {
+					in <- &entities.InvalidHeightAlert{NodeStatement: statement}
+				}
+				return nil
+			},
+		),
+		criteria.NewCriterion(criteria.ChallengedBlockCriterionName,
+			[]entities.NodeStatus{entities.Incomplete, entities.OK},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewChallengedBlockCriterion(a.options().ChallengeCriterionOpts, a.zap)
+				criterion.Analyze(in, ts, slices.All(statements))
+				return nil
+			},
+		),
+		criteria.NewCriterion(criteria.UnreachableCriterionName, []entities.NodeStatus{entities.Unreachable},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewUnreachableCriterion(a.es, a.options().UnreachableCriteriaOpts, a.zap)
+				return criterion.Analyze(in, ts, statements)
+			},
+		),
+		criteria.NewCriterion(criteria.HeightCriterionName, []entities.NodeStatus{entities.OK},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewHeightCriterion(a.options().HeightCriteriaOpts, a.zap)
+				criterion.Analyze(in, ts, statements)
+				return nil
+			},
+		),
+		criteria.NewCriterion(criteria.StateHashCriterionName, []entities.NodeStatus{entities.OK},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewStateHashCriterion(a.es, a.options().StateHashCriteriaOpts, a.zap)
+				return criterion.Analyze(in, ts, statements)
+			},
+		),
+		criteria.NewCriterion(criteria.BaseTargetCriterionName, []entities.NodeStatus{entities.OK},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion, err := criteria.NewBaseTargetCriterion(a.options().BaseTargetCriterionOpts)
+				if err != nil {
+					return err
+				}
+				criterion.Analyze(in, ts, statements)
+				return nil
+			},
+		),
+		criteria.NewCriterion(criteria.VersionCriterionName,
+			[]entities.NodeStatus{entities.OK, entities.Incomplete, entities.InvalidHeight},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewVersionCriterion(a.options().VersionCriterionOpts, versionDrift, a.zap)
+				return criterion.Analyze(in, ts, statements)
+			},
+		),
+		criteria.NewCriterion(criteria.ChainStuckCriterionName, []entities.NodeStatus{entities.OK},
+			func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+				criterion := criteria.NewChainStuckCriterion(a.es, a.options().ChainStuckCriterionOpts, a.zap)
+				return criterion.Analyze(in, ts, statements)
+			},
+		),
+		criteria.NewCriterion(criteria.MissingGeneratorCriterionName, nil, // uses the statements history only
+			func(_ context.Context, in chan<- entities.Alert, ts int64, _ entities.NodeStatements) error {
+				criterion := criteria.NewMissingGeneratorCriterion(a.es, a.options().MissingGeneratorOpts, a.zap)
+				return criterion.Analyze(in, ts)
+			},
+		),
+	}
+	for _, c := range builtin {
+		if err := a.criteria.Register(c); err != nil {
+			panic(err) // built-in criteria names are unique
+		}
+	}
+}
+
+// Criteria returns the registry of the analyzer criteria. Custom criteria should be registered before Start.
+func (a *Analyzer) Criteria() *criteria.Registry { return a.criteria }
+
+func (a *Analyzer) warnUnknownCriteria(disabled []string) {
+	for _, name := range disabled {
+		if !a.criteria.Has(name) {
+			a.zap.Warn("Unknown criterion is disabled", zap.String("criterion", name))
+		}
 	}
 }
 
 func (a *Analyzer) Start(notifications <-chan entities.NodesGatheringNotification) <-chan entities.Alert {
+	a.warnUnknownCriteria(a.options().DisabledCriteria)
 	out := make(chan entities.Alert)
 	go func(alerts chan<- entities.Alert) {
 		defer close(alerts)
@@ -1,6 +1,7 @@
 package analysis_test
 
 import (
+	"context"
 	"encoding/binary"
 	"fmt"
 	"log"
@@ -11,7 +12,9 @@ import (
 	"nodemon/pkg/analysis/criteria"
 	"nodemon/pkg/entities"
 	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/nodes"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
@@ -166,7 +169,7 @@ func runTestCase(zap *zap.Logger, test testCase) func(t *testing.T) {
 		alerts := make(chan entities.Alert)
 		go func() {
 			defer close(done)
-			analyzer := analysis.NewAnalyzer(es, test.opts, zap)
+			analyzer := analysis.NewAnalyzer("mainnet", es, nil, test.opts, zap)
 			event := entities.NewNodesGatheringComplete(test.nodes, mkTimestamp(test.height))
 			notifications := make(chan entities.NodesGatheringNotification)
 			analyzerOut := analyzer.Start(notifications)
@@ -188,3 +191,103 @@ func runTestCase(zap *zap.Logger, test testCase) func(t *testing.T) {
 		}
 	}
 }
+
+func TestAnalyzer_customCriterion(t *testing.T) {
+	es, err := events.NewStorage(time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+	const ts = 100
+	fillEventsStorage(t, es, []entities.Event{
+		entities.NewHeightEvent("a", ts, "V", 1),
+		entities.NewUnreachableEvent("b", ts),
+		entities.NewUnreachableEvent("c", ts),
+	})
+
+	analyzer := analysis.NewAnalyzer("mainnet", es, nil, &analysis.AnalyzerOptions{
+		DisabledCriteria: analyzerBuiltinCriteria(),
+	}, zap.NewNop())
+	custom := criteria.NewCriterion("custom", []entities.NodeStatus{entities.Unreachable},
+		func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+			in <- &entities.SimpleAlert{Timestamp: ts, Description: fmt.Sprintf("%d unreachable", len(statements))}
+			return nil
+		},
+	)
+	require.NoError(t, analyzer.Criteria().Register(custom))
+
+	notifications := make(chan entities.NodesGatheringNotification, 1)
+	notifications <- entities.NewNodesGatheringComplete(entities.Nodes{"a", "b", "c"}, ts)
+	close(notifications)
+	var received []entities.Alert
+	for alert := range analyzer.Start(notifications) {
+		received = append(received, alert)
+	}
+	require.Len(t, received, 1)
+	assert.Equal(t, &entities.SimpleAlert{Timestamp: ts, Description: "2 unreachable"}, received[0])
+}
+
+type taggedNodesStorage struct {
+	nodes.Storage
+	nodes []entities.Node
+}
+
+func (s taggedNodesStorage) Nodes(specific bool) ([]entities.Node, error) {
+	if specific {
+		return nil, nil
+	}
+	return s.nodes, nil
+}
+
+func TestAnalyzer_groupedCriterion(t *testing.T) {
+	es, err := events.NewStorage(time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+	const ts = 100
+	fillEventsStorage(t, es, []entities.Event{
+		entities.NewUnreachableEvent("a", ts),
+		entities.NewUnreachableEvent("b", ts),
+		entities.NewUnreachableEvent("c", ts),
+		entities.NewUnreachableEvent("d", ts),
+	})
+	ns := taggedNodesStorage{nodes: []entities.Node{
+		{URL: "a", Tags: []string{"x"}},
+		{URL: "b", Tags: []string{"x", "y"}},
+		{URL: "c"},
+		{URL: "d", Tags: []string{"y"}},
+	}}
+
+	analyzer := analysis.NewAnalyzer("mainnet", es, ns, &analysis.AnalyzerOptions{
+		DisabledCriteria: analyzerBuiltinCriteria(),
+		GroupedCriteria:  []string{"custom"},
+	}, zap.NewNop())
+	custom := criteria.NewCriterion("custom", []entities.NodeStatus{entities.Unreachable},
+		func(_ context.Context, in chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+			in <- &entities.SimpleAlert{Timestamp: ts, Description: fmt.Sprint(statements.Nodes())}
+			return nil
+		},
+	)
+	require.NoError(t, analyzer.Criteria().Register(custom))
+
+	notifications := make(chan entities.NodesGatheringNotification, 1)
+	notifications <- entities.NewNodesGatheringComplete(entities.Nodes{"a", "b", "c", "d"}, ts)
+	close(notifications)
+	var received []string
+	for alert := range analyzer.Start(notifications) {
+		received = append(received, alert.Message())
+	}
+	assert.Equal(t, []string{"[c]", "[a b]", "[b d]"}, received)
+}
+
+func analyzerBuiltinCriteria() []string {
+	return []string{
+		criteria.UnreachableCriterionName,
+		criteria.IncompleteCriterionName,
+		criteria.InvalidHeightCriterionName,
+		criteria.ChallengedBlockCriterionName,
+		criteria.HeightCriterionName,
+		criteria.StateHashCriterionName,
+		criteria.BaseTargetCriterionName,
+		criteria.VersionCriterionName,
+		criteria.ChainStuckCriterionName,
+		criteria.MissingGeneratorCriterionName,
+	}
+}
@@ -0,0 +1,190 @@
+package analysis
+
+import (
+	"bytes"
+	stderrs "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the analyzer configuration which can be loaded from a YAML or JSON file.
+// The fields which are absent in the file keep their default values.
+type Config struct {
+	AlertBackoff       int                                       `yaml:"alert_backoff"`
+	AlertVacuumQuota   int                                       `yaml:"alert_vacuum_quota"`
+	AlertConfirmations map[entities.AlertName]int                `yaml:"alert_confirmations"`
+	Unreachable        criteria.UnreachableCriterionOptions      `yaml:"unreachable"`
+	Incomplete         criteria.IncompleteCriterionOptions       `yaml:"incomplete"`
+	Height             criteria.HeightCriterionOptions           `yaml:"height"`
+	StateHash          criteria.StateHashCriterionOptions        `yaml:"state_hash"`
+	BaseTarget         criteria.BaseTargetCriterionOptions       `yaml:"base_target"`
+	Version            criteria.VersionCriterionOptions          `yaml:"version"`
+	ChainStuck         criteria.ChainStuckCriterionOptions       `yaml:"chain_stuck"`
+	MissingGenerator   criteria.MissingGeneratorCriterionOptions `yaml:"missing_generator"`
+	DisabledCriteria   []string                                  `yaml:"disabled_criteria"`
+	GroupedCriteria    []string                                  `yaml:"grouped_criteria"`
+}
+
+// DefaultConfig returns the configuration with the default values. Base target threshold has no default value.
+func DefaultConfig() *Config {
+	return &Config{
+		AlertBackoff:     storage.DefaultAlertBackoff,
+		AlertVacuumQuota: storage.DefaultAlertVacuumQuota,
+		AlertConfirmations: map[entities.AlertName]int{
+			entities.HeightAlertName: heightAlertConfirmationsDefault,
+		},
+		Unreachable:      *criteria.DefaultUnreachableCriterionOptions(),
+		Incomplete:       *criteria.DefaultIncompleteCriterionOptions(),
+		Height:           *criteria.DefaultHeightCriterionOptions(),
+		StateHash:        *criteria.DefaultStateHashCriterionOptions(),
+		Version:          *criteria.DefaultVersionCriterionOptions(),
+		ChainStuck:       *criteria.DefaultChainStuckCriterionOptions(),
+		MissingGenerator: *criteria.DefaultMissingGeneratorCriterionOptions(),
+		GroupedCriteria:  []string{criteria.HeightCriterionName, criteria.StateHashCriterionName},
+	}
+}
+
+// LoadConfig reads the configuration file on top of the default configuration. If the path is empty,
+// the default configuration is returned. The configuration isn't validated.
+func LoadConfig(path string) (*Config, error) {
+	cfg := DefaultConfig()
+	if path == "" {
+		return cfg, nil
+	}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read analyzer config file '%s'", path)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data)) // JSON is a subset of YAML
+	dec.KnownFields(true)
+	if decErr := dec.Decode(cfg); decErr != nil && !errors.Is(decErr, io.EOF) { // EOF means empty file
+		return nil, errors.Wrapf(decErr, "failed to parse analyzer config file '%s'", path)
+	}
+	return cfg, nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	if c.AlertBackoff <= 0 {
+		errs = append(errs, errors.Errorf("alert_backoff must be positive, got %d", c.AlertBackoff))
+	}
+	if c.AlertVacuumQuota <= 0 {
+		errs = append(errs, errors.Errorf("alert_vacuum_quota must be positive, got %d", c.AlertVacuumQuota))
+	}
+	for name, confirmations := range c.AlertConfirmations {
+		if _, ok := name.AlertType(); !ok {
+			errs = append(errs, errors.Errorf("alert_confirmations: unknown alert name '%s'", name))
+		}
+		if confirmations < 0 {
+			errs = append(errs, errors.Errorf("alert_confirmations: negative value for '%s'", name))
+		}
+	}
+	errs = append(errs,
+		validateStreakAndDepth("unreachable", c.Unreachable.Streak, c.Unreachable.Depth),
+		validateStreakAndDepth("incomplete", c.Incomplete.Streak, c.Incomplete.Depth),
+	)
+	if c.Height.MaxHeightDiff == 0 {
+		errs = append(errs, errors.New("height.max_height_diff must be positive"))
+	}
+	if c.StateHash.MaxForkDepth == 0 {
+		errs = append(errs, errors.New("state_hash.max_fork_depth must be positive"))
+	}
+	if c.StateHash.HeightBucketSize == 0 {
+		errs = append(errs, errors.New("state_hash.height_bucket_size must be positive"))
+	}
+	if c.Version.DriftGracePeriod <= 0 {
+		errs = append(errs, errors.New("version.drift_grace_period must be positive"))
+	}
+	if c.Version.MinVersion != "" {
+		if _, err := criteria.ParseVersion(c.Version.MinVersion); err != nil {
+			errs = append(errs, errors.Wrap(err, "version.min_version is invalid"))
+		}
+	}
+	if c.ChainStuck.Duration <= 0 {
+		errs = append(errs, errors.New("chain_stuck.duration must be positive"))
+	}
+	if c.MissingGenerator.Interval <= 0 {
+		errs = append(errs, errors.New("missing_generator.interval must be positive"))
+	}
+	for _, address := range c.MissingGenerator.Addresses {
+		if _, err := proto.NewAddressFromString(address); err != nil {
+			errs = append(errs, errors.Wrapf(err, "missing_generator.addresses: invalid address '%s'", address))
+		}
+	}
+	for _, name := range c.DisabledCriteria {
+		if name == "" {
+			errs = append(errs, errors.New("disabled_criteria: empty criterion name"))
+		}
+	}
+	for _, name := range c.GroupedCriteria {
+		if name == "" {
+			errs = append(errs, errors.New("grouped_criteria: empty criterion name"))
+		}
+	}
+	if c.BaseTarget.Threshold == 0 {
+		errs = append(errs, errors.New("base_target.threshold must be specified"))
+	}
+	return stderrs.Join(errs...)
+}
+
+func validateStreakAndDepth(section string, streak, depth int) error {
+	if streak <= 0 {
+		return errors.Errorf("%s.streak must be positive, got %d", section, streak)
+	}
+	if depth < streak {
+		return errors.Errorf("%s.depth must be greater or equal to streak, got %d < %d", section, depth, streak)
+	}
+	return nil
+}
+
+// Options converts the configuration to the analyzer options.
+func (c *Config) Options() *AnalyzerOptions {
+	confirmations := make([]storage.AlertConfirmationsValue, 0, len(c.AlertConfirmations))
+	for name, value := range c.AlertConfirmations {
+		alertType, ok := name.AlertType()
+		if !ok {
+			continue // checked by validation
+		}
+		confirmations = append(confirmations, storage.AlertConfirmationsValue{
+			AlertType:     alertType,
+			Confirmations: value,
+		})
+	}
+	var ( // copies to be independent of the config
+		unreachable = c.Unreachable
+		incomplete  = c.Incomplete
+		height      = c.Height
+		stateHash   = c.StateHash
+		baseTarget  = c.BaseTarget
+		version     = c.Version
+		chainStuck  = c.ChainStuck
+		generators  = c.MissingGenerator
+	)
+	generators.Addresses = slices.Clone(generators.Addresses)
+	return &AnalyzerOptions{
+		AlertBackoff:            c.AlertBackoff,
+		AlertVacuumQuota:        c.AlertVacuumQuota,
+		AlertConfirmations:      confirmations,
+		UnreachableCriteriaOpts: &unreachable,
+		IncompleteCriteriaOpts:  &incomplete,
+		HeightCriteriaOpts:      &height,
+		StateHashCriteriaOpts:   &stateHash,
+		BaseTargetCriterionOpts: &baseTarget,
+		ChallengeCriterionOpts:  &criteria.ChallengedBlockCriterionOptions{},
+		VersionCriterionOpts:    &version,
+		ChainStuckCriterionOpts: &chainStuck,
+		MissingGeneratorOpts:    &generators,
+		DisabledCriteria:        slices.Clone(c.DisabledCriteria),
+		GroupedCriteria:         slices.Clone(c.GroupedCriteria),
+	}
+}
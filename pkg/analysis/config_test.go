@@ -0,0 +1,108 @@
+package analysis_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis"
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	const yamlConfig = `
+alert_backoff: 3
+alert_confirmations:
+  UnreachableAlert: 1
+unreachable:
+  streak: 4
+  depth: 10
+height:
+  max_height_diff: 7
+base_target:
+  threshold: 100
+version:
+  drift_grace_period: 30m
+  min_version: 1.5.3
+`
+	const jsonConfig = `{
+  "alert_backoff": 3,
+  "alert_confirmations": {"UnreachableAlert": 1},
+  "unreachable": {"streak": 4, "depth": 10},
+  "height": {"max_height_diff": 7},
+  "base_target": {"threshold": 100},
+  "version": {"drift_grace_period": "30m", "min_version": "1.5.3"}
+}`
+	expected := analysis.DefaultConfig()
+	expected.AlertBackoff = 3
+	expected.AlertConfirmations[entities.UnreachableAlertName] = 1
+	expected.Unreachable = criteria.UnreachableCriterionOptions{Streak: 4, Depth: 10}
+	expected.Height.MaxHeightDiff = 7
+	expected.BaseTarget.Threshold = 100
+	expected.Version = criteria.VersionCriterionOptions{DriftGracePeriod: 30 * time.Minute, MinVersion: "1.5.3"}
+
+	for name, content := range map[string]string{"config.yaml": yamlConfig, "config.json": jsonConfig} {
+		t.Run(name, func(t *testing.T) {
+			cfg, err := analysis.LoadConfig(writeConfigFile(t, name, content))
+			require.NoError(t, err)
+			assert.Equal(t, expected, cfg)
+			assert.NoError(t, cfg.Validate())
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	_, err := analysis.LoadConfig(writeConfigFile(t, "config.yaml", "unknown_field: 1\n"))
+	assert.ErrorContains(t, err, "field unknown_field not found")
+
+	cfg, err := analysis.LoadConfig(writeConfigFile(t, "config.yaml", ""))
+	require.NoError(t, err)
+	assert.Equal(t, analysis.DefaultConfig(), cfg)
+	assert.EqualError(t, cfg.Validate(), "base_target.threshold must be specified")
+
+	cfg.BaseTarget.Threshold = 1
+	cfg.Incomplete.Depth = 1
+	cfg.AlertConfirmations["NoSuchAlert"] = 1
+	cfg.Version.MinVersion = "latest"
+	cfg.MissingGenerator.Addresses = []string{"not-an-address"}
+	err = cfg.Validate()
+	assert.ErrorContains(t, err, "alert_confirmations: unknown alert name 'NoSuchAlert'")
+	assert.ErrorContains(t, err, "incomplete.depth must be greater or equal to streak")
+	assert.ErrorContains(t, err, "version.min_version is invalid")
+	assert.ErrorContains(t, err, "missing_generator.addresses: invalid address 'not-an-address'")
+}
+
+func TestConfigOptions(t *testing.T) {
+	cfg := analysis.DefaultConfig()
+	cfg.BaseTarget.Threshold = 42
+	opts := cfg.Options()
+	assert.Equal(t, storage.DefaultAlertBackoff, opts.AlertBackoff)
+	assert.Equal(t, []storage.AlertConfirmationsValue{
+		{AlertType: entities.HeightAlertType, Confirmations: 2},
+	}, opts.AlertConfirmations)
+	assert.Equal(t, criteria.DefaultUnreachableCriterionOptions(), opts.UnreachableCriteriaOpts)
+	assert.Equal(t, criteria.DefaultIncompleteCriterionOptions(), opts.IncompleteCriteriaOpts)
+	assert.Equal(t, criteria.DefaultHeightCriterionOptions(), opts.HeightCriteriaOpts)
+	assert.Equal(t, criteria.DefaultStateHashCriterionOptions(), opts.StateHashCriteriaOpts)
+	assert.Equal(t, &criteria.BaseTargetCriterionOptions{Threshold: 42}, opts.BaseTargetCriterionOpts)
+	assert.NotNil(t, opts.ChallengeCriterionOpts)
+	assert.Equal(t, criteria.DefaultVersionCriterionOptions(), opts.VersionCriterionOpts)
+	assert.Equal(t, criteria.DefaultChainStuckCriterionOptions(), opts.ChainStuckCriterionOpts)
+	assert.Equal(t, criteria.DefaultMissingGeneratorCriterionOptions(), opts.MissingGeneratorOpts)
+
+	cfg.BaseTarget.Threshold = 1 // options don't depend on the config after conversion
+	assert.Equal(t, uint64(42), opts.BaseTargetCriterionOpts.Threshold)
+}
@@ -0,0 +1,456 @@
+// Package correlation groups the related alerts into composite alerts and suppresses the alerts storms.
+// It's the stage between the analyzer and the alerts publisher.
+package correlation
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultGroupWait = 2 * time.Second
+	DefaultRateLimit = 30
+
+	rateLimitPeriod = time.Minute
+
+	// forgetAfter is how long the alert is remembered since it has been sent last time. The alerts which aren't
+	// saved in the alerts storage are never fixed, so they are forgotten after it as the bots forget their messages.
+	forgetAfter = 7 * 24 * time.Hour
+)
+
+type Options struct {
+	// GroupWait is how long the alerts of the same polling round are collected before they are grouped.
+	// Zero value disables grouping.
+	GroupWait time.Duration
+	// RateLimit is the max number of alerts sent per minute. The excess alerts are sent as one storm group
+	// after the minute. Zero value disables rate limiting. Alerts about fixes aren't limited.
+	RateLimit int
+}
+
+type openGroup struct {
+	alert *entities.AlertGroup
+	open  map[crypto.Digest]struct{} // the member alerts which aren't fixed yet
+}
+
+// Correlator groups the alerts produced for the same timestamp which share nodes. The fixes of the grouped alerts
+// are held until all member alerts of the group are fixed, then the single fix of the group is sent.
+type Correlator struct {
+	opts Options
+	zap  *zap.Logger
+	now  func() time.Time
+
+	mu        *sync.Mutex // guards the fields below, they are read by the mutes wrapper
+	groups    map[crypto.Digest]*openGroup
+	memberOf  map[crypto.Digest]map[crypto.Digest]struct{} // member alert ID -> IDs of the open groups
+	sentAlone map[crypto.Digest]struct{}                   // the alerts which have been sent not grouped too
+	lastSent  map[crypto.Digest]time.Time                  // alert ID -> the last time it has been sent alone or grouped
+	forgotten time.Time                                    // the last time the stale alerts have been forgotten
+
+	// the state of the Run loop
+	pending    []entities.Alert
+	sent       []time.Time // the send times of the alerts during the last rate limit period
+	suppressed []entities.Alert
+}
+
+func NewCorrelator(opts Options, logger *zap.Logger) *Correlator {
+	return &Correlator{
+		opts:      opts,
+		zap:       logger,
+		now:       time.Now,
+		mu:        new(sync.Mutex),
+		groups:    make(map[crypto.Digest]*openGroup),
+		memberOf:  make(map[crypto.Digest]map[crypto.Digest]struct{}),
+		sentAlone: make(map[crypto.Digest]struct{}),
+		lastSent:  make(map[crypto.Digest]time.Time),
+	}
+}
+
+// Run correlates the input alerts. The output channel is closed after the input one, the collected alerts
+// are sent before it.
+func (c *Correlator) Run(input <-chan entities.Alert) <-chan entities.Alert {
+	output := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		c.loop(input, output)
+	}()
+	return output
+}
+
+func (c *Correlator) loop(input <-chan entities.Alert, output chan<- entities.Alert) {
+	var groupTimer, stormTimer <-chan time.Time
+	for {
+		select {
+		case alert, ok := <-input:
+			if !ok {
+				c.flushPending(output)
+				c.flushStorm(output)
+				return
+			}
+			if fixed, isFixed := alert.(*entities.AlertFixed); isFixed {
+				// the fixes are sent after all alerts of the polling round, so the round is complete
+				c.flushPending(output)
+				groupTimer = nil
+				c.handleFixed(fixed, output)
+				break
+			}
+			if c.opts.GroupWait <= 0 || len(entities.AlertNodes(alert)) == 0 {
+				c.send(alert, output)
+				break
+			}
+			if len(c.pending) > 0 && c.pending[0].Time() != alert.Time() {
+				c.flushPending(output)
+				groupTimer = nil
+			}
+			c.pending = append(c.pending, alert)
+			if groupTimer == nil {
+				groupTimer = time.After(c.opts.GroupWait)
+			}
+		case <-groupTimer:
+			groupTimer = nil
+			c.flushPending(output)
+		case <-stormTimer:
+			stormTimer = nil
+			c.flushStorm(output)
+		}
+		if stormTimer == nil && len(c.suppressed) > 0 {
+			stormTimer = time.After(rateLimitPeriod)
+		}
+	}
+}
+
+// flushPending groups the collected alerts of the polling round and sends them.
+func (c *Correlator) flushPending(output chan<- entities.Alert) {
+	if len(c.pending) == 0 {
+		return
+	}
+	for _, component := range relatedAlerts(c.pending) {
+		if len(component) == 1 {
+			c.send(component[0], output)
+			continue
+		}
+		group := newGroup(component[0].Time().Unix(), component)
+		group.RootCause = guessRootCause(component)
+		if c.send(group, output) {
+			c.register(group, component)
+		}
+	}
+	c.pending = nil
+}
+
+// flushStorm sends the alerts suppressed by the rate limit as one group.
+func (c *Correlator) flushStorm(output chan<- entities.Alert) {
+	if len(c.suppressed) == 0 {
+		return
+	}
+	var members []entities.Alert
+	for _, alert := range c.suppressed {
+		if group, ok := alert.(*entities.AlertGroup); ok {
+			for _, m := range group.Alerts {
+				members = append(members, &memberAlert{AlertGroupMember: m, ts: group.Timestamp})
+			}
+			continue
+		}
+		members = append(members, alert)
+	}
+	storm := newGroup(c.now().Unix(), members)
+	storm.Storm = true
+	storm.RootCause = fmt.Sprintf("alerts storm, %d alerts have exceeded the limit of %d alerts per minute",
+		len(members), c.opts.RateLimit,
+	)
+	c.register(storm, members)
+	c.suppressed = nil
+	c.zap.Warn("Alerts storm has been suppressed", zap.Int("alerts", len(members)))
+	c.sent = append(c.sent, c.now())
+	output <- storm
+}
+
+// send sends the alert if the rate limit allows it, otherwise the alert is suppressed and false is returned.
+func (c *Correlator) send(alert entities.Alert, output chan<- entities.Alert) bool {
+	if !c.allow() {
+		c.suppressed = append(c.suppressed, alert)
+		return false
+	}
+	c.forgetStale()
+	if _, isGroup := alert.(*entities.AlertGroup); !isGroup && len(entities.AlertNodes(alert)) > 0 {
+		c.mu.Lock()
+		c.sentAlone[alert.ID()] = struct{}{}
+		c.lastSent[alert.ID()] = c.now()
+		c.mu.Unlock()
+	}
+	output <- alert
+	return true
+}
+
+// forgetStale forgets the alerts which haven't been sent for forgetAfter, it runs at most once per hour.
+// The open groups are forgotten with their last alert.
+func (c *Correlator) forgetStale() {
+	now := c.now()
+	if now.Sub(c.forgotten) < time.Hour {
+		return
+	}
+	c.forgotten = now
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, sent := range c.lastSent {
+		if now.Sub(sent) < forgetAfter {
+			continue
+		}
+		for groupID := range c.memberOf[id] {
+			g := c.groups[groupID]
+			delete(g.open, id)
+			if len(g.open) == 0 {
+				delete(c.groups, groupID)
+			}
+		}
+		delete(c.memberOf, id)
+		delete(c.sentAlone, id)
+		delete(c.lastSent, id)
+	}
+}
+
+func (c *Correlator) allow() bool {
+	if c.opts.RateLimit <= 0 {
+		return true
+	}
+	now := c.now()
+	c.sent = slices.DeleteFunc(c.sent, func(t time.Time) bool { return now.Sub(t) >= rateLimitPeriod })
+	if len(c.sent) >= c.opts.RateLimit {
+		return false
+	}
+	c.sent = append(c.sent, now)
+	return true
+}
+
+// handleFixed sends the fix if the alert has been sent alone and the fixes of the groups which are fixed completely.
+func (c *Correlator) handleFixed(fixed *entities.AlertFixed, output chan<- entities.Alert) {
+	if fixed.Fixed == nil {
+		output <- fixed
+		return
+	}
+	id := fixed.Fixed.ID()
+	if c.unsuppress(id) {
+		return // the alert hasn't been sent yet
+	}
+	c.mu.Lock()
+	groupIDs, grouped := c.memberOf[id]
+	_, alone := c.sentAlone[id]
+	delete(c.memberOf, id)
+	delete(c.sentAlone, id)
+	delete(c.lastSent, id)
+	var fixedGroups []*entities.AlertGroup
+	for groupID := range groupIDs {
+		g := c.groups[groupID]
+		delete(g.open, id)
+		if len(g.open) == 0 {
+			fixedGroups = append(fixedGroups, g.alert)
+			delete(c.groups, groupID)
+		}
+	}
+	c.mu.Unlock()
+
+	if !grouped || alone {
+		output <- fixed
+	}
+	for _, g := range fixedGroups {
+		output <- &entities.AlertFixed{Timestamp: fixed.Timestamp, Fixed: g}
+	}
+}
+
+func (c *Correlator) register(group *entities.AlertGroup, members []entities.Alert) {
+	groupID := group.ID()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g := &openGroup{alert: group, open: make(map[crypto.Digest]struct{}, len(members))}
+	now := c.now()
+	for _, m := range members {
+		id := m.ID()
+		g.open[id] = struct{}{}
+		c.lastSent[id] = now
+		if c.memberOf[id] == nil {
+			c.memberOf[id] = make(map[crypto.Digest]struct{})
+		}
+		c.memberOf[id][groupID] = struct{}{}
+	}
+	c.groups[groupID] = g // the repeated group replaces the previous one
+}
+
+// unsuppress removes the fixed alert from the suppressed alerts and the suppressed groups.
+func (c *Correlator) unsuppress(id crypto.Digest) bool {
+	found := false
+	c.suppressed = slices.DeleteFunc(c.suppressed, func(alert entities.Alert) bool {
+		group, isGroup := alert.(*entities.AlertGroup)
+		if !isGroup {
+			if alert.ID() == id {
+				found = true
+				return true
+			}
+			return false
+		}
+		n := len(group.Alerts)
+		group.Alerts = slices.DeleteFunc(group.Alerts, func(m entities.AlertGroupMember) bool { return m.ID == id })
+		found = found || len(group.Alerts) != n
+		return len(group.Alerts) == 0
+	})
+	return found
+}
+
+// groupMembers returns the IDs of the alerts of the open group.
+func (c *Correlator) groupMembers(groupID crypto.Digest) ([]crypto.Digest, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	g, ok := c.groups[groupID]
+	if !ok {
+		return nil, false
+	}
+	ids := make([]crypto.Digest, 0, len(g.alert.Alerts))
+	for _, m := range g.alert.Alerts {
+		ids = append(ids, m.ID)
+	}
+	return ids, true
+}
+
+// memberAlert is the member of the group which is regrouped into the storm group.
+type memberAlert struct {
+	entities.AlertGroupMember
+	ts int64
+}
+
+func (a *memberAlert) Name() entities.AlertName { return a.AlertGroupMember.Name }
+
+func (a *memberAlert) ID() crypto.Digest { return a.AlertGroupMember.ID }
+
+func (a *memberAlert) Message() string { return a.AlertGroupMember.Message }
+
+func (a *memberAlert) Time() time.Time { return time.Unix(a.ts, 0) }
+
+func (a *memberAlert) Type() entities.AlertType {
+	t, _ := a.AlertGroupMember.Name.AlertType()
+	return t
+}
+
+func (a *memberAlert) Level() string { return a.AlertGroupMember.Level }
+
+func (a *memberAlert) String() string { return fmt.Sprintf("%s: %s", a.Name(), a.Message()) }
+
+func newGroup(ts int64, members []entities.Alert) *entities.AlertGroup {
+	group := &entities.AlertGroup{Timestamp: ts, Alerts: make([]entities.AlertGroupMember, 0, len(members))}
+	nodes := make(map[string]struct{})
+	for _, m := range members {
+		member := entities.NewAlertGroupMember(m)
+		member.Nodes = alertNodes(m)
+		group.Alerts = append(group.Alerts, member)
+		for _, node := range member.Nodes {
+			nodes[node] = struct{}{}
+		}
+	}
+	for node := range nodes {
+		group.Nodes = append(group.Nodes, node)
+	}
+	slices.Sort(group.Nodes)
+	return group
+}
+
+func alertNodes(alert entities.Alert) []string {
+	if m, ok := alert.(*memberAlert); ok {
+		return m.Nodes
+	}
+	return entities.AlertNodes(alert)
+}
+
+// relatedAlerts splits the alerts into the groups of the alerts which share nodes directly or through other alerts.
+// The groups and the alerts in them keep the input order.
+func relatedAlerts(alerts []entities.Alert) [][]entities.Alert {
+	parent := make([]int, len(alerts))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	nodeOwner := make(map[string]int)
+	for i, alert := range alerts {
+		for _, node := range entities.AlertNodes(alert) {
+			owner, ok := nodeOwner[node]
+			if !ok {
+				nodeOwner[node] = i
+				continue
+			}
+			if a, b := find(owner), find(i); a != b {
+				parent[max(a, b)] = min(a, b) // the root is the first alert of the group
+			}
+		}
+	}
+	var (
+		out   [][]entities.Alert
+		index = make(map[int]int) // root -> index in out
+	)
+	for i, alert := range alerts {
+		root := find(i)
+		j, ok := index[root]
+		if !ok {
+			j = len(out)
+			index[root] = j
+			out = append(out, nil)
+		}
+		out[j] = append(out[j], alert)
+	}
+	return out
+}
+
+// guessRootCause returns the most likely reason of the related alerts. Unreachable nodes break the comparison of
+// the heights and the state hashes, so they are the first suspects, then forks and then lagging nodes.
+func guessRootCause(alerts []entities.Alert) string {
+	var (
+		unreachable []string
+		incomplete  []string
+		hasFork     bool
+		hasHeight   bool
+	)
+	for _, alert := range alerts {
+		switch a := alert.(type) {
+		case *entities.UnreachableAlert:
+			unreachable = append(unreachable, a.Node)
+		case *entities.IncompleteAlert:
+			incomplete = append(incomplete, a.Node)
+		case *entities.InvalidHeightAlert:
+			incomplete = append(incomplete, a.Node)
+		case *entities.StateHashAlert:
+			hasFork = true
+		case *entities.HeightAlert:
+			hasHeight = true
+		}
+	}
+	switch {
+	case len(unreachable) > 0:
+		return fmt.Sprintf("%s unreachable, the other alerts are likely caused by it", nodesPhrase(unreachable))
+	case hasFork:
+		return "the nodes are on different forks"
+	case hasHeight:
+		return "the nodes are at different heights"
+	case len(incomplete) > 0:
+		return fmt.Sprintf("%s incomplete or invalid statements", nodesPhrase(incomplete)) +
+			", the other alerts are likely caused by it"
+	default:
+		return "the alerts are related to the same nodes"
+	}
+}
+
+func nodesPhrase(nodes []string) string {
+	nodes = slices.Compact(slices.Sorted(slices.Values(nodes)))
+	if len(nodes) == 1 {
+		return "node " + nodes[0] + " is"
+	}
+	return "nodes " + strings.Join(nodes, ", ") + " are"
+}
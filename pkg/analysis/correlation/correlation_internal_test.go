@@ -0,0 +1,144 @@
+package correlation
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	nodeA = "http://a.example.com"
+	nodeB = "http://b.example.com"
+	nodeC = "http://c.example.com"
+)
+
+// correlate sends the alerts to the correlator and returns everything it has sent after the input is closed.
+func correlate(c *Correlator, alerts ...entities.Alert) []entities.Alert {
+	input := make(chan entities.Alert)
+	output := c.Run(input)
+	go func() {
+		defer close(input)
+		for _, alert := range alerts {
+			input <- alert
+		}
+	}()
+	var out []entities.Alert
+	for alert := range output {
+		out = append(out, alert)
+	}
+	return out
+}
+
+func TestCorrelator_Grouping(t *testing.T) {
+	const ts = 100
+	unreachable := &entities.UnreachableAlert{Timestamp: ts, Node: nodeA}
+	height := &entities.HeightAlert{
+		Timestamp:        ts,
+		MaxHeightGroup:   entities.HeightGroup{Height: 10, Nodes: entities.Nodes{nodeB}},
+		OtherHeightGroup: entities.HeightGroup{Height: 5, Nodes: entities.Nodes{nodeA}},
+	}
+	other := &entities.UnreachableAlert{Timestamp: ts, Node: nodeC}
+	internal := entities.NewInternalErrorAlert(ts, assert.AnError)
+
+	c := NewCorrelator(Options{GroupWait: time.Hour}, zap.NewNop())
+	out := correlate(c,
+		unreachable, internal, height, other,
+		&entities.AlertFixed{Timestamp: ts + 1, Fixed: unreachable},
+		&entities.AlertFixed{Timestamp: ts + 1, Fixed: other},
+		&entities.AlertFixed{Timestamp: ts + 1, Fixed: height},
+	)
+	require.Len(t, out, 5)
+	assert.Equal(t, internal, out[0], "the alert without nodes must be sent immediately")
+	group, ok := out[1].(*entities.AlertGroup)
+	require.True(t, ok, "the alerts sharing node A must be grouped")
+	assert.Equal(t, []string{nodeA, nodeB}, group.Nodes)
+	assert.Equal(t, []entities.AlertGroupMember{
+		entities.NewAlertGroupMember(unreachable),
+		entities.NewAlertGroupMember(height),
+	}, group.Alerts)
+	assert.Contains(t, group.RootCause, "node "+nodeA+" is unreachable")
+	assert.Equal(t, other, out[2], "the unrelated alert must be sent alone")
+	assert.Equal(t, &entities.AlertFixed{Timestamp: ts + 1, Fixed: other}, out[3])
+	assert.Equal(t, &entities.AlertFixed{Timestamp: ts + 1, Fixed: group}, out[4],
+		"the group must be fixed after all its alerts are fixed",
+	)
+}
+
+func TestCorrelator_Storm(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCorrelator(Options{RateLimit: 2}, zap.NewNop())
+	c.now = func() time.Time { return now }
+
+	alerts := []entities.Alert{
+		&entities.UnreachableAlert{Timestamp: 1, Node: nodeA},
+		&entities.UnreachableAlert{Timestamp: 1, Node: nodeB},
+		&entities.UnreachableAlert{Timestamp: 1, Node: nodeC},
+		&entities.UnreachableAlert{Timestamp: 2, Node: "http://d.example.com"},
+		&entities.UnreachableAlert{Timestamp: 2, Node: "http://e.example.com"},
+	}
+	out := correlate(c, append(alerts, &entities.AlertFixed{Timestamp: 3, Fixed: alerts[4]})...)
+	require.Len(t, out, 3)
+	assert.Equal(t, alerts[:2], out[:2])
+	storm, ok := out[2].(*entities.AlertGroup)
+	require.True(t, ok, "the alerts over the limit must be sent as one group")
+	assert.True(t, storm.Storm)
+	assert.Equal(t, []entities.AlertGroupMember{
+		entities.NewAlertGroupMember(alerts[2]),
+		entities.NewAlertGroupMember(alerts[3]),
+	}, storm.Alerts, "the fixed alert must be dropped from the storm")
+}
+
+func TestCorrelator_Mutes(t *testing.T) {
+	const ts = 100
+	first := &entities.UnreachableAlert{Timestamp: ts, Node: nodeA}
+	second := &entities.InvalidHeightAlert{NodeStatement: entities.NodeStatement{Node: nodeA, Timestamp: ts}}
+	c := NewCorrelator(Options{GroupWait: time.Hour}, zap.NewNop())
+	out := correlate(c, first, second)
+	require.Len(t, out, 1)
+	group, ok := out[0].(*entities.AlertGroup)
+	require.True(t, ok)
+
+	as := storage.NewAlertsStorage(zap.NewNop())
+	mutes := c.Mutes(as)
+	mute, err := mutes.PutMute(entities.NewAlertMuteByID(entities.AckAlertMuteKind, group.ID(), 0))
+	require.NoError(t, err)
+	assert.Len(t, as.Mutes(), 3, "the group alerts must be muted with the group")
+
+	assert.True(t, mutes.DeleteMute(mute.ID))
+	assert.Empty(t, as.Mutes(), "the mutes of the group alerts must be deleted with the group mute")
+}
+
+func TestCorrelator_ForgetStale(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewCorrelator(Options{}, zap.NewNop())
+	c.now = func() time.Time { return now }
+	output := make(chan entities.Alert, 10)
+
+	alone := &entities.UnreachableAlert{Timestamp: 1, Node: nodeA}
+	members := []entities.Alert{
+		&entities.UnreachableAlert{Timestamp: 1, Node: nodeB},
+		&entities.InvalidHeightAlert{NodeStatement: entities.NodeStatement{Node: nodeB, Timestamp: 1}},
+	}
+	require.True(t, c.send(alone, output))
+	c.register(newGroup(1, members), members)
+
+	now = now.Add(forgetAfter + time.Hour)
+	fresh := &entities.UnreachableAlert{Timestamp: 2, Node: nodeC}
+	require.True(t, c.send(fresh, output))
+	assert.Empty(t, c.groups, "the group of the never fixed alerts must be forgotten")
+	assert.Empty(t, c.memberOf)
+	assert.Equal(t, map[crypto.Digest]struct{}{fresh.ID(): {}}, c.sentAlone)
+	assert.Len(t, c.lastSent, 1)
+
+	out := correlate(c, &entities.AlertFixed{Timestamp: 3, Fixed: alone})
+	assert.Equal(t, []entities.Alert{&entities.AlertFixed{Timestamp: 3, Fixed: alone}}, out,
+		"the fix of the forgotten alert must be sent as is",
+	)
+}
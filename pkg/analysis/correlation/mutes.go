@@ -0,0 +1,67 @@
+package correlation
+
+import (
+	"sync"
+
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+// groupMutes expands the mutes of the alert groups to the mutes of their member alerts, because the analyzer
+// which honours the mutes knows nothing about the groups.
+type groupMutes struct {
+	storage.AlertMutes
+	c       *Correlator
+	mu      *sync.Mutex
+	members map[crypto.Digest][]crypto.Digest // group mute ID -> member mute IDs
+}
+
+// Mutes wraps the mutes of the analyzer, so the alert groups can be acked and silenced by their IDs.
+func (c *Correlator) Mutes(inner storage.AlertMutes) storage.AlertMutes {
+	return &groupMutes{
+		AlertMutes: inner,
+		c:          c,
+		mu:         new(sync.Mutex),
+		members:    make(map[crypto.Digest][]crypto.Digest),
+	}
+}
+
+func (m *groupMutes) PutMute(mute entities.AlertMute) (entities.AlertMute, error) {
+	if mute.AlertID == nil {
+		return m.AlertMutes.PutMute(mute)
+	}
+	memberIDs, isGroup := m.c.groupMembers(*mute.AlertID)
+	if !isGroup {
+		return m.AlertMutes.PutMute(mute)
+	}
+	put, err := m.AlertMutes.PutMute(mute)
+	if err != nil {
+		return entities.AlertMute{}, err
+	}
+	muteIDs := make([]crypto.Digest, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		memberMute, putErr := m.AlertMutes.PutMute(entities.NewAlertMuteByID(mute.Kind, id, mute.ExpiresAt))
+		if putErr != nil {
+			return entities.AlertMute{}, errors.Wrapf(putErr, "failed to mute alert %s of group", id.String())
+		}
+		muteIDs = append(muteIDs, memberMute.ID)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.members[put.ID] = muteIDs
+	return put, nil
+}
+
+func (m *groupMutes) DeleteMute(id crypto.Digest) bool {
+	m.mu.Lock()
+	memberMutes := m.members[id]
+	delete(m.members, id)
+	m.mu.Unlock()
+	for _, memberID := range memberMutes {
+		m.AlertMutes.DeleteMute(memberID)
+	}
+	return m.AlertMutes.DeleteMute(id)
+}
@@ -7,7 +7,7 @@ import (
 )
 
 type BaseTargetCriterionOptions struct {
-	Threshold uint64
+	Threshold uint64 `yaml:"threshold"`
 }
 
 type BaseTargetCriterion struct {
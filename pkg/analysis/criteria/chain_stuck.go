@@ -0,0 +1,100 @@
+package criteria
+
+import (
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultChainStuckDuration = 10 * time.Minute
+)
+
+type ChainStuckCriterionOptions struct {
+	// Duration is the time during which the max height of the nodes may stay the same.
+	Duration time.Duration `yaml:"duration"`
+}
+
+func DefaultChainStuckCriterionOptions() *ChainStuckCriterionOptions {
+	return &ChainStuckCriterionOptions{
+		Duration: defaultChainStuckDuration,
+	}
+}
+
+// ChainStuckCriterion detects that the whole network stopped producing blocks: the nodes may agree with each other,
+// but the max height among them doesn't grow.
+type ChainStuckCriterion struct {
+	opts *ChainStuckCriterionOptions
+	es   *events.Storage
+	zap  *zap.Logger
+}
+
+func NewChainStuckCriterion(
+	es *events.Storage,
+	opts *ChainStuckCriterionOptions,
+	logger *zap.Logger,
+) *ChainStuckCriterion {
+	if opts == nil { // default
+		opts = DefaultChainStuckCriterionOptions()
+	}
+	return &ChainStuckCriterion{opts: opts, es: es, zap: logger}
+}
+
+func (c *ChainStuckCriterion) Analyze(
+	alerts chan<- entities.Alert,
+	timestamp int64,
+	statements entities.NodeStatements,
+) error {
+	var maxHeight uint64
+	for _, statement := range statements {
+		maxHeight = max(maxHeight, statement.Height)
+	}
+	if maxHeight == 0 { // no OK nodes
+		return nil
+	}
+	since := timestamp
+	for _, statement := range statements {
+		reached, err := c.heightReachedAt(statement.Node, maxHeight, timestamp)
+		if err != nil {
+			return err
+		}
+		since = min(since, reached)
+	}
+	stuck := time.Duration(timestamp-since) * time.Second
+	if stuck < c.opts.Duration {
+		return nil
+	}
+	c.zap.Info("ChainStuckCriterion: max height hasn't changed",
+		zap.Uint64("height", maxHeight), zap.Duration("duration", stuck),
+	)
+	alerts <- &entities.ChainStuckAlert{
+		Timestamp: timestamp,
+		Height:    maxHeight,
+		Since:     since,
+	}
+	return nil
+}
+
+// heightReachedAt returns the timestamp of the earliest statement of the node in the sequence of the latest
+// OK statements which have at least the given height. Non-OK statements don't interrupt the sequence.
+func (c *ChainStuckCriterion) heightReachedAt(node string, height uint64, timestamp int64) (int64, error) {
+	reached := timestamp
+	err := c.es.ViewStatementsByNodeWithDescendKeys(node, func(statement *entities.NodeStatement) bool {
+		if statement.Timestamp > timestamp || statement.Status != entities.OK {
+			return true
+		}
+		if statement.Height < height {
+			return false
+		}
+		reached = statement.Timestamp
+		return true
+	})
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to analyze %q by chain stuck criterion", node)
+	}
+	return reached, nil
+}
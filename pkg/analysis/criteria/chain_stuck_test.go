@@ -0,0 +1,76 @@
+package criteria_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestChainStuckCriterion_Analyze(t *testing.T) {
+	okEvent := func(node string, ts int64, height uint64) entities.Event {
+		return entities.NewStateHashEvent(node, ts, "v1", height, nil, 0, nil, nil, false)
+	}
+	tests := []struct {
+		name      string
+		history   []entities.Event
+		timestamp int64
+		expected  []entities.Alert
+	}{
+		{
+			name: "height advances",
+			history: []entities.Event{
+				okEvent("a", 1000, 10), okEvent("a", 1300, 11), okEvent("a", 1600, 12),
+				okEvent("b", 1000, 10), okEvent("b", 1300, 11), okEvent("b", 1600, 11),
+			},
+			timestamp: 1600,
+		},
+		{
+			name: "stuck with unreachable gap",
+			history: []entities.Event{
+				okEvent("a", 1000, 10), okEvent("a", 1300, 12), okEvent("a", 1600, 12), okEvent("a", 2000, 12),
+				okEvent("b", 1000, 11), okEvent("b", 1300, 11), entities.NewUnreachableEvent("b", 1600),
+				okEvent("b", 2000, 12),
+			},
+			timestamp: 2000,
+			expected:  []entities.Alert{&entities.ChainStuckAlert{Timestamp: 2000, Height: 12, Since: 1300}},
+		},
+		{
+			name: "not long enough",
+			history: []entities.Event{
+				okEvent("a", 1000, 10), okEvent("a", 1300, 12), okEvent("a", 1600, 12),
+			},
+			timestamp: 1600,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			es, err := events.NewStorage(time.Hour, zap.NewNop())
+			require.NoError(t, err)
+			defer func() { require.NoError(t, es.Close()) }()
+			var statements entities.NodeStatements
+			for _, event := range test.history {
+				require.NoError(t, es.PutEvent(event))
+				if event.Timestamp() == test.timestamp && event.Statement().Status == entities.OK {
+					statements = append(statements, event.Statement())
+				}
+			}
+			alerts := make(chan entities.Alert, 1)
+			opts := &criteria.ChainStuckCriterionOptions{Duration: 10 * time.Minute}
+			criterion := criteria.NewChainStuckCriterion(es, opts, zap.NewNop())
+			require.NoError(t, criterion.Analyze(alerts, test.timestamp, statements))
+			close(alerts)
+			var actual []entities.Alert
+			for alert := range alerts {
+				actual = append(actual, alert)
+			}
+			assert.Equal(t, test.expected, actual)
+		})
+	}
+}
@@ -13,7 +13,7 @@ const (
 )
 
 type HeightCriterionOptions struct {
-	MaxHeightDiff uint64
+	MaxHeightDiff uint64 `yaml:"max_height_diff"`
 }
 
 type HeightCriterion struct {
@@ -21,11 +21,15 @@ type HeightCriterion struct {
 	logger *zap.Logger
 }
 
+func DefaultHeightCriterionOptions() *HeightCriterionOptions {
+	return &HeightCriterionOptions{
+		MaxHeightDiff: defaultMaxHeightDiff,
+	}
+}
+
 func NewHeightCriterion(opts *HeightCriterionOptions, logger *zap.Logger) *HeightCriterion {
 	if opts == nil { // default
-		opts = &HeightCriterionOptions{
-			MaxHeightDiff: defaultMaxHeightDiff,
-		}
+		opts = DefaultHeightCriterionOptions()
 	}
 	return &HeightCriterion{opts: opts, logger: logger}
 }
@@ -9,9 +9,9 @@ import (
 )
 
 type IncompleteCriterionOptions struct {
-	Streak                              int
-	Depth                               int
-	ConsiderPrevUnreachableAsIncomplete bool
+	Streak                              int  `yaml:"streak"`
+	Depth                               int  `yaml:"depth"`
+	ConsiderPrevUnreachableAsIncomplete bool `yaml:"consider_prev_unreachable_as_incomplete"`
 }
 
 type IncompleteCriterion struct {
@@ -26,17 +26,21 @@ const (
 	incompleteConsiderPrevUnreachableAsIncompleteDefault = true
 )
 
+func DefaultIncompleteCriterionOptions() *IncompleteCriterionOptions {
+	return &IncompleteCriterionOptions{
+		Streak:                              incompleteStreakDefault,
+		Depth:                               incompleteDepthDefault,
+		ConsiderPrevUnreachableAsIncomplete: incompleteConsiderPrevUnreachableAsIncompleteDefault,
+	}
+}
+
 func NewIncompleteCriterion(
 	es *events.Storage,
 	opts *IncompleteCriterionOptions,
 	logger *zap.Logger,
 ) *IncompleteCriterion {
 	if opts == nil { // by default
-		opts = &IncompleteCriterionOptions{
-			Streak:                              incompleteStreakDefault,
-			Depth:                               incompleteDepthDefault,
-			ConsiderPrevUnreachableAsIncomplete: incompleteConsiderPrevUnreachableAsIncompleteDefault,
-		}
+		opts = DefaultIncompleteCriterionOptions()
 	}
 	return &IncompleteCriterion{opts: opts, es: es, zap: logger}
 }
@@ -0,0 +1,80 @@
+package criteria
+
+import (
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMissingGeneratorInterval = time.Hour
+)
+
+type MissingGeneratorCriterionOptions struct {
+	// Addresses are the generators which are expected to produce blocks. Empty list disables the criterion.
+	Addresses []string `yaml:"addresses"`
+	// Interval is the time during which each generator is expected to produce at least one block.
+	Interval time.Duration `yaml:"interval"`
+}
+
+func DefaultMissingGeneratorCriterionOptions() *MissingGeneratorCriterionOptions {
+	return &MissingGeneratorCriterionOptions{
+		Interval: defaultMissingGeneratorInterval,
+	}
+}
+
+type MissingGeneratorCriterion struct {
+	opts *MissingGeneratorCriterionOptions
+	es   *events.Storage
+	zap  *zap.Logger
+}
+
+func NewMissingGeneratorCriterion(
+	es *events.Storage,
+	opts *MissingGeneratorCriterionOptions,
+	logger *zap.Logger,
+) *MissingGeneratorCriterion {
+	if opts == nil { // default
+		opts = DefaultMissingGeneratorCriterionOptions()
+	}
+	return &MissingGeneratorCriterion{opts: opts, es: es, zap: logger}
+}
+
+func (c *MissingGeneratorCriterion) Analyze(alerts chan<- entities.Alert, timestamp int64) error {
+	if len(c.opts.Addresses) == 0 {
+		return nil
+	}
+	// the last blocks and the history start are looked up directly, so the cost doesn't grow with the history
+	since, found, err := c.es.EarliestTimestamp()
+	if err != nil {
+		return errors.Wrap(err, "failed to analyze generators by missing generator criterion")
+	}
+	interval := int64(c.opts.Interval / time.Second)
+	if !found || timestamp-since < interval { // the history is too short to make a decision
+		return nil
+	}
+	for _, address := range c.opts.Addresses {
+		generator, produced, lastErr := c.es.LastGeneratorBlock(address)
+		if lastErr != nil {
+			return errors.Wrap(lastErr, "failed to analyze generators by missing generator criterion")
+		}
+		if produced && timestamp-generator.LastBlockTime < interval {
+			continue
+		}
+		c.zap.Info("MissingGeneratorCriterion: generator hasn't produced blocks",
+			zap.String("generator", address), zap.Duration("interval", c.opts.Interval),
+		)
+		alerts <- &entities.MissingGeneratorAlert{
+			Timestamp:       timestamp,
+			Generator:       address,
+			Interval:        c.opts.Interval,
+			LastBlockHeight: generator.LastBlockHeight,
+			LastBlockTime:   generator.LastBlockTime,
+		}
+	}
+	return nil
+}
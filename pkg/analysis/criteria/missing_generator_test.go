@@ -0,0 +1,69 @@
+package criteria_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+func TestMissingGeneratorCriterion_Analyze(t *testing.T) {
+	newAddress := func(pk crypto.PublicKey) proto.WavesAddress {
+		addr, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+		require.NoError(t, err)
+		return addr
+	}
+	var (
+		active = newAddress(crypto.PublicKey{0x01})
+		stale  = newAddress(crypto.PublicKey{0x02})
+		absent = newAddress(crypto.PublicKey{0x03})
+	)
+	blockEvent := func(ts int64, height uint64, generator proto.WavesAddress) entities.Event {
+		blockID := proto.NewBlockIDFromDigest(crypto.Digest{byte(height)})
+		return entities.NewStateHashEvent("a", ts, "", height, nil, 1, &blockID, &generator, false)
+	}
+	es, err := events.NewStorage(time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+	for _, event := range []entities.Event{
+		blockEvent(1000, 1, stale),
+		blockEvent(1600, 2, active),
+		blockEvent(2200, 3, active),
+	} {
+		require.NoError(t, es.PutEvent(event))
+	}
+	opts := &criteria.MissingGeneratorCriterionOptions{
+		Addresses: []string{active.String(), stale.String(), absent.String()},
+		Interval:  25 * time.Minute,
+	}
+	analyze := func(ts int64) []entities.Alert {
+		alerts := make(chan entities.Alert, len(opts.Addresses))
+		criterion := criteria.NewMissingGeneratorCriterion(es, opts, zap.NewNop())
+		require.NoError(t, criterion.Analyze(alerts, ts))
+		close(alerts)
+		var out []entities.Alert
+		for alert := range alerts {
+			out = append(out, alert)
+		}
+		return out
+	}
+	assert.Empty(t, analyze(2200)) // the history is shorter than the interval
+	assert.Equal(t, []entities.Alert{
+		&entities.MissingGeneratorAlert{
+			Timestamp:       2200 + 600,
+			Generator:       stale.String(),
+			Interval:        opts.Interval,
+			LastBlockHeight: 1,
+			LastBlockTime:   1000,
+		},
+		&entities.MissingGeneratorAlert{Timestamp: 2200 + 600, Generator: absent.String(), Interval: opts.Interval},
+	}, analyze(2200+600))
+}
@@ -0,0 +1,121 @@
+package criteria
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the built-in criteria.
+const (
+	UnreachableCriterionName      = "unreachable"
+	IncompleteCriterionName       = "incomplete"
+	InvalidHeightCriterionName    = "invalid_height"
+	ChallengedBlockCriterionName  = "challenged_block"
+	HeightCriterionName           = "height"
+	StateHashCriterionName        = "state_hash"
+	BaseTargetCriterionName       = "base_target"
+	VersionCriterionName          = "version"
+	ChainStuckCriterionName       = "chain_stuck"
+	MissingGeneratorCriterionName = "missing_generator"
+)
+
+// Criterion checks the node statements collected at the same timestamp and sends alerts about the problems found.
+type Criterion interface {
+	// Name returns the unique name of the criterion. It's used to enable or disable the criterion.
+	Name() string
+	// Statuses returns the statuses of the statements which the criterion consumes.
+	Statuses() []entities.NodeStatus
+	// Analyze checks the statements which have one of the consumed statuses.
+	// The statements are grouped by the status in the order of Statuses and sorted by node inside each group.
+	Analyze(ctx context.Context, alerts chan<- entities.Alert, ts int64, statements entities.NodeStatements) error
+}
+
+type AnalyzeFunc func(ctx context.Context, alerts chan<- entities.Alert, ts int64, statements entities.NodeStatements) error
+
+type funcCriterion struct {
+	name     string
+	statuses []entities.NodeStatus
+	analyze  AnalyzeFunc
+}
+
+// NewCriterion creates a criterion from the analyze function.
+func NewCriterion(name string, statuses []entities.NodeStatus, analyze AnalyzeFunc) Criterion {
+	return &funcCriterion{name: name, statuses: statuses, analyze: analyze}
+}
+
+func (c *funcCriterion) Name() string { return c.name }
+
+func (c *funcCriterion) Statuses() []entities.NodeStatus { return c.statuses }
+
+func (c *funcCriterion) Analyze(
+	ctx context.Context,
+	alerts chan<- entities.Alert,
+	ts int64,
+	statements entities.NodeStatements,
+) error {
+	return c.analyze(ctx, alerts, ts, statements)
+}
+
+// Registry keeps the criteria in the order of registration. It's safe for concurrent use.
+type Registry struct {
+	mu       *sync.RWMutex
+	criteria []Criterion
+}
+
+func NewRegistry() *Registry {
+	return &Registry{mu: new(sync.RWMutex)}
+}
+
+// Register adds the criterion to the registry. Criteria names must be unique.
+func (r *Registry) Register(c Criterion) error {
+	if c.Name() == "" {
+		return errors.New("criterion name is empty")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.unsafeIndex(c.Name()) >= 0 {
+		return errors.Errorf("criterion '%s' is already registered", c.Name())
+	}
+	r.criteria = append(r.criteria, c)
+	return nil
+}
+
+// Has checks whether the criterion with the given name is registered.
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.unsafeIndex(name) >= 0
+}
+
+// Names returns the names of the registered criteria in the order of registration.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, len(r.criteria))
+	for i, c := range r.criteria {
+		names[i] = c.Name()
+	}
+	return names
+}
+
+// Enabled returns the registered criteria except the disabled ones.
+func (r *Registry) Enabled(disabled []string) []Criterion {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]Criterion, 0, len(r.criteria))
+	for _, c := range r.criteria {
+		if !slices.Contains(disabled, c.Name()) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func (r *Registry) unsafeIndex(name string) int {
+	return slices.IndexFunc(r.criteria, func(c Criterion) bool { return c.Name() == name })
+}
@@ -0,0 +1,34 @@
+package criteria_test
+
+import (
+	"context"
+	"testing"
+
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	noop := func(context.Context, chan<- entities.Alert, int64, entities.NodeStatements) error { return nil }
+	statuses := []entities.NodeStatus{entities.OK}
+
+	r := criteria.NewRegistry()
+	require.NoError(t, r.Register(criteria.NewCriterion("a", statuses, noop)))
+	require.NoError(t, r.Register(criteria.NewCriterion("b", statuses, noop)))
+	require.NoError(t, r.Register(criteria.NewCriterion("c", statuses, noop)))
+	assert.EqualError(t, r.Register(criteria.NewCriterion("b", statuses, noop)), "criterion 'b' is already registered")
+	assert.EqualError(t, r.Register(criteria.NewCriterion("", statuses, noop)), "criterion name is empty")
+
+	assert.Equal(t, []string{"a", "b", "c"}, r.Names())
+	assert.True(t, r.Has("a"))
+	assert.False(t, r.Has("d"))
+
+	var enabled []string
+	for _, c := range r.Enabled([]string{"b", "d"}) {
+		enabled = append(enabled, c.Name())
+	}
+	assert.Equal(t, []string{"a", "c"}, enabled)
+}
@@ -17,8 +17,8 @@ const (
 )
 
 type StateHashCriterionOptions struct {
-	MaxForkDepth     uint32
-	HeightBucketSize uint32
+	MaxForkDepth     uint32 `yaml:"max_fork_depth"`
+	HeightBucketSize uint32 `yaml:"height_bucket_size"`
 }
 
 type StateHashCriterion struct {
@@ -27,12 +27,16 @@ type StateHashCriterion struct {
 	zap  *zap.Logger
 }
 
+func DefaultStateHashCriterionOptions() *StateHashCriterionOptions {
+	return &StateHashCriterionOptions{
+		MaxForkDepth:     defaultMaxForkDepth,
+		HeightBucketSize: defaultHeightBucketSize,
+	}
+}
+
 func NewStateHashCriterion(es *events.Storage, opts *StateHashCriterionOptions, zap *zap.Logger) *StateHashCriterion {
 	if opts == nil { // default
-		opts = &StateHashCriterionOptions{
-			MaxForkDepth:     defaultMaxForkDepth,
-			HeightBucketSize: defaultHeightBucketSize,
-		}
+		opts = DefaultStateHashCriterionOptions()
 	}
 	return &StateHashCriterion{opts: opts, es: es, zap: zap}
 }
@@ -9,8 +9,8 @@ import (
 )
 
 type UnreachableCriterionOptions struct {
-	Streak int
-	Depth  int
+	Streak int `yaml:"streak"`
+	Depth  int `yaml:"depth"`
 }
 
 type UnreachableCriterion struct {
@@ -24,16 +24,20 @@ const (
 	unreachableDepthDefault  = 5
 )
 
+func DefaultUnreachableCriterionOptions() *UnreachableCriterionOptions {
+	return &UnreachableCriterionOptions{
+		Streak: unreachableStreakDefault,
+		Depth:  unreachableDepthDefault,
+	}
+}
+
 func NewUnreachableCriterion(
 	es *events.Storage,
 	opts *UnreachableCriterionOptions,
 	logger *zap.Logger,
 ) *UnreachableCriterion {
 	if opts == nil { // by default
-		opts = &UnreachableCriterionOptions{
-			Streak: unreachableStreakDefault,
-			Depth:  unreachableDepthDefault,
-		}
+		opts = DefaultUnreachableCriterionOptions()
 	}
 	return &UnreachableCriterion{opts: opts, es: es, zap: logger}
 }
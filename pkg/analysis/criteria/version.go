@@ -0,0 +1,166 @@
+package criteria
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultVersionDriftGracePeriod = time.Hour
+)
+
+type VersionCriterionOptions struct {
+	// DriftGracePeriod is the time during which the nodes may run different versions without an alert.
+	DriftGracePeriod time.Duration `yaml:"drift_grace_period"`
+	// MinVersion is the lowest allowed node version. Empty value disables the check.
+	MinVersion string `yaml:"min_version"`
+}
+
+func DefaultVersionCriterionOptions() *VersionCriterionOptions {
+	return &VersionCriterionOptions{
+		DriftGracePeriod: defaultVersionDriftGracePeriod,
+	}
+}
+
+// VersionDrift keeps the timestamp since which the nodes run different versions. It outlives the criterion,
+// so the same drift has to be passed to the criteria created for the consecutive analyses.
+type VersionDrift struct {
+	mu    sync.Mutex
+	since int64
+}
+
+func NewVersionDrift() *VersionDrift {
+	return &VersionDrift{}
+}
+
+// observe returns the timestamp of the drift start or zero if there is no drift.
+func (d *VersionDrift) observe(ts int64, drift bool) int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	switch {
+	case !drift:
+		d.since = 0
+	case d.since == 0:
+		d.since = ts
+	}
+	return d.since
+}
+
+type VersionCriterion struct {
+	opts  *VersionCriterionOptions
+	drift *VersionDrift
+	zap   *zap.Logger
+}
+
+func NewVersionCriterion(opts *VersionCriterionOptions, drift *VersionDrift, logger *zap.Logger) *VersionCriterion {
+	if opts == nil { // default
+		opts = DefaultVersionCriterionOptions()
+	}
+	return &VersionCriterion{opts: opts, drift: drift, zap: logger}
+}
+
+func (c *VersionCriterion) Analyze(alerts chan<- entities.Alert, ts int64, statements entities.NodeStatements) error {
+	var minVersion Version
+	if c.opts.MinVersion != "" {
+		v, err := ParseVersion(c.opts.MinVersion)
+		if err != nil {
+			return errors.Wrap(err, "invalid minimal node version")
+		}
+		minVersion = v
+	}
+	split := statements.SplitByNodeVersion()
+	delete(split, "") // the version is unknown
+	groups := make([]entities.VersionGroup, 0, len(split))
+	for version, nodeStatements := range split {
+		groups = append(groups, entities.VersionGroup{Version: version, Nodes: nodeStatements.Nodes().Sort()})
+	}
+	slices.SortFunc(groups, func(a, b entities.VersionGroup) int { return strings.Compare(a.Version, b.Version) })
+
+	since := c.drift.observe(ts, len(groups) > 1)
+	if since != 0 && time.Duration(ts-since)*time.Second >= c.opts.DriftGracePeriod {
+		c.zap.Info("VersionCriterion: nodes run different versions",
+			zap.Int("versions", len(groups)), zap.Int64("since", since),
+		)
+		alerts <- &entities.VersionMismatchAlert{
+			Timestamp: ts,
+			Kind:      entities.VersionDrift,
+			Groups:    groups,
+			Since:     since,
+		}
+	}
+	if minVersion == nil {
+		return nil
+	}
+	for _, group := range groups {
+		version, err := ParseVersion(group.Version)
+		if err != nil {
+			c.zap.Warn("VersionCriterion: failed to parse node version",
+				zap.String("version", group.Version), zap.Error(err),
+			)
+			continue
+		}
+		if version.Compare(minVersion) >= 0 {
+			continue
+		}
+		for _, node := range group.Nodes {
+			alerts <- &entities.VersionMismatchAlert{
+				Timestamp:  ts,
+				Kind:       entities.VersionBelowMinimum,
+				Node:       node,
+				Version:    group.Version,
+				MinVersion: c.opts.MinVersion,
+			}
+		}
+	}
+	return nil
+}
+
+// Version is the numeric part of the node version, e.g. [1 5 3] for "Waves v1.5.3".
+type Version []int
+
+var versionRegexp = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// ParseVersion extracts the first dotted numeric sequence from the version string.
+func ParseVersion(s string) (Version, error) {
+	numeric := versionRegexp.FindString(s)
+	if numeric == "" {
+		return nil, errors.Errorf("no version number in '%s'", s)
+	}
+	parts := strings.Split(numeric, ".")
+	v := make(Version, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid version number in '%s'", s)
+		}
+		v[i] = n
+	}
+	return v, nil
+}
+
+// Compare compares the versions component by component, the missing components are treated as zeros.
+func (v Version) Compare(other Version) int {
+	for i := range max(len(v), len(other)) {
+		var a, b int
+		if i < len(v) {
+			a = v[i]
+		}
+		if i < len(other) {
+			b = other[i]
+		}
+		if c := cmp.Compare(a, b); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
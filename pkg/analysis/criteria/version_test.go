@@ -0,0 +1,100 @@
+package criteria_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/criteria"
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func mkVersionStatements(ts int64, versions map[string]string) entities.NodeStatements {
+	var statements entities.NodeStatements
+	for node, version := range versions {
+		statements = append(statements, entities.NewHeightEvent(node, ts, version, 1).Statement())
+	}
+	return statements
+}
+
+func analyzeVersions(
+	t *testing.T,
+	opts *criteria.VersionCriterionOptions,
+	drift *criteria.VersionDrift,
+	ts int64,
+	versions map[string]string,
+) []entities.Alert {
+	alerts := make(chan entities.Alert, len(versions)+1)
+	criterion := criteria.NewVersionCriterion(opts, drift, zap.NewNop())
+	require.NoError(t, criterion.Analyze(alerts, ts, mkVersionStatements(ts, versions)))
+	close(alerts)
+	var out []entities.Alert
+	for alert := range alerts {
+		out = append(out, alert)
+	}
+	return out
+}
+
+func TestVersionCriterion_Drift(t *testing.T) {
+	var (
+		opts  = &criteria.VersionCriterionOptions{DriftGracePeriod: time.Minute}
+		drift = criteria.NewVersionDrift()
+		mixed = map[string]string{"n1": "Waves v1.5.3", "n2": "Waves v1.5.2", "n3": "Waves v1.5.3", "n4": ""}
+	)
+	assert.Empty(t, analyzeVersions(t, opts, drift, 100, mixed))
+	assert.Empty(t, analyzeVersions(t, opts, drift, 130, mixed))
+	assert.Equal(t, []entities.Alert{&entities.VersionMismatchAlert{
+		Timestamp: 160,
+		Kind:      entities.VersionDrift,
+		Groups: []entities.VersionGroup{
+			{Version: "Waves v1.5.2", Nodes: entities.Nodes{"n2"}},
+			{Version: "Waves v1.5.3", Nodes: entities.Nodes{"n1", "n3"}},
+		},
+		Since: 100,
+	}}, analyzeVersions(t, opts, drift, 160, mixed))
+
+	// the drift is over, the grace period starts again
+	assert.Empty(t, analyzeVersions(t, opts, drift, 190, map[string]string{"n1": "v1.5.3", "n2": "v1.5.3"}))
+	assert.Empty(t, analyzeVersions(t, opts, drift, 220, mixed))
+}
+
+func TestVersionCriterion_MinVersion(t *testing.T) {
+	opts := &criteria.VersionCriterionOptions{DriftGracePeriod: time.Hour, MinVersion: "1.5.3"}
+	alerts := analyzeVersions(t, opts, criteria.NewVersionDrift(), 100,
+		map[string]string{"n1": "Waves v1.5.3", "n2": "Waves v1.5.2", "n3": "Waves v1.6", "n4": "unknown"},
+	)
+	assert.Equal(t, []entities.Alert{&entities.VersionMismatchAlert{
+		Timestamp:  100,
+		Kind:       entities.VersionBelowMinimum,
+		Node:       "n2",
+		Version:    "Waves v1.5.2",
+		MinVersion: "1.5.3",
+	}}, alerts)
+}
+
+func TestParseVersion(t *testing.T) {
+	for _, test := range []struct {
+		in       string
+		expected criteria.Version
+		err      bool
+	}{
+		{in: "Waves v1.5.3", expected: criteria.Version{1, 5, 3}},
+		{in: "1.5.3-SNAPSHOT", expected: criteria.Version{1, 5, 3}},
+		{in: "v2", expected: criteria.Version{2}},
+		{in: "unknown", err: true},
+	} {
+		v, err := criteria.ParseVersion(test.in)
+		if test.err {
+			assert.Error(t, err, test.in)
+			continue
+		}
+		require.NoError(t, err, test.in)
+		assert.Equal(t, test.expected, v, test.in)
+	}
+	assert.Equal(t, 0, criteria.Version{1, 5}.Compare(criteria.Version{1, 5, 0}))
+	assert.Equal(t, -1, criteria.Version{1, 4, 10}.Compare(criteria.Version{1, 5}))
+	assert.Equal(t, 1, criteria.Version{1, 10}.Compare(criteria.Version{1, 9, 9}))
+}
@@ -0,0 +1,174 @@
+package forks
+
+import (
+	"slices"
+	"sync"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultMaxHeights   = 10
+	DefaultReportsLimit = 100
+)
+
+// Reporter builds the fork reports of the state hash alerts from the statements history and keeps the recent ones.
+// It's safe for concurrent use.
+type Reporter struct {
+	es         *events.Storage
+	maxHeights uint64
+	limit      int
+	mu         *sync.RWMutex
+	reports    map[crypto.Digest]entities.ForkReport
+	order      []crypto.Digest // the oldest first
+	zap        *zap.Logger
+}
+
+// NewReporter creates a reporter which compares at most maxHeights heights and keeps at most limit reports.
+func NewReporter(es *events.Storage, maxHeights uint64, limit int, logger *zap.Logger) *Reporter {
+	if maxHeights == 0 {
+		maxHeights = DefaultMaxHeights
+	}
+	if limit <= 0 {
+		limit = DefaultReportsLimit
+	}
+	return &Reporter{
+		es:         es,
+		maxHeights: maxHeights,
+		limit:      limit,
+		mu:         new(sync.RWMutex),
+		reports:    make(map[crypto.Digest]entities.ForkReport),
+		zap:        logger,
+	}
+}
+
+// Run attaches the fork reports to the state hash alerts and passes all the alerts through.
+func (r *Reporter) Run(input <-chan entities.Alert) <-chan entities.Alert {
+	output := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		for alert := range input {
+			if a, ok := alert.(*entities.StateHashAlert); ok {
+				r.attach(a)
+			}
+			output <- alert
+		}
+	}()
+	return output
+}
+
+func (r *Reporter) attach(alert *entities.StateHashAlert) {
+	report, err := r.Analyze(alert)
+	if err != nil {
+		r.zap.Error("Failed to build fork report", zap.Stringer("alert-id", alert.ID()), zap.Error(err))
+		return
+	}
+	r.put(report)
+	alert.Fork = &report
+}
+
+// Analyze compares the groups of the alert at the heights from the last common one up to the alert height, but at
+// most maxHeights heights after the last common one. Without the last common height the latest maxHeights heights
+// are compared, and the fork height is only set if the groups are known to have the same state at the height before.
+// The report is rebuilt on each repeat of the alert, so the newer heights of the growing fork are included.
+func (r *Reporter) Analyze(alert *entities.StateHashAlert) (entities.ForkReport, error) {
+	report := entities.ForkReport{
+		AlertID:     alert.ID(),
+		Timestamp:   alert.Timestamp,
+		FirstGroup:  alert.FirstGroup.Nodes,
+		SecondGroup: alert.SecondGroup.Nodes,
+	}
+	stop, start := alert.CurrentGroupsBucketHeight, uint64(1)
+	switch {
+	case alert.LastCommonStateHashExist: // the fork height of a deep fork has to stay in the window
+		report.LastCommonHeight = alert.LastCommonStateHashHeight
+		start = alert.LastCommonStateHashHeight
+		stop = min(stop, start+r.maxHeights)
+	case stop > r.maxHeights:
+		start = stop - r.maxHeights + 1
+	}
+	commonBefore := false // the groups have the same state at the previous height
+	for h := start; h <= stop; h++ {
+		fh := entities.ForkHeight{Height: h}
+		var err error
+		if fh.First, err = r.block(alert.FirstGroup.Nodes, h); err != nil {
+			return entities.ForkReport{}, err
+		}
+		if fh.Second, err = r.block(alert.SecondGroup.Nodes, h); err != nil {
+			return entities.ForkReport{}, err
+		}
+		diverged := fh.Diverged()
+		if diverged {
+			fh.DivergedFields = entities.DivergedStateHashFields(fh.First.Fields, fh.Second.Fields)
+			if report.ForkHeight == 0 && commonBefore {
+				report.ForkHeight = h
+				report.DivergedFields = fh.DivergedFields
+			}
+		}
+		commonBefore = fh.First != nil && fh.Second != nil && !diverged
+		report.Heights = append(report.Heights, fh)
+	}
+	return report, nil
+}
+
+// block returns the block of the first group node which has the full statement at the height.
+// Nil is returned if there's no such node.
+func (r *Reporter) block(nodes []string, height uint64) (*entities.ForkBlock, error) {
+	for _, node := range nodes {
+		statement, err := r.es.GetFullStatementAtHeight(node, height)
+		if err != nil {
+			if errors.Is(err, events.ErrNoFullStatement) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get statement of node '%s' at height %d", node, height)
+		}
+		return &entities.ForkBlock{
+			Node:      node,
+			BlockID:   statement.StateHash.BlockID,
+			StateHash: statement.StateHash.SumHash,
+			Fields:    statement.StateHash.FieldsHashes,
+			Generator: statement.Generator,
+		}, nil
+	}
+	return nil, nil
+}
+
+func (r *Reporter) put(report entities.ForkReport) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.reports[report.AlertID]; ok {
+		r.order = slices.DeleteFunc(r.order, func(id crypto.Digest) bool { return id == report.AlertID })
+	}
+	if len(r.order) >= r.limit {
+		for _, id := range r.order[:len(r.order)-r.limit+1] {
+			delete(r.reports, id)
+		}
+		r.order = slices.Delete(r.order, 0, len(r.order)-r.limit+1)
+	}
+	r.order = append(r.order, report.AlertID)
+	r.reports[report.AlertID] = report
+}
+
+// Report returns the last report of the alert.
+func (r *Reporter) Report(alertID crypto.Digest) (entities.ForkReport, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	report, ok := r.reports[alertID]
+	return report, ok
+}
+
+// Reports returns the kept reports, the most recently built first.
+func (r *Reporter) Reports() []entities.ForkReport {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]entities.ForkReport, 0, len(r.order))
+	for i := len(r.order) - 1; i >= 0; i-- {
+		out = append(out, r.reports[r.order[i]])
+	}
+	return out
+}
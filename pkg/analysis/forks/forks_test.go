@@ -0,0 +1,159 @@
+package forks_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/forks"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+// putChain puts the statements of the node at heights [1, len(shs)] one minute apart.
+func putChain(t *testing.T, es *events.Storage, node string, shs []proto.StateHash) {
+	const start = 1700000000 // the keys are ordered as strings, so the timestamps must have the same length
+	generator, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, crypto.PublicKey{0x01})
+	require.NoError(t, err)
+	for i := range shs {
+		h := uint64(i + 1)
+		event := entities.NewStateHashEvent(node, start+int64(h*60), "1.5.7", h, &shs[i], 1, &shs[i].BlockID, &generator,
+			false,
+		)
+		require.NoError(t, es.PutEvent(event))
+	}
+}
+
+func stateHash(block, sum byte, fields proto.FieldsHashes) proto.StateHash {
+	return proto.StateHash{
+		BlockID:      proto.NewBlockIDFromDigest(crypto.Digest{block}),
+		SumHash:      crypto.Digest{sum},
+		FieldsHashes: fields,
+	}
+}
+
+func TestReporter_Analyze(t *testing.T) {
+	es, err := events.NewStorage(time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	var (
+		common   = []proto.StateHash{stateHash(1, 1, proto.FieldsHashes{}), stateHash(2, 2, proto.FieldsHashes{})}
+		diverged = proto.FieldsHashes{WavesBalanceHash: crypto.Digest{0x01}, AliasesHash: crypto.Digest{0x02}}
+	)
+	putChain(t, es, "a", append(common, stateHash(3, 3, proto.FieldsHashes{}), stateHash(4, 4, proto.FieldsHashes{})))
+	putChain(t, es, "b", append(common, stateHash(3, 13, diverged), stateHash(14, 14, diverged)))
+
+	alert := &entities.StateHashAlert{
+		Timestamp:                 240,
+		CurrentGroupsBucketHeight: 4,
+		LastCommonStateHashExist:  true,
+		LastCommonStateHashHeight: 2,
+		LastCommonStateHash:       common[1],
+		FirstGroup:                entities.StateHashGroup{Nodes: []string{"a"}},
+		SecondGroup:               entities.StateHashGroup{Nodes: []string{"c", "b"}}, // c has no statements
+	}
+	r := forks.NewReporter(es, 0, 0, zap.NewNop())
+	report, err := r.Analyze(alert)
+	require.NoError(t, err)
+
+	assert.Equal(t, alert.ID(), report.AlertID)
+	assert.Equal(t, uint64(2), report.LastCommonHeight)
+	assert.Equal(t, uint64(3), report.ForkHeight)
+	assert.Equal(t, []string{"aliases", "waves_balance"}, report.DivergedFields)
+	require.Len(t, report.Heights, 3)
+	assert.False(t, report.Heights[0].Diverged(), "the report starts from the last common height")
+	assert.Equal(t, "b", report.Heights[1].Second.Node)
+	assert.Equal(t, report.Heights[1].First.BlockID, report.Heights[1].Second.BlockID, "same block, different state")
+	assert.NotEqual(t, report.Heights[2].First.BlockID, report.Heights[2].Second.BlockID)
+	assert.True(t, report.Heights[2].Diverged())
+}
+
+func TestReporter_AnalyzeDeepFork(t *testing.T) {
+	es, err := events.NewStorage(time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	var first, second []proto.StateHash
+	for i := range byte(8) {
+		first = append(first, stateHash(i+1, i+1, proto.FieldsHashes{}))
+		if i < 2 {
+			second = append(second, first[i])
+		} else {
+			second = append(second, stateHash(i+1, i+11, proto.FieldsHashes{AliasesHash: crypto.Digest{i}}))
+		}
+	}
+	putChain(t, es, "a", first)
+	putChain(t, es, "b", second)
+
+	const maxHeights = 3
+	alert := &entities.StateHashAlert{
+		Timestamp:                 480,
+		CurrentGroupsBucketHeight: 8,
+		LastCommonStateHashExist:  true,
+		LastCommonStateHashHeight: 2,
+		LastCommonStateHash:       first[1],
+		FirstGroup:                entities.StateHashGroup{Nodes: []string{"a"}},
+		SecondGroup:               entities.StateHashGroup{Nodes: []string{"b"}},
+	}
+	r := forks.NewReporter(es, maxHeights, 0, zap.NewNop())
+	heights := func(report entities.ForkReport) []uint64 {
+		var out []uint64
+		for _, fh := range report.Heights {
+			out = append(out, fh.Height)
+		}
+		return out
+	}
+
+	report, err := r.Analyze(alert)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{2, 3, 4, 5}, heights(report), "the window must start from the last common height")
+	assert.Equal(t, uint64(3), report.ForkHeight)
+	assert.Equal(t, []string{"aliases"}, report.DivergedFields)
+
+	alert.LastCommonStateHashExist, alert.LastCommonStateHashHeight = false, 0
+	report, err = r.Analyze(alert)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{6, 7, 8}, heights(report), "the latest heights must be compared")
+	assert.Zero(t, report.ForkHeight, "the fork height is unknown if the window starts diverged")
+	assert.Empty(t, report.DivergedFields)
+}
+
+func TestReporter_Run(t *testing.T) {
+	es, err := events.NewStorage(time.Hour, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	const limit = 2
+	r := forks.NewReporter(es, 0, limit, zap.NewNop())
+	in := make(chan entities.Alert)
+	out := r.Run(in)
+	var alerts []*entities.StateHashAlert
+	for i := range 3 {
+		alert := &entities.StateHashAlert{
+			Timestamp:                 int64(i),
+			CurrentGroupsBucketHeight: 10,
+			FirstGroup:                entities.StateHashGroup{Nodes: []string{"a"}},
+			SecondGroup:               entities.StateHashGroup{Nodes: []string{string(rune('b' + i))}},
+		}
+		in <- alert
+		require.Equal(t, alert, <-out)
+		require.NotNil(t, alert.Fork, "the report must be attached to the alert")
+		alerts = append(alerts, alert)
+	}
+	in <- &entities.SimpleAlert{Description: "passed through"}
+	assert.IsType(t, &entities.SimpleAlert{}, <-out)
+	close(in)
+
+	_, ok := r.Report(alerts[0].ID())
+	assert.False(t, ok, "the oldest report must be removed")
+	reports := r.Reports()
+	require.Len(t, reports, limit)
+	assert.Equal(t, alerts[2].ID(), reports[0].AlertID)
+	assert.Equal(t, alerts[1].ID(), reports[1].AlertID)
+}
@@ -13,6 +13,7 @@ import (
 
 	"nodemon/pkg/analysis/storage"
 	"nodemon/pkg/entities"
+	"nodemon/pkg/metrics"
 	"nodemon/pkg/tools"
 
 	"go.uber.org/zap"
@@ -96,13 +97,14 @@ type Node struct {
 	Name string
 }
 
-func runCollector(ctx context.Context, nodeURL string, logger *zap.Logger) <-chan uint64 {
+func runCollector(ctx context.Context, scheme, nodeURL string, logger *zap.Logger) <-chan uint64 {
 	collectAndSend := func(heightCh chan<- uint64) {
 		height, ok := collectL2Height(ctx, nodeURL, logger)
 		if !ok {
 			return // failed to collect height
 		}
 		logger.Info("L2 height collected", zap.Uint64("height", height), zap.String("nodeURL", nodeURL))
+		metrics.L2NodeHeight(scheme, nodeURL, height)
 		select {
 		case heightCh <- height:
 		case <-ctx.Done():
@@ -158,6 +160,7 @@ const defaultAlertVacuumQuota = int(l2NodesSameHeightTimerDuration/heightCollect
 func analyzerLoop(
 	ctx context.Context,
 	zap *zap.Logger,
+	scheme string,
 	node Node,
 	alertsL2 chan<- entities.Alert,
 	heightCh <-chan uint64,
@@ -165,7 +168,10 @@ func analyzerLoop(
 	defer close(alertsL2)
 	alertTimer := time.NewTimer(l2NodesSameHeightTimerDuration)
 	defer alertTimer.Stop()
-	s := storage.NewAlertsStorage(zap, storage.AlertVacuumQuota(defaultAlertVacuumQuota))
+	s := storage.NewAlertsStorage(zap,
+		storage.AlertVacuumQuota(defaultAlertVacuumQuota),
+		storage.MetricsScheme(scheme),
+	)
 
 	var lastHeight uint64
 	for {
@@ -199,16 +205,18 @@ func analyzerLoop(
 	}
 }
 
-func RunL2Analyzer(ctx context.Context, zap *zap.Logger, node Node) <-chan entities.Alert {
-	heightCh := runCollector(ctx, node.URL, zap)
+// RunL2Analyzer analyzes the L2 node which is monitored along with the network of the given scheme.
+func RunL2Analyzer(ctx context.Context, zap *zap.Logger, scheme string, node Node) <-chan entities.Alert {
+	heightCh := runCollector(ctx, scheme, node.URL, zap)
 	alertsL2 := make(chan entities.Alert)
-	go analyzerLoop(ctx, zap, node, alertsL2, heightCh)
+	go analyzerLoop(ctx, zap, scheme, node, alertsL2, heightCh)
 	return alertsL2
 }
 
 func RunL2Analyzers(
 	ctx context.Context,
 	zap *zap.Logger,
+	scheme string,
 	nodes []Node,
 ) <-chan entities.Alert {
 	// intentionally not using tools.FanInSeqCtx to avoid context propagation
@@ -218,7 +226,7 @@ func RunL2Analyzers(
 			return
 		}
 		for _, node := range nodes {
-			alertsL2 := RunL2Analyzer(ctx, zap, node)
+			alertsL2 := RunL2Analyzer(ctx, zap, scheme, node)
 			if !yield(alertsL2) {
 				return
 			}
@@ -1,11 +1,18 @@
 package storage
 
 import (
+	"bytes"
+	"cmp"
 	"iter"
 	"maps"
+	"slices"
+	"sync"
+	"time"
 
 	"nodemon/pkg/entities"
+	"nodemon/pkg/metrics"
 
+	"github.com/pkg/errors"
 	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"go.uber.org/zap"
 )
@@ -33,12 +40,35 @@ func (s alertsInternalStorage) infos() iter.Seq[alertInfo] {
 	return maps.Values(s)
 }
 
+// AlertMutes manages acks and silences of the alerts.
+type AlertMutes interface {
+	PutMute(mute entities.AlertMute) (entities.AlertMute, error)
+	DeleteMute(id crypto.Digest) bool
+	Mutes() []entities.AlertMute
+}
+
+// MutesStorage keeps the alert mutes, so they survive a restart of nodemon.
+type MutesStorage interface {
+	PutMute(mute entities.AlertMute) error
+	DeleteMute(id crypto.Digest) error
+	Mutes() ([]entities.AlertMute, error)
+}
+
 type AlertsStorage struct {
+	mu                    *sync.RWMutex
+	scheme                string // the network of the alerts, it labels the metrics
 	alertBackoff          int
 	alertVacuumQuota      int
 	requiredConfirmations alertConfirmations
 	internalStorage       alertsInternalStorage
-	logger                *zap.Logger
+	mutes                 map[crypto.Digest]entities.AlertMute
+	mutesStorage          MutesStorage // optional, keeps the mutes across restarts
+	// names of the alerts which aren't saved because alertVacuumQuota <= 1, they are counted as active
+	// from the end of their round until the end of the next one
+	unsavedRound  []entities.AlertName
+	unsavedActive []entities.AlertName
+	now           func() time.Time
+	logger        *zap.Logger
 }
 
 type alertConfirmations map[entities.AlertType]int
@@ -70,6 +100,16 @@ func AlertConfirmations(confirmations ...AlertConfirmationsValue) AlertsStorageO
 	return func(s *AlertsStorage) { s.requiredConfirmations = newAlertConfirmations(confirmations...) }
 }
 
+// MetricsScheme sets the blockchain scheme which the metrics of the alerts are labeled with.
+func MetricsScheme(scheme string) AlertsStorageOption {
+	return func(s *AlertsStorage) { s.scheme = scheme }
+}
+
+// PersistentMutes saves the alert mutes to the given storage, RestoreMutes loads them back.
+func PersistentMutes(ms MutesStorage) AlertsStorageOption {
+	return func(s *AlertsStorage) { s.mutesStorage = ms }
+}
+
 func NewAlertsStorage(logger *zap.Logger, opts ...AlertsStorageOption) *AlertsStorage {
 	s := newAlertsStorage(DefaultAlertBackoff, DefaultAlertVacuumQuota, newAlertConfirmations(), logger)
 	for _, opt := range opts {
@@ -78,22 +118,55 @@ func NewAlertsStorage(logger *zap.Logger, opts ...AlertsStorageOption) *AlertsSt
 	return s
 }
 
+// Reconfigure applies the options to the storage. Already tracked alerts are kept.
+func (s *AlertsStorage) Reconfigure(opts ...AlertsStorageOption) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, opt := range opts {
+		opt(s)
+	}
+}
+
 func newAlertsStorage(
 	alertBackoff, alertVacuumQuota int,
 	requiredConfirmations alertConfirmations,
 	logger *zap.Logger,
 ) *AlertsStorage {
 	return &AlertsStorage{
+		mu:                    new(sync.RWMutex),
 		alertBackoff:          alertBackoff,
 		alertVacuumQuota:      alertVacuumQuota,
 		requiredConfirmations: requiredConfirmations,
 		internalStorage:       make(alertsInternalStorage),
+		mutes:                 make(map[crypto.Digest]entities.AlertMute),
+		now:                   time.Now,
 		logger:                logger,
 	}
 }
 
+// PutAlert saves the alert and reports whether the alert should be sent now.
+// Muted alerts are tracked as usual, but they are never reported to be sent.
 func (s *AlertsStorage) PutAlert(alert entities.Alert) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sendNow := s.unsafePutAlert(alert)
+	if sendNow {
+		if mute, muted := s.unsafeFindMute(alert, alert.Time().Unix()); muted {
+			s.logger.Info("Alert is muted, skipping it",
+				zap.Stringer("alert", alert),
+				zap.String("mute-kind", string(mute.Kind)),
+				zap.Stringer("mute-id", mute.ID),
+			)
+			return false
+		}
+		metrics.AlertSent(s.scheme, alert.Name())
+	}
+	return sendNow
+}
+
+func (s *AlertsStorage) unsafePutAlert(alert entities.Alert) bool {
 	if s.alertVacuumQuota <= 1 { // no need to save alerts which can't outlive even one vacuum stage
+		s.unsavedRound = append(s.unsavedRound, alert.Name())
 		return true
 	}
 	var (
@@ -112,6 +185,7 @@ func (s *AlertsStorage) PutAlert(alert entities.Alert) bool {
 	}()
 
 	if !old.confirmed && repeats >= s.requiredConfirmations[alert.Type()] { // send confirmed alert
+		metrics.AlertConfirmed(s.scheme, alert.Name())
 		s.internalStorage[alertID] = alertInfo{
 			vacuumQuota:      s.alertVacuumQuota,
 			repeats:          1, // now it's a confirmed alert, so reset repeats counter
@@ -143,6 +217,8 @@ func (s *AlertsStorage) PutAlert(alert entities.Alert) bool {
 }
 
 func (s *AlertsStorage) Vacuum() []entities.Alert {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	var alertsFixed []entities.Alert
 	for id := range s.internalStorage.ids() {
 		info := s.internalStorage[id]
@@ -150,11 +226,161 @@ func (s *AlertsStorage) Vacuum() []entities.Alert {
 		if info.vacuumQuota <= 0 {
 			if info.confirmed {
 				alertsFixed = append(alertsFixed, info.alert)
+				metrics.AlertFixed(s.scheme, info.alert.Name())
 			}
+			s.unsafeDeleteAcks(info.alert)
 			delete(s.internalStorage, id)
 		} else {
 			s.internalStorage[id] = info
 		}
 	}
+	s.unsafeRotateUnsavedAlerts()
+	s.unsafeDeleteExpiredMutes(s.now().Unix())
 	return alertsFixed
 }
+
+// unsafeRotateUnsavedAlerts updates the active alerts metric with the alerts which aren't saved in the storage.
+// Such alerts are never fixed explicitly, so they are active until the next vacuum stage.
+func (s *AlertsStorage) unsafeRotateUnsavedAlerts() {
+	for _, name := range s.unsavedActive {
+		metrics.AlertFixed(s.scheme, name)
+	}
+	for _, name := range s.unsavedRound {
+		metrics.AlertConfirmed(s.scheme, name)
+	}
+	s.unsavedActive, s.unsavedRound = s.unsavedRound, s.unsavedActive[:0]
+}
+
+// AlertState describes the repeats and backoff state of an alert kept in the AlertsStorage.
+type AlertState struct {
+	Repeats          int  `json:"repeats"`
+	BackoffThreshold int  `json:"backoff_threshold"`
+	VacuumQuota      int  `json:"vacuum_quota"`
+	Confirmed        bool `json:"confirmed"`
+	Muted            bool `json:"muted"`
+}
+
+// AlertState returns the current state of the alert with the given ID. It's safe for concurrent use.
+func (s *AlertsStorage) AlertState(alertID crypto.Digest) (AlertState, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	info, ok := s.internalStorage[alertID]
+	if !ok {
+		return AlertState{}, false
+	}
+	_, muted := s.unsafeFindMute(info.alert, s.now().Unix())
+	return AlertState{
+		Repeats:          info.repeats,
+		BackoffThreshold: info.backoffThreshold,
+		VacuumQuota:      info.vacuumQuota,
+		Confirmed:        info.confirmed,
+		Muted:            muted,
+	}, true
+}
+
+// PutMute adds the alert mute or replaces the existing one with the same ID.
+func (s *AlertsStorage) PutMute(mute entities.AlertMute) (entities.AlertMute, error) {
+	if err := mute.Validate(); err != nil {
+		return entities.AlertMute{}, errors.Wrap(err, "invalid alert mute")
+	}
+	now := s.now().Unix()
+	if mute.Expired(now) {
+		return entities.AlertMute{}, errors.New("alert mute is already expired")
+	}
+	mute.CreatedAt = now
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mutesStorage != nil {
+		if err := s.mutesStorage.PutMute(mute); err != nil {
+			return entities.AlertMute{}, errors.Wrap(err, "failed to persist alert mute")
+		}
+	}
+	s.mutes[mute.ID] = mute
+	s.logger.Info("Alert mute has been put into storage",
+		zap.Stringer("mute-id", mute.ID),
+		zap.String("mute-kind", string(mute.Kind)),
+		zap.Int64("expires-at", mute.ExpiresAt),
+	)
+	return mute, nil
+}
+
+// DeleteMute deletes the alert mute and reports whether it has existed.
+func (s *AlertsStorage) DeleteMute(id crypto.Digest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.mutes[id]
+	s.unsafeDeleteMute(id)
+	return ok
+}
+
+// RestoreMutes loads the alert mutes saved by the persistent mutes storage, see PersistentMutes.
+// The loaded mutes replace the ones with the same IDs, the expired mutes are skipped.
+func (s *AlertsStorage) RestoreMutes() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.mutesStorage == nil {
+		return nil
+	}
+	mutes, err := s.mutesStorage.Mutes()
+	if err != nil {
+		return errors.Wrap(err, "failed to restore alert mutes")
+	}
+	now := s.now().Unix()
+	for _, m := range mutes {
+		if !m.Expired(now) {
+			s.mutes[m.ID] = m
+		}
+	}
+	s.logger.Info("Alert mutes have been restored", zap.Int("count", len(s.mutes)))
+	return nil
+}
+
+// Mutes returns the alert mutes which are not expired yet, the most recently created first.
+func (s *AlertsStorage) Mutes() []entities.AlertMute {
+	now := s.now().Unix()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.unsafeDeleteExpiredMutes(now)
+	out := slices.AppendSeq(make([]entities.AlertMute, 0, len(s.mutes)), maps.Values(s.mutes))
+	slices.SortFunc(out, func(a, b entities.AlertMute) int {
+		if c := cmp.Compare(b.CreatedAt, a.CreatedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return out
+}
+
+func (s *AlertsStorage) unsafeFindMute(alert entities.Alert, ts int64) (entities.AlertMute, bool) {
+	for _, m := range s.mutes {
+		if !m.Expired(ts) && m.Matches(alert) {
+			return m, true
+		}
+	}
+	return entities.AlertMute{}, false
+}
+
+func (s *AlertsStorage) unsafeDeleteExpiredMutes(ts int64) {
+	maps.DeleteFunc(s.mutes, func(_ crypto.Digest, m entities.AlertMute) bool { return m.Expired(ts) })
+}
+
+// unsafeDeleteMute deletes the alert mute from memory and from the persistent mutes storage if it's set.
+// The expired mutes expire in the persistent storage by themselves, so they're deleted from memory only.
+func (s *AlertsStorage) unsafeDeleteMute(id crypto.Digest) {
+	delete(s.mutes, id)
+	if s.mutesStorage == nil {
+		return
+	}
+	if err := s.mutesStorage.DeleteMute(id); err != nil {
+		s.logger.Error("Failed to delete persisted alert mute", zap.Stringer("mute-id", id), zap.Error(err))
+	}
+}
+
+// unsafeDeleteAcks deletes acks of the resolved alert, because they are not needed anymore.
+func (s *AlertsStorage) unsafeDeleteAcks(alert entities.Alert) {
+	for id, m := range s.mutes {
+		if m.Kind == entities.AckAlertMuteKind && m.Matches(alert) {
+			s.unsafeDeleteMute(id)
+		}
+	}
+}
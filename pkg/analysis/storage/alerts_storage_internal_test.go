@@ -2,12 +2,17 @@ package storage
 
 import (
 	"log"
+	"maps"
 	"slices"
 	"testing"
+	"time"
 
 	"nodemon/pkg/entities"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"go.uber.org/zap"
 )
 
@@ -172,3 +177,145 @@ func TestAlertsStorage(t *testing.T) {
 		require.ElementsMatch(t, test.expectedAlertsInfo, actualInfos, "test case#%d", tcNum)
 	}
 }
+
+func TestAlertsStorageMutes(t *testing.T) {
+	const node = "https://node.example.com"
+	var (
+		now         = time.Unix(1000, 0)
+		unreachable = &entities.UnreachableAlert{Timestamp: now.Unix(), Node: node}
+		simple      = &entities.SimpleAlert{Timestamp: now.Unix(), Description: "simple alert"}
+	)
+	s := newAlertsStorage(DefaultAlertBackoff, 2, newAlertConfirmations(), zap.NewNop())
+	s.now = func() time.Time { return now }
+
+	silence, err := s.PutMute(entities.NewAlertMuteByNode(
+		entities.SilenceAlertMuteKind, entities.UnreachableAlertName, node, now.Unix()+10,
+	))
+	require.NoError(t, err)
+	require.Equal(t, now.Unix(), silence.CreatedAt)
+	ack, err := s.PutMute(entities.NewAlertMuteByID(entities.AckAlertMuteKind, simple.ID(), 0))
+	require.NoError(t, err)
+	require.Len(t, s.Mutes(), 2)
+
+	require.False(t, s.PutAlert(unreachable)) // silenced, but tracked
+	require.False(t, s.PutAlert(simple))      // acked, but tracked
+	state, ok := s.AlertState(unreachable.ID())
+	require.True(t, ok)
+	require.True(t, state.Confirmed)
+	require.True(t, state.Muted)
+
+	// resolved alerts are still reported as fixed, acks of them are removed
+	require.ElementsMatch(t, []entities.Alert{}, s.Vacuum())
+	require.ElementsMatch(t, []entities.Alert{unreachable, simple}, s.Vacuum())
+	require.Equal(t, []entities.AlertMute{silence}, s.Mutes())
+
+	// silence is expired
+	now = now.Add(10 * time.Second)
+	require.True(t, s.PutAlert(&entities.UnreachableAlert{Timestamp: now.Unix(), Node: node}))
+	require.Empty(t, s.Mutes())
+
+	require.False(t, s.DeleteMute(ack.ID))
+	_, err = s.PutMute(entities.AlertMute{Kind: entities.SilenceAlertMuteKind, AlertName: entities.UnreachableAlertName})
+	require.Error(t, err)
+}
+
+type mapMutesStorage map[crypto.Digest]entities.AlertMute
+
+func (m mapMutesStorage) PutMute(mute entities.AlertMute) error {
+	m[mute.ID] = mute
+	return nil
+}
+
+func (m mapMutesStorage) DeleteMute(id crypto.Digest) error {
+	delete(m, id)
+	return nil
+}
+
+func (m mapMutesStorage) Mutes() ([]entities.AlertMute, error) {
+	return slices.Collect(maps.Values(m)), nil
+}
+
+func TestAlertsStoragePersistentMutes(t *testing.T) {
+	const node = "https://node.example.com"
+	var (
+		now    = time.Unix(1000, 0)
+		simple = &entities.SimpleAlert{Timestamp: now.Unix(), Description: "simple alert"}
+		ms     = make(mapMutesStorage)
+	)
+	newStorage := func() *AlertsStorage { // emulates restart of nodemon
+		s := NewAlertsStorage(zap.NewNop(), AlertVacuumQuota(2), PersistentMutes(ms))
+		s.now = func() time.Time { return now }
+		require.NoError(t, s.RestoreMutes())
+		return s
+	}
+	s := newStorage()
+	silence, err := s.PutMute(entities.NewAlertMuteByNode(
+		entities.SilenceAlertMuteKind, entities.UnreachableAlertName, node, now.Unix()+10,
+	))
+	require.NoError(t, err)
+	ack, err := s.PutMute(entities.NewAlertMuteByID(entities.AckAlertMuteKind, simple.ID(), 0))
+	require.NoError(t, err)
+
+	s = newStorage()
+	require.ElementsMatch(t, []entities.AlertMute{silence, ack}, s.Mutes())
+	require.False(t, s.PutAlert(simple)) // still acked after restart
+
+	// ack of the resolved alert is removed from the persistent storage too
+	s.Vacuum()
+	s.Vacuum()
+	assert.Equal(t, mapMutesStorage{silence.ID: silence}, ms)
+	s = newStorage()
+	require.Equal(t, []entities.AlertMute{silence}, s.Mutes())
+
+	require.True(t, s.DeleteMute(silence.ID))
+	assert.Empty(t, ms)
+
+	// expired mutes aren't restored
+	ms[silence.ID] = silence
+	now = now.Add(10 * time.Second)
+	require.Empty(t, newStorage().Mutes())
+}
+
+func activeAlertsMetric(t *testing.T, scheme string, name entities.AlertName) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != "nodemon_alerts_active" {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["scheme"] == scheme && labels["alert"] == name.String() {
+				return m.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestAlertsStorageUnsavedAlertsMetric(t *testing.T) {
+	const scheme = "mainnet"
+	s := newAlertsStorage(DefaultAlertBackoff, 1, newAlertConfirmations(), zap.NewNop())
+	MetricsScheme(scheme)(s)
+	alert := &entities.ChainStuckAlert{Timestamp: 100}
+	before := activeAlertsMetric(t, scheme, alert.Name())
+	otherBefore := activeAlertsMetric(t, "testnet", alert.Name())
+
+	require.True(t, s.PutAlert(alert))
+	require.True(t, s.PutAlert(alert))
+	assert.Empty(t, s.Vacuum(), "unsaved alerts are never fixed")
+	assert.Equal(t, before+2, activeAlertsMetric(t, scheme, alert.Name()), "alerts of the round are active")
+
+	require.True(t, s.PutAlert(alert))
+	assert.Equal(t, before+2, activeAlertsMetric(t, scheme, alert.Name()), "metric is updated on the vacuum stage")
+	s.Vacuum()
+	assert.Equal(t, before+1, activeAlertsMetric(t, scheme, alert.Name()))
+	s.Vacuum()
+	assert.Equal(t, before, activeAlertsMetric(t, scheme, alert.Name()))
+	assert.Equal(t, otherBefore, activeAlertsMetric(t, "testnet", alert.Name()),
+		"the alerts of the other network must not be counted",
+	)
+}
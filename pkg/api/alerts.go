@@ -0,0 +1,123 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
+
+	"github.com/go-chi/chi"
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+const alertMuteRequestLimit = kb
+
+type activeAlertsResponse struct {
+	Alerts proto.NonNullableSlice[alertlog.ActiveAlert] `json:"alerts"`
+}
+
+type alertsHistoryResponse struct {
+	Alerts proto.NonNullableSlice[alertlog.ResolvedAlert] `json:"alerts"`
+	Limit  int                                            `json:"limit"`
+}
+
+// activeAlerts returns the alerts which have not been resolved yet, the most recently opened first.
+func (a *API) activeAlerts(w http.ResponseWriter, r *http.Request) {
+	a.writeJSON(w, r, activeAlertsResponse{Alerts: a.alertsLog.Active()})
+}
+
+// alertsHistory returns the resolved alerts, the most recently closed first.
+func (a *API) alertsHistory(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := parsePaging(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	a.writeJSON(w, r, alertsHistoryResponse{Alerts: a.alertsLog.History(limit), Limit: limit})
+}
+
+type alertMutesResponse struct {
+	Mutes proto.NonNullableSlice[entities.AlertMute] `json:"mutes"`
+}
+
+// alertMuteRequest is the body of the ack or silence request. The alert is defined either by its ID
+// or by its name and node. Empty duration means that the mute never expires.
+type alertMuteRequest struct {
+	Kind      entities.AlertMuteKind `json:"kind"`
+	AlertID   *crypto.Digest         `json:"alert_id,omitempty"`
+	AlertName entities.AlertName     `json:"alert_name,omitempty"`
+	Node      string                 `json:"node,omitempty"`
+	Duration  string                 `json:"duration,omitempty"`
+}
+
+func (r *alertMuteRequest) toAlertMute(now time.Time) (entities.AlertMute, error) {
+	var expiresAt int64
+	if r.Duration != "" {
+		d, err := time.ParseDuration(r.Duration)
+		if err != nil {
+			return entities.AlertMute{}, errors.Wrap(err, "invalid duration")
+		}
+		if d <= 0 {
+			return entities.AlertMute{}, errors.New("duration must be positive")
+		}
+		expiresAt = entities.ExpiresAfter(now, d)
+	}
+	if r.AlertID != nil {
+		return entities.NewAlertMuteByID(r.Kind, *r.AlertID, expiresAt), nil
+	}
+	node, err := entities.CheckAndUpdateURL(r.Node)
+	if err != nil {
+		return entities.AlertMute{}, errors.Wrap(err, "invalid node")
+	}
+	return entities.NewAlertMuteByNode(r.Kind, r.AlertName, node, expiresAt), nil
+}
+
+// alertMutes returns the active acks and silences of the alerts.
+func (a *API) alertMutes(w http.ResponseWriter, r *http.Request) {
+	a.writeJSON(w, r, alertMutesResponse{Mutes: a.mutes.Mutes()})
+}
+
+// putAlertMute acks or silences the alerts.
+func (a *API) putAlertMute(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, alertMuteRequestLimit))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req := new(alertMuteRequest)
+	if err = json.Unmarshal(body, req); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode alert mute: %v", err), http.StatusBadRequest)
+		return
+	}
+	mute, err := req.toAlertMute(time.Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid alert mute: %v", err), http.StatusBadRequest)
+		return
+	}
+	mute, err = a.mutes.PutMute(mute)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid alert mute: %v", err), http.StatusBadRequest)
+		return
+	}
+	a.writeJSON(w, r, mute)
+}
+
+// deleteAlertMute deletes the ack or silence with the given ID.
+func (a *API) deleteAlertMute(w http.ResponseWriter, r *http.Request) {
+	id, err := crypto.NewDigestFromBase58(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid alert mute ID: %v", err), http.StatusBadRequest)
+		return
+	}
+	if !a.mutes.DeleteMute(id) {
+		http.Error(w, "Alert mute not found", http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestActiveAlertsAndHistory(t *testing.T) {
+	al := alertlog.NewLog(alertlog.DefaultHistorySize, nil, zap.NewNop())
+	var (
+		active   = &entities.UnreachableAlert{Timestamp: 200, Node: "http://node-1.example.com"}
+		resolved = &entities.UnreachableAlert{Timestamp: 100, Node: "http://node-2.example.com"}
+	)
+	al.Put(resolved)
+	al.Put(active)
+	al.Put(&entities.AlertFixed{Timestamp: 150, Fixed: resolved})
+	h := (&API{alertsLog: al, zap: zap.NewNop()}).routes(zap.NewNop())
+
+	rec := doGet(t, h, "/alerts/active")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var activeResp activeAlertsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &activeResp))
+	require.Len(t, activeResp.Alerts, 1)
+	assert.Equal(t, active.ID(), activeResp.Alerts[0].ID)
+	assert.Equal(t, int64(200), activeResp.Alerts[0].OpenedAt)
+
+	rec = doGet(t, h, "/alerts/history?limit=10")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var historyResp alertsHistoryResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &historyResp))
+	require.Len(t, historyResp.Alerts, 1)
+	assert.Equal(t, resolved.ID(), historyResp.Alerts[0].ID)
+	assert.Equal(t, int64(100), historyResp.Alerts[0].OpenedAt)
+	assert.Equal(t, int64(150), historyResp.Alerts[0].ClosedAt)
+
+	rec = doGet(t, h, "/alerts/history?limit=-1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestAlertMutes(t *testing.T) {
+	mutes := storage.NewAlertsStorage(zap.NewNop())
+	h := (&API{mutes: mutes, zap: zap.NewNop()}).routes(zap.NewNop())
+
+	body := `{"kind":"silence","alert_name":"UnreachableAlert","node":"node-1.example.com","duration":"1h"}`
+	req := httptest.NewRequest(http.MethodPost, "/alerts/mutes", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var mute entities.AlertMute
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &mute))
+	assert.Equal(t, entities.SilenceAlertMuteKind, mute.Kind)
+	assert.Equal(t, "http://node-1.example.com", mute.Node)
+	assert.NotZero(t, mute.ExpiresAt)
+
+	rec = doGet(t, h, "/alerts/mutes")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var mutesResp alertMutesResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &mutesResp))
+	require.Len(t, mutesResp.Mutes, 1)
+	assert.Equal(t, mute.ID, mutesResp.Mutes[0].ID)
+
+	req = httptest.NewRequest(http.MethodPost, "/alerts/mutes", strings.NewReader(`{"kind":"silence"}`))
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodDelete, "/alerts/mutes/"+mute.ID.String(), nil)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, mutes.Mutes())
+}
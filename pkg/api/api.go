@@ -10,11 +10,15 @@ import (
 	"io"
 	"net"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
 	"nodemon/internal"
+	"nodemon/pkg/analysis/forks"
+	"nodemon/pkg/analysis/storage"
 	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
 	"nodemon/pkg/storing/events"
 	"nodemon/pkg/storing/nodes"
 	"nodemon/pkg/storing/specific"
@@ -37,8 +41,13 @@ type API struct {
 	srv                *http.Server
 	nodesStorage       nodes.Storage
 	eventsStorage      *events.Storage
+	alertsLog          *alertlog.Log
+	mutes              storage.AlertMutes
+	forks              *forks.Reporter
 	zap                *zap.Logger
 	privateNodesEvents specific.PrivateNodesEventsWriter
+	pollingInterval    time.Duration
+	maxPollingInterval func() time.Duration
 	atom               *zap.AtomicLevel
 }
 
@@ -48,23 +57,33 @@ func (m mwLog) Print(v ...interface{}) { m.Sugar().Info(v...) }
 
 func (m mwLog) Println(v ...interface{}) { m.Sugar().Infoln(v...) }
 
+// Network is the set of the storages of one monitored blockchain network served by the API.
+type Network struct {
+	Scheme             string
+	NodesStorage       nodes.Storage
+	EventsStorage      *events.Storage
+	AlertsLog          *alertlog.Log
+	Mutes              storage.AlertMutes
+	Forks              *forks.Reporter
+	PrivateNodesEvents specific.PrivateNodesEventsWriter
+	PollingInterval    time.Duration        // interval of the polling rounds, zero if unknown
+	MaxPollingInterval func() time.Duration // limit of the node polling interval, nil means no limit
+}
+
+// NewAPI creates the API of the monitored networks. The routes of each network are served
+// under '/networks/{scheme}' prefix, the first network is also served without the prefix.
 func NewAPI(
 	bind string,
-	nodesStorage nodes.Storage,
-	eventsStorage *events.Storage,
+	networks []Network,
 	apiReadTimeout time.Duration,
 	logger *zap.Logger,
-	privateNodesEvents specific.PrivateNodesEventsWriter,
 	atom *zap.AtomicLevel,
 	development bool,
 ) (*API, error) {
-	a := &API{
-		nodesStorage:       nodesStorage,
-		eventsStorage:      eventsStorage,
-		zap:                logger,
-		privateNodesEvents: privateNodesEvents,
-		atom:               atom,
+	if len(networks) == 0 {
+		return nil, errors.New("no networks to serve")
 	}
+	a := newNetworkAPI(networks[0], atom, logger)
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
@@ -75,10 +94,36 @@ func NewAPI(
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.SetHeader("Content-Type", "application/json"))
 	r.Mount("/", a.routes(logger))
+	schemes := make(map[string]struct{}, len(networks))
+	for _, network := range networks {
+		if _, ok := schemes[network.Scheme]; ok {
+			return nil, errors.Errorf("duplicate network scheme '%s'", network.Scheme)
+		}
+		schemes[network.Scheme] = struct{}{}
+		na := newNetworkAPI(network, atom, logger)
+		nr := chi.NewRouter()
+		na.networkRoutes(nr)
+		r.Mount("/networks/"+url.PathEscape(network.Scheme), nr)
+	}
 	a.srv = &http.Server{Addr: bind, Handler: r, ReadHeaderTimeout: apiReadTimeout, ReadTimeout: apiReadTimeout}
 	return a, nil
 }
 
+func newNetworkAPI(network Network, atom *zap.AtomicLevel, logger *zap.Logger) *API {
+	return &API{
+		nodesStorage:       network.NodesStorage,
+		eventsStorage:      network.EventsStorage,
+		alertsLog:          network.AlertsLog,
+		mutes:              network.Mutes,
+		forks:              network.Forks,
+		zap:                logger,
+		privateNodesEvents: network.PrivateNodesEvents,
+		pollingInterval:    network.PollingInterval,
+		maxPollingInterval: network.MaxPollingInterval,
+		atom:               atom,
+	}
+}
+
 func (a *API) Start() error {
 	l, listenErr := net.Listen("tcp", a.srv.Addr)
 	if listenErr != nil {
@@ -104,16 +149,36 @@ func (a *API) Shutdown() {
 
 func (a *API) routes(logger *zap.Logger) chi.Router {
 	r := chi.NewRouter()
-	r.Get("/nodes/all", a.nodes)
-	r.Get("/nodes/enabled", a.enabled)
-	r.Post("/nodes/specific/statements", a.specificNodesHandler)
-	r.Get("/health", a.health)
+	a.networkRoutes(r)
 	r.Handle("/log/level", a.atom)
 	r.Handle("/metrics", tools.PrometheusHTTPMetricsHandler(mwLog{logger}))
 	r.Get("/version", internal.VersionHTTPHandler)
 	return r
 }
 
+// networkRoutes registers the routes which serve the storages of the network.
+func (a *API) networkRoutes(r chi.Router) {
+	r.Get("/nodes/all", a.nodes)
+	r.Get("/nodes/enabled", a.enabled)
+	r.Post("/nodes/specific/statements", a.specificNodesHandler)
+	r.Get("/nodes/{node}/statements", a.nodeStatements)
+	r.Get("/nodes/{node}/statehash/{height}", a.nodeStateHash)
+	r.Put("/nodes/{node}/maintenance", a.putNodeMaintenance)
+	r.Delete("/nodes/{node}/maintenance", a.deleteNodeMaintenance)
+	r.Put("/nodes/{node}/polling", a.putNodePolling)
+	r.Delete("/nodes/{node}/polling", a.deleteNodePolling)
+	r.Get("/statements", a.statementsByTimestamp)
+	r.Get("/generators", a.generators)
+	r.Get("/alerts/active", a.activeAlerts)
+	r.Get("/alerts/history", a.alertsHistory)
+	r.Get("/alerts/mutes", a.alertMutes)
+	r.Post("/alerts/mutes", a.putAlertMute)
+	r.Delete("/alerts/mutes/{id}", a.deleteAlertMute)
+	r.Get("/forks", a.forkReports)
+	r.Get("/forks/{id}", a.forkReport)
+	r.Get("/health", a.health)
+}
+
 func (a *API) health(w http.ResponseWriter, r *http.Request) {
 	enabledNodes, enErr := a.nodesStorage.EnabledNodes()
 	if enErr != nil {
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nodemon/pkg/storing/nodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNewAPI_Networks(t *testing.T) {
+	const (
+		mainnetNode = "http://mainnet.example.com"
+		testnetNode = "http://testnet.example.com"
+	)
+	newNetwork := func(scheme, node string) Network {
+		path := filepath.Join(t.TempDir(), scheme+".json")
+		ns, err := nodes.NewJSONFileStorage(path, []string{node}, zap.NewNop())
+		require.NoError(t, err)
+		return Network{Scheme: scheme, NodesStorage: ns}
+	}
+	networks := []Network{newNetwork("mainnet", mainnetNode), newNetwork("testnet", testnetNode)}
+	a, err := NewAPI(":0", networks, time.Second, zap.NewNop(), nil, false)
+	require.NoError(t, err)
+
+	nodesOf := func(target string) []string {
+		rec := httptest.NewRecorder()
+		a.srv.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var resp nodesResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		urls := make([]string, 0, len(resp.Regular))
+		for _, node := range resp.Regular {
+			urls = append(urls, node.URL)
+		}
+		return urls
+	}
+	assert.Equal(t, []string{mainnetNode}, nodesOf("/nodes/all"), "the first network is served without the prefix")
+	assert.Equal(t, []string{mainnetNode}, nodesOf("/networks/mainnet/nodes/all"))
+	assert.Equal(t, []string{testnetNode}, nodesOf("/networks/testnet/nodes/all"))
+
+	_, err = NewAPI(":0", append(networks, networks[0]), time.Second, zap.NewNop(), nil, false)
+	assert.ErrorContains(t, err, "duplicate network scheme 'mainnet'")
+}
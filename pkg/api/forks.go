@@ -0,0 +1,43 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"nodemon/pkg/entities"
+
+	"github.com/go-chi/chi"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+type forkReportsResponse struct {
+	Reports proto.NonNullableSlice[entities.ForkReport] `json:"reports"`
+	Limit   int                                         `json:"limit"`
+}
+
+// forkReports returns the recent fork reports, the most recently built first.
+func (a *API) forkReports(w http.ResponseWriter, r *http.Request) {
+	limit, _, err := parsePaging(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	reports := a.forks.Reports()
+	a.writeJSON(w, r, forkReportsResponse{Reports: reports[:min(limit, len(reports))], Limit: limit})
+}
+
+// forkReport returns the fork report of the state hash alert with the given ID.
+func (a *API) forkReport(w http.ResponseWriter, r *http.Request) {
+	id, err := crypto.NewDigestFromBase58(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid alert ID: %v", err), http.StatusBadRequest)
+		return
+	}
+	report, ok := a.forks.Report(id)
+	if !ok {
+		http.Error(w, "Fork report not found", http.StatusNotFound)
+		return
+	}
+	a.writeJSON(w, r, report)
+}
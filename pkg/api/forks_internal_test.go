@@ -0,0 +1,58 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"nodemon/pkg/analysis/forks"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestForkReports(t *testing.T) {
+	es, err := events.NewStorage(time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, es.Close()) })
+	reporter := forks.NewReporter(es, 0, 0, zap.NewNop())
+	a := &API{eventsStorage: es, forks: reporter, zap: zap.NewNop()}
+	h := a.routes(zap.NewNop())
+
+	rec := doGet(t, h, "/forks")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"reports":[],"limit":100}`, rec.Body.String())
+
+	alert := &entities.StateHashAlert{
+		Timestamp:                 1700000000,
+		CurrentGroupsBucketHeight: 10,
+		FirstGroup:                entities.StateHashGroup{Nodes: []string{"a"}},
+		SecondGroup:               entities.StateHashGroup{Nodes: []string{"b"}},
+	}
+	in := make(chan entities.Alert, 1)
+	in <- alert
+	close(in)
+	<-reporter.Run(in)
+
+	rec = doGet(t, h, "/forks/"+alert.ID().String())
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var report entities.ForkReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	assert.Equal(t, alert.ID(), report.AlertID)
+	assert.Len(t, report.Heights, forks.DefaultMaxHeights)
+
+	rec = doGet(t, h, "/forks?limit=1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var page forkReportsResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Len(t, page.Reports, 1)
+
+	rec = doGet(t, h, "/forks/"+(&entities.SimpleAlert{}).ID().String())
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	rec = doGet(t, h, "/forks/invalid")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
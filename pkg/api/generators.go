@@ -0,0 +1,23 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/middleware"
+	"go.uber.org/zap"
+)
+
+// generators returns the block production statistics of the generators over the statements history.
+func (a *API) generators(w http.ResponseWriter, r *http.Request) {
+	stats, err := a.eventsStorage.GeneratorsStats()
+	if err != nil {
+		a.zap.Error("[API] Failed to collect generators stats",
+			zap.Error(err),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, r, stats)
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+func TestGenerators(t *testing.T) {
+	rec := doGet(t, newTestStatementsRouter(t), "/generators")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.JSONEq(t, `{"since":0,"until":0,"blocks":0,"generators":[]}`, rec.Body.String())
+
+	generator, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, crypto.PublicKey{0x01})
+	require.NoError(t, err)
+	blockID := proto.NewBlockIDFromDigest(crypto.Digest{0x01})
+	h := newTestStatementsRouter(t,
+		entities.NewStateHashEvent("a", 1700000001, "", 10, nil, 1, &blockID, &generator, false),
+		entities.NewStateHashEvent("b", 1700000002, "", 10, nil, 1, &blockID, &generator, false),
+	)
+	rec = doGet(t, h, "/generators")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var stats entities.GeneratorsStats
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, entities.GeneratorsStats{
+		Since:  1700000001,
+		Until:  1700000002,
+		Blocks: 1,
+		Generators: []entities.GeneratorStats{
+			{Generator: generator.String(), Blocks: 1, LastBlockHeight: 10, LastBlockTime: 1700000001},
+		},
+	}, stats)
+}
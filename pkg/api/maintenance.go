@@ -0,0 +1,125 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const maintenanceRequestLimit = kb
+
+// maintenanceRequest is the body of the node maintenance request. The window is defined either by its bounds
+// in unix seconds or by its duration. Start defaults to the current time.
+type maintenanceRequest struct {
+	Start    int64  `json:"start,omitempty"`
+	End      int64  `json:"end,omitempty"`
+	Duration string `json:"duration,omitempty"`
+}
+
+func (r *maintenanceRequest) toWindow(now time.Time) (*entities.MaintenanceWindow, error) {
+	window := &entities.MaintenanceWindow{Start: r.Start, End: r.End}
+	if window.Start == 0 {
+		window.Start = now.Unix()
+	}
+	switch {
+	case r.Duration != "" && r.End != 0:
+		return nil, errors.New("either 'end' or 'duration' must be set, not both")
+	case r.Duration != "":
+		d, err := time.ParseDuration(r.Duration)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid duration")
+		}
+		window.End = time.Unix(window.Start, 0).Add(d).Unix()
+	case r.End == 0:
+		return nil, errors.New("either 'end' or 'duration' must be set")
+	}
+	if err := window.Validate(); err != nil {
+		return nil, err
+	}
+	return window, nil
+}
+
+func (a *API) nodeExists(url string) (bool, error) {
+	for _, specific := range []bool{false, true} {
+		nodes, err := a.nodesStorage.Nodes(specific)
+		if err != nil {
+			return false, err
+		}
+		for _, node := range nodes {
+			if node.URL == url {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// putNodeMaintenance puts the node under maintenance: its statements are still collected,
+// but the alerts about it are suppressed until the window ends.
+func (a *API) putNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maintenanceRequestLimit))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req := new(maintenanceRequest)
+	if err = json.Unmarshal(body, req); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode maintenance window: %v", err), http.StatusBadRequest)
+		return
+	}
+	window, err := req.toWindow(time.Now())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid maintenance window: %v", err), http.StatusBadRequest)
+		return
+	}
+	node, ok := a.setNodeMaintenance(w, r, window)
+	if !ok {
+		return
+	}
+	a.writeJSON(w, r, entities.Node{URL: node, Maintenance: window})
+}
+
+// deleteNodeMaintenance finishes the maintenance of the node.
+func (a *API) deleteNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	if _, ok := a.setNodeMaintenance(w, r, nil); ok {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (a *API) setNodeMaintenance(
+	w http.ResponseWriter,
+	r *http.Request,
+	window *entities.MaintenanceWindow,
+) (string, bool) {
+	node, err := parseNodeURLParam(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid node: %v", err), http.StatusBadRequest)
+		return "", false
+	}
+	exists, err := a.nodeExists(node)
+	if err == nil && exists {
+		err = a.nodesStorage.SetMaintenance(node, window)
+	}
+	if err != nil {
+		a.zap.Error("[API] Failed to set node maintenance",
+			zap.Error(err),
+			zap.String("node", node),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return "", false
+	}
+	if !exists {
+		http.Error(w, "Node not found", http.StatusNotFound)
+		return "", false
+	}
+	return node, true
+}
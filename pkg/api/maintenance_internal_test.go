@@ -0,0 +1,59 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/nodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNodeMaintenance(t *testing.T) {
+	const node = "http://node-1.example.com"
+	ns, err := nodes.NewJSONFileStorage(filepath.Join(t.TempDir(), "nodes.json"), []string{node}, zap.NewNop())
+	require.NoError(t, err)
+	h := (&API{nodesStorage: ns, zap: zap.NewNop()}).routes(zap.NewNop())
+
+	doRequest := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	target := "/nodes/" + url.PathEscape(node) + "/maintenance"
+
+	rec := doRequest(http.MethodPut, target, `{"start":1700000000,"duration":"2h"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp entities.Node
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	expected := &entities.MaintenanceWindow{Start: 1700000000, End: 1700007200}
+	assert.Equal(t, entities.Node{URL: node, Maintenance: expected}, resp)
+
+	stored, err := ns.Nodes(false)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, expected, stored[0].Maintenance)
+
+	rec = doRequest(http.MethodPut, target, `{"start":1700000000,"end":1600000000}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = doRequest(http.MethodPut, "/nodes/"+url.PathEscape("http://unknown.example.com")+"/maintenance",
+		`{"duration":"1h"}`,
+	)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(http.MethodDelete, target, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	stored, err = ns.Nodes(false)
+	require.NoError(t, err)
+	assert.Nil(t, stored[0].Maintenance)
+}
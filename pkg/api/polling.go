@@ -0,0 +1,124 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const pollingRequestLimit = kb
+
+// pollingRequest is the body of the node polling options request. Empty values mean the scraper defaults.
+type pollingRequest struct {
+	Interval string `json:"interval,omitempty"`
+	Timeout  string `json:"timeout,omitempty"`
+}
+
+// toOptions parses the polling options. The timeout is checked against the effective node interval, because
+// the round waits for all its polls, so the timeout longer than the round interval would stall the rounds.
+func (r *pollingRequest) toOptions(roundInterval, maxInterval time.Duration) (*entities.PollingOptions, error) {
+	parse := func(name, value string) (int64, error) {
+		if value == "" {
+			return 0, nil
+		}
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, errors.Wrapf(err, "invalid %s", name)
+		}
+		if d%time.Second != 0 {
+			return 0, errors.Errorf("%s must be a whole number of seconds", name)
+		}
+		return int64(d / time.Second), nil
+	}
+	interval, err := parse("interval", r.Interval)
+	if err != nil {
+		return nil, err
+	}
+	timeout, err := parse("timeout", r.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	opts := &entities.PollingOptions{Interval: interval, Timeout: timeout}
+	if validateErr := opts.Validate(); validateErr != nil {
+		return nil, validateErr
+	}
+	if validateErr := opts.ValidateInterval(maxInterval); validateErr != nil {
+		return nil, validateErr
+	}
+	if roundInterval > 0 && opts.TimeoutOr(0) > opts.IntervalOr(roundInterval) {
+		return nil, errors.Errorf("polling timeout must not be greater than the polling interval %s",
+			opts.IntervalOr(roundInterval),
+		)
+	}
+	return opts, nil
+}
+
+// putNodePolling overrides the polling interval and timeout of the node. The interval longer than the network
+// limit is rejected, otherwise the alerts about the node would be resolved between its polls.
+func (a *API) putNodePolling(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, pollingRequestLimit))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Failed to read body: %v", err), http.StatusBadRequest)
+		return
+	}
+	req := new(pollingRequest)
+	if err = json.Unmarshal(body, req); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to decode polling options: %v", err), http.StatusBadRequest)
+		return
+	}
+	var maxInterval time.Duration
+	if a.maxPollingInterval != nil {
+		maxInterval = a.maxPollingInterval()
+	}
+	opts, err := req.toOptions(a.pollingInterval, maxInterval)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid polling options: %v", err), http.StatusBadRequest)
+		return
+	}
+	node, ok := a.setNodePolling(w, r, opts)
+	if !ok {
+		return
+	}
+	a.writeJSON(w, r, entities.Node{URL: node, Polling: opts})
+}
+
+// deleteNodePolling restores the default polling options of the node.
+func (a *API) deleteNodePolling(w http.ResponseWriter, r *http.Request) {
+	if _, ok := a.setNodePolling(w, r, nil); ok {
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (a *API) setNodePolling(w http.ResponseWriter, r *http.Request, opts *entities.PollingOptions) (string, bool) {
+	node, err := parseNodeURLParam(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid node: %v", err), http.StatusBadRequest)
+		return "", false
+	}
+	exists, err := a.nodeExists(node)
+	if err == nil && exists {
+		err = a.nodesStorage.SetPolling(node, opts)
+	}
+	if err != nil {
+		a.zap.Error("[API] Failed to set node polling options",
+			zap.Error(err),
+			zap.String("node", node),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return "", false
+	}
+	if !exists {
+		http.Error(w, "Node not found", http.StatusNotFound)
+		return "", false
+	}
+	return node, true
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/nodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestNodePolling(t *testing.T) {
+	const node = "http://node-1.example.com"
+	ns, err := nodes.NewJSONFileStorage(filepath.Join(t.TempDir(), "nodes.json"), []string{node}, zap.NewNop())
+	require.NoError(t, err)
+	maxInterval := func() time.Duration { return 10 * time.Minute }
+	a := &API{nodesStorage: ns, pollingInterval: time.Minute, maxPollingInterval: maxInterval, zap: zap.NewNop()}
+	h := a.routes(zap.NewNop())
+
+	doRequest := func(method, target, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+	target := "/nodes/" + url.PathEscape(node) + "/polling"
+
+	rec := doRequest(http.MethodPut, target, `{"interval":"5m","timeout":"30s"}`)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var resp entities.Node
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	expected := &entities.PollingOptions{Interval: 300, Timeout: 30}
+	assert.Equal(t, entities.Node{URL: node, Polling: expected}, resp)
+
+	stored, err := ns.Nodes(false)
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	assert.Equal(t, expected, stored[0].Polling)
+
+	rec = doRequest(http.MethodPut, target, `{"interval":"10s","timeout":"30s"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(http.MethodPut, target, `{"interval":"1500ms"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doRequest(http.MethodPut, target, `{"interval":"11m"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "interval longer than the limit must be rejected")
+	rec = doRequest(http.MethodPut, target, `{"timeout":"90s"}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "timeout longer than the default interval must be rejected")
+	rec = doRequest(http.MethodPut, target, `{"timeout":"60s"}`)
+	assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = doRequest(http.MethodDelete, target, "")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	stored, err = ns.Nodes(false)
+	require.NoError(t, err)
+	assert.Nil(t, stored[0].Polling)
+}
@@ -0,0 +1,222 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultStatementsPageLimit = 100
+	maxStatementsPageLimit     = 1000
+)
+
+type statementsPage struct {
+	Statements proto.NonNullableSlice[entities.NodeStatement] `json:"statements"`
+	Total      int                                            `json:"total"`
+	Limit      int                                            `json:"limit"`
+	Offset     int                                            `json:"offset"`
+}
+
+// pageCollector collects a page of items from the stream of items which satisfy the filter.
+type pageCollector struct {
+	limit  int
+	offset int
+	total  int
+	items  entities.NodeStatements
+}
+
+func (p *pageCollector) add(statement *entities.NodeStatement) {
+	if p.total >= p.offset && len(p.items) < p.limit {
+		p.items = append(p.items, *statement)
+	}
+	p.total++
+}
+
+func (p *pageCollector) page() statementsPage {
+	return statementsPage{
+		Statements: proto.NonNullableSlice[entities.NodeStatement](p.items),
+		Total:      p.total,
+		Limit:      p.limit,
+		Offset:     p.offset,
+	}
+}
+
+func parseQueryInt64(r *http.Request, name string, defaultValue int64) (int64, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return defaultValue, nil
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid query parameter %q", name)
+	}
+	return v, nil
+}
+
+func parsePaging(r *http.Request) (int, int, error) {
+	limit, err := parseQueryInt64(r, "limit", defaultStatementsPageLimit)
+	if err != nil {
+		return 0, 0, err
+	}
+	if limit <= 0 || limit > maxStatementsPageLimit {
+		return 0, 0, errors.Errorf("query parameter 'limit' must be in range [1, %d]", maxStatementsPageLimit)
+	}
+	offset, err := parseQueryInt64(r, "offset", 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	if offset < 0 {
+		return 0, 0, errors.New("query parameter 'offset' must be non-negative")
+	}
+	if offset > math.MaxInt32 {
+		return 0, 0, errors.Errorf("query parameter 'offset' must not be greater than %d", math.MaxInt32)
+	}
+	return int(limit), int(offset), nil
+}
+
+func parseNodeURLParam(r *http.Request) (string, error) {
+	// node URL is expected to be escaped, e.g. 'https:%2F%2Fnode.example.com'
+	rawNode, err := url.PathUnescape(chi.URLParam(r, "node"))
+	if err != nil {
+		return "", errors.Wrap(err, "failed to unescape node URL")
+	}
+	if rawNode == "" {
+		return "", errors.New("empty node URL")
+	}
+	return entities.CheckAndUpdateURL(rawNode)
+}
+
+func (a *API) writeJSON(w http.ResponseWriter, r *http.Request, v any) {
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		a.zap.Error("[API] Failed to marshal response",
+			zap.Error(err),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to marshal response to JSON: %v", err), http.StatusInternalServerError)
+	}
+}
+
+// nodeStatements returns statements of the node in the [from, to] timestamps range, the newest first.
+func (a *API) nodeStatements(w http.ResponseWriter, r *http.Request) {
+	node, err := parseNodeURLParam(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid node: %v", err), http.StatusBadRequest)
+		return
+	}
+	from, err := parseQueryInt64(r, "from", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	to, err := parseQueryInt64(r, "to", math.MaxInt64)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if from > to {
+		http.Error(w, "Query parameter 'from' is greater than 'to'", http.StatusBadRequest)
+		return
+	}
+	limit, offset, err := parsePaging(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pc := &pageCollector{limit: limit, offset: offset}
+	err = a.eventsStorage.ViewStatementsByNodeWithDescendKeys(node, func(statement *entities.NodeStatement) bool {
+		ts := statement.Timestamp
+		if ts < from {
+			return false // statements are sorted by timestamp in descending order
+		}
+		if ts <= to {
+			pc.add(statement)
+		}
+		return true
+	})
+	if err != nil {
+		a.zap.Error("[API] Failed to fetch node statements from storage",
+			zap.Error(err),
+			zap.String("node", node),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, r, pc.page())
+}
+
+// statementsByTimestamp returns statements of all nodes collected at the given timestamp.
+func (a *API) statementsByTimestamp(w http.ResponseWriter, r *http.Request) {
+	if !r.URL.Query().Has("timestamp") {
+		http.Error(w, "Query parameter 'timestamp' is required", http.StatusBadRequest)
+		return
+	}
+	timestamp, err := parseQueryInt64(r, "timestamp", 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	limit, offset, err := parsePaging(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pc := &pageCollector{limit: limit, offset: offset}
+	err = a.eventsStorage.ViewStatementsByTimestamp(timestamp, func(statement *entities.NodeStatement) bool {
+		pc.add(statement)
+		return true
+	})
+	if err != nil {
+		a.zap.Error("[API] Failed to fetch statements by timestamp from storage",
+			zap.Error(err),
+			zap.Int64("timestamp", timestamp),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, r, pc.page())
+}
+
+// nodeStateHash returns the full statement of the node at the given height.
+func (a *API) nodeStateHash(w http.ResponseWriter, r *http.Request) {
+	node, err := parseNodeURLParam(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid node: %v", err), http.StatusBadRequest)
+		return
+	}
+	height, err := strconv.ParseUint(chi.URLParam(r, "height"), 10, 64)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid height: %v", err), http.StatusBadRequest)
+		return
+	}
+	statement, err := a.eventsStorage.GetFullStatementAtHeight(node, height)
+	if err != nil {
+		if errors.Is(err, events.ErrNoFullStatement) {
+			http.Error(w, fmt.Sprintf("Statement not found: %v", err), http.StatusNotFound)
+			return
+		}
+		a.zap.Error("[API] Failed to fetch full node statement from storage",
+			zap.Error(err),
+			zap.String("node", node),
+			zap.Uint64("height", height),
+			zap.String("request-id", middleware.GetReqID(r.Context())),
+		)
+		http.Error(w, fmt.Sprintf("Failed to complete request: %v", err), http.StatusInternalServerError)
+		return
+	}
+	a.writeJSON(w, r, statement)
+}
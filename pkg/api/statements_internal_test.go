@@ -0,0 +1,95 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestStatementsRouter(t *testing.T, statements ...entities.Event) http.Handler {
+	es, err := events.NewStorage(time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, es.Close()) })
+	for _, st := range statements {
+		require.NoError(t, es.PutEvent(st))
+	}
+	a := &API{eventsStorage: es, zap: zap.NewNop()}
+	return a.routes(zap.NewNop())
+}
+
+func doGet(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestNodeStatementsPaging(t *testing.T) {
+	const node = "http://node-1.example.com"
+	var statements []entities.Event
+	for ts := int64(1700000001); ts <= 1700000005; ts++ {
+		statements = append(statements, entities.NewUnreachableEvent(node, ts))
+	}
+	h := newTestStatementsRouter(t, statements...)
+
+	escaped := url.PathEscape(node)
+	rec := doGet(t, h, "/nodes/"+escaped+"/statements?from=1700000002&to=1700000004&limit=2&offset=1")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	var page statementsPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 3, page.Total)
+	assert.Equal(t, 2, page.Limit)
+	assert.Equal(t, 1, page.Offset)
+	require.Len(t, page.Statements, 2)
+	assert.Equal(t, int64(1700000003), page.Statements[0].Timestamp)
+	assert.Equal(t, int64(1700000002), page.Statements[1].Timestamp)
+
+	rec = doGet(t, h, "/nodes/"+escaped+"/statements?limit=0")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = doGet(t, h, "/nodes/"+escaped+"/statements?offset=-1")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "must be non-negative")
+	rec = doGet(t, h, "/nodes/"+escaped+"/statements?offset=2147483648")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "must not be greater than 2147483647")
+}
+
+func TestStatementsByTimestamp(t *testing.T) {
+	h := newTestStatementsRouter(t,
+		entities.NewUnreachableEvent("http://a.example.com", 100),
+		entities.NewUnreachableEvent("http://b.example.com", 100),
+		entities.NewUnreachableEvent("http://b.example.com", 200),
+	)
+	rec := doGet(t, h, "/statements?timestamp=100")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var page statementsPage
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &page))
+	assert.Equal(t, 2, page.Total)
+	assert.Len(t, page.Statements, 2)
+
+	rec = doGet(t, h, "/statements")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestNodeStateHashNotFound(t *testing.T) {
+	const node = "http://node-1.example.com"
+	h := newTestStatementsRouter(t,
+		entities.NewHeightEvent(node, 100, "v1.5.0", 10),
+	)
+	rec := doGet(t, h, "/nodes/"+url.PathEscape(node)+"/statehash/10")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doGet(t, h, "/nodes/"+url.PathEscape(node)+"/statehash/abc")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,102 @@
+package entities
+
+import (
+	"bytes"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+type AlertMuteKind string
+
+const (
+	// AckAlertMuteKind mutes the matching alerts until they are resolved or the ack expires.
+	AckAlertMuteKind AlertMuteKind = "ack"
+	// SilenceAlertMuteKind mutes the matching alerts, including the future ones, until the silence expires.
+	SilenceAlertMuteKind AlertMuteKind = "silence"
+)
+
+// AlertMute stops sending of the matching alerts. An alert is matched either by its ID
+// or by its name and one of the nodes it's related to. Muted alerts are still tracked by nodemon.
+type AlertMute struct {
+	ID        crypto.Digest  `json:"id"`
+	Kind      AlertMuteKind  `json:"kind"`
+	AlertID   *crypto.Digest `json:"alert_id,omitempty"`
+	AlertName AlertName      `json:"alert_name,omitempty"`
+	Node      string         `json:"node,omitempty"`
+	CreatedAt int64          `json:"created_at"`
+	ExpiresAt int64          `json:"expires_at,omitempty"` // zero value means that mute never expires
+}
+
+func NewAlertMuteByID(kind AlertMuteKind, alertID crypto.Digest, expiresAt int64) AlertMute {
+	m := AlertMute{Kind: kind, AlertID: &alertID, ExpiresAt: expiresAt}
+	m.ID = m.key()
+	return m
+}
+
+func NewAlertMuteByNode(kind AlertMuteKind, alertName AlertName, node string, expiresAt int64) AlertMute {
+	m := AlertMute{Kind: kind, AlertName: alertName, Node: node, ExpiresAt: expiresAt}
+	m.ID = m.key()
+	return m
+}
+
+// ExpiresAfter returns the expiration timestamp for the mute with the given duration.
+// Zero duration means that mute never expires.
+func ExpiresAfter(now time.Time, d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return now.Add(d).Unix()
+}
+
+func (m AlertMute) key() crypto.Digest {
+	var buf bytes.Buffer
+	buf.WriteString(string(m.Kind))
+	if m.AlertID != nil {
+		buf.Write(m.AlertID[:])
+	} else {
+		buf.WriteString(m.AlertName.String())
+		buf.WriteString(m.Node)
+	}
+	return crypto.MustFastHash(buf.Bytes())
+}
+
+func (m AlertMute) Validate() error {
+	switch m.Kind {
+	case AckAlertMuteKind, SilenceAlertMuteKind:
+	default:
+		return errors.Errorf("unknown alert mute kind %q", m.Kind)
+	}
+	if m.AlertID != nil {
+		if m.AlertName != "" || m.Node != "" {
+			return errors.New("alert mute must have either alert ID or alert name and node, not both")
+		}
+	} else {
+		alertType, ok := m.AlertName.AlertType()
+		if !ok || alertType == AlertFixedType {
+			return errors.Errorf("invalid alert name %q", m.AlertName)
+		}
+		if m.Node == "" {
+			return errors.New("empty node of the alert mute")
+		}
+	}
+	if m.ID != m.key() {
+		return errors.Errorf("invalid alert mute ID %q", m.ID.String())
+	}
+	return nil
+}
+
+// Expired checks whether the mute is expired at the given unix timestamp.
+func (m AlertMute) Expired(ts int64) bool {
+	return m.ExpiresAt != 0 && ts >= m.ExpiresAt
+}
+
+// Matches checks whether the alert is muted by this mute.
+func (m AlertMute) Matches(alert Alert) bool {
+	if m.AlertID != nil {
+		return *m.AlertID == alert.ID()
+	}
+	return m.AlertName == alert.Name() && slices.Contains(AlertNodes(alert), m.Node)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -27,21 +28,31 @@ const (
 	InternalErrorAlertType
 	ChallengedBlockAlertType
 	L2StuckAlertType
+	MaintenanceEndedAlertType
+	VersionMismatchAlertType
+	ChainStuckAlertType
+	MissingGeneratorAlertType
+	AlertGroupType
 )
 
 func GetAllAlertTypesAndNames() map[AlertType]AlertName {
 	return map[AlertType]AlertName{
-		SimpleAlertType:          SimpleAlertName,
-		UnreachableAlertType:     UnreachableAlertName,
-		IncompleteAlertType:      IncompleteAlertName,
-		InvalidHeightAlertType:   InvalidHeightAlertName,
-		HeightAlertType:          HeightAlertName,
-		StateHashAlertType:       StateHashAlertName,
-		AlertFixedType:           AlertFixedName,
-		BaseTargetAlertType:      BaseTargetAlertName,
-		InternalErrorAlertType:   InternalErrorName,
-		ChallengedBlockAlertType: ChallengedBlockAlertName,
-		L2StuckAlertType:         L2StuckAlertName,
+		SimpleAlertType:           SimpleAlertName,
+		UnreachableAlertType:      UnreachableAlertName,
+		IncompleteAlertType:       IncompleteAlertName,
+		InvalidHeightAlertType:    InvalidHeightAlertName,
+		HeightAlertType:           HeightAlertName,
+		StateHashAlertType:        StateHashAlertName,
+		AlertFixedType:            AlertFixedName,
+		BaseTargetAlertType:       BaseTargetAlertName,
+		InternalErrorAlertType:    InternalErrorName,
+		ChallengedBlockAlertType:  ChallengedBlockAlertName,
+		L2StuckAlertType:          L2StuckAlertName,
+		MaintenanceEndedAlertType: MaintenanceEndedAlertName,
+		VersionMismatchAlertType:  VersionMismatchAlertName,
+		ChainStuckAlertType:       ChainStuckAlertName,
+		MissingGeneratorAlertType: MissingGeneratorAlertName,
+		AlertGroupType:            AlertGroupName,
 	}
 }
 
@@ -70,6 +81,16 @@ func (t AlertType) AlertName() (AlertName, bool) {
 		alertName = ChallengedBlockAlertName
 	case L2StuckAlertType:
 		alertName = L2StuckAlertName
+	case MaintenanceEndedAlertType:
+		alertName = MaintenanceEndedAlertName
+	case VersionMismatchAlertType:
+		alertName = VersionMismatchAlertName
+	case ChainStuckAlertType:
+		alertName = ChainStuckAlertName
+	case MissingGeneratorAlertType:
+		alertName = MissingGeneratorAlertName
+	case AlertGroupType:
+		alertName = AlertGroupName
 	default:
 		return alertName, false
 	}
@@ -84,17 +105,22 @@ func (t AlertType) Exist() bool {
 type AlertName string
 
 const (
-	SimpleAlertName          AlertName = "SimpleAlert"
-	UnreachableAlertName     AlertName = "UnreachableAlert"
-	IncompleteAlertName      AlertName = "IncompleteAlert"
-	InvalidHeightAlertName   AlertName = "InvalidHeightAlert"
-	HeightAlertName          AlertName = "HeightAlert"
-	StateHashAlertName       AlertName = "StateHashAlert"
-	AlertFixedName           AlertName = "Resolved"
-	BaseTargetAlertName      AlertName = "BaseTargetAlert"
-	InternalErrorName        AlertName = "InternalErrorAlert"
-	ChallengedBlockAlertName AlertName = "ChallengedBlockAlert"
-	L2StuckAlertName         AlertName = "L2StuckAlert"
+	SimpleAlertName           AlertName = "SimpleAlert"
+	UnreachableAlertName      AlertName = "UnreachableAlert"
+	IncompleteAlertName       AlertName = "IncompleteAlert"
+	InvalidHeightAlertName    AlertName = "InvalidHeightAlert"
+	HeightAlertName           AlertName = "HeightAlert"
+	StateHashAlertName        AlertName = "StateHashAlert"
+	AlertFixedName            AlertName = "Resolved"
+	BaseTargetAlertName       AlertName = "BaseTargetAlert"
+	InternalErrorName         AlertName = "InternalErrorAlert"
+	ChallengedBlockAlertName  AlertName = "ChallengedBlockAlert"
+	L2StuckAlertName          AlertName = "L2StuckAlert"
+	MaintenanceEndedAlertName AlertName = "MaintenanceEndedAlert"
+	VersionMismatchAlertName  AlertName = "VersionMismatchAlert"
+	ChainStuckAlertName       AlertName = "ChainStuckAlert"
+	MissingGeneratorAlertName AlertName = "MissingGeneratorAlert"
+	AlertGroupName            AlertName = "AlertGroup"
 )
 
 func (n AlertName) AlertType() (AlertType, bool) {
@@ -122,6 +148,16 @@ func (n AlertName) AlertType() (AlertType, bool) {
 		alertType = ChallengedBlockAlertType
 	case L2StuckAlertName:
 		alertType = L2StuckAlertType
+	case MaintenanceEndedAlertName:
+		alertType = MaintenanceEndedAlertType
+	case VersionMismatchAlertName:
+		alertType = VersionMismatchAlertType
+	case ChainStuckAlertName:
+		alertType = ChainStuckAlertType
+	case MissingGeneratorAlertName:
+		alertType = MissingGeneratorAlertType
+	case AlertGroupName:
+		alertType = AlertGroupType
 	default:
 		return alertType, false
 	}
@@ -349,6 +385,8 @@ type StateHashAlert struct {
 	LastCommonStateHash proto.StateHash `json:"last_common_state_hash"`
 	FirstGroup          StateHashGroup  `json:"first_group"`
 	SecondGroup         StateHashGroup  `json:"second_group"`
+	// Fork is the block-by-block comparison of the groups, it's attached after the analysis and can be nil.
+	Fork *ForkReport `json:"fork,omitempty"`
 }
 
 func (a *StateHashAlert) Name() AlertName {
@@ -356,6 +394,10 @@ func (a *StateHashAlert) Name() AlertName {
 }
 
 func (a *StateHashAlert) Message() string {
+	return a.message() + a.forkMessage()
+}
+
+func (a *StateHashAlert) message() string {
 	if a.LastCommonStateHashExist {
 		return fmt.Sprintf(
 			"Nodes have different statehashes at the same height %d\n\n"+
@@ -388,6 +430,18 @@ func (a *StateHashAlert) Message() string {
 	)
 }
 
+// forkMessage describes the attached fork report, it's empty if there's no report.
+func (a *StateHashAlert) forkMessage() string {
+	if a.Fork == nil || a.Fork.ForkHeight == 0 {
+		return ""
+	}
+	fields := "none"
+	if len(a.Fork.DivergedFields) != 0 {
+		fields = strings.Join(a.Fork.DivergedFields, ", ")
+	}
+	return fmt.Sprintf("\n\nState hashes diverged at height %d, diverged fields: %s", a.Fork.ForkHeight, fields)
+}
+
 func (a *StateHashAlert) Time() time.Time {
 	return time.Unix(a.Timestamp, 0)
 }
@@ -445,39 +499,61 @@ func (a *AlertFixed) UnmarshalJSON(msg []byte) error {
 	if err := json.Unmarshal(msg, &descriptor); err != nil {
 		return errors.Wrapf(err, "failed to unrmarshal alert type descriptor")
 	}
+	t := descriptor.FixedAlertType
+	if t == AlertFixedType {
+		return errors.Errorf("nested fixed alerts (%d) are not allowed", t)
+	}
+	fixed, err := NewAlertByType(t)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal alert fixed")
+	}
 	type shadowed AlertFixed
-	var out shadowed
-	switch t := descriptor.FixedAlertType; t {
+	out := shadowed{Fixed: fixed}
+	if err := json.Unmarshal(msg, &out); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal")
+	}
+	*a = AlertFixed(out)
+	return nil
+}
+
+// NewAlertByType returns the empty alert of the given type, e.g. to unmarshal the alert into it.
+func NewAlertByType(t AlertType) (Alert, error) {
+	switch t {
 	case SimpleAlertType:
-		out.Fixed = &SimpleAlert{}
+		return &SimpleAlert{}, nil
 	case UnreachableAlertType:
-		out.Fixed = &UnreachableAlert{}
+		return &UnreachableAlert{}, nil
 	case IncompleteAlertType:
-		out.Fixed = &IncompleteAlert{}
+		return &IncompleteAlert{}, nil
 	case InvalidHeightAlertType:
-		out.Fixed = &InvalidHeightAlert{}
+		return &InvalidHeightAlert{}, nil
 	case HeightAlertType:
-		out.Fixed = &HeightAlert{}
+		return &HeightAlert{}, nil
 	case StateHashAlertType:
-		out.Fixed = &StateHashAlert{}
+		return &StateHashAlert{}, nil
 	case BaseTargetAlertType:
-		out.Fixed = &BaseTargetAlert{}
+		return &BaseTargetAlert{}, nil
 	case InternalErrorAlertType:
-		out.Fixed = &InternalErrorAlert{}
+		return &InternalErrorAlert{}, nil
 	case ChallengedBlockAlertType:
-		out.Fixed = &ChallengedBlockAlert{}
+		return &ChallengedBlockAlert{}, nil
 	case L2StuckAlertType:
-		out.Fixed = &L2StuckAlert{}
+		return &L2StuckAlert{}, nil
+	case MaintenanceEndedAlertType:
+		return &MaintenanceEndedAlert{}, nil
+	case VersionMismatchAlertType:
+		return &VersionMismatchAlert{}, nil
+	case ChainStuckAlertType:
+		return &ChainStuckAlert{}, nil
+	case MissingGeneratorAlertType:
+		return &MissingGeneratorAlert{}, nil
+	case AlertGroupType:
+		return &AlertGroup{}, nil
 	case AlertFixedType:
-		return errors.Errorf("nested fixed alerts (%d) are not allowed", t)
+		return &AlertFixed{}, nil
 	default:
-		return errors.Errorf("failed to unmarshal alert fixed, unknown internal alert type (%d)", t)
-	}
-	if err := json.Unmarshal(msg, &out); err != nil {
-		return errors.Wrapf(err, "failed to unmarshal")
+		return nil, errors.Errorf("unknown alert type (%d)", t)
 	}
-	*a = AlertFixed(out)
-	return nil
 }
 
 func (a *AlertFixed) Name() AlertName {
@@ -687,3 +763,369 @@ func (a *L2StuckAlert) Type() AlertType {
 func (a *L2StuckAlert) Level() string {
 	return ErrorLevel
 }
+
+// MaintenanceEndedAlert is the summary of the node's maintenance window which has ended.
+type MaintenanceEndedAlert struct {
+	Timestamp         int64      `json:"timestamp"`
+	Node              string     `json:"node"`
+	Start             int64      `json:"start"`
+	End               int64      `json:"end"`
+	StatementsCount   int        `json:"statements_count"`
+	UnreachableCount  int        `json:"unreachable_count"`
+	LastStatus        NodeStatus `json:"last_status,omitempty"`
+	LastHeight        uint64     `json:"last_height,omitempty"`
+	LastVersion       string     `json:"last_version,omitempty"`
+	LastStatementTime int64      `json:"last_statement_time,omitempty"`
+}
+
+func (a *MaintenanceEndedAlert) Name() AlertName {
+	return MaintenanceEndedAlertName
+}
+
+func (a *MaintenanceEndedAlert) Message() string {
+	msg := fmt.Sprintf("Maintenance of node %s from %s to %s has ended: %d statements, %d unreachable",
+		a.Node,
+		time.Unix(a.Start, 0).UTC().Format(time.DateTime),
+		time.Unix(a.End, 0).UTC().Format(time.DateTime),
+		a.StatementsCount, a.UnreachableCount,
+	)
+	if a.LastStatus != "" {
+		msg += fmt.Sprintf("; last status %s, height %d, version %s", a.LastStatus, a.LastHeight, a.LastVersion)
+	}
+	return msg
+}
+
+func (a *MaintenanceEndedAlert) Time() time.Time {
+	return time.Unix(a.Timestamp, 0)
+}
+
+func (a *MaintenanceEndedAlert) String() string {
+	return fmt.Sprintf("%s: %s", a.Name(), a.Message())
+}
+
+func (a *MaintenanceEndedAlert) ID() crypto.Digest {
+	var buff bytes.Buffer
+	buff.WriteString(a.Name().String())
+	buff.WriteString(a.Node)
+	buff.WriteString(strconv.FormatInt(a.Start, 10))
+	digest := crypto.MustFastHash(buff.Bytes())
+	return digest
+}
+
+func (a *MaintenanceEndedAlert) Type() AlertType {
+	return MaintenanceEndedAlertType
+}
+
+func (a *MaintenanceEndedAlert) Level() string {
+	return InfoLevel
+}
+
+type VersionMismatchKind string
+
+const (
+	// VersionDrift means that the nodes run different versions for longer than the grace period.
+	VersionDrift VersionMismatchKind = "drift"
+	// VersionBelowMinimum means that the node runs the version which is lower than the required minimum.
+	VersionBelowMinimum VersionMismatchKind = "below_minimum"
+)
+
+type VersionGroup struct {
+	Version string `json:"version"`
+	Nodes   Nodes  `json:"nodes"`
+}
+
+// VersionMismatchAlert is either the version drift between the nodes or the node running an outdated version,
+// depending on the Kind. Groups and Since are set for the drift, Node, Version and MinVersion for the outdated node.
+type VersionMismatchAlert struct {
+	Timestamp  int64               `json:"timestamp"`
+	Kind       VersionMismatchKind `json:"kind"`
+	Groups     []VersionGroup      `json:"groups,omitempty"`
+	Since      int64               `json:"since,omitempty"`
+	Node       string              `json:"node,omitempty"`
+	Version    string              `json:"version,omitempty"`
+	MinVersion string              `json:"min_version,omitempty"`
+}
+
+func (a *VersionMismatchAlert) Name() AlertName {
+	return VersionMismatchAlertName
+}
+
+func (a *VersionMismatchAlert) Message() string {
+	if a.Kind == VersionBelowMinimum {
+		return fmt.Sprintf("Node %s runs version %s which is lower than the required minimum %s",
+			a.Node, a.Version, a.MinVersion,
+		)
+	}
+	msg := fmt.Sprintf("Nodes run different versions since %s",
+		time.Unix(a.Since, 0).UTC().Format(time.DateTime),
+	)
+	for _, group := range a.Groups {
+		msg += fmt.Sprintf("\n\nVersion %s:\n%s", group.Version, strings.Join(group.Nodes, "\n"))
+	}
+	return msg
+}
+
+func (a *VersionMismatchAlert) Time() time.Time {
+	return time.Unix(a.Timestamp, 0)
+}
+
+func (a *VersionMismatchAlert) String() string {
+	return fmt.Sprintf("%s: %s", a.Name(), a.Message())
+}
+
+func (a *VersionMismatchAlert) ID() crypto.Digest {
+	var buff bytes.Buffer
+	buff.WriteString(a.Name().String())
+	buff.WriteString(string(a.Kind))
+	if a.Kind == VersionBelowMinimum {
+		buff.WriteString(a.Node)
+		buff.WriteString(a.MinVersion)
+	}
+	digest := crypto.MustFastHash(buff.Bytes())
+	return digest
+}
+
+func (a *VersionMismatchAlert) Type() AlertType {
+	return VersionMismatchAlertType
+}
+
+func (a *VersionMismatchAlert) Level() string {
+	return WarnLevel
+}
+
+// ChainStuckAlert means that the max height of the nodes hasn't advanced since the Since timestamp.
+type ChainStuckAlert struct {
+	Timestamp int64  `json:"timestamp"`
+	Height    uint64 `json:"height"`
+	Since     int64  `json:"since"`
+}
+
+func (a *ChainStuckAlert) Name() AlertName {
+	return ChainStuckAlertName
+}
+
+func (a *ChainStuckAlert) Message() string {
+	return fmt.Sprintf("Blockchain is stuck at height %d for %s",
+		a.Height, time.Duration(a.Timestamp-a.Since)*time.Second,
+	)
+}
+
+func (a *ChainStuckAlert) Time() time.Time {
+	return time.Unix(a.Timestamp, 0)
+}
+
+func (a *ChainStuckAlert) String() string {
+	return fmt.Sprintf("%s: %s", a.Name(), a.Message())
+}
+
+func (a *ChainStuckAlert) ID() crypto.Digest {
+	digest := crypto.MustFastHash([]byte(a.Name().String() + strconv.FormatUint(a.Height, 10)))
+	return digest
+}
+
+func (a *ChainStuckAlert) Type() AlertType {
+	return ChainStuckAlertType
+}
+
+func (a *ChainStuckAlert) Level() string {
+	return ErrorLevel
+}
+
+// MissingGeneratorAlert means that the generator hasn't produced a block during the expected interval.
+// LastBlockHeight and LastBlockTime are zero if there are no blocks of the generator in the statements history.
+type MissingGeneratorAlert struct {
+	Timestamp       int64         `json:"timestamp"`
+	Generator       string        `json:"generator"`
+	Interval        time.Duration `json:"interval"`
+	LastBlockHeight uint64        `json:"last_block_height,omitempty"`
+	LastBlockTime   int64         `json:"last_block_time,omitempty"`
+}
+
+func (a *MissingGeneratorAlert) Name() AlertName {
+	return MissingGeneratorAlertName
+}
+
+func (a *MissingGeneratorAlert) Message() string {
+	msg := fmt.Sprintf("Generator %s hasn't produced blocks for %s", a.Generator, a.Interval)
+	if a.LastBlockHeight != 0 {
+		msg += fmt.Sprintf("; last block at height %d at %s", a.LastBlockHeight,
+			time.Unix(a.LastBlockTime, 0).UTC().Format(time.DateTime),
+		)
+	}
+	return msg
+}
+
+func (a *MissingGeneratorAlert) Time() time.Time {
+	return time.Unix(a.Timestamp, 0)
+}
+
+func (a *MissingGeneratorAlert) String() string {
+	return fmt.Sprintf("%s: %s", a.Name(), a.Message())
+}
+
+func (a *MissingGeneratorAlert) ID() crypto.Digest {
+	digest := crypto.MustFastHash([]byte(a.Name().String() + a.Generator))
+	return digest
+}
+
+func (a *MissingGeneratorAlert) Type() AlertType {
+	return MissingGeneratorAlertType
+}
+
+func (a *MissingGeneratorAlert) Level() string {
+	return WarnLevel
+}
+
+// AlertGroupMember is the alert which has been grouped into the AlertGroup.
+type AlertGroupMember struct {
+	ID      crypto.Digest `json:"id"`
+	Name    AlertName     `json:"name"`
+	Level   string        `json:"level"`
+	Message string        `json:"message"`
+	Nodes   []string      `json:"nodes,omitempty"`
+}
+
+func NewAlertGroupMember(alert Alert) AlertGroupMember {
+	return AlertGroupMember{
+		ID:      alert.ID(),
+		Name:    alert.Name(),
+		Level:   alert.Level(),
+		Message: alert.Message(),
+		Nodes:   AlertNodes(alert),
+	}
+}
+
+// AlertGroup is the composite alert which replaces the related alerts, e.g. the alerts about the same nodes
+// produced in the same polling round or the alerts suppressed during the alerts storm.
+// RootCause is the human-readable guess of the reason of the member alerts.
+type AlertGroup struct {
+	Timestamp int64              `json:"timestamp"`
+	Storm     bool               `json:"storm,omitempty"`
+	RootCause string             `json:"root_cause"`
+	Nodes     []string           `json:"nodes,omitempty"`
+	Alerts    []AlertGroupMember `json:"alerts"`
+}
+
+func (a *AlertGroup) Name() AlertName {
+	return AlertGroupName
+}
+
+func (a *AlertGroup) Message() string {
+	return fmt.Sprintf("%d related alerts: %s", len(a.Alerts), a.RootCause)
+}
+
+func (a *AlertGroup) Time() time.Time {
+	return time.Unix(a.Timestamp, 0)
+}
+
+func (a *AlertGroup) String() string {
+	return fmt.Sprintf("%s: %s", a.Name(), a.Message())
+}
+
+// ID depends only on the member alerts, so the same group has the same ID when its alerts are repeated.
+func (a *AlertGroup) ID() crypto.Digest {
+	ids := make([]crypto.Digest, 0, len(a.Alerts))
+	for _, m := range a.Alerts {
+		ids = append(ids, m.ID)
+	}
+	slices.SortFunc(ids, func(x, y crypto.Digest) int { return bytes.Compare(x[:], y[:]) })
+	var buff bytes.Buffer
+	buff.WriteString(a.Name().String())
+	for _, id := range ids {
+		buff.Write(id[:])
+	}
+	return crypto.MustFastHash(buff.Bytes())
+}
+
+func (a *AlertGroup) Type() AlertType {
+	return AlertGroupType
+}
+
+// FilterMembers returns the group with only the member alerts which match, its nodes are narrowed to the nodes
+// of these members. The group itself is returned if all members match and nil is returned if none does.
+func (a *AlertGroup) FilterMembers(match func(m AlertGroupMember) bool) *AlertGroup {
+	members := make([]AlertGroupMember, 0, len(a.Alerts))
+	nodes := make(map[string]struct{})
+	for _, m := range a.Alerts {
+		if !match(m) {
+			continue
+		}
+		members = append(members, m)
+		for _, node := range m.Nodes {
+			nodes[node] = struct{}{}
+		}
+	}
+	switch len(members) {
+	case 0:
+		return nil
+	case len(a.Alerts):
+		return a
+	}
+	filtered := *a
+	filtered.Alerts = members
+	filtered.Nodes = slices.DeleteFunc(slices.Clone(a.Nodes), func(node string) bool {
+		_, ok := nodes[node]
+		return !ok
+	})
+	return &filtered
+}
+
+// Level is the highest level of the member alerts.
+func (a *AlertGroup) Level() string {
+	level := InfoLevel
+	for _, m := range a.Alerts {
+		switch m.Level {
+		case ErrorLevel:
+			return ErrorLevel
+		case WarnLevel:
+			level = WarnLevel
+		}
+	}
+	return level
+}
+
+// AlertNodes returns the nodes which the alert is related to.
+func AlertNodes(alert Alert) []string {
+	switch a := alert.(type) {
+	case *UnreachableAlert:
+		return []string{a.Node}
+	case *IncompleteAlert:
+		return []string{a.Node}
+	case *InvalidHeightAlert:
+		return []string{a.Node}
+	case *HeightAlert:
+		return append(slices.Clone(a.MaxHeightGroup.Nodes), a.OtherHeightGroup.Nodes...)
+	case *StateHashAlert:
+		return append(slices.Clone(a.FirstGroup.Nodes), a.SecondGroup.Nodes...)
+	case *BaseTargetAlert:
+		out := make([]string, 0, len(a.BaseTargetValues))
+		for _, v := range a.BaseTargetValues {
+			out = append(out, v.Node)
+		}
+		return out
+	case *ChallengedBlockAlert:
+		return slices.Clone(a.Nodes)
+	case *L2StuckAlert:
+		return []string{a.L2Node}
+	case *MaintenanceEndedAlert:
+		return []string{a.Node}
+	case *VersionMismatchAlert:
+		if a.Kind == VersionBelowMinimum {
+			return []string{a.Node}
+		}
+		var out []string
+		for _, group := range a.Groups {
+			out = append(out, group.Nodes...)
+		}
+		return out
+	case *AlertGroup:
+		return slices.Clone(a.Nodes)
+	case *AlertFixed:
+		if a.Fixed == nil {
+			return nil
+		}
+		return AlertNodes(a.Fixed)
+	default:
+		return nil
+	}
+}
@@ -8,3 +8,8 @@ type Chat struct {
 	ChatID   ChatID   `json:"chatID"`
 	Platform Platform `json:"platform"`
 }
+
+const (
+	TelegramPlatform Platform = iota + 1
+	DiscordPlatform
+)
@@ -0,0 +1,67 @@
+package entities
+
+import (
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+)
+
+// ForkBlock is the block and the state hash of a group of nodes at some height.
+type ForkBlock struct {
+	Node      string              `json:"node"` // node of the group which statement is used
+	BlockID   proto.BlockID       `json:"block_id"`
+	StateHash crypto.Digest       `json:"state_hash"`
+	Fields    proto.FieldsHashes  `json:"fields"`
+	Generator *proto.WavesAddress `json:"generator,omitempty"`
+}
+
+// ForkHeight compares the groups of nodes at one height. The side is nil if none of the group nodes has
+// the full statement at the height.
+type ForkHeight struct {
+	Height         uint64     `json:"height"`
+	First          *ForkBlock `json:"first,omitempty"`
+	Second         *ForkBlock `json:"second,omitempty"`
+	DivergedFields []string   `json:"diverged_fields,omitempty"`
+}
+
+// Diverged reports whether the groups have different state hashes at the height.
+func (h ForkHeight) Diverged() bool {
+	return h.First != nil && h.Second != nil && h.First.StateHash != h.Second.StateHash
+}
+
+// ForkReport is the block-by-block comparison of two groups of nodes with diverged state hashes.
+// Heights are sorted in ascending order and start from the last common height if it's known.
+type ForkReport struct {
+	AlertID          crypto.Digest `json:"alert_id"`
+	Timestamp        int64         `json:"timestamp"`
+	LastCommonHeight uint64        `json:"last_common_height,omitempty"` // zero if there's no common state hash
+	ForkHeight       uint64        `json:"fork_height,omitempty"`        // first diverged height, zero if unknown
+	DivergedFields   []string      `json:"diverged_fields,omitempty"`    // fields diverged at the fork height
+	FirstGroup       []string      `json:"first_group"`
+	SecondGroup      []string      `json:"second_group"`
+	Heights          []ForkHeight  `json:"heights"`
+}
+
+// DivergedStateHashFields returns the names of the state hash fields which differ.
+func DivergedStateHashFields(a, b proto.FieldsHashes) []string {
+	fields := []struct {
+		name string
+		a, b crypto.Digest
+	}{
+		{"data_entry", a.DataEntryHash, b.DataEntryHash},
+		{"account_script", a.AccountScriptHash, b.AccountScriptHash},
+		{"asset_script", a.AssetScriptHash, b.AssetScriptHash},
+		{"lease_status", a.LeaseStatusHash, b.LeaseStatusHash},
+		{"sponsorship", a.SponsorshipHash, b.SponsorshipHash},
+		{"aliases", a.AliasesHash, b.AliasesHash},
+		{"waves_balance", a.WavesBalanceHash, b.WavesBalanceHash},
+		{"asset_balance", a.AssetBalanceHash, b.AssetBalanceHash},
+		{"lease_balance", a.LeaseBalanceHash, b.LeaseBalanceHash},
+	}
+	var out []string
+	for _, f := range fields {
+		if f.a != f.b {
+			out = append(out, f.name)
+		}
+	}
+	return out
+}
@@ -0,0 +1,28 @@
+package entities
+
+// GeneratorStats is the block production statistics of the generator.
+type GeneratorStats struct {
+	Generator       string `json:"generator"`
+	Blocks          int    `json:"blocks"`
+	LastBlockHeight uint64 `json:"last_block_height"`
+	LastBlockTime   int64  `json:"last_block_time"` // timestamp of the first statement with the last block
+}
+
+// GeneratorsStats is the block production statistics over the statements history in [Since, Until] range.
+// Generators are sorted by the number of produced blocks, the most productive first.
+type GeneratorsStats struct {
+	Since      int64            `json:"since"`
+	Until      int64            `json:"until"`
+	Blocks     int              `json:"blocks"`
+	Generators []GeneratorStats `json:"generators"`
+}
+
+// Generator returns the statistics of the generator with the given address.
+func (s GeneratorsStats) Generator(address string) (GeneratorStats, bool) {
+	for _, g := range s.Generators {
+		if g.Generator == address {
+			return g, true
+		}
+	}
+	return GeneratorStats{}, false
+}
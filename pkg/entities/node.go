@@ -2,7 +2,9 @@ package entities
 
 import (
 	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -13,9 +15,128 @@ const (
 )
 
 type Node struct {
-	URL     string `json:"url"`
-	Enabled bool   `json:"enabled"`
-	Alias   string `json:"alias"`
+	URL         string             `json:"url"`
+	Enabled     bool               `json:"enabled"`
+	Alias       string             `json:"alias"`
+	Maintenance *MaintenanceWindow `json:"maintenance,omitempty"`
+	// Tags are the groups of the node, e.g. "our-validators" or "public-api". The comparing criteria compare
+	// the nodes within the groups and the bot chats may receive only the alerts of the chosen groups.
+	Tags []string `json:"tags,omitempty"`
+	// Polling overrides the polling interval and timeout of the node, nil means the scraper defaults.
+	Polling *PollingOptions `json:"polling,omitempty"`
+}
+
+// HasTag checks whether the node belongs to the group.
+func (n Node) HasTag(tag string) bool {
+	return slices.Contains(n.Tags, tag)
+}
+
+const maxTagLength = 32
+
+// NormalizeTag converts the tag to the lower case and checks that it consists of latin letters, digits,
+// dashes and underscores.
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.ToLower(strings.TrimSpace(tag))
+	if normalized == "" || len(normalized) > maxTagLength {
+		return "", errors.Errorf("tag '%s' must be from 1 to %d characters long", tag, maxTagLength)
+	}
+	for _, r := range normalized {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '-' && r != '_' {
+			return "", errors.Errorf("tag '%s' must consist of latin letters, digits, '-' and '_'", tag)
+		}
+	}
+	return normalized, nil
+}
+
+// NormalizeTags normalizes the tags and returns them sorted and without duplicates.
+func NormalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		normalized, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, normalized)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+// PollingOptions are the polling interval and the requests timeout of the node in seconds.
+// Zero values mean the scraper defaults.
+type PollingOptions struct {
+	Interval int64 `json:"interval,omitempty"`
+	Timeout  int64 `json:"timeout,omitempty"`
+}
+
+// Validate checks the options themselves. The timeout of the node with the default interval has to be checked
+// against the scraper interval by the caller, which knows it.
+func (o *PollingOptions) Validate() error {
+	if o.Interval < 0 || o.Timeout < 0 {
+		return errors.New("polling interval and timeout must not be negative")
+	}
+	if o.Interval != 0 && o.Timeout > o.Interval {
+		return errors.New("polling timeout must not be greater than the interval")
+	}
+	return nil
+}
+
+// ValidateInterval checks that the polling interval isn't longer than maxInterval. Zero maxInterval means no limit.
+func (o *PollingOptions) ValidateInterval(maxInterval time.Duration) error {
+	if maxInterval > 0 && o.IntervalOr(0) > maxInterval {
+		return errors.Errorf("polling interval must not be longer than %s", maxInterval)
+	}
+	return nil
+}
+
+// MaxPollingInterval returns the longest node polling interval which keeps the alerts about the node active between
+// its polls. The alerts which aren't repeated for alertVacuumQuota rounds are resolved, so the node has to be polled
+// at least once per alertVacuumQuota-1 rounds. The interval of one round is always allowed.
+func MaxPollingInterval(roundInterval time.Duration, alertVacuumQuota int) time.Duration {
+	return time.Duration(max(alertVacuumQuota-1, 1)) * roundInterval
+}
+
+// IntervalOr returns the polling interval or the default one if it's not set. Nil options return the default.
+func (o *PollingOptions) IntervalOr(def time.Duration) time.Duration {
+	if o == nil || o.Interval == 0 {
+		return def
+	}
+	return time.Duration(o.Interval) * time.Second
+}
+
+// TimeoutOr returns the requests timeout or the default one if it's not set. Nil options return the default.
+func (o *PollingOptions) TimeoutOr(def time.Duration) time.Duration {
+	if o == nil || o.Timeout == 0 {
+		return def
+	}
+	return time.Duration(o.Timeout) * time.Second
+}
+
+// MaintenanceWindow is a period of time [Start, End) in unix seconds during which the node is still polled,
+// but alerts about it are suppressed.
+type MaintenanceWindow struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+func (w *MaintenanceWindow) Validate() error {
+	if w.Start <= 0 || w.End <= 0 {
+		return errors.New("maintenance window bounds must be positive")
+	}
+	if w.Start >= w.End {
+		return errors.New("maintenance window start must be before its end")
+	}
+	return nil
+}
+
+// Active checks whether the window is active at the given timestamp. Nil window is never active.
+func (w *MaintenanceWindow) Active(ts int64) bool {
+	return w != nil && w.Start <= ts && ts < w.End
+}
+
+// Ended checks whether the window has ended by the given timestamp. Nil window never ends.
+func (w *MaintenanceWindow) Ended(ts int64) bool {
+	return w != nil && ts >= w.End
 }
 
 func CheckAndUpdateURL(s string) (string, error) {
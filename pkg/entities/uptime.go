@@ -0,0 +1,10 @@
+package entities
+
+// UptimeBucket is the number of the node polls and alerts during the hour which starts at Start.
+type UptimeBucket struct {
+	Node    string `json:"node"`
+	Start   int64  `json:"start"`
+	Polls   int    `json:"polls"`
+	OKPolls int    `json:"ok_polls"`
+	Alerts  int    `json:"alerts"`
+}
@@ -11,4 +11,11 @@ const (
 	RequestDeleteNodeType
 	RequestNodesStatusType
 	RequestNodeStatementType
+	RequestAlertsType
+	RequestMuteAlertType
+	RequestNodeMaintenanceType
+	RequestGeneratorsType
+	RequestNodesSummaryType
+	RequestNodeTagsType
+	RequestForkReportType
 )
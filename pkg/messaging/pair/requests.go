@@ -1,5 +1,11 @@
 package pair
 
+import (
+	"nodemon/pkg/entities"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
 type Request interface {
 	requestMarker()
 	RequestType() RequestPairType
@@ -65,3 +71,59 @@ type NodeStatementRequest struct {
 func (r *NodeStatementRequest) RequestType() RequestPairType { return RequestNodeStatementType }
 
 func (*NodeStatementRequest) requestMarker() {}
+
+type AlertsRequest struct {
+	HistoryLimit int
+}
+
+func (r *AlertsRequest) RequestType() RequestPairType { return RequestAlertsType }
+
+func (*AlertsRequest) requestMarker() {}
+
+type MuteAlertRequest struct {
+	Mute entities.AlertMute
+}
+
+func (r *MuteAlertRequest) RequestType() RequestPairType { return RequestMuteAlertType }
+
+func (*MuteAlertRequest) requestMarker() {}
+
+type NodeMaintenanceRequest struct {
+	URL    string
+	Window *entities.MaintenanceWindow // nil window removes the maintenance
+}
+
+func (r *NodeMaintenanceRequest) RequestType() RequestPairType { return RequestNodeMaintenanceType }
+
+func (*NodeMaintenanceRequest) requestMarker() {}
+
+type GeneratorsRequest struct{}
+
+func (r *GeneratorsRequest) RequestType() RequestPairType { return RequestGeneratorsType }
+
+func (*GeneratorsRequest) requestMarker() {}
+
+type NodesSummaryRequest struct {
+	Since int64 // unix timestamp
+}
+
+func (r *NodesSummaryRequest) RequestType() RequestPairType { return RequestNodesSummaryType }
+
+func (*NodesSummaryRequest) requestMarker() {}
+
+type NodeTagsRequest struct {
+	URL  string
+	Tags []string // empty list removes all tags
+}
+
+func (r *NodeTagsRequest) RequestType() RequestPairType { return RequestNodeTagsType }
+
+func (*NodeTagsRequest) requestMarker() {}
+
+type ForkReportRequest struct {
+	AlertID crypto.Digest // ID of the state hash alert
+}
+
+func (r *ForkReportRequest) RequestType() RequestPairType { return RequestForkReportType }
+
+func (*ForkReportRequest) requestMarker() {}
@@ -2,6 +2,8 @@ package pair
 
 import (
 	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
+	"nodemon/pkg/storing/uptime"
 
 	"github.com/wavesplatform/gowaves/pkg/proto"
 )
@@ -22,12 +24,64 @@ type NodeStatementResponse struct {
 	ErrMessage    string                 `json:"err_message"`
 }
 
+type AlertsResponse struct {
+	Active     []alertlog.ActiveAlert   `json:"active"`
+	Resolved   []alertlog.ResolvedAlert `json:"resolved"`
+	ErrMessage string                   `json:"err_message"`
+}
+
+type MuteAlertResponse struct {
+	Mute       entities.AlertMute `json:"mute"`
+	ErrMessage string             `json:"err_message"`
+}
+
+type NodeMaintenanceResponse struct {
+	URL        string                      `json:"url"`
+	Window     *entities.MaintenanceWindow `json:"window,omitempty"`
+	ErrMessage string                      `json:"err_message"`
+}
+
+type GeneratorsResponse struct {
+	Stats      entities.GeneratorsStats `json:"stats"`
+	ErrMessage string                   `json:"err_message"`
+}
+
+type NodeTagsResponse struct {
+	URL        string   `json:"url"`
+	Tags       []string `json:"tags,omitempty"`
+	ErrMessage string   `json:"err_message"`
+}
+
+type ForkReportResponse struct {
+	Report     *entities.ForkReport `json:"report,omitempty"` // nil if the report isn't found
+	ErrMessage string               `json:"err_message"`
+}
+
+type NodesSummaryResponse struct {
+	Since int64                `json:"since"`
+	Nodes []uptime.NodeSummary `json:"nodes"`
+}
+
 func (nl *NodesListResponse) responseMarker() {}
 
 func (nl *NodesStatementsResponse) responseMarker() {}
 
 func (nl *NodeStatementResponse) responseMarker() {}
 
+func (ar *AlertsResponse) responseMarker() {}
+
+func (mr *MuteAlertResponse) responseMarker() {}
+
+func (mr *NodeMaintenanceResponse) responseMarker() {}
+
+func (gr *GeneratorsResponse) responseMarker() {}
+
+func (sr *NodesSummaryResponse) responseMarker() {}
+
+func (tr *NodeTagsResponse) responseMarker() {}
+
+func (fr *ForkReportResponse) responseMarker() {}
+
 type NodeStatement struct {
 	URL       string              `json:"url"`
 	StateHash *proto.StateHash    `json:"statehash"`
@@ -35,4 +89,5 @@ type NodeStatement struct {
 	Status    entities.NodeStatus `json:"status"`
 	BlockID   *proto.BlockID      `json:"block_id"`
 	Generator *proto.WavesAddress `json:"generator"`
+	Version   string              `json:"version,omitempty"`
 }
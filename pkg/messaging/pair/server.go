@@ -3,16 +3,22 @@ package pair
 import (
 	"context"
 	"encoding/json"
+	"strconv"
 	"strings"
 	"time"
 
+	"nodemon/pkg/analysis/forks"
+	"nodemon/pkg/analysis/storage"
 	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
 	"nodemon/pkg/storing/events"
 	"nodemon/pkg/storing/nodes"
 	"nodemon/pkg/storing/specific"
+	"nodemon/pkg/storing/uptime"
 
 	"github.com/nats-io/nats.go"
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"go.uber.org/zap"
 )
 
@@ -24,6 +30,10 @@ func StartPairMessagingServer(
 	ns nodes.Storage,
 	es *events.Storage,
 	pew specific.PrivateNodesEventsWriter,
+	al *alertlog.Log,
+	mutes storage.AlertMutes,
+	ut *uptime.Tracker,
+	fr *forks.Reporter,
 	logger *zap.Logger,
 	botRequestsTopic string,
 ) error {
@@ -39,7 +49,7 @@ func StartPairMessagingServer(
 	}
 
 	_, subErr := nc.Subscribe(botRequestsTopic, func(request *nats.Msg) {
-		response, handleErr := handleMessage(request.Data, ns, logger, es, pew)
+		response, handleErr := handleMessage(request.Data, ns, logger, es, pew, al, mutes, ut, fr)
 		if handleErr != nil {
 			logger.Error("failed to handle bot request", zap.Error(handleErr))
 			return
@@ -63,6 +73,10 @@ func handleMessage(
 	logger *zap.Logger,
 	es *events.Storage,
 	pew specific.PrivateNodesEventsWriter,
+	al *alertlog.Log,
+	mutes storage.AlertMutes,
+	ut *uptime.Tracker,
+	fr *forks.Reporter,
 ) ([]byte, error) {
 	if len(rawMsg) == 0 {
 		logger.Warn("empty raw message received from pair socket")
@@ -96,6 +110,48 @@ func handleMessage(
 	case RequestNodesStatusType, RequestNodeStatementType:
 		response := handleNodesStatementsRequest(msg, es, logger)
 		return response, nil
+	case RequestAlertsType:
+		response, err := handleAlertsRequest(msg, al, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestMuteAlertType:
+		response, err := handleMuteAlertRequest(msg, mutes, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestNodeMaintenanceType:
+		response, err := handleNodeMaintenanceRequest(msg, ns, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestGeneratorsType:
+		response, err := handleGeneratorsRequest(es, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestNodesSummaryType:
+		response, err := handleNodesSummaryRequest(msg, ut, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestNodeTagsType:
+		response, err := handleNodeTagsRequest(msg, ns, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
+	case RequestForkReportType:
+		response, err := handleForkReportRequest(msg, fr, logger)
+		if err != nil {
+			return nil, err
+		}
+		return response, nil
 	default:
 		logger.Error("Unknown request type", zap.Int("type", int(t)), zap.Binary("message", msg))
 	}
@@ -188,6 +244,7 @@ func handleNodesStatementsRequest(msg []byte, es *events.Storage, logger *zap.Lo
 			Status:    statement.Status,
 			BlockID:   statement.BlockID,
 			Generator: statement.Generator,
+			Version:   statement.Version,
 		}
 		nodesStatusResp.NodesStatements = append(nodesStatusResp.NodesStatements, nodeStat)
 	}
@@ -197,3 +254,132 @@ func handleNodesStatementsRequest(msg []byte, es *events.Storage, logger *zap.Lo
 	}
 	return response
 }
+
+func handleAlertsRequest(msg []byte, al *alertlog.Log, logger *zap.Logger) ([]byte, error) {
+	var response AlertsResponse
+	historyLimit, err := strconv.Atoi(string(msg))
+	if err != nil {
+		// the bot waits for the reply, so the error is replied instead of being dropped
+		logger.Error("Failed to parse alerts history limit", zap.Error(err), zap.ByteString("message", msg))
+		response.ErrMessage = errors.Wrap(err, "failed to parse alerts history limit").Error()
+	} else {
+		response.Active, response.Resolved = al.Active(), al.History(historyLimit)
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal alerts to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal alerts to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleMuteAlertRequest(msg []byte, mutes storage.AlertMutes, logger *zap.Logger) ([]byte, error) {
+	var (
+		mute     entities.AlertMute
+		response MuteAlertResponse
+	)
+	if err := json.Unmarshal(msg, &mute); err != nil {
+		logger.Error("Failed to unmarshal alert mute", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to unmarshal alert mute")
+	}
+	added, err := mutes.PutMute(mute)
+	if err != nil {
+		logger.Warn("Failed to put alert mute", zap.Error(err))
+		response.ErrMessage = err.Error()
+	} else {
+		response.Mute = added
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal alert mute response to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal alert mute response to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleNodeMaintenanceRequest(msg []byte, ns nodes.Storage, logger *zap.Logger) ([]byte, error) {
+	var node entities.Node
+	if err := json.Unmarshal(msg, &node); err != nil {
+		logger.Error("Failed to unmarshal node maintenance", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to unmarshal node maintenance")
+	}
+	response := NodeMaintenanceResponse{URL: node.URL, Window: node.Maintenance}
+	if err := ns.SetMaintenance(node.URL, node.Maintenance); err != nil {
+		logger.Warn("Failed to set node maintenance", zap.String("node", node.URL), zap.Error(err))
+		response.ErrMessage = err.Error()
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal node maintenance response to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal node maintenance response to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleNodeTagsRequest(msg []byte, ns nodes.Storage, logger *zap.Logger) ([]byte, error) {
+	var node entities.Node
+	if err := json.Unmarshal(msg, &node); err != nil {
+		logger.Error("Failed to unmarshal node tags", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to unmarshal node tags")
+	}
+	response := NodeTagsResponse{URL: node.URL}
+	if err := ns.SetTags(node.URL, node.Tags); err != nil {
+		logger.Warn("Failed to set node tags", zap.String("node", node.URL), zap.Error(err))
+		response.ErrMessage = err.Error()
+	} else {
+		response.Tags, _ = entities.NormalizeTags(node.Tags) // the tags are valid, they have been stored
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal node tags response to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal node tags response to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleForkReportRequest(msg []byte, fr *forks.Reporter, logger *zap.Logger) ([]byte, error) {
+	var response ForkReportResponse
+	if alertID, err := crypto.NewDigestFromBase58(string(msg)); err != nil {
+		response.ErrMessage = errors.Wrap(err, "invalid alert ID").Error()
+	} else if report, ok := fr.Report(alertID); ok {
+		response.Report = &report
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal fork report response to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal fork report response to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleGeneratorsRequest(es *events.Storage, logger *zap.Logger) ([]byte, error) {
+	var response GeneratorsResponse
+	stats, err := es.GeneratorsStats()
+	if err != nil {
+		logger.Error("Failed to collect generators stats", zap.Error(err))
+		response.ErrMessage = err.Error()
+	} else {
+		response.Stats = stats
+	}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal generators response to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal generators response to json")
+	}
+	return marshaledResponse, nil
+}
+
+func handleNodesSummaryRequest(msg []byte, ut *uptime.Tracker, logger *zap.Logger) ([]byte, error) {
+	since, err := strconv.ParseInt(string(msg), 10, 64)
+	if err != nil {
+		logger.Error("Failed to parse nodes summary start", zap.Error(err), zap.ByteString("message", msg))
+		return nil, errors.Wrap(err, "failed to parse nodes summary start")
+	}
+	response := NodesSummaryResponse{Since: since, Nodes: ut.Summary(since)}
+	marshaledResponse, err := json.Marshal(response)
+	if err != nil {
+		logger.Error("Failed to marshal nodes summary to json", zap.Error(err))
+		return nil, errors.Wrap(err, "failed to marshal nodes summary to json")
+	}
+	return marshaledResponse, nil
+}
@@ -0,0 +1,20 @@
+package pair
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandleAlertsRequest_InvalidLimit(t *testing.T) {
+	raw, err := handleAlertsRequest([]byte("ten"), nil, zap.NewNop())
+	require.NoError(t, err, "the error must be replied to the bot")
+	var resp AlertsResponse
+	require.NoError(t, json.Unmarshal(raw, &resp))
+	assert.Contains(t, resp.ErrMessage, "failed to parse alerts history limit")
+	assert.Empty(t, resp.Active)
+	assert.Empty(t, resp.Resolved)
+}
@@ -10,6 +10,7 @@ import (
 
 	"nodemon/pkg/entities"
 	"nodemon/pkg/messaging"
+	"nodemon/pkg/metrics"
 )
 
 func StartPubMessagingServer(
@@ -54,6 +55,7 @@ func enterLoop(ctx context.Context, alerts <-chan entities.Alert,
 			err = nc.Publish(topic, data)
 			if err != nil {
 				logger.Error("Failed to send alert to socket", zap.Error(err))
+				metrics.PubSubPublishFailed(scheme, alert.Name())
 			}
 		}
 	}
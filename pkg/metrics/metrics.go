@@ -0,0 +1,162 @@
+// Package metrics keeps the Prometheus collectors of the monitoring service. The collectors are registered
+// in the default registry, so they are exposed by tools.PrometheusHTTPMetricsHandler.
+package metrics
+
+import (
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "nodemon"
+
+const (
+	schemeLabel = "scheme" // the blockchain scheme of the monitored network, e.g. mainnet
+	nodeLabel   = "node"
+	statusLabel = "status"
+	alertLabel  = "alert"
+)
+
+//nolint:gochecknoglobals // the collectors are registered once in the default registry
+var (
+	nodeHeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_height",
+		Help:      "The last height reported by the node.",
+	}, []string{schemeLabel, nodeLabel})
+	nodeStatus = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_status",
+		Help:      "The status of the node after the last scrape: 1 for the current status and 0 for the others.",
+	}, []string{schemeLabel, nodeLabel, statusLabel})
+	nodeBaseTarget = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_base_target",
+		Help:      "The last base target reported by the node.",
+	}, []string{schemeLabel, nodeLabel})
+	nodeLastSuccessfulScrape = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "node_last_successful_scrape_timestamp_seconds",
+		Help:      "Unix time of the last scrape which collected the full statement of the node.",
+	}, []string{schemeLabel, nodeLabel})
+	nodeScrapeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "node_scrape_duration_seconds",
+		Help:      "Duration of the node scrape.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{schemeLabel, nodeLabel})
+
+	alertsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "alerts_sent_total",
+		Help:      "The number of the alerts sent by the analyzers, the repeats of the same alert are counted.",
+	}, []string{schemeLabel, alertLabel})
+	alertsActive = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "alerts_active",
+		Help:      "The number of the confirmed alerts which have not been fixed yet.",
+	}, []string{schemeLabel, alertLabel})
+
+	l2NodeHeight = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "l2_node_height",
+		Help:      "The last height reported by the L2 node.",
+	}, []string{schemeLabel, nodeLabel})
+
+	pubSubPublishFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "pubsub_publish_failures_total",
+		Help:      "The number of the alerts which failed to be published to NATS.",
+	}, []string{schemeLabel, alertLabel})
+
+	// scrapedNodes are the sets of the nodes which have the series by the network scheme,
+	// it's needed to delete them.
+	scrapedNodes = struct {
+		mu    sync.Mutex
+		nodes map[string]map[string]struct{}
+	}{nodes: make(map[string]map[string]struct{})}
+)
+
+// NodeScraped updates the node metrics with the scraped event of the node of the given network.
+func NodeScraped(scheme string, event entities.Event, duration time.Duration) {
+	statement := event.Statement()
+	node := statement.Node
+
+	scrapedNodes.mu.Lock()
+	if scrapedNodes.nodes[scheme] == nil {
+		scrapedNodes.nodes[scheme] = make(map[string]struct{})
+	}
+	scrapedNodes.nodes[scheme][node] = struct{}{}
+	scrapedNodes.mu.Unlock()
+
+	nodeScrapeDuration.WithLabelValues(scheme, node).Observe(duration.Seconds())
+	statuses := []entities.NodeStatus{entities.OK, entities.Incomplete, entities.Unreachable, entities.InvalidHeight}
+	for _, status := range statuses {
+		var v float64
+		if status == statement.Status {
+			v = 1
+		}
+		nodeStatus.WithLabelValues(scheme, node, string(status)).Set(v)
+	}
+	if statement.Height != 0 {
+		nodeHeight.WithLabelValues(scheme, node).Set(float64(statement.Height))
+	}
+	if statement.BaseTarget != 0 {
+		nodeBaseTarget.WithLabelValues(scheme, node).Set(float64(statement.BaseTarget))
+	}
+	if statement.Status == entities.OK {
+		nodeLastSuccessfulScrape.WithLabelValues(scheme, node).Set(float64(statement.Timestamp))
+	}
+}
+
+// KeepNodes deletes the series of the network nodes which are absent in the given list,
+// e.g. removed or disabled nodes. The nodes of the other networks are kept.
+func KeepNodes(scheme string, nodes []string) {
+	keep := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		keep[node] = struct{}{}
+	}
+	scrapedNodes.mu.Lock()
+	defer scrapedNodes.mu.Unlock()
+	for node := range scrapedNodes.nodes[scheme] {
+		if _, ok := keep[node]; ok {
+			continue
+		}
+		labels := prometheus.Labels{schemeLabel: scheme, nodeLabel: node}
+		nodeHeight.DeletePartialMatch(labels)
+		nodeStatus.DeletePartialMatch(labels)
+		nodeBaseTarget.DeletePartialMatch(labels)
+		nodeLastSuccessfulScrape.DeletePartialMatch(labels)
+		nodeScrapeDuration.DeletePartialMatch(labels)
+		delete(scrapedNodes.nodes[scheme], node)
+	}
+}
+
+// AlertSent counts the alert sent by an analyzer of the given network.
+func AlertSent(scheme string, name entities.AlertName) {
+	alertsSent.WithLabelValues(scheme, name.String()).Inc()
+}
+
+// AlertConfirmed increments the number of the active alerts of the given network.
+func AlertConfirmed(scheme string, name entities.AlertName) {
+	alertsActive.WithLabelValues(scheme, name.String()).Inc()
+}
+
+// AlertFixed decrements the number of the active alerts of the given network.
+func AlertFixed(scheme string, name entities.AlertName) {
+	alertsActive.WithLabelValues(scheme, name.String()).Dec()
+}
+
+// L2NodeHeight sets the last height of the L2 node monitored along with the given network.
+func L2NodeHeight(scheme, node string, height uint64) {
+	l2NodeHeight.WithLabelValues(scheme, node).Set(float64(height))
+}
+
+// PubSubPublishFailed counts the alert of the given network which failed to be published.
+func PubSubPublishFailed(scheme string, name entities.AlertName) {
+	pubSubPublishFailures.WithLabelValues(scheme, name.String()).Inc()
+}
@@ -0,0 +1,72 @@
+package metrics_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/metrics"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatherNodeSeries returns the values of the gauges and the sample counts of the histograms of the network node by
+// the metric name and the status label, if any.
+func gatherNodeSeries(t *testing.T, scheme, node string) map[string]float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	out := make(map[string]float64)
+	for _, family := range families {
+		for _, m := range family.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["scheme"] != scheme || labels["node"] != node {
+				continue
+			}
+			key := family.GetName()
+			if status, ok := labels["status"]; ok {
+				key += "/" + status
+			}
+			switch {
+			case m.GetGauge() != nil:
+				out[key] = m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				out[key] = float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return out
+}
+
+func TestNodeScraped(t *testing.T) {
+	const (
+		scheme = "mainnet"
+		other  = "testnet"
+		node   = "https://metrics-test.node"
+	)
+	metrics.NodeScraped(scheme, entities.NewBaseTargetEvent(node, 100, "v1", 10, 70, nil, nil, false), time.Second)
+	metrics.NodeScraped(scheme, entities.NewUnreachableEvent(node, 160), time.Second)
+	metrics.NodeScraped(other, entities.NewBaseTargetEvent(node, 100, "v1", 20, 80, nil, nil, false), time.Second)
+
+	assert.Equal(t, map[string]float64{
+		"nodemon_node_height":                  10,
+		"nodemon_node_base_target":             70,
+		"nodemon_node_status/OK":               0,
+		"nodemon_node_status/incomplete":       0,
+		"nodemon_node_status/invalid_height":   0,
+		"nodemon_node_status/unreachable":      1,
+		"nodemon_node_scrape_duration_seconds": 2,
+	}, gatherNodeSeries(t, scheme, node), "the same node of the other network must not mix in")
+	assert.InDelta(t, 20, gatherNodeSeries(t, other, node)["nodemon_node_height"], 0)
+
+	metrics.KeepNodes(other, []string{"https://other.node"})
+	assert.NotEmpty(t, gatherNodeSeries(t, scheme, node), "the nodes of the other network must be kept")
+	assert.Empty(t, gatherNodeSeries(t, other, node))
+
+	metrics.KeepNodes(scheme, []string{"https://other.node"})
+	assert.Empty(t, gatherNodeSeries(t, scheme, node))
+}
@@ -0,0 +1,298 @@
+package scraping
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	eventsgrpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	nodegrpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	DefaultGRPCBlocksPort  = 6870
+	DefaultGRPCUpdatesPort = 6881
+
+	// grpcKeepaliveTime is how often the idle connection is pinged. The nodes reject more frequent pings
+	// by the default keepalive policy of the gRPC servers.
+	grpcKeepaliveTime    = 5 * time.Minute
+	grpcKeepaliveTimeout = 20 * time.Second
+)
+
+// GRPCOptions are the options of the gRPC scraping backend. The gRPC APIs are expected on the host of the node
+// REST API URL.
+type GRPCOptions struct {
+	BlocksPort     uint16        // port of the blocks API, used to get the current height
+	UpdatesPort    uint16        // port of the blockchain updates API which streams the block headers
+	ReconnectDelay time.Duration // delay before the broken stream is reconnected
+	// MaxHeaderAge is how long the streamed header is trusted without updates. The older header is checked against
+	// the height by REST API, because the stream may hang without an error. Zero disables the check.
+	MaxHeaderAge time.Duration
+}
+
+// streamedHeader is the last block header streamed by the node.
+type streamedHeader struct {
+	height         uint64
+	blockID        proto.BlockID // ID of the liquid block, i.e. with the applied micro blocks
+	generator      proto.WavesAddress
+	challenged     bool
+	baseTarget     uint64
+	prevBaseTarget uint64    // base target of the previous block, zero if unknown
+	received       time.Time // when the block or its last micro block has been streamed
+}
+
+// headersWatcher streams the block headers of the node using the gRPC blockchain updates API.
+type headersWatcher struct {
+	node        string
+	opts        GRPCOptions
+	cancel      context.CancelFunc
+	mu          *sync.Mutex
+	last        *streamedHeader    // nil if the stream isn't established or the last header is unknown
+	closeStream context.CancelFunc // breaks the current stream, nil if there is no stream
+	zap         *zap.Logger
+}
+
+func newHeadersWatcher(node string, opts GRPCOptions, logger *zap.Logger) *headersWatcher {
+	return &headersWatcher{node: node, opts: opts, mu: new(sync.Mutex), zap: logger}
+}
+
+// header returns the last streamed header. False is returned if the header is unknown, in this case
+// the node has to be scraped by REST API.
+func (w *headersWatcher) header() (streamedHeader, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.last == nil {
+		return streamedHeader{}, false
+	}
+	return *w.last, true
+}
+
+func (w *headersWatcher) reset() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.last = nil
+}
+
+// run streams the headers until the context is canceled, the broken stream is reconnected after the delay.
+func (w *headersWatcher) run(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+	go func() {
+		for {
+			err := w.stream(ctx)
+			w.reset()
+			if ctx.Err() != nil {
+				return
+			}
+			w.zap.Warn("[SCRAPER] Block headers stream is broken, falling back to REST API",
+				zap.String("node", w.node), zap.Error(err),
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.opts.ReconnectDelay):
+			}
+		}
+	}()
+}
+
+func (w *headersWatcher) stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+}
+
+// restart breaks the current stream, so it's reconnected after the delay.
+func (w *headersWatcher) restart() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closeStream != nil {
+		w.closeStream()
+	}
+}
+
+func (w *headersWatcher) stream(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	w.mu.Lock()
+	w.closeStream = cancel
+	w.mu.Unlock()
+
+	blocksConn, err := dialGRPC(w.node, w.opts.BlocksPort)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = blocksConn.Close() }()
+	updatesConn, err := dialGRPC(w.node, w.opts.UpdatesPort)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = updatesConn.Close() }()
+
+	height, err := nodegrpc.NewBlocksApiClient(blocksConn).GetCurrentHeight(ctx, &emptypb.Empty{})
+	if err != nil {
+		return errors.Wrap(err, "failed to get current height")
+	}
+	// the stream starts from the previous block to know its base target
+	from := max(int32(height.GetValue())-1, 1)
+	sub, err := eventsgrpc.NewBlockchainUpdatesApiClient(updatesConn).Subscribe(ctx,
+		&eventsgrpc.SubscribeRequest{FromHeight: from},
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to subscribe to blockchain updates")
+	}
+	w.zap.Info("[SCRAPER] Block headers stream is established",
+		zap.String("node", w.node), zap.Int32("from", from),
+	)
+	for {
+		event, recvErr := sub.Recv()
+		if recvErr != nil {
+			return errors.Wrap(recvErr, "failed to receive blockchain update")
+		}
+		if applyErr := w.apply(event.GetUpdate()); applyErr != nil {
+			return applyErr
+		}
+	}
+}
+
+// apply updates the last header with the blockchain update. The header becomes unknown after a rollback
+// until the next block is appended.
+func (w *headersWatcher) apply(update *events.BlockchainUpdated) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if update.GetRollback() != nil {
+		w.last = nil
+		return nil
+	}
+	appended := update.GetAppend()
+	if appended == nil {
+		return nil
+	}
+	blockID, err := proto.NewBlockIDFromBytes(update.GetId())
+	if err != nil {
+		return errors.Wrap(err, "invalid block ID")
+	}
+	if appended.GetMicroBlock() != nil {
+		if w.last != nil && w.last.height == uint64(update.GetHeight()) {
+			w.last.blockID = blockID
+			w.last.received = time.Now()
+		}
+		return nil
+	}
+	header := appended.GetBlock().GetBlock().GetHeader()
+	if header == nil {
+		return nil
+	}
+	pk, err := crypto.NewPublicKeyFromBytes(header.GetGenerator())
+	if err != nil {
+		return errors.Wrap(err, "invalid block generator")
+	}
+	generator, err := proto.NewAddressFromPublicKey(byte(header.GetChainId()), pk)
+	if err != nil {
+		return errors.Wrap(err, "failed to get block generator address")
+	}
+	next := &streamedHeader{
+		height:     uint64(update.GetHeight()),
+		blockID:    blockID,
+		generator:  generator,
+		challenged: header.GetChallengedHeader() != nil,
+		baseTarget: uint64(header.GetBaseTarget()),
+		received:   time.Now(),
+	}
+	if w.last != nil && w.last.height+1 == next.height {
+		next.prevBaseTarget = w.last.baseTarget
+	}
+	w.last = next
+	return nil
+}
+
+// dialGRPC creates the client connection to the gRPC API on the host of the node REST API URL.
+func dialGRPC(node string, port uint16) (*grpc.ClientConn, error) {
+	u, err := url.Parse(node)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid node URL '%s'", node)
+	}
+	target := net.JoinHostPort(u.Hostname(), strconv.Itoa(int(port)))
+	conn, err := grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(keepalive.ClientParameters{ // detects the dead connections of the idle streams
+			Time:                grpcKeepaliveTime,
+			Timeout:             grpcKeepaliveTimeout,
+			PermitWithoutStream: false,
+		}),
+	)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create gRPC client for '%s'", target)
+	}
+	return conn, nil
+}
+
+// headersWatchers keeps the headers watchers of the enabled nodes.
+type headersWatchers struct {
+	opts     GRPCOptions
+	mu       *sync.Mutex
+	watchers map[string]*headersWatcher // by node URL
+	zap      *zap.Logger
+}
+
+func newHeadersWatchers(opts GRPCOptions, logger *zap.Logger) *headersWatchers {
+	return &headersWatchers{
+		opts:     opts,
+		mu:       new(sync.Mutex),
+		watchers: make(map[string]*headersWatcher),
+		zap:      logger,
+	}
+}
+
+// sync starts the watchers of the new nodes and stops the watchers of the nodes which are absent in the list.
+func (ws *headersWatchers) sync(ctx context.Context, nodes []string) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	keep := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		keep[node] = struct{}{}
+		if _, ok := ws.watchers[node]; ok {
+			continue
+		}
+		w := newHeadersWatcher(node, ws.opts, ws.zap)
+		w.run(ctx)
+		ws.watchers[node] = w
+	}
+	for node, w := range ws.watchers {
+		if _, ok := keep[node]; !ok {
+			w.stop()
+			delete(ws.watchers, node)
+		}
+	}
+}
+
+func (ws *headersWatchers) header(node string) (streamedHeader, bool) {
+	ws.mu.Lock()
+	w, ok := ws.watchers[node]
+	ws.mu.Unlock()
+	if !ok {
+		return streamedHeader{}, false
+	}
+	return w.header()
+}
+
+// restart breaks the stream of the node, e.g. if it lags behind the node.
+func (ws *headersWatchers) restart(node string) {
+	ws.mu.Lock()
+	w, ok := ws.watchers[node]
+	ws.mu.Unlock()
+	if ok {
+		w.restart()
+	}
+}
@@ -0,0 +1,241 @@
+package scraping
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves"
+	"github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events"
+	eventsgrpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/events/grpc"
+	nodegrpc "github.com/wavesplatform/gowaves/pkg/grpc/generated/waves/node/grpc"
+	"github.com/wavesplatform/gowaves/pkg/proto"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type fakeNodeServer struct {
+	nodegrpc.UnimplementedBlocksApiServer
+	eventsgrpc.UnimplementedBlockchainUpdatesApiServer
+	height     uint32
+	updates    []*events.BlockchainUpdated
+	subscribed chan int32 // from height of the subscriptions
+}
+
+func (s *fakeNodeServer) GetCurrentHeight(context.Context, *emptypb.Empty) (*wrapperspb.UInt32Value, error) {
+	return wrapperspb.UInt32(s.height), nil
+}
+
+func (s *fakeNodeServer) Subscribe(
+	req *eventsgrpc.SubscribeRequest,
+	stream eventsgrpc.BlockchainUpdatesApi_SubscribeServer,
+) error {
+	s.subscribed <- req.GetFromHeight()
+	for _, update := range s.updates {
+		if err := stream.Send(&eventsgrpc.SubscribeEvent{Update: update}); err != nil {
+			return err
+		}
+	}
+	<-stream.Context().Done()
+	return nil
+}
+
+// startFakeNode starts the gRPC server and returns its port.
+func startFakeNode(t *testing.T, srv *fakeNodeServer) uint16 {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	nodegrpc.RegisterBlocksApiServer(s, srv)
+	eventsgrpc.RegisterBlockchainUpdatesApiServer(s, srv)
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+	return uint16(lis.Addr().(*net.TCPAddr).Port)
+}
+
+func blockAppended(height int32, id crypto.Digest, header *waves.Block_Header) *events.BlockchainUpdated {
+	return &events.BlockchainUpdated{
+		Id:     id.Bytes(),
+		Height: height,
+		Update: &events.BlockchainUpdated_Append_{Append: &events.BlockchainUpdated_Append{
+			Body: &events.BlockchainUpdated_Append_Block{Block: &events.BlockchainUpdated_Append_BlockAppend{
+				Block: &waves.Block{Header: header},
+			}},
+		}},
+	}
+}
+
+func microBlockAppended(height int32, id crypto.Digest) *events.BlockchainUpdated {
+	return &events.BlockchainUpdated{
+		Id:     id.Bytes(),
+		Height: height,
+		Update: &events.BlockchainUpdated_Append_{Append: &events.BlockchainUpdated_Append{
+			Body: &events.BlockchainUpdated_Append_MicroBlock{
+				MicroBlock: &events.BlockchainUpdated_Append_MicroBlockAppend{},
+			},
+		}},
+	}
+}
+
+func TestHeadersWatcher(t *testing.T) {
+	generatorPK := crypto.PublicKey{0x01}
+	generator, err := proto.NewAddressFromPublicKey(proto.MainNetScheme, generatorPK)
+	require.NoError(t, err)
+	srv := &fakeNodeServer{
+		height: 10,
+		updates: []*events.BlockchainUpdated{
+			blockAppended(9, crypto.Digest{0x09}, &waves.Block_Header{
+				ChainId: int32(proto.MainNetScheme), BaseTarget: 100, Generator: generatorPK.Bytes(),
+			}),
+			blockAppended(10, crypto.Digest{0x0a}, &waves.Block_Header{
+				ChainId:          int32(proto.MainNetScheme),
+				BaseTarget:       110,
+				Generator:        generatorPK.Bytes(),
+				ChallengedHeader: &waves.Block_Header_ChallengedHeader{},
+			}),
+			microBlockAppended(10, crypto.Digest{0x0b}),
+		},
+		subscribed: make(chan int32, 1),
+	}
+	port := startFakeNode(t, srv)
+
+	w := newHeadersWatcher("http://127.0.0.1:6869", GRPCOptions{BlocksPort: port, UpdatesPort: port}, zap.NewNop())
+	w.run(context.Background())
+	defer w.stop()
+	assert.Equal(t, int32(9), <-srv.subscribed, "the stream must start from the previous block")
+
+	expected := streamedHeader{
+		height:         10,
+		blockID:        proto.NewBlockIDFromDigest(crypto.Digest{0x0b}),
+		generator:      generator,
+		challenged:     true,
+		baseTarget:     110,
+		prevBaseTarget: 100,
+	}
+	require.Eventually(t, func() bool {
+		header, ok := w.header()
+		received := header.received
+		header.received = time.Time{}
+		return ok && header == expected && !received.IsZero()
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, w.apply(&events.BlockchainUpdated{
+		Height: 9,
+		Update: &events.BlockchainUpdated_Rollback_{Rollback: &events.BlockchainUpdated_Rollback{}},
+	}))
+	_, ok := w.header()
+	assert.False(t, ok, "the header must be unknown after the rollback")
+}
+
+func TestScraper_queryNodeWithHeader(t *testing.T) {
+	sh := proto.StateHash{BlockID: proto.NewBlockIDFromDigest(crypto.Digest{0x09})}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/node/version", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.5.7"}`))
+	})
+	mux.HandleFunc("/debug/stateHash/9", func(w http.ResponseWriter, _ *http.Request) {
+		require.NoError(t, json.NewEncoder(w).Encode(sh))
+	})
+	node := httptest.NewServer(mux)
+	defer node.Close()
+
+	s, err := NewScraper("mainnet", nil, nil, time.Minute, time.Second, zap.NewNop())
+	require.NoError(t, err)
+	header := streamedHeader{
+		height:         10,
+		blockID:        proto.NewBlockIDFromDigest(crypto.Digest{0x0a}),
+		baseTarget:     110,
+		prevBaseTarget: 100,
+	}
+	const ts = 100
+	event := s.queryNodeWithHeader(context.Background(), node.URL, time.Second, ts, header)
+	statement := event.Statement()
+	assert.Equal(t, entities.OK, statement.Status)
+	assert.Equal(t, uint64(9), statement.Height)
+	assert.Equal(t, uint64(100), statement.BaseTarget, "the base target of the previous block must be used")
+	assert.Equal(t, "1.5.7", statement.Version)
+	assert.Equal(t, &header.blockID, statement.BlockID)
+}
+
+func TestScraper_StaleHeadersStream(t *testing.T) {
+	generatorPK := crypto.PublicKey{0x01}
+	srv := &fakeNodeServer{ // the stream hangs after the first blocks as if the connection is lost silently
+		height: 10,
+		updates: []*events.BlockchainUpdated{
+			blockAppended(9, crypto.Digest{0x09}, &waves.Block_Header{
+				ChainId: int32(proto.MainNetScheme), BaseTarget: 100, Generator: generatorPK.Bytes(),
+			}),
+			blockAppended(10, crypto.Digest{0x0a}, &waves.Block_Header{
+				ChainId: int32(proto.MainNetScheme), BaseTarget: 110, Generator: generatorPK.Bytes(),
+			}),
+		},
+		subscribed: make(chan int32, 2),
+	}
+	port := startFakeNode(t, srv)
+	restHeight := atomic.Uint64{}
+	restHeight.Store(10)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/node/version", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"version":"1.5.7"}`))
+	})
+	mux.HandleFunc("/blocks/height", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = fmt.Fprintf(w, `{"height":%d}`, restHeight.Load())
+	})
+	rest := httptest.NewServer(mux)
+	defer rest.Close()
+
+	const maxAge = 50 * time.Millisecond
+	s, err := NewScraper("mainnet", nil, nil, time.Minute, time.Second, zap.NewNop(), WithGRPC(GRPCOptions{
+		BlocksPort:     port,
+		UpdatesPort:    port,
+		ReconnectDelay: time.Millisecond,
+		MaxHeaderAge:   maxAge,
+	}))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	s.headers.sync(ctx, []string{rest.URL})
+	require.Equal(t, int32(9), <-srv.subscribed)
+	require.Eventually(t, func() bool {
+		header, ok := s.headers.header(rest.URL)
+		return ok && header.height == 10
+	}, 5*time.Second, 10*time.Millisecond)
+
+	time.Sleep(2 * maxAge)
+	node := entities.Node{URL: rest.URL}
+	header, ok := s.headers.header(rest.URL)
+	require.True(t, ok)
+	assert.True(t, s.isHeaderCurrent(ctx, rest.URL, time.Second, header),
+		"the old header is current while the node has no new blocks",
+	)
+
+	restHeight.Store(12)
+	statement := s.queryNode(ctx, node, 100).Statement()
+	assert.Equal(t, uint64(12), statement.Height, "the lagging stream must be replaced by REST API")
+	select {
+	case from := <-srv.subscribed:
+		assert.Equal(t, int32(9), from, "the lagging stream must be restarted")
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "the lagging stream hasn't been restarted")
+	}
+}
+
+func TestDialGRPC(t *testing.T) {
+	conn, err := dialGRPC("https://node.example.com:6869", DefaultGRPCBlocksPort)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, conn.Close()) }()
+	assert.Equal(t, "node.example.com:"+strconv.Itoa(DefaultGRPCBlocksPort), conn.Target())
+}
@@ -0,0 +1,171 @@
+package scraping
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+)
+
+// SchedulingOptions are the options of the nodes polling schedule. Zero values disable the corresponding features.
+type SchedulingOptions struct {
+	Jitter       time.Duration // max random delay of the node poll start within the round
+	Concurrency  int           // max number of the nodes polled at the same time
+	BackoffAfter time.Duration // the node unreachable for this time is probed with the growing intervals
+	MaxBackoff   time.Duration // max interval between the probes of the unreachable node
+	// FollowUpDuration is the time during which the alerted node is polled each round. It only affects the nodes
+	// with their own interval longer than the rounds one, the other nodes are polled each round anyway.
+	FollowUpDuration time.Duration
+	// MaxInterval returns the limit of the node interval, longer node intervals are shortened to it. Nil means
+	// no limit.
+	MaxInterval func() time.Duration
+}
+
+// WithScheduling sets the polling schedule options of the scraper.
+func WithScheduling(opts SchedulingOptions) ScraperOption {
+	return func(s *Scraper) { s.schedule.opts = opts }
+}
+
+// nodeSchedule is the polling state of the node.
+type nodeSchedule struct {
+	lastPoll         int64         // unix seconds of the last poll
+	unreachableSince int64         // unix seconds of the first unreachable poll in a row, zero if reachable
+	backoff          time.Duration // interval between the probes of the unreachable node, zero if not backed off
+	followUpUntil    int64         // unix seconds
+}
+
+// pollPlan is the set of the nodes of the polling round. The nodes which are absent in the plan aren't due yet.
+type pollPlan struct {
+	polled  []entities.Node // nodes to poll
+	assumed []string        // backed off unreachable nodes which are assumed unreachable without polling
+}
+
+// scheduler decides which nodes are polled in the round. The rounds go with the scraper interval, so the node
+// interval shorter than it has no effect.
+type scheduler struct {
+	opts     SchedulingOptions
+	interval time.Duration // interval of the rounds
+	mu       *sync.Mutex
+	nodes    map[string]*nodeSchedule
+	sem      chan struct{} // limits the concurrent polls, nil if there's no limit
+}
+
+func newScheduler(interval time.Duration) *scheduler {
+	return &scheduler{interval: interval, mu: new(sync.Mutex), nodes: make(map[string]*nodeSchedule)}
+}
+
+// init applies the options, it must be called before the first round.
+func (s *scheduler) init() {
+	if s.opts.Concurrency > 0 {
+		s.sem = make(chan struct{}, s.opts.Concurrency)
+	}
+}
+
+func (s *scheduler) plan(enabled []entities.Node, now int64) pollPlan {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var (
+		plan pollPlan
+		keep = make(map[string]struct{}, len(enabled))
+		// the rounds don't go exactly with the interval, so the nodes which are almost due are polled
+		tolerance = s.interval / 2 //nolint:mnd // half of the interval
+	)
+	for _, node := range enabled {
+		keep[node.URL] = struct{}{}
+		ns, ok := s.nodes[node.URL]
+		if !ok {
+			ns = new(nodeSchedule)
+			s.nodes[node.URL] = ns
+		}
+		elapsed := time.Duration(now-ns.lastPoll)*time.Second + tolerance
+		switch {
+		case ns.lastPoll == 0 || now < ns.followUpUntil:
+			plan.polled = append(plan.polled, node)
+		case ns.backoff > 0 && elapsed < ns.backoff:
+			plan.assumed = append(plan.assumed, node.URL)
+		case ns.backoff > 0 || elapsed >= s.nodeInterval(node):
+			plan.polled = append(plan.polled, node)
+		}
+	}
+	for url := range s.nodes {
+		if _, ok := keep[url]; !ok {
+			delete(s.nodes, url)
+		}
+	}
+	return plan
+}
+
+// polled updates the node schedule with the poll result. The back-off interval of the node which is unreachable
+// longer than BackoffAfter starts from the doubled node interval and doubles with each probe up to MaxBackoff.
+func (s *scheduler) polled(node entities.Node, ts int64, status entities.NodeStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ns, ok := s.nodes[node.URL]
+	if !ok {
+		return
+	}
+	ns.lastPoll = ts
+	if status != entities.Unreachable {
+		ns.unreachableSince, ns.backoff = 0, 0
+		return
+	}
+	if ns.unreachableSince == 0 {
+		ns.unreachableSince = ts
+	}
+	if s.opts.BackoffAfter <= 0 || time.Duration(ts-ns.unreachableSince)*time.Second < s.opts.BackoffAfter {
+		return
+	}
+	next := 2 * ns.backoff //nolint:mnd // doubles with each probe
+	if next == 0 {
+		next = 2 * s.nodeInterval(node) //nolint:mnd // starts from the doubled interval
+	}
+	ns.backoff = min(next, max(s.opts.MaxBackoff, s.interval))
+}
+
+// nodeInterval returns the polling interval of the node limited by MaxInterval.
+func (s *scheduler) nodeInterval(node entities.Node) time.Duration {
+	interval := node.Polling.IntervalOr(s.interval)
+	if s.opts.MaxInterval != nil {
+		interval = min(interval, max(s.opts.MaxInterval(), s.interval))
+	}
+	return interval
+}
+
+// followUp makes the nodes polled each round for FollowUpDuration. The rounds aren't added, so the nodes
+// which are already polled each round aren't affected.
+func (s *scheduler) followUp(nodes []string, now int64) {
+	if s.opts.FollowUpDuration <= 0 {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	until := now + int64(s.opts.FollowUpDuration/time.Second)
+	for _, node := range nodes {
+		if ns, ok := s.nodes[node]; ok {
+			ns.followUpUntil = max(ns.followUpUntil, until)
+		}
+	}
+}
+
+// acquire waits for the random jitter delay and for the free polling slot. The returned function releases the slot.
+// False is returned if the context is canceled.
+func (s *scheduler) acquire(ctx context.Context) (func(), bool) {
+	if s.opts.Jitter > 0 {
+		select {
+		case <-ctx.Done():
+			return nil, false
+		case <-time.After(rand.N(s.opts.Jitter)): //nolint:gosec // jitter doesn't need a secure random
+		}
+	}
+	if s.sem == nil {
+		return func() {}, true
+	}
+	select {
+	case <-ctx.Done():
+		return nil, false
+	case s.sem <- struct{}{}:
+		return func() { <-s.sem }, true
+	}
+}
@@ -0,0 +1,132 @@
+package scraping
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func planURLs(plan pollPlan) ([]string, []string) {
+	polled := make([]string, 0, len(plan.polled))
+	for _, node := range plan.polled {
+		polled = append(polled, node.URL)
+	}
+	return polled, plan.assumed
+}
+
+func TestScheduler_plan(t *testing.T) {
+	const (
+		interval = time.Minute
+		start    = 1000
+	)
+	var (
+		fast = entities.Node{URL: "fast"}
+		slow = entities.Node{URL: "slow", Polling: &entities.PollingOptions{Interval: 180}}
+		s    = newScheduler(interval)
+	)
+	s.init()
+	nodes := []entities.Node{fast, slow}
+	for i, expected := range [][]string{
+		{"fast", "slow"}, // new nodes are polled at once
+		{"fast"},
+		{"fast"},
+		{"fast", "slow"},
+	} {
+		ts := int64(start + i*60)
+		plan := s.plan(nodes, ts)
+		polled, assumed := planURLs(plan)
+		assert.Equal(t, expected, polled, "round %d", i)
+		assert.Empty(t, assumed)
+		for _, node := range plan.polled {
+			s.polled(node, ts, entities.OK)
+		}
+	}
+
+	s.opts.FollowUpDuration = 2 * time.Minute
+	s.followUp([]string{"slow"}, start+240)
+	polled, _ := planURLs(s.plan(nodes, start+240))
+	assert.Equal(t, []string{"fast", "slow"}, polled, "followed up node must be polled each round")
+
+	s.plan(nodes[:1], start+300)
+	assert.NotContains(t, s.nodes, "slow", "removed node must be forgotten")
+}
+
+func TestScheduler_MaxInterval(t *testing.T) {
+	const (
+		interval = time.Minute
+		start    = 1000
+	)
+	var (
+		slow = entities.Node{URL: "slow", Polling: &entities.PollingOptions{Interval: 600}}
+		s    = newScheduler(interval)
+	)
+	s.opts.MaxInterval = func() time.Duration { return 2 * time.Minute }
+	s.init()
+	var rounds []bool
+	for ts := int64(start); ts <= start+300; ts += 60 {
+		plan := s.plan([]entities.Node{slow}, ts)
+		rounds = append(rounds, len(plan.polled) == 1)
+		for _, node := range plan.polled {
+			s.polled(node, ts, entities.OK)
+		}
+	}
+	assert.Equal(t, []bool{true, false, true, false, true, false}, rounds,
+		"node interval longer than the limit must be shortened to it",
+	)
+}
+
+func TestScheduler_backoff(t *testing.T) {
+	const (
+		interval = time.Minute
+		start    = 1000
+	)
+	var (
+		node = entities.Node{URL: "node"}
+		s    = newScheduler(interval)
+	)
+	s.opts = SchedulingOptions{BackoffAfter: 2 * time.Minute, MaxBackoff: 4 * time.Minute}
+	s.init()
+	var rounds []string
+	for ts := int64(start); ts <= start+900; ts += 60 {
+		polled, assumed := planURLs(s.plan([]entities.Node{node}, ts))
+		switch {
+		case len(polled) == 1:
+			rounds = append(rounds, "p")
+			s.polled(node, ts, entities.Unreachable)
+		case len(assumed) == 1:
+			rounds = append(rounds, "a")
+		default:
+			rounds = append(rounds, "-")
+		}
+	}
+	// back-off starts after 2 minutes with 2 intervals and doubles up to 4 minutes
+	assert.Equal(t, []string{"p", "p", "p", "a", "p", "a", "a", "a", "p", "a", "a", "a", "p", "a", "a", "a"}, rounds)
+
+	s.polled(node, start+960, entities.OK)
+	polled, assumed := planURLs(s.plan([]entities.Node{node}, start+1020))
+	assert.Equal(t, []string{"node"}, polled, "reachable node must be polled with its interval")
+	assert.Empty(t, assumed)
+}
+
+func TestScheduler_acquire(t *testing.T) {
+	s := newScheduler(time.Minute)
+	s.opts.Concurrency = 1
+	s.init()
+	release, ok := s.acquire(context.Background())
+	require.True(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, ok = s.acquire(ctx)
+	assert.False(t, ok, "the slot must be busy")
+
+	release()
+	release, ok = s.acquire(context.Background())
+	require.True(t, ok)
+	release()
+}
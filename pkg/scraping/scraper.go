@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 
 	"nodemon/pkg/entities"
+	"nodemon/pkg/metrics"
 	"nodemon/pkg/storing/events"
 	"nodemon/pkg/storing/nodes"
 
@@ -16,20 +17,46 @@ import (
 )
 
 type Scraper struct {
+	scheme   string
 	ns       nodes.Storage
 	es       *events.Storage
 	interval time.Duration
 	timeout  time.Duration
+	headers  *headersWatchers // nil if the gRPC backend is disabled
+	schedule *scheduler
 	zap      *zap.Logger
 }
 
+type ScraperOption func(*Scraper)
+
+// WithGRPC enables the gRPC scraping backend: the block headers are streamed by the nodes gRPC API,
+// the REST API is used for the version and the state hash, and as the fallback if the stream is broken.
+func WithGRPC(opts GRPCOptions) ScraperOption {
+	return func(s *Scraper) { s.headers = newHeadersWatchers(opts, s.zap) }
+}
+
 func NewScraper(
+	scheme string,
 	ns nodes.Storage,
 	es *events.Storage,
 	interval, timeout time.Duration,
 	logger *zap.Logger,
+	opts ...ScraperOption,
 ) (*Scraper, error) {
-	return &Scraper{ns: ns, es: es, interval: interval, timeout: timeout, zap: logger}, nil
+	s := &Scraper{
+		scheme:   scheme,
+		ns:       ns,
+		es:       es,
+		interval: interval,
+		timeout:  timeout,
+		schedule: newScheduler(interval),
+		zap:      logger,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.schedule.init()
+	return s, nil
 }
 
 func (s *Scraper) Start(ctx context.Context) <-chan entities.NodesGatheringNotification {
@@ -55,21 +82,32 @@ func (s *Scraper) Start(ctx context.Context) <-chan entities.NodesGatheringNotif
 }
 
 func (s *Scraper) poll(ctx context.Context, notifications chan<- entities.NodesGatheringNotification, now int64) {
-	ctx, cancel := context.WithCancel(ctx)
+	pollCtx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	enabledNodes, storageErr := s.ns.EnabledNodes()
+	var plan pollPlan
 	if storageErr != nil {
 		s.zap.Error("[SCRAPER] Failed to get nodes from storage", zap.Error(storageErr))
 		notifications <- entities.NewNodesGatheringError(
 			errors.Wrapf(storageErr, "scraper: failed to get nodes from storage"), now,
 		)
+	} else {
+		urls := make([]string, len(enabledNodes))
+		for i := range enabledNodes {
+			urls[i] = enabledNodes[i].URL
+		}
+		metrics.KeepNodes(s.scheme, urls)
+		if s.headers != nil {
+			s.headers.sync(ctx, urls) // the streams live longer than the poll
+		}
+		plan = s.schedule.plan(enabledNodes, now)
 	}
 
-	ec := s.queryNodes(ctx, enabledNodes, now)
+	ec := s.queryNodes(pollCtx, plan.polled, now)
 	cnt := 0
 	var errs []error
-	for e := range ec {
+	put := func(e entities.Event) {
 		if err := s.es.PutEvent(e); err != nil {
 			s.zap.Sugar().Errorf("[SCRAPER] Failed to collect event '%T' from node %s, statement=%+v: %v",
 				e, e.Node(), e.Statement(), err)
@@ -80,17 +118,26 @@ func (s *Scraper) poll(ctx context.Context, notifications chan<- entities.NodesG
 			cnt++
 		}
 	}
+	for e := range ec {
+		put(e)
+	}
+	for _, url := range plan.assumed { // backed off nodes stay unreachable until the next probe
+		put(entities.NewUnreachableEvent(url, now))
+	}
 	if len(errs) > 0 {
 		sumErr := errors.Wrapf(stderrs.Join(errs...), "scraper: failed to collect %d events", len(errs))
 		notifications <- entities.NewNodesGatheringError(sumErr, now)
 		return
 	}
-	s.zap.Sugar().Infof("[SCRAPER] Polling of %d nodes completed with %d events saved", len(enabledNodes), cnt)
+	s.zap.Sugar().Infof("[SCRAPER] Polling of %d nodes completed with %d events saved, %d nodes are backed off",
+		len(plan.polled), cnt, len(plan.assumed),
+	)
 
-	urls := make([]string, len(enabledNodes))
-	for i := range enabledNodes {
-		urls[i] = enabledNodes[i].URL
+	urls := make([]string, 0, len(plan.polled)+len(plan.assumed))
+	for i := range plan.polled {
+		urls = append(urls, plan.polled[i].URL)
 	}
+	urls = append(urls, plan.assumed...)
 	notifications <- entities.NewNodesGatheringComplete(urls, now)
 }
 
@@ -103,12 +150,20 @@ func (s *Scraper) queryNodes(ctx context.Context, nodes []entities.Node, now int
 		}()
 		wg.Add(len(nodes))
 		for i := range nodes {
-			nodeURL := nodes[i].URL
+			node := nodes[i]
 			go func() {
 				defer wg.Done()
-				event := s.queryNode(ctx, nodeURL, now)
+				release, ok := s.schedule.acquire(ctx)
+				if !ok {
+					return
+				}
+				defer release()
+				start := time.Now()
+				event := s.queryNode(ctx, node, now)
+				metrics.NodeScraped(s.scheme, event, time.Since(start))
+				s.schedule.polled(node, now, event.Statement().Status)
 				s.zap.Sugar().Infof("[SCRAPER] Collected event (%T) at height %d for node %s",
-					event, event.Height(), nodeURL,
+					event, event.Height(), node.URL,
 				)
 				ec <- event
 			}()
@@ -119,8 +174,32 @@ func (s *Scraper) queryNodes(ctx context.Context, nodes []entities.Node, now int
 	return ec
 }
 
-func (s *Scraper) queryNode(ctx context.Context, url string, ts int64) entities.Event {
-	node := newNodeClient(url, s.timeout, s.zap)
+// RunAlerts passes the alerts through and makes the alerted nodes polled each round for a while, which only
+// matters for the nodes with their own longer polling interval.
+func (s *Scraper) RunAlerts(input <-chan entities.Alert) <-chan entities.Alert {
+	output := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		for alert := range input {
+			switch alert.(type) {
+			case *entities.AlertFixed, *entities.UnreachableAlert: // unreachable nodes are probed by back-off
+			default:
+				s.schedule.followUp(entities.AlertNodes(alert), time.Now().Unix())
+			}
+			output <- alert
+		}
+	}()
+	return output
+}
+
+func (s *Scraper) queryNode(ctx context.Context, n entities.Node, ts int64) entities.Event {
+	url, timeout := n.URL, n.Polling.TimeoutOr(s.timeout)
+	if s.headers != nil {
+		if header, ok := s.headers.header(url); ok && s.isHeaderCurrent(ctx, url, timeout, header) {
+			return s.queryNodeWithHeader(ctx, url, timeout, ts, header)
+		}
+	}
+	node := newNodeClient(url, timeout, s.zap)
 	v, err := node.version(ctx)
 	if err != nil {
 		s.zap.Sugar().Warnf("[SCRAPER] Failed to get version for node %s: %v", url, err)
@@ -171,3 +250,71 @@ func (s *Scraper) queryNode(ctx context.Context, url string, ts int64) entities.
 	s.zap.Sugar().Debugf("[SCRAPER] Node %s has state hash %s at height %d", url, sh.SumHash.Hex(), h)
 	return entities.NewStateHashEvent(url, ts, v, h, sh, bs, &blockID, &generator, challenged) // sending full info
 }
+
+// isHeaderCurrent reports whether the streamed header is still the last block of the node. The header which
+// hasn't been updated for MaxHeaderAge is checked against the height by REST API: if it lags behind, the stream
+// is restarted and the node is polled by REST API only.
+func (s *Scraper) isHeaderCurrent(ctx context.Context, url string, timeout time.Duration, header streamedHeader) bool {
+	maxAge := s.headers.opts.MaxHeaderAge
+	if maxAge <= 0 || time.Since(header.received) < maxAge {
+		return true
+	}
+	h, err := newNodeClient(url, timeout, s.zap).height(ctx)
+	if err != nil {
+		return false // REST API polling reports what's wrong with the node
+	}
+	if h == header.height {
+		return true // there are no new blocks
+	}
+	s.zap.Warn("[SCRAPER] Block headers stream lags behind the node, restarting it",
+		zap.String("node", url), zap.Uint64("streamed", header.height), zap.Uint64("height", h),
+	)
+	s.headers.restart(url)
+	return false
+}
+
+// queryNodeWithHeader completes the streamed block header with the node version, base target and state hash.
+// The event is the same as the one collected by REST API only.
+func (s *Scraper) queryNodeWithHeader(
+	ctx context.Context,
+	url string,
+	timeout time.Duration,
+	ts int64,
+	header streamedHeader,
+) entities.Event {
+	node := newNodeClient(url, timeout, s.zap)
+	v, err := node.version(ctx)
+	if err != nil {
+		s.zap.Sugar().Warnf("[SCRAPER] Failed to get version for node %s: %v", url, err)
+		return entities.NewUnreachableEvent(url, ts)
+	}
+	const minValidHeight = 2
+	h := header.height
+	if h < minValidHeight {
+		s.zap.Sugar().Warnf("[SCRAPER] Node %s has invalid height %d", url, h)
+		return entities.NewInvalidHeightEvent(url, ts, v, h)
+	}
+	var (
+		blockID    = header.blockID
+		generator  = header.generator
+		challenged = header.challenged
+	)
+
+	h-- // Go to previous height to request base target and state hash
+
+	bs := header.prevBaseTarget
+	if bs == 0 { // the previous block isn't streamed yet
+		bs, err = node.baseTarget(ctx, h)
+		if err != nil {
+			s.zap.Sugar().Warnf("[SCRAPER] Failed to get base target at height %d for node %s: %v", h, url, err)
+			return entities.NewBlockHeaderEvent(url, ts, v, h, &blockID, &generator, challenged)
+		}
+	}
+
+	sh, err := node.stateHash(ctx, h)
+	if err != nil {
+		s.zap.Sugar().Warnf("[SCRAPER] Failed to get state hash for node %s at height %d: %v", url, h, err)
+		return entities.NewBaseTargetEvent(url, ts, v, h, bs, &blockID, &generator, challenged)
+	}
+	return entities.NewStateHashEvent(url, ts, v, h, sh, bs, &blockID, &generator, challenged)
+}
@@ -0,0 +1,151 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+)
+
+const alertmanagerAlertsPath = "/api/v2/alerts"
+
+// Labels of the alerts pushed to Alertmanager.
+const (
+	AlertmanagerNameLabel   = "alertname"
+	AlertmanagerLevelLabel  = "level"
+	AlertmanagerNodesLabel  = "nodes"
+	AlertmanagerSchemeLabel = "scheme"
+	AlertmanagerIDLabel     = "alert_id"
+)
+
+// AlertmanagerAlert is the alert in the format of Alertmanager API v2.
+type AlertmanagerAlert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      *time.Time        `json:"endsAt,omitempty"`
+}
+
+// NewAlertmanagerAlert converts the alert to the Alertmanager format. The alert ID is used as a label, so it defines
+// the Alertmanager fingerprint of the alert. AlertFixed gets the labels of the fixed alert and sets the end time,
+// which resolves the alert in Alertmanager. The firing alert has no end time.
+func NewAlertmanagerAlert(alert entities.Alert, scheme string) AlertmanagerAlert {
+	firing := alert
+	fixed, isFixed := alert.(*entities.AlertFixed)
+	if isFixed && fixed.Fixed != nil {
+		firing = fixed.Fixed
+	}
+	nodes := entities.AlertNodes(firing)
+	slices.Sort(nodes)
+	out := AlertmanagerAlert{
+		Labels: map[string]string{
+			AlertmanagerNameLabel:   firing.Name().String(),
+			AlertmanagerLevelLabel:  firing.Level(),
+			AlertmanagerSchemeLabel: scheme,
+			AlertmanagerIDLabel:     firing.ID().Hex(),
+		},
+		Annotations: map[string]string{
+			"summary": firing.Message(),
+		},
+		StartsAt: firing.Time().UTC(),
+	}
+	if len(nodes) > 0 {
+		out.Labels[AlertmanagerNodesLabel] = strings.Join(slices.Compact(nodes), ",")
+	}
+	if isFixed {
+		endsAt := alert.Time().UTC()
+		out.EndsAt = &endsAt
+		out.Annotations["resolution"] = alert.Message()
+	}
+	return out
+}
+
+const (
+	// alertmanagerEndsAtRefreshes is the number of the refresh intervals the pushed firing alert lasts for
+	// in Alertmanager, so a single failed refresh doesn't resolve it.
+	alertmanagerEndsAtRefreshes = 3
+	// alertmanagerFiringTTL is the time after which the firing alert which isn't repeated by the monitoring service
+	// is no longer refreshed. The notification about its fix may be lost, e.g. on the monitoring service restart.
+	alertmanagerFiringTTL = 24 * time.Hour
+)
+
+type firingAlert struct {
+	alert    entities.Alert
+	received time.Time // the last time the alert was received from the monitoring service
+}
+
+// alertmanagerSink pushes the alerts to Alertmanager API. The monitoring service repeats the active alerts with
+// the growing intervals, so the sink re-pushes the firing alerts each refresh interval with the end time a few
+// intervals ahead. Otherwise, Alertmanager would resolve them after its resolve_timeout.
+type alertmanagerSink struct {
+	poster          *poster
+	scheme          string
+	refreshInterval time.Duration
+	firing          map[crypto.Digest]firingAlert // accessed by the sink worker only
+	now             func() time.Time
+}
+
+func newAlertmanagerSink(cfg SinkConfig, scheme string) *alertmanagerSink {
+	cfg.URL = strings.TrimSuffix(cfg.URL, "/") + alertmanagerAlertsPath
+	return &alertmanagerSink{
+		poster:          newPoster(cfg),
+		scheme:          scheme,
+		refreshInterval: cfg.RefreshInterval,
+		firing:          make(map[crypto.Digest]firingAlert),
+		now:             time.Now,
+	}
+}
+
+func (s *alertmanagerSink) Send(ctx context.Context, n *Notification) error {
+	if fixed, ok := n.Alert.(*entities.AlertFixed); ok {
+		if fixed.Fixed != nil {
+			delete(s.firing, fixed.Fixed.ID())
+		}
+		return s.push(ctx, []AlertmanagerAlert{NewAlertmanagerAlert(n.Alert, s.scheme)})
+	}
+	now := s.now()
+	s.firing[n.Alert.ID()] = firingAlert{alert: n.Alert, received: now}
+	return s.push(ctx, []AlertmanagerAlert{s.firingAlert(n.Alert, now)})
+}
+
+func (s *alertmanagerSink) interval() time.Duration {
+	return s.refreshInterval
+}
+
+// refresh re-pushes the firing alerts and forgets the ones which haven't been repeated for alertmanagerFiringTTL.
+func (s *alertmanagerSink) refresh(ctx context.Context) error {
+	now := s.now()
+	alerts := make([]AlertmanagerAlert, 0, len(s.firing))
+	for id, f := range s.firing {
+		if now.Sub(f.received) > alertmanagerFiringTTL {
+			delete(s.firing, id)
+			continue
+		}
+		alerts = append(alerts, s.firingAlert(f.alert, now))
+	}
+	if len(alerts) == 0 {
+		return nil
+	}
+	return s.push(ctx, alerts)
+}
+
+func (s *alertmanagerSink) firingAlert(alert entities.Alert, now time.Time) AlertmanagerAlert {
+	out := NewAlertmanagerAlert(alert, s.scheme)
+	endsAt := now.Add(alertmanagerEndsAtRefreshes * s.refreshInterval).UTC()
+	out.EndsAt = &endsAt
+	return out
+}
+
+func (s *alertmanagerSink) push(ctx context.Context, alerts []AlertmanagerAlert) error {
+	body, err := json.Marshal(alerts)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alertmanager payload")
+	}
+	return s.poster.post(ctx, body, nil)
+}
@@ -0,0 +1,92 @@
+package sinks_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/sinks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertmanagerSink(t *testing.T) {
+	alert := &entities.HeightAlert{
+		Timestamp:        100,
+		MaxHeightGroup:   entities.HeightGroup{Height: 10, Nodes: entities.Nodes{"node-b", "node-a"}},
+		OtherHeightGroup: entities.HeightGroup{Height: 5, Nodes: entities.Nodes{"node-c"}},
+	}
+	recv, srv := newStandIn(t)
+	runDispatcher(t, sinks.SinkConfig{Name: "am", Type: sinks.AlertmanagerType, URL: srv.URL + "/"},
+		mkNotification(t, alert),
+		mkNotification(t, &entities.AlertFixed{Timestamp: 160, Fixed: alert}),
+	)
+	requests := recv.wait(t, 2)
+	expectedLabels := map[string]string{
+		"alertname": "HeightAlert",
+		"level":     entities.ErrorLevel,
+		"nodes":     "node-a,node-b,node-c",
+		"scheme":    "mainnet",
+		"alert_id":  alert.ID().Hex(),
+	}
+
+	assert.Equal(t, "/api/v2/alerts", requests[0].path)
+	var firing []sinks.AlertmanagerAlert
+	require.NoError(t, json.Unmarshal(requests[0].body, &firing))
+	require.Len(t, firing, 1)
+	assert.Equal(t, expectedLabels, firing[0].Labels)
+	assert.Equal(t, map[string]string{"summary": alert.Message()}, firing[0].Annotations)
+	assert.True(t, time.Unix(100, 0).Equal(firing[0].StartsAt))
+	require.NotNil(t, firing[0].EndsAt)
+	assert.True(t, firing[0].EndsAt.After(time.Now().Add(time.Hour)), "firing alert must last a few refreshes")
+
+	var resolved []sinks.AlertmanagerAlert
+	require.NoError(t, json.Unmarshal(requests[1].body, &resolved))
+	require.Len(t, resolved, 1)
+	assert.Equal(t, expectedLabels, resolved[0].Labels) // the same fingerprint
+	assert.True(t, time.Unix(100, 0).Equal(resolved[0].StartsAt))
+	require.NotNil(t, resolved[0].EndsAt)
+	assert.True(t, time.Unix(160, 0).Equal(*resolved[0].EndsAt))
+}
+
+func TestAlertmanagerSink_Refresh(t *testing.T) {
+	const refreshInterval = 10 * time.Millisecond
+	var (
+		height      = &entities.HeightAlert{Timestamp: 100}
+		unreachable = &entities.UnreachableAlert{Timestamp: 100, Node: "node-a"}
+	)
+	recv, srv := newStandIn(t)
+	runDispatcher(t, sinks.SinkConfig{
+		Name:            "am",
+		Type:            sinks.AlertmanagerType,
+		URL:             srv.URL,
+		RefreshInterval: refreshInterval,
+	},
+		mkNotification(t, height),
+		mkNotification(t, unreachable),
+		mkNotification(t, &entities.AlertFixed{Timestamp: 160, Fixed: unreachable}),
+	)
+	// the refreshes after the fix of the unreachable alert must carry only the height alert
+	var refreshes [][]sinks.AlertmanagerAlert
+	for fixed := false; len(refreshes) < 2; {
+		requests := recv.wait(t, 1)
+		var pushed []sinks.AlertmanagerAlert
+		require.NoError(t, json.Unmarshal(requests[len(requests)-1].body, &pushed))
+		switch {
+		case len(pushed) == 1 && pushed[0].Labels["alert_id"] == unreachable.ID().Hex() &&
+			pushed[0].EndsAt != nil && pushed[0].EndsAt.Equal(time.Unix(160, 0)):
+			fixed = true
+		case fixed:
+			refreshes = append(refreshes, pushed)
+		}
+	}
+	for _, refreshed := range refreshes {
+		require.Len(t, refreshed, 1, "only the firing alert must be refreshed")
+		assert.Equal(t, height.ID().Hex(), refreshed[0].Labels["alert_id"])
+		assert.True(t, time.Unix(100, 0).Equal(refreshed[0].StartsAt))
+		require.NotNil(t, refreshed[0].EndsAt)
+		assert.True(t, refreshed[0].EndsAt.After(time.Now()))
+	}
+}
@@ -0,0 +1,46 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
+
+// chatPayload is the body of Slack and Mattermost incoming webhooks.
+type chatPayload struct {
+	Text string `json:"text"`
+}
+
+type chatFormat func(alert entities.Alert) string
+
+// slackFormat uses Slack mrkdwn where the bold text is wrapped in single asterisks.
+func slackFormat(alert entities.Alert) string {
+	return fmt.Sprintf("*%s* (%s)\n%s", alert.Name(), alert.Level(), alert.Message())
+}
+
+// mattermostFormat uses the regular Markdown.
+func mattermostFormat(alert entities.Alert) string {
+	return fmt.Sprintf("**%s** (%s)\n%s", alert.Name(), alert.Level(), alert.Message())
+}
+
+// chatSink posts the human-readable alert text to Slack or Mattermost compatible incoming webhook.
+type chatSink struct {
+	poster *poster
+	format chatFormat
+}
+
+func newChatSink(cfg SinkConfig, format chatFormat) *chatSink {
+	return &chatSink{poster: newPoster(cfg), format: format}
+}
+
+func (s *chatSink) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(chatPayload{Text: s.format(n.Alert)})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chat payload")
+	}
+	return s.poster.post(ctx, body, nil)
+}
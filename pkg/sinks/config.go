@@ -0,0 +1,168 @@
+package sinks
+
+import (
+	"bytes"
+	stderrs "errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	defaultTimeout         = 10 * time.Second
+	defaultMaxAttempts     = 5
+	defaultRetryBackoff    = time.Second
+	defaultMaxRetryBackoff = time.Minute
+	defaultQueueSize       = 100
+	defaultRefreshInterval = time.Minute
+)
+
+// Type is the kind of the sink which defines the payload format.
+type Type string
+
+const (
+	WebhookType    Type = "webhook"
+	SlackType      Type = "slack"
+	MattermostType Type = "mattermost"
+	// AlertmanagerType pushes the alerts to Alertmanager API v2, the URL is the base URL of Alertmanager.
+	AlertmanagerType Type = "alertmanager"
+)
+
+// Config is the list of the sinks which can be loaded from a YAML or JSON file.
+type Config struct {
+	Sinks []SinkConfig `yaml:"sinks"`
+}
+
+// SinkConfig describes a single alert receiver. The zero values of the optional fields are replaced with defaults.
+type SinkConfig struct {
+	Name string `yaml:"name"`
+	Type Type   `yaml:"type"`
+	URL  string `yaml:"url"`
+	// Secret is the key of the HMAC-SHA256 signature of the webhook body. Empty value disables signing.
+	Secret string `yaml:"secret"`
+	// Alerts is the list of the alert names delivered to the sink. Empty list means all alerts.
+	Alerts []entities.AlertName `yaml:"alerts"`
+	// Timeout is the timeout of a single delivery attempt.
+	Timeout time.Duration `yaml:"timeout"`
+	// MaxAttempts is the number of delivery attempts including the first one.
+	MaxAttempts int `yaml:"max_attempts"`
+	// RetryBackoff is the delay before the first retry, it's doubled for every next retry up to MaxRetryBackoff.
+	RetryBackoff    time.Duration `yaml:"retry_backoff"`
+	MaxRetryBackoff time.Duration `yaml:"max_retry_backoff"`
+	// QueueSize is the number of the alerts waiting for delivery, new alerts are dropped when the queue is full.
+	QueueSize int `yaml:"queue_size"`
+	// RefreshInterval is the period of re-pushing the firing alerts to Alertmanager, it must be shorter than
+	// resolve_timeout of Alertmanager. Used by the alertmanager sink only.
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+}
+
+// LoadConfig reads the sinks configuration file. Environment variables in the form of ${VAR} are expanded in
+// the file, so the secrets and webhook URLs may be kept out of it. The configuration isn't validated.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read sinks config file '%s'", path)
+	}
+	expanded := os.ExpandEnv(string(data))
+	cfg := new(Config)
+	dec := yaml.NewDecoder(bytes.NewReader([]byte(expanded))) // JSON is a subset of YAML
+	dec.KnownFields(true)
+	if decErr := dec.Decode(cfg); decErr != nil && !errors.Is(decErr, io.EOF) { // EOF means empty file
+		return nil, errors.Wrapf(decErr, "failed to parse sinks config file '%s'", path)
+	}
+	cfg.applyDefaults()
+	return cfg, nil
+}
+
+func (c *Config) applyDefaults() {
+	for i := range c.Sinks {
+		s := &c.Sinks[i]
+		if s.Timeout == 0 {
+			s.Timeout = defaultTimeout
+		}
+		if s.MaxAttempts == 0 {
+			s.MaxAttempts = defaultMaxAttempts
+		}
+		if s.RetryBackoff == 0 {
+			s.RetryBackoff = defaultRetryBackoff
+		}
+		if s.MaxRetryBackoff == 0 {
+			s.MaxRetryBackoff = defaultMaxRetryBackoff
+		}
+		if s.QueueSize == 0 {
+			s.QueueSize = defaultQueueSize
+		}
+		if s.Type == AlertmanagerType && s.RefreshInterval == 0 {
+			s.RefreshInterval = defaultRefreshInterval
+		}
+	}
+}
+
+func (c *Config) Validate() error {
+	if len(c.Sinks) == 0 {
+		return errors.New("no sinks configured")
+	}
+	var errs []error
+	names := make(map[string]struct{}, len(c.Sinks))
+	for i, s := range c.Sinks {
+		if s.Name == "" {
+			errs = append(errs, errors.Errorf("sinks[%d]: name is required", i))
+		} else if _, ok := names[s.Name]; ok {
+			errs = append(errs, errors.Errorf("sinks[%d]: duplicate name '%s'", i, s.Name))
+		}
+		names[s.Name] = struct{}{}
+		if err := s.validate(); err != nil {
+			errs = append(errs, errors.Wrapf(err, "sinks[%d]", i))
+		}
+	}
+	return stderrs.Join(errs...)
+}
+
+func (s *SinkConfig) validate() error {
+	var errs []error
+	switch s.Type {
+	case WebhookType:
+	case SlackType, MattermostType, AlertmanagerType:
+		if s.Secret != "" {
+			errs = append(errs, errors.Errorf("secret isn't supported by '%s' sink", s.Type))
+		}
+	default:
+		errs = append(errs, errors.Errorf("unknown sink type '%s'", s.Type))
+	}
+	if u, err := url.Parse(s.URL); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid url"))
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		errs = append(errs, errors.Errorf("url '%s' must be http or https", s.URL))
+	}
+	for _, name := range s.Alerts {
+		if _, ok := name.AlertType(); !ok {
+			errs = append(errs, errors.Errorf("unknown alert name '%s'", name))
+		}
+	}
+	if s.Timeout <= 0 {
+		errs = append(errs, errors.New("timeout must be positive"))
+	}
+	if s.MaxAttempts <= 0 {
+		errs = append(errs, errors.New("max_attempts must be positive"))
+	}
+	if s.RetryBackoff <= 0 || s.MaxRetryBackoff < s.RetryBackoff {
+		errs = append(errs, errors.New("retry_backoff must be positive and not greater than max_retry_backoff"))
+	}
+	if s.QueueSize <= 0 {
+		errs = append(errs, errors.New("queue_size must be positive"))
+	}
+	switch {
+	case s.Type == AlertmanagerType && s.RefreshInterval <= 0:
+		errs = append(errs, errors.New("refresh_interval must be positive"))
+	case s.Type != AlertmanagerType && s.RefreshInterval != 0:
+		errs = append(errs, errors.Errorf("refresh_interval isn't supported by '%s' sink", s.Type))
+	}
+	return stderrs.Join(errs...)
+}
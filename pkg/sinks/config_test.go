@@ -0,0 +1,76 @@
+package sinks_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/sinks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig(t *testing.T) {
+	t.Setenv("SINKS_TEST_SECRET", "s3cr3t")
+	path := filepath.Join(t.TempDir(), "sinks.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+sinks:
+  - name: oncall
+    type: webhook
+    url: https://example.com/hook
+    secret: ${SINKS_TEST_SECRET}
+    alerts: [UnreachableAlert]
+    max_attempts: 3
+  - name: chat
+    type: slack
+    url: https://hooks.slack.com/services/T/B/X
+  - name: am
+    type: alertmanager
+    url: http://alertmanager:9093
+`), 0o600))
+	cfg, err := sinks.LoadConfig(path)
+	require.NoError(t, err)
+	require.NoError(t, cfg.Validate())
+	require.Len(t, cfg.Sinks, 3)
+	assert.Equal(t, "s3cr3t", cfg.Sinks[0].Secret)
+	assert.Equal(t, []entities.AlertName{entities.UnreachableAlertName}, cfg.Sinks[0].Alerts)
+	assert.Equal(t, 3, cfg.Sinks[0].MaxAttempts)
+	assert.Equal(t, 5, cfg.Sinks[1].MaxAttempts)
+	assert.Equal(t, 10*time.Second, cfg.Sinks[1].Timeout)
+	assert.Zero(t, cfg.Sinks[1].RefreshInterval)
+	assert.Equal(t, time.Minute, cfg.Sinks[2].RefreshInterval)
+}
+
+func TestConfigValidate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sinks.yml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+sinks:
+  - name: a
+    type: unknown
+    url: ftp://example.com
+  - name: a
+    type: mattermost
+    url: https://example.com
+    secret: x
+    alerts: [NoSuchAlert]
+    refresh_interval: 1m
+`), 0o600))
+	cfg, err := sinks.LoadConfig(path)
+	require.NoError(t, err)
+	err = cfg.Validate()
+	require.Error(t, err)
+	for _, msg := range []string{
+		"unknown sink type 'unknown'",
+		"must be http or https",
+		"duplicate name 'a'",
+		"secret isn't supported",
+		"unknown alert name 'NoSuchAlert'",
+		"refresh_interval isn't supported by 'mattermost' sink",
+	} {
+		assert.ErrorContains(t, err, msg)
+	}
+	assert.Error(t, (&sinks.Config{}).Validate())
+}
@@ -0,0 +1,265 @@
+package sinks
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/messaging"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+// Notification is the alert message received from the pubsub server and decoded for delivery.
+type Notification struct {
+	ReferenceID crypto.Digest
+	Alert       entities.Alert
+	Data        json.RawMessage // the alert in JSON as it was published
+}
+
+func NewNotification(msg messaging.AlertMessage) (*Notification, error) {
+	alert, err := entities.NewAlertByType(msg.AlertType())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode alert message")
+	}
+	if unmarshalErr := json.Unmarshal(msg.Data(), alert); unmarshalErr != nil {
+		return nil, errors.Wrapf(unmarshalErr, "failed to unmarshal alert of type %d", msg.AlertType())
+	}
+	return &Notification{ReferenceID: msg.ReferenceID(), Alert: alert, Data: msg.Data()}, nil
+}
+
+// withAlert returns the notification about the alert which has replaced the original one, e.g. the alert group
+// with some members stripped. The reference ID is kept, so the fix of the alert still refers to it.
+func (n *Notification) withAlert(alert entities.Alert) (*Notification, error) {
+	if alert == n.Alert {
+		return n, nil
+	}
+	data, err := json.Marshal(alert)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal alert %s", alert.Name())
+	}
+	return &Notification{ReferenceID: n.ReferenceID, Alert: alert, Data: data}, nil
+}
+
+// Sink delivers the notification to an external receiver.
+type Sink interface {
+	Send(ctx context.Context, n *Notification) error
+}
+
+func newSink(cfg SinkConfig, scheme string) Sink {
+	switch cfg.Type {
+	case AlertmanagerType:
+		return newAlertmanagerSink(cfg, scheme)
+	case SlackType:
+		return newChatSink(cfg, slackFormat)
+	case MattermostType:
+		return newChatSink(cfg, mattermostFormat)
+	default:
+		return newWebhookSink(cfg)
+	}
+}
+
+// filter matches the notifications by the alert type. The notification about the fixed alert matches
+// if the fixed alert type is subscribed or if AlertFixed itself is subscribed. The alert group matches
+// if any of its member alerts matches, the other members are stripped from it, unless AlertGroup itself
+// is subscribed.
+type filter map[entities.AlertType]struct{}
+
+func newFilter(names []entities.AlertName) filter {
+	if len(names) == 0 {
+		return nil // all alerts
+	}
+	f := make(filter, len(names))
+	for _, name := range names {
+		if t, ok := name.AlertType(); ok {
+			f[t] = struct{}{}
+		}
+	}
+	return f
+}
+
+// apply returns the alert as the sink receives it or nil if the sink doesn't receive it.
+func (f filter) apply(alert entities.Alert) entities.Alert {
+	if f == nil {
+		return alert
+	}
+	if _, ok := f[alert.Type()]; ok {
+		return alert
+	}
+	switch a := alert.(type) {
+	case *entities.AlertFixed:
+		if a.Fixed == nil {
+			return nil
+		}
+		fixed := f.apply(a.Fixed)
+		if fixed == nil {
+			return nil
+		}
+		if fixed == a.Fixed {
+			return a
+		}
+		return &entities.AlertFixed{Timestamp: a.Timestamp, Fixed: fixed}
+	case *entities.AlertGroup:
+		group := a.FilterMembers(func(m entities.AlertGroupMember) bool {
+			t, ok := m.Name.AlertType()
+			if !ok {
+				return false
+			}
+			_, ok = f[t]
+			return ok
+		})
+		if group == nil {
+			return nil // the typed nil pointer must not be returned as the interface
+		}
+		return group
+	default:
+		return nil
+	}
+}
+
+func (f filter) types() []entities.AlertType {
+	if f == nil {
+		all := entities.GetAllAlertTypesAndNames()
+		types := make([]entities.AlertType, 0, len(all))
+		for t := range all {
+			types = append(types, t)
+		}
+		return types
+	}
+	types := make([]entities.AlertType, 0, len(f)+1)
+	for t := range f {
+		types = append(types, t)
+	}
+	types = append(types, entities.AlertFixedType)
+	if _, ok := f[entities.AlertGroupType]; !ok {
+		types = append(types, entities.AlertGroupType) // the groups may contain the subscribed alerts
+	}
+	return types
+}
+
+// refresher is the sink which re-sends its state periodically.
+type refresher interface {
+	interval() time.Duration
+	refresh(ctx context.Context) error
+}
+
+type worker struct {
+	name   string
+	sink   Sink
+	filter filter
+	queue  chan *Notification
+}
+
+// Dispatcher delivers the notifications to the configured sinks. Every sink has its own queue,
+// so a slow or unavailable receiver doesn't delay the others.
+type Dispatcher struct {
+	workers []*worker
+	zap     *zap.Logger
+}
+
+// NewDispatcher creates the sinks for the alerts of the given blockchain scheme.
+func NewDispatcher(cfg *Config, scheme string, logger *zap.Logger) (*Dispatcher, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Wrap(err, "invalid sinks config")
+	}
+	workers := make([]*worker, 0, len(cfg.Sinks))
+	for _, sc := range cfg.Sinks {
+		workers = append(workers, &worker{
+			name:   sc.Name,
+			sink:   newSink(sc, scheme),
+			filter: newFilter(sc.Alerts),
+			queue:  make(chan *Notification, sc.QueueSize),
+		})
+	}
+	return &Dispatcher{workers: workers, zap: logger}, nil
+}
+
+// AlertTypes returns the alert types which are delivered at least to one sink.
+func (d *Dispatcher) AlertTypes() []entities.AlertType {
+	set := make(map[entities.AlertType]struct{})
+	for _, w := range d.workers {
+		for _, t := range w.filter.types() {
+			set[t] = struct{}{}
+		}
+	}
+	types := make([]entities.AlertType, 0, len(set))
+	for t := range set {
+		types = append(types, t)
+	}
+	return types
+}
+
+// Dispatch enqueues the notification for the matching sinks. It never blocks: if the queue of a sink is full,
+// the notification is dropped for that sink.
+func (d *Dispatcher) Dispatch(n *Notification) {
+	for _, w := range d.workers {
+		alert := w.filter.apply(n.Alert)
+		if alert == nil {
+			continue
+		}
+		filtered, err := n.withAlert(alert)
+		if err != nil {
+			d.zap.Error("Failed to filter alert for sink",
+				zap.String("sink", w.name), zap.Stringer("alert", n.Alert.Name()), zap.Error(err),
+			)
+			continue
+		}
+		select {
+		case w.queue <- filtered:
+		default:
+			d.zap.Warn("Sink queue is full, alert is dropped",
+				zap.String("sink", w.name), zap.Stringer("alert", n.Alert.Name()),
+				zap.Stringer("reference", n.ReferenceID),
+			)
+		}
+	}
+}
+
+// Run delivers the dispatched notifications until the context is canceled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, w := range d.workers {
+		wg.Add(1)
+		go func(w *worker) {
+			defer wg.Done()
+			d.runWorker(ctx, w)
+		}(w)
+	}
+	wg.Wait()
+}
+
+func (d *Dispatcher) runWorker(ctx context.Context, w *worker) {
+	var refresh <-chan time.Time
+	r, isRefresher := w.sink.(refresher)
+	if isRefresher {
+		ticker := time.NewTicker(r.interval())
+		defer ticker.Stop()
+		refresh = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-refresh:
+			if err := r.refresh(ctx); err != nil {
+				d.zap.Error("Failed to refresh sink", zap.String("sink", w.name), zap.Error(err))
+			}
+		case n := <-w.queue:
+			if err := w.sink.Send(ctx, n); err != nil {
+				d.zap.Error("Failed to deliver alert",
+					zap.String("sink", w.name), zap.Stringer("alert", n.Alert.Name()),
+					zap.Stringer("reference", n.ReferenceID), zap.Error(err),
+				)
+				continue
+			}
+			d.zap.Debug("Alert delivered",
+				zap.String("sink", w.name), zap.Stringer("alert", n.Alert.Name()),
+				zap.Stringer("reference", n.ReferenceID),
+			)
+		}
+	}
+}
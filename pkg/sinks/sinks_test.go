@@ -0,0 +1,275 @@
+package sinks_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/messaging"
+	"nodemon/pkg/sinks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type receivedRequest struct {
+	path      string
+	body      []byte
+	signature string
+}
+
+// standIn is the local HTTP receiver which replies with the given statuses in turn and with 200 after them.
+type standIn struct {
+	mu       sync.Mutex
+	statuses []int
+	received []receivedRequest
+	done     chan struct{}
+}
+
+func newStandIn(t *testing.T, statuses ...int) (*standIn, *httptest.Server) {
+	s := &standIn{statuses: statuses, done: make(chan struct{}, 100)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		s.mu.Lock()
+		s.received = append(s.received, receivedRequest{
+			path:      r.URL.Path,
+			body:      body,
+			signature: r.Header.Get(sinks.SignatureHeader),
+		})
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+		w.WriteHeader(status)
+		s.done <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *standIn) wait(t *testing.T, n int) []receivedRequest {
+	for range n {
+		select {
+		case <-s.done:
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timeout waiting for requests")
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedRequest(nil), s.received...)
+}
+
+func mkNotification(t *testing.T, alert entities.Alert) *sinks.Notification {
+	msg, err := messaging.NewAlertMessageFromAlert(alert)
+	require.NoError(t, err)
+	data, err := msg.MarshalBinary()
+	require.NoError(t, err)
+	msg, err = messaging.NewAlertMessageFromBytes(data)
+	require.NoError(t, err)
+	n, err := sinks.NewNotification(msg)
+	require.NoError(t, err)
+	return n
+}
+
+func runDispatcher(t *testing.T, cfg sinks.SinkConfig, notifications ...*sinks.Notification) {
+	cfg.Timeout = time.Second
+	cfg.RetryBackoff = time.Millisecond
+	cfg.MaxRetryBackoff = time.Millisecond
+	cfg.QueueSize = 10
+	if cfg.Type == sinks.AlertmanagerType && cfg.RefreshInterval == 0 {
+		cfg.RefreshInterval = time.Hour
+	}
+	if cfg.MaxAttempts == 0 {
+		cfg.MaxAttempts = 3
+	}
+	d, err := sinks.NewDispatcher(&sinks.Config{Sinks: []sinks.SinkConfig{cfg}}, "mainnet", zap.NewNop())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		d.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	for _, n := range notifications {
+		d.Dispatch(n)
+	}
+}
+
+func TestWebhookSink(t *testing.T) {
+	alert := &entities.UnreachableAlert{Timestamp: 100, Node: "node-1"}
+	recv, srv := newStandIn(t, http.StatusInternalServerError, http.StatusTooManyRequests)
+	runDispatcher(t, sinks.SinkConfig{Name: "hook", Type: sinks.WebhookType, URL: srv.URL, Secret: "s3cr3t"},
+		mkNotification(t, alert),
+	)
+	requests := recv.wait(t, 3) // two failures are retried
+	for _, req := range requests {
+		assert.Equal(t, sinks.Sign("s3cr3t", req.body), req.signature)
+	}
+	var payload sinks.WebhookPayload
+	require.NoError(t, json.Unmarshal(requests[2].body, &payload))
+	assert.Equal(t, entities.UnreachableAlertType, payload.AlertType)
+	assert.Equal(t, entities.UnreachableAlertName, payload.AlertName)
+	assert.Equal(t, alert.ID().String(), payload.ReferenceID)
+	assert.Equal(t, entities.ErrorLevel, payload.Level)
+	assert.Equal(t, alert.Message(), payload.Message)
+	assert.Equal(t, int64(100), payload.Timestamp)
+	var decoded entities.UnreachableAlert
+	require.NoError(t, json.Unmarshal(payload.Alert, &decoded))
+	assert.Equal(t, *alert, decoded)
+}
+
+func TestWebhookSink_PermanentError(t *testing.T) {
+	recv, srv := newStandIn(t, http.StatusBadRequest)
+	runDispatcher(t, sinks.SinkConfig{Name: "hook", Type: sinks.WebhookType, URL: srv.URL},
+		mkNotification(t, &entities.SimpleAlert{Timestamp: 1, Description: "first"}),
+		mkNotification(t, &entities.SimpleAlert{Timestamp: 2, Description: "second"}),
+	)
+	requests := recv.wait(t, 2) // the first alert isn't retried
+	assert.Empty(t, requests[0].signature)
+	var payload sinks.WebhookPayload
+	require.NoError(t, json.Unmarshal(requests[1].body, &payload))
+	assert.Equal(t, int64(2), payload.Timestamp)
+}
+
+func TestChatSinks(t *testing.T) {
+	alert := &entities.HeightAlert{Timestamp: 100}
+	for _, test := range []struct {
+		sinkType sinks.Type
+		expected string
+	}{
+		{sinkType: sinks.SlackType, expected: "*HeightAlert* (Error)\n" + alert.Message()},
+		{sinkType: sinks.MattermostType, expected: "**HeightAlert** (Error)\n" + alert.Message()},
+	} {
+		t.Run(string(test.sinkType), func(t *testing.T) {
+			recv, srv := newStandIn(t)
+			runDispatcher(t, sinks.SinkConfig{Name: "chat", Type: test.sinkType, URL: srv.URL},
+				mkNotification(t, alert),
+			)
+			requests := recv.wait(t, 1)
+			assert.JSONEq(t, `{"text":`+mustMarshal(t, test.expected)+`}`, string(requests[0].body))
+		})
+	}
+}
+
+func TestDispatcher_Filter(t *testing.T) {
+	unreachable := &entities.UnreachableAlert{Timestamp: 1, Node: "node-1"}
+	recv, srv := newStandIn(t)
+	runDispatcher(t, sinks.SinkConfig{
+		Name:   "hook",
+		Type:   sinks.WebhookType,
+		URL:    srv.URL,
+		Alerts: []entities.AlertName{entities.UnreachableAlertName},
+	},
+		mkNotification(t, &entities.HeightAlert{Timestamp: 1}),
+		mkNotification(t, unreachable),
+		mkNotification(t, &entities.AlertFixed{Timestamp: 2, Fixed: &entities.HeightAlert{Timestamp: 1}}),
+		mkNotification(t, &entities.AlertFixed{Timestamp: 2, Fixed: unreachable}),
+	)
+	requests := recv.wait(t, 2)
+	names := make([]entities.AlertName, 0, len(requests))
+	for _, req := range requests {
+		var payload sinks.WebhookPayload
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		names = append(names, payload.AlertName)
+	}
+	assert.Equal(t, []entities.AlertName{entities.UnreachableAlertName, entities.AlertFixedName}, names)
+	select {
+	case <-recv.done:
+		assert.Fail(t, "unexpected request")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_FilterGroups(t *testing.T) {
+	const ts = 1
+	var (
+		unreachable = &entities.UnreachableAlert{Timestamp: ts, Node: "node-1"}
+		height      = &entities.HeightAlert{
+			Timestamp:        ts,
+			MaxHeightGroup:   entities.HeightGroup{Height: 10, Nodes: entities.Nodes{"node-2"}},
+			OtherHeightGroup: entities.HeightGroup{Height: 5, Nodes: entities.Nodes{"node-1"}},
+		}
+		group = &entities.AlertGroup{
+			Timestamp: ts,
+			Nodes:     []string{"node-1", "node-2"},
+			Alerts: []entities.AlertGroupMember{
+				entities.NewAlertGroupMember(unreachable),
+				entities.NewAlertGroupMember(height),
+			},
+		}
+		heightOnly = &entities.AlertGroup{
+			Timestamp: ts,
+			Nodes:     []string{"node-1", "node-2"},
+			Alerts:    []entities.AlertGroupMember{entities.NewAlertGroupMember(height)},
+		}
+	)
+	recv, srv := newStandIn(t)
+	runDispatcher(t, sinks.SinkConfig{
+		Name:   "hook",
+		Type:   sinks.WebhookType,
+		URL:    srv.URL,
+		Alerts: []entities.AlertName{entities.UnreachableAlertName},
+	},
+		mkNotification(t, heightOnly),
+		mkNotification(t, group),
+		mkNotification(t, &entities.AlertFixed{Timestamp: ts + 1, Fixed: heightOnly}),
+		mkNotification(t, &entities.AlertFixed{Timestamp: ts + 1, Fixed: group}),
+	)
+	requests := recv.wait(t, 2)
+	stripped := &entities.AlertGroup{
+		Timestamp: ts,
+		Nodes:     []string{"node-1"},
+		Alerts:    []entities.AlertGroupMember{entities.NewAlertGroupMember(unreachable)},
+	}
+	var payload sinks.WebhookPayload
+	require.NoError(t, json.Unmarshal(requests[0].body, &payload))
+	assert.Equal(t, entities.AlertGroupName, payload.AlertName, "the group with the subscribed alert must match")
+	assert.Equal(t, group.ID().String(), payload.ReferenceID)
+	var decoded entities.AlertGroup
+	require.NoError(t, json.Unmarshal(payload.Alert, &decoded))
+	assert.Equal(t, stripped, &decoded, "the alerts which the sink isn't subscribed to must be stripped")
+
+	require.NoError(t, json.Unmarshal(requests[1].body, &payload))
+	assert.Equal(t, entities.AlertFixedName, payload.AlertName)
+	var fixed entities.AlertFixed
+	require.NoError(t, json.Unmarshal(payload.Alert, &fixed))
+	assert.Equal(t, stripped.ID(), fixed.Fixed.ID(), "the fix must refer to the same stripped group")
+	select {
+	case <-recv.done:
+		assert.Fail(t, "unexpected request")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestDispatcher_AlertTypes(t *testing.T) {
+	d, err := sinks.NewDispatcher(&sinks.Config{Sinks: []sinks.SinkConfig{{
+		Name: "hook", Type: sinks.WebhookType, URL: "http://localhost", Timeout: time.Second, MaxAttempts: 1,
+		RetryBackoff: time.Second, MaxRetryBackoff: time.Second, QueueSize: 1,
+		Alerts: []entities.AlertName{entities.HeightAlertName},
+	}}}, "mainnet", zap.NewNop())
+	require.NoError(t, err)
+	assert.ElementsMatch(t,
+		[]entities.AlertType{entities.HeightAlertType, entities.AlertFixedType, entities.AlertGroupType},
+		d.AlertTypes(), "the alert groups may contain the subscribed alerts",
+	)
+}
+
+func mustMarshal(t *testing.T, v any) string {
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
@@ -0,0 +1,138 @@
+package sinks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SignatureHeader keeps the hex encoded HMAC-SHA256 of the webhook body prefixed with "sha256=".
+	SignatureHeader = "X-Nodemon-Signature"
+	signaturePrefix = "sha256="
+)
+
+// WebhookPayload is the JSON body posted to the generic webhook.
+type WebhookPayload struct {
+	AlertType   entities.AlertType `json:"alert_type"`
+	AlertName   entities.AlertName `json:"alert_name"`
+	ReferenceID string             `json:"reference_id"`
+	Level       string             `json:"level"`
+	Message     string             `json:"message"`
+	Timestamp   int64              `json:"timestamp"`
+	Alert       json.RawMessage    `json:"alert"`
+}
+
+// Sign returns the value of the signature header for the body.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write(body) // never returns an error
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+type webhookSink struct {
+	poster *poster
+	secret string
+}
+
+func newWebhookSink(cfg SinkConfig) *webhookSink {
+	return &webhookSink{poster: newPoster(cfg), secret: cfg.Secret}
+}
+
+func (s *webhookSink) Send(ctx context.Context, n *Notification) error {
+	body, err := json.Marshal(WebhookPayload{
+		AlertType:   n.Alert.Type(),
+		AlertName:   n.Alert.Name(),
+		ReferenceID: n.ReferenceID.String(),
+		Level:       n.Alert.Level(),
+		Message:     n.Alert.Message(),
+		Timestamp:   n.Alert.Time().Unix(),
+		Alert:       n.Data,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal webhook payload")
+	}
+	var headers http.Header
+	if s.secret != "" {
+		headers = http.Header{SignatureHeader: []string{Sign(s.secret, body)}}
+	}
+	return s.poster.post(ctx, body, headers)
+}
+
+// permanentError is the delivery error which won't be fixed by retrying.
+type permanentError struct {
+	error
+}
+
+// poster posts JSON bodies to the URL retrying the failed attempts with exponential backoff.
+type poster struct {
+	url             string
+	client          *http.Client
+	maxAttempts     int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+}
+
+func newPoster(cfg SinkConfig) *poster {
+	return &poster{
+		url:             cfg.URL,
+		client:          &http.Client{Timeout: cfg.Timeout},
+		maxAttempts:     cfg.MaxAttempts,
+		retryBackoff:    cfg.RetryBackoff,
+		maxRetryBackoff: cfg.MaxRetryBackoff,
+	}
+}
+
+func (p *poster) post(ctx context.Context, body []byte, headers http.Header) error {
+	backoff := p.retryBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = p.postOnce(ctx, body, headers)
+		var permanent permanentError
+		if err == nil || errors.As(err, &permanent) || attempt >= p.maxAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "delivery canceled after %d attempts: %v", attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, p.maxRetryBackoff)
+	}
+	return err
+}
+
+func (p *poster) postOnce(ctx context.Context, body []byte, headers http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return permanentError{errors.Wrap(err, "failed to create request")}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		req.Header[k] = v
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to post")
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body) // drain the body to reuse the connection
+	switch code := resp.StatusCode; {
+	case code >= http.StatusOK && code < http.StatusMultipleChoices:
+		return nil
+	case code == http.StatusTooManyRequests || code >= http.StatusInternalServerError:
+		return errors.Errorf("unexpected response status %d", code)
+	default:
+		return permanentError{errors.Errorf("unexpected response status %d", code)}
+	}
+}
@@ -0,0 +1,176 @@
+package alertlog
+
+import (
+	"bytes"
+	"cmp"
+	"slices"
+	"sync"
+
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+const DefaultHistorySize = 1000
+
+// AlertStatesProvider provides the repeats and backoff state of the alerts tracked by an analyzer.
+type AlertStatesProvider interface {
+	AlertState(alertID crypto.Digest) (storage.AlertState, bool)
+}
+
+type ActiveAlert struct {
+	ID         crypto.Digest       `json:"id"`
+	Type       entities.AlertType  `json:"type"`
+	Name       entities.AlertName  `json:"name"`
+	Level      string              `json:"level"`
+	Message    string              `json:"message"`
+	OpenedAt   int64               `json:"opened_at"`
+	LastSentAt int64               `json:"last_sent_at"`
+	Sent       int                 `json:"sent"`
+	State      *storage.AlertState `json:"state,omitempty"`
+}
+
+type ResolvedAlert struct {
+	ID       crypto.Digest      `json:"id"`
+	Type     entities.AlertType `json:"type"`
+	Name     entities.AlertName `json:"name"`
+	Level    string             `json:"level"`
+	Message  string             `json:"message"`
+	OpenedAt int64              `json:"opened_at"`
+	ClosedAt int64              `json:"closed_at"`
+}
+
+// Log keeps track of the alerts which have been sent by nodemon: the active ones and the resolved ones.
+// It's safe for concurrent use.
+type Log struct {
+	mu          *sync.RWMutex
+	active      map[crypto.Digest]ActiveAlert
+	resolved    []ResolvedAlert // the oldest first
+	historySize int
+	states      AlertStatesProvider
+	zap         *zap.Logger
+}
+
+// NewLog creates an alerts log which keeps at most historySize resolved alerts.
+// The states provider is optional and can be nil.
+func NewLog(historySize int, states AlertStatesProvider, logger *zap.Logger) *Log {
+	if historySize <= 0 {
+		historySize = DefaultHistorySize
+	}
+	return &Log{
+		mu:          new(sync.RWMutex),
+		active:      make(map[crypto.Digest]ActiveAlert),
+		historySize: historySize,
+		states:      states,
+		zap:         logger,
+	}
+}
+
+// Run records every alert from the input channel and passes it through to the output channel.
+func (l *Log) Run(input <-chan entities.Alert) <-chan entities.Alert {
+	output := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		for alert := range input {
+			l.Put(alert)
+			output <- alert
+		}
+	}()
+	return output
+}
+
+// Put records the sent alert. AlertFixed closes the corresponding active alert and moves it to the history.
+func (l *Log) Put(alert entities.Alert) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if fixed, ok := alert.(*entities.AlertFixed); ok {
+		l.unsafeResolve(fixed)
+		return
+	}
+	var (
+		id = alert.ID()
+		ts = alert.Time().Unix()
+	)
+	a, ok := l.active[id]
+	if !ok {
+		a = ActiveAlert{ID: id, Type: alert.Type(), Name: alert.Name(), OpenedAt: ts}
+	}
+	a.Level = alert.Level()
+	a.Message = alert.Message()
+	a.LastSentAt = ts
+	a.Sent++
+	l.active[id] = a
+}
+
+func (l *Log) unsafeResolve(fixed *entities.AlertFixed) {
+	if fixed.Fixed == nil {
+		l.zap.Warn("Received fixed alert without the alert which has been fixed")
+		return
+	}
+	id := fixed.Fixed.ID()
+	a, ok := l.active[id]
+	if !ok { // alert has been sent before nodemon start
+		a = ActiveAlert{
+			ID:       id,
+			Type:     fixed.Fixed.Type(),
+			Name:     fixed.Fixed.Name(),
+			Level:    fixed.Fixed.Level(),
+			Message:  fixed.Fixed.Message(),
+			OpenedAt: fixed.Fixed.Time().Unix(),
+		}
+	}
+	delete(l.active, id)
+	if len(l.resolved) >= l.historySize {
+		l.resolved = slices.Delete(l.resolved, 0, len(l.resolved)-l.historySize+1)
+	}
+	l.resolved = append(l.resolved, ResolvedAlert{
+		ID:       a.ID,
+		Type:     a.Type,
+		Name:     a.Name,
+		Level:    a.Level,
+		Message:  a.Message,
+		OpenedAt: a.OpenedAt,
+		ClosedAt: fixed.Timestamp,
+	})
+}
+
+// Active returns the active alerts, the most recently opened first.
+func (l *Log) Active() []ActiveAlert {
+	l.mu.RLock()
+	out := make([]ActiveAlert, 0, len(l.active))
+	for _, a := range l.active {
+		out = append(out, a)
+	}
+	l.mu.RUnlock()
+	if l.states != nil {
+		for i := range out {
+			if state, ok := l.states.AlertState(out[i].ID); ok {
+				out[i].State = &state
+			}
+		}
+	}
+	slices.SortFunc(out, func(a, b ActiveAlert) int {
+		if c := cmp.Compare(b.OpenedAt, a.OpenedAt); c != 0 {
+			return c
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	return out
+}
+
+// History returns at most limit resolved alerts, the most recently closed first.
+// If limit is not positive, the whole history is returned.
+func (l *Log) History(limit int) []ResolvedAlert {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if limit <= 0 || limit > len(l.resolved) {
+		limit = len(l.resolved)
+	}
+	out := make([]ResolvedAlert, 0, limit)
+	for i := len(l.resolved) - 1; i >= len(l.resolved)-limit; i-- {
+		out = append(out, l.resolved[i])
+	}
+	return out
+}
@@ -0,0 +1,82 @@
+package alertlog_test
+
+import (
+	"testing"
+
+	"nodemon/pkg/analysis/storage"
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/alertlog"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+type staticStates map[crypto.Digest]storage.AlertState
+
+func (s staticStates) AlertState(alertID crypto.Digest) (storage.AlertState, bool) {
+	state, ok := s[alertID]
+	return state, ok
+}
+
+func TestLogActiveAndResolved(t *testing.T) {
+	var (
+		first  = &entities.UnreachableAlert{Timestamp: 100, Node: "http://a.example.com"}
+		repeat = &entities.UnreachableAlert{Timestamp: 160, Node: "http://a.example.com"}
+		second = &entities.UnreachableAlert{Timestamp: 120, Node: "http://b.example.com"}
+		state  = storage.AlertState{Repeats: 1, BackoffThreshold: 4, VacuumQuota: 5, Confirmed: true}
+	)
+	l := alertlog.NewLog(10, staticStates{first.ID(): state}, zap.NewNop())
+	l.Put(first)
+	l.Put(second)
+	l.Put(repeat)
+
+	active := l.Active()
+	require.Len(t, active, 2)
+	assert.Equal(t, second.ID(), active[0].ID)
+	assert.Nil(t, active[0].State)
+	assert.Equal(t, first.ID(), active[1].ID)
+	assert.Equal(t, int64(100), active[1].OpenedAt)
+	assert.Equal(t, int64(160), active[1].LastSentAt)
+	assert.Equal(t, 2, active[1].Sent)
+	require.NotNil(t, active[1].State)
+	assert.Equal(t, state, *active[1].State)
+
+	l.Put(&entities.AlertFixed{Timestamp: 220, Fixed: repeat})
+
+	active = l.Active()
+	require.Len(t, active, 1)
+	assert.Equal(t, second.ID(), active[0].ID)
+
+	history := l.History(0)
+	require.Len(t, history, 1)
+	assert.Equal(t, alertlog.ResolvedAlert{
+		ID:       first.ID(),
+		Type:     entities.UnreachableAlertType,
+		Name:     entities.UnreachableAlertName,
+		Level:    entities.ErrorLevel,
+		Message:  first.Message(),
+		OpenedAt: 100,
+		ClosedAt: 220,
+	}, history[0])
+}
+
+func TestLogHistoryIsBounded(t *testing.T) {
+	const historySize = 3
+	l := alertlog.NewLog(historySize, nil, zap.NewNop())
+	for ts := int64(1); ts <= 5; ts++ {
+		alert := &entities.SimpleAlert{Timestamp: ts, Description: string(rune('a' + ts))}
+		l.Put(alert)
+		l.Put(&entities.AlertFixed{Timestamp: ts * 10, Fixed: alert})
+	}
+	history := l.History(0)
+	require.Len(t, history, historySize)
+	assert.Equal(t, int64(50), history[0].ClosedAt)
+	assert.Equal(t, int64(30), history[2].ClosedAt)
+
+	history = l.History(1)
+	require.Len(t, history, 1)
+	assert.Equal(t, int64(50), history[0].ClosedAt)
+	assert.Empty(t, l.Active())
+}
@@ -1,15 +1,19 @@
 package events
 
 import (
+	"cmp"
 	"encoding/json"
 	"math"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"nodemon/pkg/entities"
 
 	"github.com/pkg/errors"
 	"github.com/tidwall/buntdb"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 	"github.com/wavesplatform/gowaves/pkg/proto"
 	"go.uber.org/zap"
 )
@@ -29,14 +33,74 @@ type Storage struct {
 	zap               *zap.Logger
 }
 
+const (
+	inMemoryStoragePath = ":memory:"
+
+	autoShrinkPercentage = 100
+	autoShrinkMinSize    = 8 * 1024 * 1024 // 8 MB
+)
+
+// statementsByTimestampIndex orders the statements by their timestamp, the uptime buckets aren't indexed.
+const statementsByTimestampIndex = "statements_by_timestamp"
+
+// createIndexes creates the indexes of the storage. The indexes aren't persisted, so they are built on each open.
+func createIndexes(db *buntdb.DB) error {
+	err := db.CreateIndex(statementsByTimestampIndex, statementKeyNodePartPrefix+"*", buntdb.IndexJSON("timestamp"))
+	return errors.Wrap(err, "failed to create events storage indexes")
+}
+
+// NewStorage creates an in-memory events storage which is lost on restart.
 func NewStorage(retentionDuration time.Duration, logger *zap.Logger) (*Storage, error) {
-	db, err := buntdb.Open(":memory:")
+	db, err := buntdb.Open(inMemoryStoragePath)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to open events storage")
 	}
+	if idxErr := createIndexes(db); idxErr != nil {
+		_ = db.Close()
+		return nil, idxErr
+	}
 	return &Storage{db: db, retentionDuration: retentionDuration, zap: logger}, nil
 }
 
+// NewPersistentStorage creates an events storage backed by the append-only file at the given path.
+// Statements stored earlier are replayed on open with their original expiration time,
+// and the file is compacted in the background as it grows.
+func NewPersistentStorage(path string, retentionDuration time.Duration, logger *zap.Logger) (*Storage, error) {
+	if path == "" || path == inMemoryStoragePath {
+		return nil, errors.Errorf("invalid events storage path %q", path)
+	}
+	db, err := buntdb.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open events storage at %q", path)
+	}
+	cfgErr := db.SetConfig(buntdb.Config{
+		SyncPolicy:           buntdb.EverySecond,
+		AutoShrinkPercentage: autoShrinkPercentage,
+		AutoShrinkMinSize:    autoShrinkMinSize,
+	})
+	if cfgErr != nil {
+		_ = db.Close()
+		return nil, errors.Wrap(cfgErr, "failed to configure events storage")
+	}
+	// compact the file right away to drop statements which have expired while nodemon was down
+	if shrinkErr := db.Shrink(); shrinkErr != nil {
+		_ = db.Close()
+		return nil, errors.Wrapf(shrinkErr, "failed to compact events storage at %q", path)
+	}
+	if idxErr := createIndexes(db); idxErr != nil {
+		_ = db.Close()
+		return nil, idxErr
+	}
+	s := &Storage{db: db, retentionDuration: retentionDuration, zap: logger}
+	cnt, err := s.StatementsCount()
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	logger.Info("Events storage has been loaded", zap.String("path", path), zap.Int("statements", cnt))
+	return s, nil
+}
+
 func (s *Storage) Close() error {
 	if err := s.db.Close(); err != nil {
 		return errors.Wrap(err, "failed to close events storage")
@@ -90,7 +154,10 @@ func (s *Storage) PutEvent(event entities.Event) error {
 	err = s.db.Update(func(tx *buntdb.Tx) error {
 		var setErr error
 		_, _, setErr = tx.Set(key, v, opts)
-		return setErr
+		if setErr != nil {
+			return setErr
+		}
+		return putGeneratorBlock(tx, event.Statement(), opts)
 	})
 	if err != nil {
 		return errors.Wrap(err, "failed to store event")
@@ -104,7 +171,20 @@ func (s *Storage) StatementsCount() (int, error) {
 	err := s.db.View(func(tx *buntdb.Tx) error {
 		var err error
 		cnt, err = tx.Len()
-		return err
+		if err != nil {
+			return err
+		}
+		// the uptime buckets, the generators and the mutes are few, so it's cheaper to subtract them than to count
+		// the statements one by one
+		for _, prefix := range []string{uptimeBucketKeyPrefix, generatorKeyPrefix, muteKeyPrefix} {
+			if ascErr := tx.AscendKeys(prefix+"*", func(_, _ string) bool {
+				cnt--
+				return true
+			}); ascErr != nil {
+				return ascErr
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		return 0, errors.Wrap(err, "failed to query statements")
@@ -345,6 +425,252 @@ func (s *Storage) StateHashAtHeight(node string, height uint64) (proto.StateHash
 	return *st.StateHash, nil
 }
 
+// PutUptimeBucket saves the uptime counters of the node, the bucket expires after the given TTL.
+func (s *Storage) PutUptimeBucket(bucket entities.UptimeBucket, ttl time.Duration) error {
+	v, err := json.Marshal(bucket)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal uptime bucket")
+	}
+	key := uptimeBucketKey(bucket.Node, bucket.Start)
+	err = s.db.Update(func(tx *buntdb.Tx) error {
+		_, _, setErr := tx.Set(key, string(v), &buntdb.SetOptions{Expires: true, TTL: ttl})
+		return setErr
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to store uptime bucket by key %q", key)
+	}
+	return nil
+}
+
+// UptimeBuckets returns all the uptime buckets which haven't expired yet.
+func (s *Storage) UptimeBuckets() ([]entities.UptimeBucket, error) {
+	var buckets []entities.UptimeBucket
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var unmarshalErr error
+		dbErr := tx.AscendKeys(uptimeBucketKeyPrefix+"*", func(key, value string) bool {
+			var b entities.UptimeBucket
+			if unmarshalErr = json.Unmarshal([]byte(value), &b); unmarshalErr != nil {
+				unmarshalErr = errors.Wrapf(unmarshalErr, "failed to unmarshal uptime bucket by key %q", key)
+				return false
+			}
+			buckets = append(buckets, b)
+			return true
+		})
+		if dbErr != nil {
+			return dbErr
+		}
+		return unmarshalErr
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load uptime buckets")
+	}
+	return buckets, nil
+}
+
+// PutMute saves the alert mute or replaces the existing one with the same ID.
+// The mute expires at its deadline, a mute without the deadline is kept until it's deleted.
+func (s *Storage) PutMute(mute entities.AlertMute) error {
+	v, err := json.Marshal(mute)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal alert mute")
+	}
+	var opts *buntdb.SetOptions
+	if mute.ExpiresAt != 0 {
+		ttl := time.Until(time.Unix(mute.ExpiresAt, 0))
+		if ttl <= 0 {
+			return nil // already expired, nothing to keep
+		}
+		opts = &buntdb.SetOptions{Expires: true, TTL: ttl}
+	}
+	key := muteKey(mute.ID)
+	err = s.db.Update(func(tx *buntdb.Tx) error {
+		_, _, setErr := tx.Set(key, string(v), opts)
+		return setErr
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to store alert mute by key %q", key)
+	}
+	return nil
+}
+
+// DeleteMute deletes the alert mute. It's not an error if the mute doesn't exist.
+func (s *Storage) DeleteMute(id crypto.Digest) error {
+	key := muteKey(id)
+	err := s.db.Update(func(tx *buntdb.Tx) error {
+		_, delErr := tx.Delete(key)
+		return delErr
+	})
+	if err != nil && !errors.Is(err, buntdb.ErrNotFound) {
+		return errors.Wrapf(err, "failed to delete alert mute by key %q", key)
+	}
+	return nil
+}
+
+// Mutes returns all the alert mutes which haven't expired yet.
+func (s *Storage) Mutes() ([]entities.AlertMute, error) {
+	var mutes []entities.AlertMute
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var unmarshalErr error
+		dbErr := tx.AscendKeys(muteKeyPrefix+"*", func(key, value string) bool {
+			var m entities.AlertMute
+			if unmarshalErr = json.Unmarshal([]byte(value), &m); unmarshalErr != nil {
+				unmarshalErr = errors.Wrapf(unmarshalErr, "failed to unmarshal alert mute by key %q", key)
+				return false
+			}
+			mutes = append(mutes, m)
+			return true
+		})
+		if dbErr != nil {
+			return dbErr
+		}
+		return unmarshalErr
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load alert mutes")
+	}
+	return mutes, nil
+}
+
+// putGeneratorBlock remembers the block of the statement as the last block of its generator if the block is higher
+// than the remembered one. The record expires with the statement, so it's the same as a search over the history,
+// but its cost doesn't grow with the history.
+func putGeneratorBlock(tx *buntdb.Tx, statement entities.NodeStatement, opts *buntdb.SetOptions) error {
+	if statement.Generator == nil || statement.BlockID == nil {
+		return nil
+	}
+	key := generatorKey(statement.Generator.String())
+	value, err := tx.Get(key)
+	switch {
+	case errors.Is(err, buntdb.ErrNotFound):
+	case err != nil:
+		return err
+	default:
+		var last entities.GeneratorStats
+		if unmarshalErr := json.Unmarshal([]byte(value), &last); unmarshalErr != nil {
+			return errors.Wrapf(unmarshalErr, "failed to unmarshal generator last block by key %q", key)
+		}
+		// the block may be reported by several nodes, the earliest statement about it is kept
+		if last.LastBlockHeight > statement.Height ||
+			(last.LastBlockHeight == statement.Height && last.LastBlockTime <= statement.Timestamp) {
+			return nil
+		}
+	}
+	v, err := json.Marshal(entities.GeneratorStats{
+		Generator:       statement.Generator.String(),
+		LastBlockHeight: statement.Height,
+		LastBlockTime:   statement.Timestamp,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal generator last block")
+	}
+	_, _, err = tx.Set(key, string(v), opts)
+	return err
+}
+
+// LastGeneratorBlock returns the last block produced by the generator within the statements history. Blocks field
+// of the result is always zero. False is returned if the generator hasn't produced blocks.
+func (s *Storage) LastGeneratorBlock(address string) (entities.GeneratorStats, bool, error) {
+	var (
+		last  entities.GeneratorStats
+		found bool
+	)
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		value, err := tx.Get(generatorKey(address))
+		if err != nil {
+			if errors.Is(err, buntdb.ErrNotFound) {
+				return nil
+			}
+			return err
+		}
+		found = true
+		return json.Unmarshal([]byte(value), &last)
+	})
+	if err != nil {
+		return entities.GeneratorStats{}, false, errors.Wrapf(err, "failed to get last block of generator %q", address)
+	}
+	return last, found, nil
+}
+
+// EarliestTimestamp returns the timestamp of the earliest statement in the history. False is returned
+// if the history is empty.
+func (s *Storage) EarliestTimestamp() (int64, bool, error) {
+	var (
+		earliest entities.NodeStatement
+		found    bool
+	)
+	err := s.db.View(func(tx *buntdb.Tx) error {
+		var unmarshalErr error
+		err := tx.Ascend(statementsByTimestampIndex, func(key, value string) bool {
+			found = true
+			if unmarshalErr = json.Unmarshal([]byte(value), &earliest); unmarshalErr != nil {
+				unmarshalErr = errors.Wrapf(unmarshalErr, "failed to unmarshal NodeStatement by key %q", key)
+			}
+			return false
+		})
+		return cmp.Or(err, unmarshalErr)
+	})
+	if err != nil {
+		return 0, false, errors.Wrap(err, "failed to get earliest statement")
+	}
+	return earliest.Timestamp, found, nil
+}
+
+// GeneratorsStats collects the block production statistics of the generators over the statements history.
+// Each block is counted once regardless of the number of nodes which have reported it.
+func (s *Storage) GeneratorsStats() (entities.GeneratorsStats, error) {
+	type block struct {
+		height uint64
+		id     string
+	}
+	var (
+		stats  entities.GeneratorsStats
+		blocks = make(map[block]*entities.NodeStatement)
+	)
+	err := s.viewByKeyPatternWithAscendKeys(newStatementKey("*", "*"), func(statement *entities.NodeStatement) bool {
+		if stats.Since == 0 || statement.Timestamp < stats.Since {
+			stats.Since = statement.Timestamp
+		}
+		stats.Until = max(stats.Until, statement.Timestamp)
+		if statement.Generator == nil || statement.BlockID == nil {
+			return true
+		}
+		b := block{height: statement.Height, id: statement.BlockID.String()}
+		if seen, ok := blocks[b]; !ok || statement.Timestamp < seen.Timestamp {
+			blocks[b] = statement
+		}
+		return true
+	})
+	if err != nil {
+		return entities.GeneratorsStats{}, errors.Wrap(err, "failed to collect generators stats")
+	}
+	generators := make(map[string]*entities.GeneratorStats)
+	for _, statement := range blocks {
+		address := statement.Generator.String()
+		g, ok := generators[address]
+		if !ok {
+			g = &entities.GeneratorStats{Generator: address}
+			generators[address] = g
+		}
+		g.Blocks++
+		if statement.Height > g.LastBlockHeight {
+			g.LastBlockHeight = statement.Height
+			g.LastBlockTime = statement.Timestamp
+		}
+	}
+	stats.Blocks = len(blocks)
+	stats.Generators = make([]entities.GeneratorStats, 0, len(generators))
+	for _, g := range generators {
+		stats.Generators = append(stats.Generators, *g)
+	}
+	slices.SortFunc(stats.Generators, func(a, b entities.GeneratorStats) int {
+		if c := cmp.Compare(b.Blocks, a.Blocks); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Generator, b.Generator)
+	})
+	return stats, nil
+}
+
 func (s *Storage) viewByKeyPatternWithDescendKeys(pattern string, iter func(*entities.NodeStatement) bool) error {
 	return s.db.View(func(tx *buntdb.Tx) (err error) {
 		var (
@@ -3,6 +3,7 @@ package events_test
 import (
 	"errors"
 	"log"
+	"path/filepath"
 	"sort"
 	"testing"
 	"time"
@@ -313,6 +314,112 @@ func TestEventsStorage(t *testing.T) {
 	suite.Run(t, new(EventsStorageTestSuite))
 }
 
+func TestPersistentStorageReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	statement := entities.NodeStatement{
+		Node:      "blah",
+		Timestamp: 100500,
+		Status:    entities.Incomplete,
+		Version:   "v42.0.0",
+		Height:    777,
+	}
+
+	es, err := events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, es.PutEvent(&dummyEvent{statement}))
+	require.NoError(t, es.Close())
+
+	es, err = events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	actual, err := es.GetStatement(statement.Node, statement.Timestamp)
+	require.NoError(t, err)
+	assert.Equal(t, statement, actual)
+}
+
+func TestPersistentStorageExpiredStatements(t *testing.T) {
+	const retention = 100 * time.Millisecond
+	path := filepath.Join(t.TempDir(), "events.db")
+	statement := entities.NodeStatement{Node: "blah", Timestamp: 100500, Status: entities.Unreachable}
+
+	es, err := events.NewPersistentStorage(path, retention, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, es.PutEvent(&dummyEvent{statement}))
+	require.NoError(t, es.Close())
+
+	time.Sleep(2 * retention)
+
+	es, err = events.NewPersistentStorage(path, retention, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	_, err = es.GetStatement(statement.Node, statement.Timestamp)
+	assert.ErrorIs(t, err, events.ErrNotFound)
+}
+
+func TestPersistentStorageUptimeBuckets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	statement := entities.NodeStatement{Node: "blah", Timestamp: 100500, Status: entities.OK}
+	bucket := entities.UptimeBucket{Node: "blah", Start: 100800, Polls: 3, OKPolls: 2, Alerts: 1}
+
+	es, err := events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, es.PutEvent(&dummyEvent{statement}))
+	require.NoError(t, es.PutUptimeBucket(bucket, time.Hour))
+	require.NoError(t, es.Close())
+
+	es, err = events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	buckets, err := es.UptimeBuckets()
+	require.NoError(t, err)
+	assert.Equal(t, []entities.UptimeBucket{bucket}, buckets)
+	cnt, err := es.StatementsCount()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cnt, "the uptime buckets aren't statements")
+}
+
+func TestPersistentStorageMutes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.db")
+	var (
+		now     = time.Now()
+		silence = entities.NewAlertMuteByNode(entities.SilenceAlertMuteKind, entities.UnreachableAlertName, "blah",
+			now.Add(time.Hour).Unix(),
+		)
+		ack     = entities.NewAlertMuteByID(entities.AckAlertMuteKind, crypto.MustFastHash([]byte("alert")), 0)
+		expired = entities.NewAlertMuteByNode(entities.SilenceAlertMuteKind, entities.HeightAlertName, "blah",
+			now.Add(-time.Second).Unix(),
+		)
+	)
+	silence.CreatedAt, ack.CreatedAt = now.Unix(), now.Unix()
+
+	es, err := events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	for _, m := range []entities.AlertMute{silence, ack, expired} {
+		require.NoError(t, es.PutMute(m))
+	}
+	require.NoError(t, es.Close())
+
+	es, err = events.NewPersistentStorage(path, time.Minute, zap.NewNop())
+	require.NoError(t, err)
+	defer func() { require.NoError(t, es.Close()) }()
+
+	mutes, err := es.Mutes()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []entities.AlertMute{silence, ack}, mutes)
+	cnt, err := es.StatementsCount()
+	require.NoError(t, err)
+	assert.Zero(t, cnt, "the alert mutes aren't statements")
+
+	require.NoError(t, es.DeleteMute(ack.ID))
+	require.NoError(t, es.DeleteMute(ack.ID), "deletion of the missing mute isn't an error")
+	mutes, err = es.Mutes()
+	require.NoError(t, err)
+	assert.Equal(t, []entities.AlertMute{silence}, mutes)
+}
+
 func TestEarliestHeight(t *testing.T) {
 	logger, logErr := zap.NewDevelopment()
 	if logErr != nil {
@@ -599,3 +706,68 @@ func TestStatusSameHeightInStorage(t *testing.T) {
 		}
 	}
 }
+
+func TestGeneratorsStats(t *testing.T) {
+	storage, err := events.NewStorage(time.Minute, zap.NewNop())
+	require.NoError(t, err)
+
+	newAddress := func(pk crypto.PublicKey) proto.WavesAddress {
+		addr, addrErr := proto.NewAddressFromPublicKey(proto.MainNetScheme, pk)
+		require.NoError(t, addrErr)
+		return addr
+	}
+	var (
+		g1 = newAddress(crypto.PublicKey{0x01})
+		g2 = newAddress(crypto.PublicKey{0x02})
+		b1 = proto.NewBlockIDFromDigest(crypto.Digest{0x01})
+		b2 = proto.NewBlockIDFromDigest(crypto.Digest{0x02})
+		b3 = proto.NewBlockIDFromDigest(crypto.Digest{0x03})
+	)
+	blockEvent := func(node string, ts int64, h uint64, blockID proto.BlockID, gen proto.WavesAddress) entities.Event {
+		return entities.NewStateHashEvent(node, ts, "", h, nil, 1, &blockID, &gen, false)
+	}
+	putEvents(t, storage, genEvents(
+		blockEvent("A", 100, 1, b1, g1),
+		blockEvent("B", 110, 1, b1, g1), // the same block from another node
+		blockEvent("A", 200, 2, b2, g2),
+		blockEvent("B", 190, 2, b2, g2),
+		blockEvent("A", 300, 3, b3, g1),
+		entities.NewUnreachableEvent("B", 300),
+		he("C", 5, 50), // no generator
+	))
+	require.NoError(t, storage.PutUptimeBucket(entities.UptimeBucket{Node: "A", Start: 10}, time.Minute))
+	cnt, err := storage.StatementsCount()
+	require.NoError(t, err)
+	assert.Equal(t, 7, cnt, "the uptime buckets and the generators last blocks aren't statements")
+
+	earliest, found, err := storage.EarliestTimestamp()
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, int64(50), earliest)
+
+	last, found, err := storage.LastGeneratorBlock(g1.String())
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, entities.GeneratorStats{Generator: g1.String(), LastBlockHeight: 3, LastBlockTime: 300}, last)
+	last, found, err = storage.LastGeneratorBlock(g2.String())
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, entities.GeneratorStats{Generator: g2.String(), LastBlockHeight: 2, LastBlockTime: 190}, last,
+		"the block time is the time of the first statement about it",
+	)
+	_, found, err = storage.LastGeneratorBlock(newAddress(crypto.PublicKey{0x03}).String())
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	stats, err := storage.GeneratorsStats()
+	require.NoError(t, err)
+	assert.Equal(t, entities.GeneratorsStats{
+		Since:  50,
+		Until:  300,
+		Blocks: 3,
+		Generators: []entities.GeneratorStats{
+			{Generator: g1.String(), Blocks: 2, LastBlockHeight: 3, LastBlockTime: 300},
+			{Generator: g2.String(), Blocks: 1, LastBlockHeight: 2, LastBlockTime: 190},
+		},
+	}, stats)
+}
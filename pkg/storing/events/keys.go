@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
 )
 
 const (
@@ -71,3 +72,21 @@ func newStatementKey(node, timestamp string) string {
 	buf.WriteString(timestamp)
 	return buf.String()
 }
+
+const uptimeBucketKeyPrefix = "uptime:"
+
+func uptimeBucketKey(node string, start int64) string {
+	return uptimeBucketKeyPrefix + node + statementKeyPartSeparator + strconv.FormatInt(start, 10)
+}
+
+const generatorKeyPrefix = "generator:"
+
+func generatorKey(address string) string {
+	return generatorKeyPrefix + address
+}
+
+const muteKeyPrefix = "mute:"
+
+func muteKey(id crypto.Digest) string {
+	return muteKeyPrefix + id.String()
+}
@@ -0,0 +1,164 @@
+package maintenance
+
+import (
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/nodes"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Handler excludes the nodes under maintenance from the polling notifications, so their statements are still
+// stored, but aren't analyzed. When the maintenance window of a node ends, the handler emits a summary alert.
+type Handler struct {
+	ns     nodes.Storage
+	es     *events.Storage
+	zap    *zap.Logger
+	active map[string]entities.MaintenanceWindow // windows which have been seen active, by node URL
+}
+
+func NewHandler(ns nodes.Storage, es *events.Storage, zap *zap.Logger) *Handler {
+	return &Handler{
+		ns:     ns,
+		es:     es,
+		zap:    zap,
+		active: make(map[string]entities.MaintenanceWindow),
+	}
+}
+
+// Run wraps the notifications channel. It returns the filtered notifications and the summaries of the ended
+// maintenance windows. Both output channels must be consumed.
+func (h *Handler) Run(
+	input <-chan entities.NodesGatheringNotification,
+) (<-chan entities.NodesGatheringNotification, <-chan entities.Alert) {
+	output := make(chan entities.NodesGatheringNotification)
+	summaries := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		defer close(summaries)
+		for notification := range input {
+			if notification.Error() != nil && notification.NodesCount() == 0 { // pass through error notifications
+				output <- notification
+				continue
+			}
+			filtered, ended := h.handle(notification)
+			for _, alert := range ended {
+				summaries <- alert
+			}
+			output <- filtered
+		}
+	}()
+	return output, summaries
+}
+
+func (h *Handler) handle(
+	notification entities.NodesGatheringNotification,
+) (entities.NodesGatheringNotification, []entities.Alert) {
+	ts := notification.Timestamp()
+	allNodes, err := h.allNodes()
+	if err != nil {
+		h.zap.Error("Failed to get nodes for maintenance check", zap.Error(err))
+		return notification, nil // nodes are analyzed as usual
+	}
+	var (
+		inMaintenance = make(map[string]struct{})
+		ended         []entities.Alert
+	)
+	for _, node := range allNodes {
+		window := node.Maintenance
+		switch {
+		case window.Active(ts):
+			inMaintenance[node.URL] = struct{}{}
+			h.active[node.URL] = *window
+		case window.Ended(ts):
+			ended = append(ended, h.summary(node.URL, *window, ts))
+			if setErr := h.ns.SetMaintenance(node.URL, nil); setErr != nil {
+				h.zap.Error("Failed to clear ended maintenance window",
+					zap.String("node", node.URL), zap.Error(setErr),
+				)
+			}
+			delete(h.active, node.URL)
+		}
+	}
+	for url, window := range h.active { // windows which have been cancelled before their end
+		if _, ok := inMaintenance[url]; ok {
+			continue
+		}
+		delete(h.active, url)
+		if _, ok := findNode(allNodes, url); ok { // skip deleted nodes
+			window.End = min(window.End, ts)
+			ended = append(ended, h.summary(url, window, ts))
+		}
+	}
+	if len(inMaintenance) == 0 {
+		return notification, ended
+	}
+	polled := notification.Nodes()
+	filtered := make([]string, 0, len(polled))
+	for _, node := range polled {
+		if _, ok := inMaintenance[node]; !ok {
+			filtered = append(filtered, node)
+		}
+	}
+	h.zap.Sugar().Infof("%d nodes are under maintenance at timestamp %d", len(polled)-len(filtered), ts)
+	var out entities.NodesGatheringNotification = entities.NewNodesGatheringComplete(filtered, ts)
+	if notificationErr := notification.Error(); notificationErr != nil {
+		out = entities.NewNodesGatheringWithError(out, notificationErr)
+	}
+	return out, ended
+}
+
+func (h *Handler) allNodes() ([]entities.Node, error) {
+	regular, err := h.ns.Nodes(false)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get regular nodes")
+	}
+	specific, err := h.ns.Nodes(true)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get specific nodes")
+	}
+	return append(regular, specific...), nil
+}
+
+func findNode(allNodes []entities.Node, url string) (entities.Node, bool) {
+	for _, node := range allNodes {
+		if node.URL == url {
+			return node, true
+		}
+	}
+	return entities.Node{}, false
+}
+
+// summary collects the statistics of the node statements gathered during the maintenance window.
+func (h *Handler) summary(node string, window entities.MaintenanceWindow, ts int64) *entities.MaintenanceEndedAlert {
+	alert := &entities.MaintenanceEndedAlert{
+		Timestamp: ts,
+		Node:      node,
+		Start:     window.Start,
+		End:       window.End,
+	}
+	err := h.es.ViewStatementsByNodeWithDescendKeys(node, func(statement *entities.NodeStatement) bool {
+		if statement.Timestamp >= window.End {
+			return true
+		}
+		if statement.Timestamp < window.Start {
+			return false // statements are sorted by timestamp in descending order
+		}
+		if alert.StatementsCount == 0 { // the latest statement in the window
+			alert.LastStatus = statement.Status
+			alert.LastHeight = statement.Height
+			alert.LastVersion = statement.Version
+			alert.LastStatementTime = statement.Timestamp
+		}
+		alert.StatementsCount++
+		if statement.Status == entities.Unreachable {
+			alert.UnreachableCount++
+		}
+		return true
+	})
+	if err != nil {
+		h.zap.Error("Failed to collect maintenance window statements", zap.String("node", node), zap.Error(err))
+	}
+	return alert
+}
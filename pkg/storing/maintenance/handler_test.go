@@ -0,0 +1,73 @@
+package maintenance_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/events"
+	"nodemon/pkg/storing/maintenance"
+	"nodemon/pkg/storing/nodes"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestHandler(t *testing.T) {
+	const (
+		nodeA = "http://a.example.com"
+		nodeB = "http://b.example.com"
+	)
+	logger := zap.NewNop()
+	ns, err := nodes.NewJSONFileStorage(filepath.Join(t.TempDir(), "nodes.json"), []string{nodeA, nodeB}, logger)
+	require.NoError(t, err)
+	es, err := events.NewStorage(time.Minute, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, es.Close()) })
+
+	require.NoError(t, ns.SetMaintenance(nodeA, &entities.MaintenanceWindow{Start: 100, End: 300}))
+	for _, e := range []entities.Event{
+		entities.NewUnreachableEvent(nodeA, 100),
+		entities.NewHeightEvent(nodeA, 200, "v1.5.0", 42),
+		entities.NewUnreachableEvent(nodeA, 300),
+	} {
+		require.NoError(t, es.PutEvent(e))
+	}
+
+	input := make(chan entities.NodesGatheringNotification)
+	output, summaries := maintenance.NewHandler(ns, es, logger).Run(input)
+
+	input <- entities.NewNodesGatheringComplete([]string{nodeA, nodeB}, 200)
+	n := <-output
+	assert.Equal(t, []string{nodeB}, n.Nodes())
+	assert.NoError(t, n.Error())
+
+	input <- entities.NewNodesGatheringComplete([]string{nodeA, nodeB}, 300)
+	alert := <-summaries
+	n = <-output
+	assert.Equal(t, []string{nodeA, nodeB}, n.Nodes())
+	assert.Equal(t, &entities.MaintenanceEndedAlert{
+		Timestamp:         300,
+		Node:              nodeA,
+		Start:             100,
+		End:               300,
+		StatementsCount:   2,
+		UnreachableCount:  1,
+		LastStatus:        entities.Incomplete,
+		LastHeight:        42,
+		LastVersion:       "v1.5.0",
+		LastStatementTime: 200,
+	}, alert)
+
+	nodesList, err := ns.Nodes(false)
+	require.NoError(t, err)
+	for _, node := range nodesList {
+		assert.Nil(t, node.Maintenance, node.URL)
+	}
+
+	close(input)
+	_, ok := <-output
+	assert.False(t, ok)
+}
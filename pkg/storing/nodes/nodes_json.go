@@ -42,6 +42,15 @@ func (n nodes) FindNodeByURL(url string) (entities.Node, bool) {
 func (n nodes) Update(updated entities.Node) bool {
 	for i, node := range n {
 		if node.URL == updated.URL {
+			if updated.Maintenance == nil { // maintenance window is managed separately
+				updated.Maintenance = node.Maintenance
+			}
+			if updated.Tags == nil { // tags are managed separately
+				updated.Tags = node.Tags
+			}
+			if updated.Polling == nil { // polling options are managed separately
+				updated.Polling = node.Polling
+			}
 			n[i] = updated
 			return true
 		}
@@ -49,6 +58,36 @@ func (n nodes) Update(updated entities.Node) bool {
 	return false
 }
 
+func (n nodes) SetMaintenance(url string, window *entities.MaintenanceWindow) bool {
+	for i, node := range n {
+		if node.URL == url {
+			n[i].Maintenance = window
+			return true
+		}
+	}
+	return false
+}
+
+func (n nodes) SetTags(url string, tags []string) bool {
+	for i, node := range n {
+		if node.URL == url {
+			n[i].Tags = tags
+			return true
+		}
+	}
+	return false
+}
+
+func (n nodes) SetPolling(url string, opts *entities.PollingOptions) bool {
+	for i, node := range n {
+		if node.URL == url {
+			n[i].Polling = opts
+			return true
+		}
+	}
+	return false
+}
+
 func appendIfNew(ns nodes, url string) (nodes, bool) {
 	for _, node := range ns {
 		if node.URL == url {
@@ -289,6 +328,80 @@ func (s *JSONStorage) FindAlias(url string) (string, error) {
 	return n.Alias, nil
 }
 
+func (s *JSONStorage) SetMaintenance(url string, window *entities.MaintenanceWindow) error {
+	if window != nil {
+		if err := window.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid maintenance window for node '%s'", url)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := s.db.CommonNodes.SetMaintenance(url, window)
+	if !updated {
+		updated = s.db.SpecificNodes.SetMaintenance(url, window)
+	}
+	if !updated {
+		return nodeNotFoundErr(url)
+	}
+
+	if err := s.syncDB(); err != nil {
+		return errors.Wrapf(err, "failed to set maintenance window for node '%s'", url)
+	}
+	s.zap.Sugar().Infof("Maintenance window of node '%s' was set to %+v", url, window)
+	return nil
+}
+
+func (s *JSONStorage) SetTags(url string, tags []string) error {
+	tags, err := entities.NormalizeTags(tags)
+	if err != nil {
+		return errors.Wrapf(err, "invalid tags for node '%s'", url)
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := s.db.CommonNodes.SetTags(url, tags)
+	if !updated {
+		updated = s.db.SpecificNodes.SetTags(url, tags)
+	}
+	if !updated {
+		return nodeNotFoundErr(url)
+	}
+
+	if err = s.syncDB(); err != nil {
+		return errors.Wrapf(err, "failed to set tags for node '%s'", url)
+	}
+	s.zap.Sugar().Infof("Tags of node '%s' were set to %v", url, tags)
+	return nil
+}
+
+func (s *JSONStorage) SetPolling(url string, opts *entities.PollingOptions) error {
+	if opts != nil {
+		if err := opts.Validate(); err != nil {
+			return errors.Wrapf(err, "invalid polling options for node '%s'", url)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := s.db.CommonNodes.SetPolling(url, opts)
+	if !updated {
+		updated = s.db.SpecificNodes.SetPolling(url, opts)
+	}
+	if !updated {
+		return nodeNotFoundErr(url)
+	}
+
+	if err := s.syncDB(); err != nil {
+		return errors.Wrapf(err, "failed to set polling options for node '%s'", url)
+	}
+	s.zap.Sugar().Infof("Polling options of node '%s' were set to %+v", url, opts)
+	return nil
+}
+
 func (s *JSONStorage) populate(nodes []string) error {
 	var (
 		needSync  bool
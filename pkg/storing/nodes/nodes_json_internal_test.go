@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"nodemon/pkg/entities"
 
@@ -502,3 +503,169 @@ func TestJSONStorage_FindAlias(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONStorage_SetMaintenance(t *testing.T) {
+	window := &entities.MaintenanceWindow{Start: 100, End: 200}
+	tests := []struct {
+		name      string
+		url       string
+		window    *entities.MaintenanceWindow
+		db        dbStruct
+		updatedDB dbStruct
+		err       string
+	}{
+		{
+			name:   "SetSpecific",
+			url:    "kekpek",
+			window: window,
+			db: dbStruct{
+				SpecificNodes: nodes{{URL: "kekpek", Enabled: true}},
+				CommonNodes:   nodes{{URL: "heh"}},
+			},
+			updatedDB: dbStruct{
+				SpecificNodes: nodes{{URL: "kekpek", Enabled: true, Maintenance: window}},
+				CommonNodes:   nodes{{URL: "heh"}},
+			},
+		},
+		{
+			name: "ClearCommon",
+			url:  "heh",
+			db: dbStruct{
+				CommonNodes: nodes{{URL: "heh", Alias: "xxx", Maintenance: window}},
+			},
+			updatedDB: dbStruct{
+				CommonNodes: nodes{{URL: "heh", Alias: "xxx"}},
+			},
+		},
+		{
+			name:   "InvalidWindow",
+			url:    "heh",
+			window: &entities.MaintenanceWindow{Start: 200, End: 100},
+			db:     dbStruct{CommonNodes: nodes{{URL: "heh"}}},
+			err:    "invalid maintenance window for node 'heh': maintenance window start must be before its end",
+		},
+		{
+			name:   "NotFound",
+			url:    "kekpek",
+			window: window,
+			db:     dbStruct{CommonNodes: nodes{{URL: "heh"}}},
+			err:    "nodeRecord 'kekpek' was not found in the storage",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storage, dbFilePath := newTestJSONStorageWithDB(t, &test.db)
+			err := storage.SetMaintenance(test.url, test.window)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, &test.updatedDB, storage.db)
+				checkFileIsUpdated(t, dbFilePath, &test.updatedDB)
+			}
+		})
+	}
+}
+
+func TestJSONStorage_SetTags(t *testing.T) {
+	tests := []struct {
+		name      string
+		url       string
+		tags      []string
+		db        dbStruct
+		updatedDB dbStruct
+		err       string
+	}{
+		{
+			name: "SetSpecific",
+			url:  "kekpek",
+			tags: []string{"Public-API", "our-validators", "public-api"},
+			db: dbStruct{
+				SpecificNodes: nodes{{URL: "kekpek", Enabled: true}},
+				CommonNodes:   nodes{{URL: "heh"}},
+			},
+			updatedDB: dbStruct{
+				SpecificNodes: nodes{{URL: "kekpek", Enabled: true, Tags: []string{"our-validators", "public-api"}}},
+				CommonNodes:   nodes{{URL: "heh"}},
+			},
+		},
+		{
+			name: "ClearCommon",
+			url:  "heh",
+			db: dbStruct{
+				CommonNodes: nodes{{URL: "heh", Alias: "xxx", Tags: []string{"public-api"}}},
+			},
+			updatedDB: dbStruct{
+				CommonNodes: nodes{{URL: "heh", Alias: "xxx"}},
+			},
+		},
+		{
+			name: "InvalidTag",
+			url:  "heh",
+			tags: []string{"public api"},
+			db:   dbStruct{CommonNodes: nodes{{URL: "heh"}}},
+			err:  "invalid tags for node 'heh': tag 'public api' must consist of latin letters, digits, '-' and '_'",
+		},
+		{
+			name: "NotFound",
+			url:  "kekpek",
+			tags: []string{"public-api"},
+			db:   dbStruct{CommonNodes: nodes{{URL: "heh"}}},
+			err:  "nodeRecord 'kekpek' was not found in the storage",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			storage, dbFilePath := newTestJSONStorageWithDB(t, &test.db)
+			err := storage.SetTags(test.url, test.tags)
+			if test.err != "" {
+				assert.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, &test.updatedDB, storage.db)
+				checkFileIsUpdated(t, dbFilePath, &test.updatedDB)
+			}
+		})
+	}
+}
+
+func TestJSONStorage_SetPolling(t *testing.T) {
+	db := dbStruct{CommonNodes: nodes{{URL: "heh", Enabled: true}}}
+	storage, dbFilePath := newTestJSONStorageWithDB(t, &db)
+
+	opts := &entities.PollingOptions{Interval: 300, Timeout: 30}
+	require.NoError(t, storage.SetPolling("heh", opts))
+	updatedDB := dbStruct{CommonNodes: nodes{{URL: "heh", Enabled: true, Polling: opts}}}
+	assert.Equal(t, &updatedDB, storage.db)
+	checkFileIsUpdated(t, dbFilePath, &updatedDB)
+
+	require.NoError(t, storage.Update(entities.Node{URL: "heh", Alias: "xxx"}))
+	stored, err := storage.Nodes(false)
+	require.NoError(t, err)
+	assert.Equal(t, opts, stored[0].Polling, "polling options must be kept on update")
+
+	err = storage.SetPolling("heh", &entities.PollingOptions{Interval: 10, Timeout: 30})
+	assert.EqualError(t, err,
+		"invalid polling options for node 'heh': polling timeout must not be greater than the interval",
+	)
+	assert.EqualError(t, storage.SetPolling("kekpek", nil), "nodeRecord 'kekpek' was not found in the storage")
+}
+
+func TestWithMaxPollingInterval(t *testing.T) {
+	db := dbStruct{CommonNodes: nodes{{URL: "heh", Enabled: true}}}
+	jsonStorage, _ := newTestJSONStorageWithDB(t, &db)
+	maxInterval := 5 * time.Minute
+	storage := WithMaxPollingInterval(jsonStorage, func() time.Duration { return maxInterval })
+
+	opts := &entities.PollingOptions{Interval: 300}
+	require.NoError(t, storage.SetPolling("heh", opts))
+	err := storage.SetPolling("heh", &entities.PollingOptions{Interval: 301})
+	assert.EqualError(t, err, "invalid polling options for node 'heh': polling interval must not be longer than 5m0s")
+	stored, err := storage.Nodes(false)
+	require.NoError(t, err)
+	assert.Equal(t, opts, stored[0].Polling, "rejected polling options must not be stored")
+
+	maxInterval = time.Minute // the limit follows the reloaded alerts vacuum quota
+	assert.Error(t, storage.SetPolling("heh", opts))
+	require.NoError(t, storage.SetPolling("heh", nil))
+}
@@ -1,6 +1,12 @@
 package nodes
 
-import "nodemon/pkg/entities"
+import (
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+)
 
 type Storage interface {
 	Close() error
@@ -11,4 +17,30 @@ type Storage interface {
 	InsertIfNew(url string, specific bool) (bool, error)
 	Delete(url string) error
 	FindAlias(url string) (string, error)
+	// SetMaintenance sets the maintenance window of the node. Nil window removes the maintenance.
+	SetMaintenance(url string, window *entities.MaintenanceWindow) error
+	// SetTags replaces the tags of the node. Empty list removes all tags.
+	SetTags(url string, tags []string) error
+	// SetPolling sets the polling options of the node. Nil options restore the defaults.
+	SetPolling(url string, opts *entities.PollingOptions) error
+}
+
+// pollingLimitedStorage rejects the node polling intervals longer than the limit, which may change at runtime.
+type pollingLimitedStorage struct {
+	Storage
+	maxInterval func() time.Duration
+}
+
+// WithMaxPollingInterval wraps the storage, so that SetPolling rejects the intervals longer than maxInterval.
+func WithMaxPollingInterval(s Storage, maxInterval func() time.Duration) Storage {
+	return &pollingLimitedStorage{Storage: s, maxInterval: maxInterval}
+}
+
+func (s *pollingLimitedStorage) SetPolling(url string, opts *entities.PollingOptions) error {
+	if opts != nil {
+		if err := opts.ValidateInterval(s.maxInterval()); err != nil {
+			return errors.Wrapf(err, "invalid polling options for node '%s'", url)
+		}
+	}
+	return s.Storage.SetPolling(url, opts)
 }
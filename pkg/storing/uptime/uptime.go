@@ -0,0 +1,242 @@
+// Package uptime collects the polling and alert statistics of the nodes for the periodic reports.
+package uptime
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"nodemon/pkg/entities"
+
+	"github.com/pkg/errors"
+	"github.com/wavesplatform/gowaves/pkg/crypto"
+	"go.uber.org/zap"
+)
+
+// MaxPeriod is the longest period which the statistics are kept for.
+const MaxPeriod = 7 * 24 * time.Hour
+
+const bucketDuration = time.Hour
+
+// bucketTTL keeps the saved bucket until it leaves the longest period.
+const bucketTTL = MaxPeriod + bucketDuration
+
+// Storage provides the node statements gathered by the scraper and keeps the uptime buckets between restarts.
+type Storage interface {
+	GetStatement(nodeURL string, timestamp int64) (entities.NodeStatement, error)
+	PutUptimeBucket(bucket entities.UptimeBucket, ttl time.Duration) error
+	UptimeBuckets() ([]entities.UptimeBucket, error)
+}
+
+// NodeSummary is the statistics of the node for the period.
+type NodeSummary struct {
+	Node string `json:"node"`
+	// Polls is the number of the node polls, the polls during the node maintenance aren't counted.
+	Polls int `json:"polls"`
+	// OKPolls is the number of the polls when the node has been reachable and has returned the full statement.
+	OKPolls int `json:"ok_polls"`
+	// Alerts is the number of the alerts about the node which have been opened in the period.
+	Alerts int `json:"alerts"`
+}
+
+// Uptime returns the share of the successful polls in percent.
+func (s NodeSummary) Uptime() float64 {
+	if s.Polls == 0 {
+		return 0
+	}
+	const percent = 100
+	return float64(s.OKPolls) * percent / float64(s.Polls)
+}
+
+type counters struct {
+	polls   int
+	okPolls int
+	alerts  int
+}
+
+// Tracker counts the polls and the alerts of every node in hourly buckets. The statistics are kept for MaxPeriod
+// and every changed bucket is saved to the storage, so the persistent storage keeps them across restarts.
+// It's safe for concurrent use.
+type Tracker struct {
+	mu      *sync.Mutex
+	es      Storage
+	zap     *zap.Logger
+	buckets map[string]map[int64]*counters // node URL -> bucket start -> counters
+	opened  map[crypto.Digest]struct{}     // the alerts which are opened and not fixed yet
+}
+
+// NewTracker creates the tracker with the buckets saved in the storage earlier.
+func NewTracker(es Storage, logger *zap.Logger) (*Tracker, error) {
+	saved, err := es.UptimeBuckets()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create uptime tracker")
+	}
+	t := &Tracker{
+		mu:      new(sync.Mutex),
+		es:      es,
+		zap:     logger,
+		buckets: make(map[string]map[int64]*counters),
+		opened:  make(map[crypto.Digest]struct{}),
+	}
+	for _, b := range saved {
+		c := t.unsafeCounters(b.Node, b.Start)
+		c.polls, c.okPolls, c.alerts = b.Polls, b.OKPolls, b.Alerts
+	}
+	return t, nil
+}
+
+// RunNotifications counts the polls of the nodes from every notification and passes it through.
+func (t *Tracker) RunNotifications(
+	input <-chan entities.NodesGatheringNotification,
+) <-chan entities.NodesGatheringNotification {
+	output := make(chan entities.NodesGatheringNotification)
+	go func() {
+		defer close(output)
+		for notification := range input {
+			if notification.Error() == nil {
+				t.putPolls(notification.Timestamp(), notification.Nodes())
+			}
+			output <- notification
+		}
+	}()
+	return output
+}
+
+// RunAlerts counts the opened alerts of every node and passes the alerts through.
+func (t *Tracker) RunAlerts(input <-chan entities.Alert) <-chan entities.Alert {
+	output := make(chan entities.Alert)
+	go func() {
+		defer close(output)
+		for alert := range input {
+			t.PutAlert(alert)
+			output <- alert
+		}
+	}()
+	return output
+}
+
+func (t *Tracker) putPolls(ts int64, nodes []string) {
+	type poll struct {
+		node string
+		ok   bool
+	}
+	polls := make([]poll, 0, len(nodes))
+	for _, node := range nodes {
+		statement, err := t.es.GetStatement(node, ts)
+		if err != nil {
+			t.zap.Debug("Failed to get node statement for uptime",
+				zap.String("node", node), zap.Int64("timestamp", ts), zap.Error(err),
+			)
+			continue
+		}
+		polls = append(polls, poll{node: node, ok: statement.Status == entities.OK})
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range polls {
+		c := t.unsafeCounters(p.node, ts)
+		c.polls++
+		if p.ok {
+			c.okPolls++
+		}
+		t.unsafeSave(p.node, ts, c)
+	}
+	t.unsafeVacuum(ts)
+}
+
+// PutAlert counts the alert for its nodes if the alert is opened, the repeated alerts aren't counted.
+func (t *Tracker) PutAlert(alert entities.Alert) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if fixed, ok := alert.(*entities.AlertFixed); ok {
+		if fixed.Fixed != nil {
+			delete(t.opened, fixed.Fixed.ID())
+		}
+		return
+	}
+	id := alert.ID()
+	if _, ok := t.opened[id]; ok {
+		return
+	}
+	t.opened[id] = struct{}{}
+	ts := alert.Time().Unix()
+	for _, node := range entities.AlertNodes(alert) {
+		c := t.unsafeCounters(node, ts)
+		c.alerts++
+		t.unsafeSave(node, ts, c)
+	}
+}
+
+// Summary returns the statistics of every node since the given time, the nodes are sorted by URL.
+func (t *Tracker) Summary(since int64) []NodeSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sinceBucket := bucketStart(since)
+	out := make([]NodeSummary, 0, len(t.buckets))
+	for node, buckets := range t.buckets {
+		s := NodeSummary{Node: node}
+		for start, c := range buckets {
+			if start < sinceBucket {
+				continue
+			}
+			s.Polls += c.polls
+			s.OKPolls += c.okPolls
+			s.Alerts += c.alerts
+		}
+		if s.Polls > 0 || s.Alerts > 0 {
+			out = append(out, s)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Node < out[j].Node })
+	return out
+}
+
+func (t *Tracker) unsafeCounters(node string, ts int64) *counters {
+	buckets, ok := t.buckets[node]
+	if !ok {
+		buckets = make(map[int64]*counters)
+		t.buckets[node] = buckets
+	}
+	start := bucketStart(ts)
+	c, ok := buckets[start]
+	if !ok {
+		c = new(counters)
+		buckets[start] = c
+	}
+	return c
+}
+
+// unsafeSave saves the bucket of the node which contains the given time, it must be called under the lock.
+// The failure is only logged, the counters are still kept in memory.
+func (t *Tracker) unsafeSave(node string, ts int64, c *counters) {
+	bucket := entities.UptimeBucket{
+		Node:    node,
+		Start:   bucketStart(ts),
+		Polls:   c.polls,
+		OKPolls: c.okPolls,
+		Alerts:  c.alerts,
+	}
+	if err := t.es.PutUptimeBucket(bucket, bucketTTL); err != nil {
+		t.zap.Warn("Failed to save uptime bucket", zap.String("node", node), zap.Error(err))
+	}
+}
+
+// unsafeVacuum deletes the buckets older than MaxPeriod, it must be called under the lock.
+func (t *Tracker) unsafeVacuum(now int64) {
+	oldest := bucketStart(now - int64(MaxPeriod/time.Second))
+	for node, buckets := range t.buckets {
+		for start := range buckets {
+			if start < oldest {
+				delete(buckets, start)
+			}
+		}
+		if len(buckets) == 0 {
+			delete(t.buckets, node)
+		}
+	}
+}
+
+func bucketStart(ts int64) int64 {
+	size := int64(bucketDuration / time.Second)
+	return ts - ts%size
+}
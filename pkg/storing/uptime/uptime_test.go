@@ -0,0 +1,143 @@
+package uptime_test
+
+import (
+	"testing"
+	"time"
+
+	"nodemon/pkg/entities"
+	"nodemon/pkg/storing/uptime"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type statementKey struct {
+	node string
+	ts   int64
+}
+
+type staticStatements map[statementKey]entities.NodeStatus
+
+func (s staticStatements) GetStatement(nodeURL string, timestamp int64) (entities.NodeStatement, error) {
+	status, ok := s[statementKey{node: nodeURL, ts: timestamp}]
+	if !ok {
+		return entities.NodeStatement{}, errors.New("not found")
+	}
+	return entities.NodeStatement{Node: nodeURL, Timestamp: timestamp, Status: status}, nil
+}
+
+// testStorage keeps the uptime buckets in memory as the events storage does.
+type testStorage struct {
+	staticStatements
+	buckets map[statementKey]entities.UptimeBucket
+}
+
+func newTestStorage(statements staticStatements) *testStorage {
+	return &testStorage{staticStatements: statements, buckets: make(map[statementKey]entities.UptimeBucket)}
+}
+
+func (s *testStorage) PutUptimeBucket(bucket entities.UptimeBucket, _ time.Duration) error {
+	s.buckets[statementKey{node: bucket.Node, ts: bucket.Start}] = bucket
+	return nil
+}
+
+func (s *testStorage) UptimeBuckets() ([]entities.UptimeBucket, error) {
+	out := make([]entities.UptimeBucket, 0, len(s.buckets))
+	for _, b := range s.buckets {
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func newTracker(t *testing.T, storage uptime.Storage) *uptime.Tracker {
+	tracker, err := uptime.NewTracker(storage, zap.NewNop())
+	require.NoError(t, err)
+	return tracker
+}
+
+func runNotifications(t *testing.T, tracker *uptime.Tracker, notifications ...entities.NodesGatheringNotification) {
+	input := make(chan entities.NodesGatheringNotification)
+	output := tracker.RunNotifications(input)
+	for _, n := range notifications {
+		input <- n
+		require.Equal(t, n, <-output)
+	}
+	close(input)
+}
+
+func TestTracker_Summary(t *testing.T) {
+	const (
+		a = "http://a.example.com"
+		b = "http://b.example.com"
+	)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	statements := staticStatements{
+		{node: a, ts: start}:        entities.OK,
+		{node: b, ts: start}:        entities.OK,
+		{node: a, ts: start + 60}:   entities.Unreachable,
+		{node: b, ts: start + 60}:   entities.OK,
+		{node: a, ts: start + 7200}: entities.OK,
+	}
+	tracker := newTracker(t, newTestStorage(statements))
+	runNotifications(t, tracker,
+		entities.NewNodesGatheringComplete([]string{a, b}, start),
+		entities.NewNodesGatheringComplete([]string{a, b}, start+60),
+		entities.NewNodesGatheringComplete([]string{a}, start+7200), // b is under maintenance
+	)
+	unreachable := &entities.UnreachableAlert{Timestamp: start + 60, Node: a}
+	tracker.PutAlert(unreachable)
+	tracker.PutAlert(&entities.UnreachableAlert{Timestamp: start + 60, Node: a}) // repeated alert
+	tracker.PutAlert(&entities.AlertFixed{Timestamp: start + 120, Fixed: unreachable})
+	tracker.PutAlert(&entities.UnreachableAlert{Timestamp: start + 7200, Node: a}) // opened again
+
+	summary := tracker.Summary(start)
+	expected := []uptime.NodeSummary{
+		{Node: a, Polls: 3, OKPolls: 2, Alerts: 2},
+		{Node: b, Polls: 2, OKPolls: 2},
+	}
+	assert.Equal(t, expected, summary)
+	assert.InDelta(t, 66.67, summary[0].Uptime(), 0.01)
+	assert.InDelta(t, 100, summary[1].Uptime(), 0.01)
+
+	assert.Equal(t, []uptime.NodeSummary{{Node: a, Polls: 1, OKPolls: 1, Alerts: 1}}, tracker.Summary(start+3600))
+}
+
+func TestTracker_Vacuum(t *testing.T) {
+	const node = "http://a.example.com"
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	later := start + int64((uptime.MaxPeriod+time.Hour)/time.Second)
+	statements := staticStatements{
+		{node: node, ts: start}: entities.OK,
+		{node: node, ts: later}: entities.Unreachable,
+	}
+	tracker := newTracker(t, newTestStorage(statements))
+	runNotifications(t, tracker,
+		entities.NewNodesGatheringComplete([]string{node}, start),
+		entities.NewNodesGatheringComplete([]string{node}, later),
+	)
+	assert.Equal(t, []uptime.NodeSummary{{Node: node, Polls: 1}}, tracker.Summary(start),
+		"the statistics older than the max period must be deleted",
+	)
+}
+
+func TestTracker_Restart(t *testing.T) {
+	const node = "http://a.example.com"
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Unix()
+	storage := newTestStorage(staticStatements{
+		{node: node, ts: start}:      entities.OK,
+		{node: node, ts: start + 60}: entities.Unreachable,
+	})
+	tracker := newTracker(t, storage)
+	runNotifications(t, tracker,
+		entities.NewNodesGatheringComplete([]string{node}, start),
+		entities.NewNodesGatheringComplete([]string{node}, start+60),
+	)
+	tracker.PutAlert(&entities.UnreachableAlert{Timestamp: start + 60, Node: node})
+
+	restarted := newTracker(t, storage)
+	assert.Equal(t, []uptime.NodeSummary{{Node: node, Polls: 2, OKPolls: 1, Alerts: 1}}, restarted.Summary(start),
+		"the statistics must survive the restart",
+	)
+}